@@ -0,0 +1,51 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/autonomy/conform/internal/enforcer"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// baselineCmd represents the baseline command
+var baselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "Snapshot the current violations into the baseline file",
+	Long: `Enforces all policies and writes the violations found to the baseline
+file. Subsequent runs of enforce report the violations in the baseline without
+failing, so that only new violations fail.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			err := errors.Errorf("The baseline command does not take arguments")
+
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		opts, err := policyOptions(cmd)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		e, err := enforcer.New(enforcerOptions(cmd)...)
+		if err != nil {
+			exitConfigError(cmd, err)
+		}
+
+		if err = e.Baseline(opts...); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	addEnforcerFlags(baselineCmd)
+	RootCmd.AddCommand(baselineCmd)
+}
@@ -0,0 +1,118 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/autonomy/conform/internal/enforcer"
+	"github.com/autonomy/conform/internal/policy/commit"
+	"github.com/autonomy/conform/internal/wizard"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// commitCmd represents the commit command
+var commitCmd = &cobra.Command{
+	Use:   "commit",
+	Short: "Interactively compose a compliant commit message and commit",
+	Long: `Prompts for the type and scope of conventional commits, the subject, the
+body, and the footers of the commit message, following the commit policy of
+the configuration, and commits the staged changes with the message. The commit
+is signed off when the policy requires a DCO, and signed when it requires a GPG
+signature.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			err := errors.Errorf("The commit command does not take arguments")
+
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		e, err := enforcer.New(enforcerOptions(cmd)...)
+		if err != nil {
+			exitConfigError(cmd, err)
+		}
+		c, err := e.CommitPolicy()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if c == nil {
+			c = &commit.Commit{}
+		}
+
+		var signOff string
+		if c.DCO {
+			if signOff, err = authorIdent(); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+
+		p := wizard.NewPrompter(os.Stdin, os.Stdout)
+		msg, err := wizard.Compose(p, c, signOff)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("\n%s\n", msg)
+
+		if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+			return
+		}
+		ok, err := p.Confirm("Commit")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if !ok {
+			os.Exit(1)
+		}
+
+		gitArgs := []string{"commit", "--cleanup=verbatim", "--file=-"}
+		if c.GPG {
+			gitArgs = append(gitArgs, "--gpg-sign")
+		}
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			gitArgs = append(gitArgs, "--all")
+		}
+		git := exec.Command("git", gitArgs...)
+		git.Stdin = strings.NewReader(msg)
+		git.Stdout = os.Stdout
+		git.Stderr = os.Stderr
+		if err = git.Run(); err != nil {
+			fmt.Println(errors.Errorf("failed to commit: %v", err))
+			os.Exit(1)
+		}
+	},
+}
+
+// authorIdent returns the name and email of the author of commits, in the
+// form of a sign off.
+func authorIdent() (string, error) {
+	out, err := exec.Command("git", "var", "GIT_AUTHOR_IDENT").Output()
+	if err != nil {
+		return "", errors.Errorf("failed to get the commit author: %v", err)
+	}
+	ident := string(out)
+	// The ident ends with the timestamp of the commit.
+	if i := strings.LastIndex(ident, ">"); i >= 0 {
+		ident = ident[:i+1]
+	}
+
+	return ident, nil
+}
+
+func init() {
+	commitCmd.Flags().StringSlice("config-file", nil, "the configuration files, merged in order with later files taking precedence (default is .conform.yaml and .conform.local.yaml)")
+	commitCmd.Flags().String("profile", "", profileUsage)
+	commitCmd.Flags().BoolP("all", "a", false, "stage the modified and deleted files before committing")
+	commitCmd.Flags().Bool("dry-run", false, "print the commit message without committing")
+	RootCmd.AddCommand(commitCmd)
+}
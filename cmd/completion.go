@@ -0,0 +1,440 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/autonomy/conform/internal/enforcer"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// nameFlags maps the flags whose values are names discovered from the
+// loaded configuration to the kind of names, as listed by the hidden
+// __complete command.
+var nameFlags = map[string]string{
+	"policy": "policies",
+	"check":  "checks",
+	"skip":   "names",
+}
+
+// fileFlags are the flags whose values are paths.
+var fileFlags = map[string]bool{
+	"config":          true,
+	"config-file":     true,
+	"commit-msg-file": true,
+	"baseline-file":   true,
+}
+
+const bashCompletionFunction = `__conform_complete()
+{
+    local IFS=$'\n' name
+    COMPREPLY=()
+    for name in $(conform __complete "$1" 2>/dev/null); do
+        if [[ $name == "$cur"* ]]; then
+            COMPREPLY+=( "$(printf '%q' "$name")" )
+        fi
+    done
+}
+
+__conform_complete_policies()
+{
+    __conform_complete policies
+}
+
+__conform_complete_checks()
+{
+    __conform_complete checks
+}
+
+__conform_complete_names()
+{
+    __conform_complete names
+}
+`
+
+// completionCmd represents the completion command
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish|powershell",
+	Short: "Generate shell completions",
+	Long: `Writes the completion script of the shell to the standard output. The
+script completes the commands and flags, and the names of the policies and
+checks declared by the configuration of the current directory.
+
+To load the completions in the current shell:
+
+	bash:        source <(conform completion bash)
+	zsh:         source <(conform completion zsh)
+	fish:        conform completion fish | source
+	powershell:  conform completion powershell | Out-String | Invoke-Expression`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			err := errors.Errorf("The completion command takes exactly one argument: bash, zsh, fish, or powershell")
+
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if err := writeCompletion(os.Stdout, RootCmd, args[0]); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+// namesCmd lists the names of the policies and checks of the configuration,
+// for the completion scripts.
+var namesCmd = &cobra.Command{
+	Use:    "__complete policies|checks|names",
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			os.Exit(1)
+		}
+		e, err := enforcer.New()
+		if err != nil {
+			os.Exit(1)
+		}
+		for _, name := range completionNames(e, args[0]) {
+			fmt.Println(name)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(completionCmd)
+	RootCmd.AddCommand(namesCmd)
+}
+
+// completionNames returns the names of the kind declared by the
+// configuration: the types of the policies, the names of their enabled
+// checks, or both.
+func completionNames(e *enforcer.Conform, kind string) []string {
+	seen := map[string]bool{}
+	var names []string
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, d := range e.List() {
+		if !d.Enabled {
+			continue
+		}
+		if kind == "policies" || kind == "names" {
+			add(d.Type)
+		}
+		if kind == "checks" || kind == "names" {
+			for _, check := range d.Checks {
+				if check.Enabled {
+					add(check.Name)
+				}
+			}
+		}
+	}
+
+	return names
+}
+
+// writeCompletion writes the completion script of the shell for the command
+// tree.
+func writeCompletion(w io.Writer, root *cobra.Command, shell string) error {
+	switch shell {
+	case "bash":
+		annotateFlags(root)
+		root.BashCompletionFunction = bashCompletionFunction
+		return root.GenBashCompletion(w)
+	case "zsh":
+		return writeZshCompletion(w, root)
+	case "fish":
+		return writeFishCompletion(w, root)
+	case "powershell":
+		return writePowerShellCompletion(w, root)
+	default:
+		return errors.Errorf("Unsupported shell %q: must be one of bash, zsh, fish, or powershell", shell)
+	}
+}
+
+// annotateFlags annotates the flags of the command tree for the bash
+// completion generator.
+func annotateFlags(c *cobra.Command) {
+	visitFlags(c, func(f *pflag.Flag) {
+		if kind, ok := nameFlags[f.Name]; ok {
+			// nolint: errcheck
+			cobra.MarkFlagCustom(c.Flags(), f.Name, "__conform_complete_"+kind)
+		} else if fileFlags[f.Name] {
+			// nolint: errcheck
+			c.Flags().SetAnnotation(f.Name, cobra.BashCompFilenameExt, nil)
+		}
+	})
+	for _, sub := range c.Commands() {
+		annotateFlags(sub)
+	}
+}
+
+// visitFlags calls fn for each flag of the command, including the persistent
+// flags of its parents, in lexicographical order.
+func visitFlags(c *cobra.Command, fn func(*pflag.Flag)) {
+	flags := map[string]*pflag.Flag{}
+	c.LocalFlags().VisitAll(func(f *pflag.Flag) { flags[f.Name] = f })
+	c.InheritedFlags().VisitAll(func(f *pflag.Flag) { flags[f.Name] = f })
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !flags[name].Hidden {
+			fn(flags[name])
+		}
+	}
+}
+
+// subcommands returns the available subcommands of the command.
+func subcommands(c *cobra.Command) []*cobra.Command {
+	var commands []*cobra.Command
+	for _, sub := range c.Commands() {
+		if sub.IsAvailableCommand() && sub.Name() != "help" {
+			commands = append(commands, sub)
+		}
+	}
+
+	return commands
+}
+
+func writeZshCompletion(w io.Writer, root *cobra.Command) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#compdef %s\n", root.Name())
+	writeZshFunction(&b, root, "_"+root.Name())
+	b.WriteString(`
+_conform_names() {
+  local -a names
+  names=(${(f)"$(conform __complete $1 2>/dev/null)"})
+  compadd -a names
+}
+
+if [[ $zsh_eval_context[-1] == loadautofunc ]]; then
+  _conform "$@"
+else
+  compdef _conform conform
+fi
+`)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeZshFunction writes the completion function of the command, followed
+// by those of its subcommands.
+func writeZshFunction(b *strings.Builder, c *cobra.Command, name string) {
+	commands := subcommands(c)
+
+	fmt.Fprintf(b, "\n%s() {\n", name)
+	b.WriteString("  local state line\n  _arguments -C \\\n")
+	visitFlags(c, func(f *pflag.Flag) {
+		fmt.Fprintf(b, "    %s \\\n", zshFlagSpec(f))
+	})
+	if len(commands) != 0 {
+		b.WriteString("    '1: :->command' \\\n    '*::arg:->args'\n\n")
+		b.WriteString("  case $state in\n    command)\n      local -a commands\n      commands=(\n")
+		for _, sub := range commands {
+			fmt.Fprintf(b, "        %s\n", zshQuote(sub.Name()+":"+sub.Short))
+		}
+		b.WriteString("      )\n      _describe command commands\n      ;;\n    args)\n      case $line[1] in\n")
+		for _, sub := range commands {
+			fmt.Fprintf(b, "        %s) %s_%s ;;\n", sub.Name(), name, strings.Replace(sub.Name(), "-", "_", -1))
+		}
+		b.WriteString("      esac\n      ;;\n  esac\n}\n")
+	} else {
+		b.WriteString("    '*: :_files'\n}\n")
+	}
+
+	for _, sub := range commands {
+		writeZshFunction(b, sub, name+"_"+strings.Replace(sub.Name(), "-", "_", -1))
+	}
+}
+
+func zshFlagSpec(f *pflag.Flag) string {
+	usage := strings.NewReplacer("[", "\\[", "]", "\\]").Replace(f.Usage)
+	var value string
+	if f.Value.Type() != "bool" {
+		switch {
+		case nameFlags[f.Name] != "":
+			value = fmt.Sprintf(":%s:_conform_names %s", f.Name, nameFlags[f.Name])
+		case fileFlags[f.Name]:
+			value = fmt.Sprintf(":%s:_files", f.Name)
+		default:
+			value = fmt.Sprintf(":%s: ", f.Name)
+		}
+	}
+	repeat := ""
+	if strings.HasSuffix(f.Value.Type(), "Slice") {
+		repeat = "*"
+	}
+	if f.Shorthand != "" {
+		return fmt.Sprintf("'(-%s --%s)'{-%s,--%s}%s", f.Shorthand, f.Name, f.Shorthand, f.Name, zshQuote("["+usage+"]"+value))
+	}
+
+	return zshQuote(repeat + "--" + f.Name + "[" + usage + "]" + value)
+}
+
+func zshQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+func writeFishCompletion(w io.Writer, root *cobra.Command) error {
+	var b strings.Builder
+	name := root.Name()
+	fmt.Fprintf(&b, "# fish completion for %s\n\n", name)
+	fmt.Fprintf(&b, "complete -c %s -f\n", name)
+	writeFishCommand(&b, root)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeFishCommand writes the completions of the command, followed by those
+// of its subcommands. The persistent flags of the root are completed for all
+// commands.
+func writeFishCommand(b *strings.Builder, c *cobra.Command) {
+	root := c.Root().Name()
+	condition := ""
+	flags := c.PersistentFlags()
+	if c.HasParent() {
+		parent := "__fish_use_subcommand"
+		if c.Parent().HasParent() {
+			parent = "__fish_seen_subcommand_from " + c.Parent().Name()
+		}
+		fmt.Fprintf(b, "complete -c %s -n %s -a %s -d %s\n", root, fishQuote(parent), c.Name(), fishQuote(c.Short))
+		condition = "__fish_seen_subcommand_from " + c.Name()
+		flags = c.LocalFlags()
+	}
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		fmt.Fprintf(b, "complete -c %s", root)
+		if condition != "" {
+			fmt.Fprintf(b, " -n %s", fishQuote(condition))
+		}
+		if f.Shorthand != "" {
+			fmt.Fprintf(b, " -s %s", f.Shorthand)
+		}
+		fmt.Fprintf(b, " -l %s -d %s", f.Name, fishQuote(f.Usage))
+		if f.Value.Type() != "bool" {
+			switch {
+			case nameFlags[f.Name] != "":
+				fmt.Fprintf(b, " -r -a %s", fishQuote(fmt.Sprintf("(%s __complete %s 2>/dev/null)", root, nameFlags[f.Name])))
+			case fileFlags[f.Name]:
+				b.WriteString(" -r -F")
+			default:
+				b.WriteString(" -r")
+			}
+		}
+		b.WriteString("\n")
+	})
+
+	for _, sub := range subcommands(c) {
+		writeFishCommand(b, sub)
+	}
+}
+
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+func writePowerShellCompletion(w io.Writer, root *cobra.Command) error {
+	var b strings.Builder
+	name := root.Name()
+	fmt.Fprintf(&b, "# powershell completion for %s\n\n", name)
+	fmt.Fprintf(&b, "Register-ArgumentCompleter -Native -CommandName %s -ScriptBlock {\n", powerShellQuote(name))
+	b.WriteString("    param($wordToComplete, $commandAst, $cursorPosition)\n\n")
+
+	b.WriteString("    $commands = @(")
+	var commands []string
+	for _, sub := range subcommands(root) {
+		commands = append(commands, powerShellQuote(sub.Name()))
+	}
+	b.WriteString(strings.Join(commands, ", ") + ")\n")
+
+	b.WriteString("    $flags = @{\n")
+	writePowerShellFlags(&b, "", root)
+	for _, sub := range subcommands(root) {
+		writePowerShellFlags(&b, sub.Name(), sub)
+	}
+	b.WriteString("    }\n")
+
+	b.WriteString("    $names = @{\n")
+	flagNames := make([]string, 0, len(nameFlags))
+	for flag := range nameFlags {
+		flagNames = append(flagNames, flag)
+	}
+	sort.Strings(flagNames)
+	for _, flag := range flagNames {
+		fmt.Fprintf(&b, "        %s = %s\n", powerShellQuote("--"+flag), powerShellQuote(nameFlags[flag]))
+	}
+	b.WriteString("    }\n\n")
+
+	fmt.Fprintf(&b, `    $elements = @($commandAst.CommandElements | Select-Object -Skip 1 | ForEach-Object { $_.ToString() })
+    if ($wordToComplete -ne '' -and $elements.Count -gt 0) {
+        $elements = @($elements | Select-Object -First ($elements.Count - 1))
+    }
+    $command = ''
+    foreach ($element in $elements) {
+        if (-not $element.StartsWith('-') -and $commands -contains $element) {
+            $command = $element
+            break
+        }
+    }
+    $previous = ''
+    if ($elements.Count -gt 0) {
+        $previous = $elements[-1]
+    }
+
+    if ($names.ContainsKey($previous)) {
+        $candidates = @(& %s __complete $names[$previous] 2>$null)
+    } elseif ($wordToComplete.StartsWith('-')) {
+        $candidates = @($flags[$command])
+    } elseif ($command -eq '') {
+        $candidates = $commands
+    } else {
+        return
+    }
+
+    $candidates | Where-Object { $_ -like "$wordToComplete*" } | ForEach-Object {
+        $text = $_
+        if ($text -match '\s') {
+            $text = "'$text'"
+        }
+        [System.Management.Automation.CompletionResult]::new($text, $_, 'ParameterValue', $_)
+    }
+}
+`, powerShellQuote(name))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writePowerShellFlags(b *strings.Builder, name string, c *cobra.Command) {
+	var flags []string
+	visitFlags(c, func(f *pflag.Flag) {
+		flags = append(flags, powerShellQuote("--"+f.Name))
+		if f.Shorthand != "" {
+			flags = append(flags, powerShellQuote("-"+f.Shorthand))
+		}
+	})
+	fmt.Fprintf(b, "        %s = @(%s)\n", powerShellQuote(name), strings.Join(flags, ", "))
+}
+
+func powerShellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
@@ -0,0 +1,67 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/autonomy/conform/internal/doctor"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the environment conform runs in",
+	Long: `Checks the git installation, the state of the repository (shallow clones,
+detached HEADs, and sparse checkouts), the installation of the hooks, the
+configuration files found and whether they load, and the credentials of the CI
+provider, and prints how to fix the problems found. Exits with a non-zero code
+if conform cannot run.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			err := errors.Errorf("The doctor command does not take arguments")
+
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		diagnoses := doctor.Diagnose(os.LookupEnv)
+
+		const padding = 8
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, padding, ' ', 0)
+		fmt.Fprintln(w, "CHECK\tSTATUS\tMESSAGE\t")
+		failed := false
+		for _, d := range diagnoses {
+			fmt.Fprintf(w, "%s\t%s\t%s\t\n", d.Name, d.Status, d.Message)
+			failed = failed || d.Status == doctor.StatusFail
+		}
+		// nolint: errcheck
+		w.Flush()
+
+		first := true
+		for _, d := range diagnoses {
+			if d.Remedy == "" {
+				continue
+			}
+			if first {
+				fmt.Println("\nTo fix the problems found:")
+				first = false
+			}
+			fmt.Printf("  %s: %s\n", d.Name, d.Remedy)
+		}
+
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(doctorCmd)
+}
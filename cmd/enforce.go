@@ -6,43 +6,316 @@ package cmd
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/autonomy/conform/internal/enforcer"
+	"github.com/autonomy/conform/internal/git"
 	"github.com/autonomy/conform/internal/policy"
+	"github.com/autonomy/conform/internal/reporter"
+	"github.com/autonomy/conform/internal/watch"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
 // enforceCmd represents the enforce command
 var enforceCmd = &cobra.Command{
-	Use:   "enforce",
+	Use:   "enforce [repository...]",
 	Short: "",
 	Long:  ``,
 	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) != 0 {
-			err := errors.Errorf("The enforce command does not take arguments")
-
-			fmt.Println(err)
-			os.Exit(1)
+		repos := args
+		if reposFile := cmd.Flags().Lookup("repos-file").Value.String(); reposFile != "" {
+			listed, err := enforcer.ReadRepos(reposFile)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, errors.Errorf("failed to read %s: %v", reposFile, err))
+				os.Exit(1)
+			}
+			if len(listed) == 0 {
+				fmt.Fprintln(os.Stderr, errors.Errorf("%s lists no repositories", reposFile))
+				os.Exit(1)
+			}
+			repos = append(repos, listed...)
 		}
-		e, err := enforcer.New()
+		watching, err := cmd.Flags().GetBool("watch")
 		if err != nil {
-			fmt.Println(err)
+			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
+		if remote := cmd.Flags().Lookup("repo").Value.String(); remote != "" {
+			if watching || len(repos) != 0 {
+				fmt.Fprintln(os.Stderr, errors.Errorf("The --repo flag cannot be used with --watch, --repos-file, or repository arguments"))
+				os.Exit(1)
+			}
+			os.Exit(remoteEnforce(cmd, remote))
+		}
+		if len(repos) != 0 {
+			if watching {
+				fmt.Fprintln(os.Stderr, errors.Errorf("The --watch flag cannot be used to enforce several repositories"))
+				os.Exit(1)
+			}
+			batchEnforce(cmd, repos)
+		}
+		if watching {
+			if cmd.Flags().Lookup("tree-ref").Value.String() != "" {
+				fmt.Fprintln(os.Stderr, errors.Errorf("The --watch flag cannot be used with --tree-ref"))
+				os.Exit(1)
+			}
+			watchEnforce(cmd)
+		}
 
-		opts := []policy.Option{}
+		opts, err := policyOptions(cmd)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
 
-		if commitMsgFile := cmd.Flags().Lookup("commit-msg-file").Value.String(); commitMsgFile != "" {
-			opts = append(opts, policy.WithCommitMsgFile(&commitMsgFile))
+		e, err := enforcer.New(enforcerOptions(cmd)...)
+		if err != nil {
+			exitConfigError(cmd, err)
 		}
 
-		e.Enforce(opts...)
+		os.Exit(e.Enforce(opts...))
 	},
 }
 
 func init() {
-	enforceCmd.Flags().String("commit-msg-file", "", "the path to the temporary commit message file")
+	addEnforcerFlags(enforceCmd)
+	enforceCmd.Flags().Int("commit-count", 0, "enforce the most recent commits, rather than only HEAD, and compare HEAD against the parent of the oldest")
+	enforceCmd.Flags().Bool("strict", false, "promote warnings to errors")
+	enforceCmd.Flags().Bool("strict-warnings", false, "exit with the warnings exit code when warnings are reported but nothing fails")
+	enforceCmd.Flags().StringToInt("exit-code", nil, "override the exit codes of outcomes (pass, failure, config, warnings, error), e.g. warnings=0")
+	enforceCmd.Flags().Bool("fail-fast", false, "stop after the policy of the first failing check, reporting the checks not run as skipped")
+	enforceCmd.Flags().Bool("dry-run", false, "report the results without failing or posting statuses")
+	enforceCmd.Flags().BoolP("quiet", "q", false, "only report violations")
+	enforceCmd.Flags().String("output", reporter.Table, "the format of the results ("+strings.Join(reporter.Formats(), ", ")+")")
+	enforceCmd.Flags().String("output-file", "", "write the results to the file, rather than to stdout, in the format of --output, creating its directory if needed")
+	enforceCmd.Flags().String("metrics-push-url", "", "push the metrics of the enforcement to the Prometheus Pushgateway at the URL")
+	enforceCmd.Flags().StringToString("metrics-label", nil, "the labels of the group the metrics are pushed to, in addition to job=conform, e.g. repository=conform")
+	enforceCmd.Flags().String("webhook-url", "", "publish the JSON report to the webhook at the https URL, signed with the secret of "+WebhookSecretEnv+" if set")
+	enforceCmd.Flags().String("notify-url", "", "notify the Slack or Microsoft Teams incoming webhook at the URL when enforcement of a protected branch fails")
+	enforceCmd.Flags().String("notify-format", "", "the format of the notifications (slack or teams), detected from --notify-url by default")
+	enforceCmd.Flags().StringSlice("notify-branch", enforcer.DefaultNotifyBranches, "the patterns of the protected branches whose failures are notified")
+	enforceCmd.Flags().Int("max-issues-per-check", 0, "write at most this many entries, each grouping identical violations, for each check in the table, Markdown, and annotations (0 is unlimited)")
+	enforceCmd.Flags().String("min-severity", "", "omit the results of the checks less severe than the severity (error, warn, or info) from the output")
+	enforceCmd.Flags().Duration("slow-threshold", 0, "highlight the checks that take longer than the duration in the results (e.g. 500ms)")
+	enforceCmd.Flags().Bool("no-annotations", false, "do not annotate the violations with workflow commands when running in GitHub Actions")
+	enforceCmd.Flags().StringSlice("policy", nil, "only enforce the policies of the specified types")
+	enforceCmd.Flags().StringSlice("check", nil, "only enforce the checks with the specified names")
+	enforceCmd.Flags().String("repo", "", "enforce the policies of a shallow clone of the remote repository at the URL, of the branch or tag of --ref")
+	enforceCmd.Flags().Bool("trust-repo", false, "enforce the policies of the configuration of --repo that run commands, and its plugins")
+	enforceCmd.Flags().String("repos-file", "", "enforce the policies of the repositories listed in the file, one path per line, in addition to those of the arguments")
+	enforceCmd.Flags().String("tree-ref", "", "read the files of the file based policies from the tree of the commit, rather than from the working tree")
+	enforceCmd.Flags().Bool("watch", false, "enforce the policies again whenever files or the commit message being authored change")
+	enforceCmd.Flags().StringSlice("skip", nil, "skip the checks with the specified names, or the policies of the specified types (also read from "+SkipEnv+")")
 	RootCmd.AddCommand(enforceCmd)
 }
+
+// remoteEnforce enforces the policies of a shallow clone of the remote
+// repository, and returns the exit code of the outcome. The clone is removed
+// once enforced. The configuration of the clone may not run commands, unless
+// the repository is trusted: that of --config-file is enforced instead.
+func remoteEnforce(cmd *cobra.Command, url string) int {
+	configFiles, err := cmd.Flags().GetStringSlice("config-file")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	// The configuration files are read once in the clone.
+	for i, name := range configFiles {
+		if configFiles[i], err = filepath.Abs(name); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	trusted, err := cmd.Flags().GetBool("trust-repo")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	enforcerOpts := append(enforcerOptions(cmd), enforcer.WithUntrusted(len(configFiles) == 0 && !trusted))
+	if len(configFiles) != 0 {
+		enforcerOpts = append(enforcerOpts, enforcer.WithConfigFiles(configFiles))
+	}
+
+	dir, err := enforcer.CloneRepo(url, cmd.Flags().Lookup("ref").Value.String())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, errors.Errorf("failed to clone %s: %v", url, err))
+		return 1
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+	if err = os.Chdir(dir); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	opts, err := policyOptions(cmd)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	e, err := enforcer.New(enforcerOpts...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return enforcer.NewDefaultOptions(enforcerOpts...).ExitCode(enforcer.OutcomeConfigError)
+	}
+
+	return e.Enforce(opts...)
+}
+
+// batchEnforce enforces the policies of each of the repositories, and exits
+// with the outcome of the combined report. Each repository is enforced from
+// its own directory, with its own configuration.
+func batchEnforce(cmd *cobra.Command, repos []string) {
+	wd, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	b := enforcer.NewBatch(enforcerOptions(cmd)...)
+	for _, repo := range repos {
+		name := filepath.Clean(repo)
+		if !filepath.IsAbs(repo) {
+			repo = filepath.Join(wd, repo)
+		}
+		if err = os.Chdir(repo); err != nil {
+			b.Fail(name, err)
+			continue
+		}
+		opts, err := policyOptions(cmd)
+		if err != nil {
+			b.Fail(name, err)
+			continue
+		}
+		e, err := enforcer.New(enforcerOptions(cmd)...)
+		if err != nil {
+			b.Fail(name, err)
+			continue
+		}
+		b.Enforce(name, e, opts...)
+	}
+	// nolint: errcheck
+	os.Chdir(wd)
+
+	os.Exit(b.Finish())
+}
+
+// watchEnforce enforces the policies, and then enforces them again whenever
+// the working tree or the message of the commit being authored changes,
+// until interrupted. Changes of the commit message only enforce the commit
+// policy against the message, and changes of files enforce the other
+// policies.
+func watchEnforce(cmd *cobra.Command) {
+	g, err := git.NewGit()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open git repo: %v\n", err)
+		os.Exit(1)
+	}
+	root, err := g.Root()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	w, err := watch.New(root, watchDebounce)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to watch %s: %v\n", root, err)
+		os.Exit(1)
+	}
+	// nolint: errcheck
+	defer w.Close()
+
+	enforceChange(cmd, w.CommitMsgFile(), watch.Change{})
+	for {
+		fmt.Println("Watching for changes, press Ctrl+C to stop")
+		select {
+		case change := <-w.Changes:
+			enforceChange(cmd, w.CommitMsgFile(), change)
+		case err := <-w.Errors:
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+}
+
+// watchDebounce is how long changes are waited for to settle before the
+// policies are enforced.
+const watchDebounce = 200 * time.Millisecond
+
+// enforceChange enforces the policies relevant to the change. The zero
+// change enforces all policies.
+func enforceChange(cmd *cobra.Command, commitMsgFile string, change watch.Change) {
+	opts, err := policyOptions(cmd)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	enforcerOpts := enforcerOptions(cmd)
+
+	switch {
+	case change.CommitMsg && len(change.Files) == 0:
+		if !selectsCommit(cmd) {
+			return
+		}
+		fmt.Println("\nThe commit message changed")
+		contents, err := ioutil.ReadFile(commitMsgFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		msg := watch.CleanCommitMsg(string(contents))
+		if msg == "" {
+			return
+		}
+		f, err := ioutil.TempFile("", "conform-commit-msg")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		// nolint: errcheck
+		defer os.Remove(f.Name())
+		_, err = f.WriteString(msg)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		name := f.Name()
+		opts = append(opts, policy.WithCommitMsgFile(&name))
+		enforcerOpts = append(enforcerOpts, enforcer.WithPolicies([]string{"commit"}))
+	case len(change.Files) != 0:
+		fmt.Printf("\nChanged: %s\n", strings.Join(change.Files, ", "))
+		enforcerOpts = append(enforcerOpts, func(o *enforcer.Options) {
+			o.Skip = append(o.Skip, "commit")
+		})
+	}
+
+	e, err := enforcer.New(enforcerOpts...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	e.Enforce(opts...)
+}
+
+// selectsCommit reports whether the commit policy is selected by the
+// --policy flag.
+func selectsCommit(cmd *cobra.Command) bool {
+	policies, err := cmd.Flags().GetStringSlice("policy")
+	if err != nil || len(policies) == 0 {
+		return true
+	}
+	for _, p := range policies {
+		if strings.EqualFold(p, "commit") {
+			return true
+		}
+	}
+
+	return false
+}
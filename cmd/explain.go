@@ -0,0 +1,109 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/autonomy/conform/internal/enforcer"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// explainCmd represents the explain command
+var explainCmd = &cobra.Command{
+	Use:   "explain <check>",
+	Short: "Explain a check in detail",
+	Long: `Explains what a check enforces, the configuration keys of its policy, examples
+of passing and failing input, and how to fix its violations. The check is
+named as in the reports of the enforce command, ignoring case, e.g.
+conform explain "Header Length". The type of a policy explains all of its
+checks, and <type>/<check> selects a check whose name several policies use.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			err := errors.Errorf("The explain command takes the name of a check")
+
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		explanations, err := enforcer.Explain(strings.Join(args, " "))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		for i, e := range explanations {
+			if i != 0 {
+				fmt.Println()
+			}
+			printExplanation(e)
+		}
+	},
+}
+
+// explainWidth is the width that the paragraphs of explanations are wrapped
+// at.
+const explainWidth = 80
+
+func printExplanation(e enforcer.Explanation) {
+	fmt.Printf("%s (%s)\n\n", e.Name, e.Policy)
+	fmt.Println(wrapText(e.Description+".", "", explainWidth))
+	fmt.Println()
+	fmt.Println(wrapText(e.Details, "", explainWidth))
+
+	if len(e.Keys) != 0 {
+		fmt.Println("\nConfiguration:")
+		const padding = 4
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, padding, ' ', 0)
+		for _, key := range e.Keys {
+			description := key.Type
+			if key.Enables {
+				description += ", enables the check"
+			}
+			fmt.Fprintf(w, "  %s\t%s\n", key.Name, description)
+		}
+		// nolint: errcheck
+		w.Flush()
+	}
+
+	fmt.Println("\nPasses:")
+	fmt.Println(indentText(e.Pass, "    "))
+	fmt.Println("\nFails:")
+	fmt.Println(indentText(e.Fail, "    "))
+	fmt.Println("\nHow to fix:")
+	fmt.Println(wrapText(e.Remedy, "  ", explainWidth))
+}
+
+// wrapText wraps the words of the text into lines of at most width
+// characters, each prefixed by indent.
+func wrapText(text, indent string, width int) string {
+	var lines []string
+	line := indent
+	for _, word := range strings.Fields(text) {
+		if line != indent && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = indent
+		}
+		if line != indent {
+			line += " "
+		}
+		line += word
+	}
+
+	return strings.Join(append(lines, line), "\n")
+}
+
+// indentText prefixes each line of the text with indent.
+func indentText(text, indent string) string {
+	return indent + strings.Replace(text, "\n", "\n"+indent, -1)
+}
+
+func init() {
+	RootCmd.AddCommand(explainCmd)
+}
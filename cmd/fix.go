@@ -0,0 +1,56 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/autonomy/conform/internal/enforcer"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// fixCmd represents the fix command
+var fixCmd = &cobra.Command{
+	Use:   "fix",
+	Short: "Repair the violations that policies can fix",
+	Long: `Asks every policy that can repair its violations to do so: license headers
+are added, files are ended with exactly one newline, and the trailing
+whitespace of added lines is removed. Commit messages are not rewritten, but
+suggestions on how to reword them are printed. A summary of the fixes is
+printed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			err := errors.Errorf("The fix command does not take arguments")
+
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		opts, err := policyOptions(cmd)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		e, err := enforcer.New(enforcerOptions(cmd)...)
+		if err != nil {
+			exitConfigError(cmd, err)
+		}
+
+		if err = e.Fix(opts...); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	addEnforcerFlags(fixCmd)
+	fixCmd.Flags().Bool("dry-run", false, "print the fixes without applying them")
+	fixCmd.Flags().StringSlice("policy", nil, "only fix the policies of the specified types")
+	fixCmd.Flags().StringSlice("skip", nil, "skip the policies of the specified types (also read from "+SkipEnv+")")
+	RootCmd.AddCommand(fixCmd)
+}
@@ -0,0 +1,74 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/autonomy/conform/internal/initializer"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// initCmd represents the init command
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Generate a starter configuration for the repository",
+	Long: `Inspects the languages, license, and commit history of the repository and
+writes a starter .conform.yaml that enforces the conventions already in use.
+Optionally, git hooks that enforce the policies are installed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			err := errors.Errorf("The init command does not take arguments")
+
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		force, _ := cmd.Flags().GetBool("force")
+		hooks, _ := cmd.Flags().GetBool("hooks")
+		output := cmd.Flags().Lookup("output").Value.String()
+
+		if _, err := os.Stat(output); err == nil && !force {
+			fmt.Printf("%s already exists, use --force to replace it\n", output)
+			os.Exit(1)
+		}
+
+		i, err := initializer.Inspect()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		config, err := i.Config()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if err = ioutil.WriteFile(output, config, 0644); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote %s\n", output)
+
+		if hooks {
+			installed, err := initializer.InstallHooks(force)
+			for _, p := range installed {
+				fmt.Printf("Installed %s\n", p)
+			}
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+func init() {
+	initCmd.Flags().String("output", ".conform.yaml", "the path of the generated configuration")
+	initCmd.Flags().Bool("hooks", false, "install git hooks that enforce the policies")
+	initCmd.Flags().Bool("force", false, "replace an existing configuration and hooks")
+	RootCmd.AddCommand(initCmd)
+}
@@ -0,0 +1,77 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/autonomy/conform/internal/enforcer"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the available policies and their checks",
+	Long: `Lists the builtin policies, and the plugins of the configuration, along with
+their checks and whether the configuration enables them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			err := errors.Errorf("The list command does not take arguments")
+
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		e, err := enforcer.New(enforcerOptions(cmd)...)
+		if err != nil {
+			exitConfigError(cmd, err)
+		}
+		descriptions := e.List()
+
+		switch output := userPreference(cmd, "output", "output"); output {
+		case "json":
+			b, err := json.MarshalIndent(descriptions, "", "  ")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			fmt.Println(string(b))
+		case "table":
+			printDescriptions(descriptions)
+		default:
+			fmt.Println(errors.Errorf("Unknown output format %q: must be table or json", output))
+			os.Exit(1)
+		}
+	},
+}
+
+func printDescriptions(descriptions []enforcer.PolicyDescription) {
+	const padding = 8
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, padding, ' ', 0)
+	fmt.Fprintln(w, "POLICY\tCHECK\tENABLED\tDESCRIPTION\t")
+	for _, d := range descriptions {
+		if len(d.Checks) == 0 {
+			fmt.Fprintf(w, "%s\t%s\t%t\t%s\t\n", d.Type, "<plugin>", d.Enabled, d.Description)
+			continue
+		}
+		for _, c := range d.Checks {
+			fmt.Fprintf(w, "%s\t%s\t%t\t%s\t\n", d.Type, c.Name, c.Enabled, c.Description)
+		}
+	}
+	// nolint: errcheck
+	w.Flush()
+}
+
+func init() {
+	listCmd.Flags().StringSlice("config-file", nil, "the configuration files, merged in order with later files taking precedence (default is .conform.yaml and .conform.local.yaml)")
+	listCmd.Flags().String("profile", "", profileUsage)
+	listCmd.Flags().String("output", "table", "the output format (table or json)")
+	RootCmd.AddCommand(listCmd)
+}
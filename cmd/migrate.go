@@ -0,0 +1,100 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/autonomy/conform/internal/enforcer"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// migrateConfigCmd represents the migrate-config command
+var migrateConfigCmd = &cobra.Command{
+	Use:   "migrate-config",
+	Short: "Upgrade the configuration to the current schema version",
+	Long: `Upgrades the configuration files to the apiVersion supported by this version of
+conform, preserving comments and formatting wherever possible. Configurations
+without an apiVersion predate versioning, and are upgraded by adding it. Files
+that are up to date are left unchanged.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			err := errors.Errorf("The migrate-config command does not take arguments")
+
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		files, err := cmd.Flags().GetStringSlice("config-file")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if len(files) == 0 {
+			dir, defaults, err := enforcer.Locate()
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			for _, file := range defaults {
+				files = append(files, filepath.Join(dir, file))
+			}
+		}
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		for _, file := range files {
+			if err = migrateConfig(file, dryRun); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+// migrateConfig migrates a configuration file in place. In dry run mode, the
+// migrated configuration is printed instead.
+func migrateConfig(file string, dryRun bool) error {
+	configBytes, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	migrated, from, err := enforcer.Migrate(file, configBytes)
+	if err != nil {
+		return err
+	}
+	if len(from) == 0 {
+		fmt.Printf("%s is up to date\n", file)
+		return nil
+	}
+
+	if dryRun {
+		fmt.Printf("# %s\n%s", file, migrated)
+		return nil
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(file, migrated, info.Mode()); err != nil {
+		return err
+	}
+	fmt.Printf("Migrated %s to %s\n", file, enforcer.APIVersion)
+
+	return nil
+}
+
+func init() {
+	migrateConfigCmd.Flags().StringSlice("config-file", nil, "the configuration files to migrate (default is .conform.yaml and .conform.local.yaml)")
+	migrateConfigCmd.Flags().Bool("dry-run", false, "print the migrated configuration instead of writing it")
+	RootCmd.AddCommand(migrateConfigCmd)
+}
@@ -0,0 +1,385 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/autonomy/conform/internal/enforcer"
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/logging"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/autonomy/conform/internal/provider"
+	"github.com/autonomy/conform/internal/terminal"
+	"github.com/autonomy/conform/internal/tracing"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// SkipEnv is the environment variable of the comma separated checks, or
+// policy types, to skip in addition to those of the --skip flag.
+const SkipEnv = "CONFORM_SKIP"
+
+// ProfileEnv is the environment variable of the profile applied when the
+// --profile flag is not set.
+const ProfileEnv = "CONFORM_PROFILE"
+
+// GitHubActionsEnv is the environment variable set to true by GitHub Actions,
+// in which the violations are annotated unless --no-annotations is set.
+const GitHubActionsEnv = "GITHUB_ACTIONS"
+
+// StepSummaryEnv is the environment variable of the path of the GitHub
+// Actions step summary file, to which a Markdown summary is appended.
+const StepSummaryEnv = "GITHUB_STEP_SUMMARY"
+
+// WebhookSecretEnv is the environment variable of the secret the requests to
+// the webhook of --webhook-url are signed with. It is not a flag, so that it
+// is not exposed in the arguments of the process.
+const WebhookSecretEnv = "CONFORM_WEBHOOK_SECRET"
+
+// profileUsage is the usage of the --profile flag.
+const profileUsage = "the profile of the configuration to apply (also read from " + ProfileEnv + ")"
+
+// addEnforcerFlags adds the flags shared by the commands that enforce
+// policies.
+func addEnforcerFlags(cmd *cobra.Command) {
+	cmd.Flags().String("commit-msg-file", "", "the path to the temporary commit message file")
+	cmd.Flags().String("base-branch", "", "the base branch to compare HEAD against")
+	cmd.Flags().Bool("fetch", false, "fetch the base branch and history missing from shallow clones from origin, rather than failing")
+	cmd.Flags().String("author-name", "", "the name of the author of the commit, overriding the author of HEAD (e.g. with --commit-msg-file)")
+	cmd.Flags().String("author-email", "", "the email of the author of the commit, overriding the author of HEAD")
+	cmd.Flags().String("ref", "", "the branch the commit is made on, overriding the branch HEAD points to (with --repo, the branch or tag cloned)")
+	cmd.Flags().StringSlice("config-file", nil, "the configuration files, merged in order with later files taking precedence (default is .conform.yaml and .conform.local.yaml)")
+	cmd.Flags().String("baseline-file", enforcer.DefaultBaselineFile, "the baseline file of existing violations")
+	cmd.Flags().String("profile", "", profileUsage)
+	cmd.Flags().StringArray("set", nil, "override a key of the policies of a type for this run, as type.key=value (e.g. commit.headerLength=100)")
+	cmd.Flags().Duration("timeout", 0, "the timeout of the policies that do not declare their own, overriding the timeout of the configuration (e.g. 5m)")
+}
+
+// policyOptions returns the policy options set by the flags of the command.
+func policyOptions(cmd *cobra.Command) ([]policy.Option, error) {
+	opts := []policy.Option{}
+
+	if commitMsgFile := cmd.Flags().Lookup("commit-msg-file").Value.String(); commitMsgFile != "" {
+		// The enforcer may change the working directory to the one
+		// containing the configuration.
+		commitMsgFile, err := filepath.Abs(commitMsgFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, policy.WithCommitMsgFile(&commitMsgFile))
+	}
+
+	baseBranch := cmd.Flags().Lookup("base-branch").Value.String()
+	fetch, err := cmd.Flags().GetBool("fetch")
+	if err != nil {
+		return nil, err
+	}
+	if commitCount, err := cmd.Flags().GetInt("commit-count"); err == nil && commitCount != 0 {
+		if commitCount < 0 {
+			return nil, errors.Errorf("The commit count must be positive")
+		}
+		if baseBranch != "" {
+			return nil, errors.Errorf("The --commit-count and --base-branch flags are mutually exclusive")
+		}
+		g, err := git.NewGit()
+		if err != nil {
+			return nil, errors.Errorf("failed to open git repo: %v", err)
+		}
+		// The changes of the most recent commits are those made since the
+		// parent of the oldest of them.
+		err = withHistory(g, fetch, func() (err error) {
+			baseBranch, err = g.Ancestor(commitCount)
+			return err
+		})
+		if err != nil {
+			return nil, errors.Errorf("failed to find the parent of the commits: %v", err)
+		}
+		opts = append(opts, policy.WithCommitCount(commitCount))
+	} else if baseBranch == "" {
+		if branch, env := provider.BaseBranch(os.LookupEnv); branch != "" {
+			g, err := git.NewGit()
+			if err != nil {
+				return nil, errors.Errorf("failed to open git repo: %v", err)
+			}
+			err = withHistory(g, fetch, func() (err error) {
+				baseBranch, err = g.MergeBase(branch)
+				return err
+			})
+			switch {
+			case err == nil:
+				logging.Info("detected base branch", "branch", branch, "env", env, "mergeBase", baseBranch)
+			case fetch:
+				return nil, errors.Errorf("failed to find the merge base of %s, read from %s: %v", branch, env, err)
+			default:
+				// The base branch of CI is only a default: without its
+				// history, e.g. when it was not fetched, the policies read
+				// the parent of HEAD.
+				logging.Warn("failed to find the merge base of the detected base branch, pass --fetch to fetch it", "branch", branch, "env", env, "error", err)
+				baseBranch = ""
+			}
+		}
+	}
+	if fetch {
+		// The policies read the history since the base branch, or the
+		// parent of HEAD.
+		g, err := git.NewGit()
+		if err != nil {
+			return nil, errors.Errorf("failed to open git repo: %v", err)
+		}
+		if err = withHistory(g, fetch, func() error { return g.History(baseBranch) }); err != nil {
+			return nil, errors.Errorf("failed to fetch the history of HEAD: %v", err)
+		}
+	}
+
+	if baseBranch != "" {
+		opts = append(opts, policy.WithBaseBranch(&baseBranch))
+	}
+
+	if authorName := cmd.Flags().Lookup("author-name").Value.String(); authorName != "" {
+		opts = append(opts, policy.WithAuthorName(&authorName))
+	}
+
+	if authorEmail := cmd.Flags().Lookup("author-email").Value.String(); authorEmail != "" {
+		opts = append(opts, policy.WithAuthorEmail(&authorEmail))
+	}
+
+	if ref := cmd.Flags().Lookup("ref").Value.String(); ref != "" {
+		ref = strings.TrimPrefix(ref, "refs/heads/")
+		opts = append(opts, policy.WithRef(&ref))
+	}
+
+	if treeRef, err := cmd.Flags().GetString("tree-ref"); err == nil && treeRef != "" {
+		g, err := git.NewGit()
+		if err != nil {
+			return nil, errors.Errorf("failed to open git repo: %v", err)
+		}
+		tree, err := g.Tree(treeRef)
+		if err != nil {
+			return nil, errors.Errorf("failed to read the tree of %s: %v", treeRef, err)
+		}
+		opts = append(opts, policy.WithTree(tree))
+	}
+
+	return opts, nil
+}
+
+// enforcerOptions returns the enforcer options set by the flags of the
+// command. Flags the command does not define are ignored.
+func enforcerOptions(cmd *cobra.Command) []enforcer.Option {
+	opts := []enforcer.Option{}
+
+	if configFiles, err := cmd.Flags().GetStringSlice("config-file"); err == nil && len(configFiles) != 0 {
+		opts = append(opts, enforcer.WithConfigFiles(configFiles))
+	}
+
+	if baselineFile, err := cmd.Flags().GetString("baseline-file"); err == nil && baselineFile != "" {
+		opts = append(opts, enforcer.WithBaselineFile(baselineFile))
+	}
+
+	if profile, err := cmd.Flags().GetString("profile"); err == nil {
+		if profile == "" {
+			profile = os.Getenv(ProfileEnv)
+		}
+		if profile != "" {
+			opts = append(opts, enforcer.WithProfile(profile))
+		} else if preferred := viper.GetString("profile"); preferred != "" {
+			// The default profile of the user does not apply to the
+			// repositories that do not define it.
+			opts = append(opts, enforcer.WithPreferredProfile(preferred))
+		}
+	}
+
+	if set, err := cmd.Flags().GetStringArray("set"); err == nil && len(set) != 0 {
+		opts = append(opts, enforcer.WithSet(set))
+	}
+
+	if timeout, err := cmd.Flags().GetDuration("timeout"); err == nil && timeout != 0 {
+		opts = append(opts, enforcer.WithTimeout(timeout))
+	}
+
+	if policies, err := cmd.Flags().GetStringSlice("policy"); err == nil && len(policies) != 0 {
+		opts = append(opts, enforcer.WithPolicies(policies))
+	}
+
+	if checks, err := cmd.Flags().GetStringSlice("check"); err == nil && len(checks) != 0 {
+		opts = append(opts, enforcer.WithChecks(checks))
+	}
+
+	if skip, err := cmd.Flags().GetStringSlice("skip"); err == nil {
+		if env, ok := os.LookupEnv(SkipEnv); ok {
+			for _, s := range strings.Split(env, ",") {
+				if s = strings.TrimSpace(s); s != "" {
+					skip = append(skip, s)
+				}
+			}
+		}
+		if len(skip) != 0 {
+			opts = append(opts, enforcer.WithSkip(skip))
+		}
+	}
+
+	if dryRun, err := cmd.Flags().GetBool("dry-run"); err == nil && dryRun {
+		opts = append(opts, enforcer.WithDryRun(dryRun))
+	}
+
+	if failFast, err := cmd.Flags().GetBool("fail-fast"); err == nil && failFast {
+		opts = append(opts, enforcer.WithFailFast(failFast))
+	}
+
+	if quiet, err := cmd.Flags().GetBool("quiet"); err == nil && quiet {
+		opts = append(opts, enforcer.WithQuiet(quiet))
+	}
+
+	if output, err := cmd.Flags().GetString("output"); err == nil && output != "" {
+		opts = append(opts, enforcer.WithOutput(output))
+	}
+
+	if outputFile, err := cmd.Flags().GetString("output-file"); err == nil && outputFile != "" {
+		// The enforcer may change the working directory to the one
+		// containing the configuration.
+		if abs, err := filepath.Abs(outputFile); err == nil {
+			outputFile = abs
+		}
+		opts = append(opts, enforcer.WithOutputFile(outputFile))
+	}
+
+	if pushURL, err := cmd.Flags().GetString("metrics-push-url"); err == nil && pushURL != "" {
+		opts = append(opts, enforcer.WithMetricsPushURL(pushURL))
+	}
+
+	if labels, err := cmd.Flags().GetStringToString("metrics-label"); err == nil && len(labels) != 0 {
+		opts = append(opts, enforcer.WithMetricsLabels(labels))
+	}
+
+	if webhook, err := cmd.Flags().GetString("webhook-url"); err == nil && webhook != "" {
+		opts = append(opts, enforcer.WithWebhookURL(webhook), enforcer.WithWebhookSecret(os.Getenv(WebhookSecretEnv)))
+	}
+
+	if notifyURL, err := cmd.Flags().GetString("notify-url"); err == nil && notifyURL != "" {
+		opts = append(opts, enforcer.WithNotifyURL(notifyURL), enforcer.WithBranch(branch(cmd)))
+		if format, err := cmd.Flags().GetString("notify-format"); err == nil && format != "" {
+			opts = append(opts, enforcer.WithNotifyFormat(format))
+		}
+		if branches, err := cmd.Flags().GetStringSlice("notify-branch"); err == nil {
+			opts = append(opts, enforcer.WithNotifyBranches(branches))
+		}
+	}
+
+	if max, err := cmd.Flags().GetInt("max-issues-per-check"); err == nil && max != 0 {
+		opts = append(opts, enforcer.WithMaxIssues(max))
+	}
+
+	if min, err := cmd.Flags().GetString("min-severity"); err == nil && min != "" {
+		opts = append(opts, enforcer.WithMinSeverity(policy.Severity(strings.ToLower(min))))
+	}
+
+	if threshold, err := cmd.Flags().GetDuration("slow-threshold"); err == nil && threshold != 0 {
+		opts = append(opts, enforcer.WithSlowThreshold(threshold))
+	}
+
+	if tracer, err := tracing.FromEnv(os.LookupEnv); err != nil {
+		// The exporter is not the OpenTelemetry SDK, and only supports a
+		// subset of its configuration.
+		logging.Warn("tracing disabled, the OTLP exporter is misconfigured", "error", err)
+	} else if tracer != nil {
+		opts = append(opts, enforcer.WithTracer(tracer))
+	}
+
+	if url := provider.CommitURL(os.LookupEnv); url != "" {
+		opts = append(opts, enforcer.WithCommitURL(url))
+	}
+
+	if noAnnotations, err := cmd.Flags().GetBool("no-annotations"); err == nil && !noAnnotations && os.Getenv(GitHubActionsEnv) == "true" {
+		opts = append(opts, enforcer.WithAnnotations(true))
+	}
+
+	if summary := os.Getenv(StepSummaryEnv); summary != "" {
+		opts = append(opts, enforcer.WithStepSummary(summary))
+	}
+
+	if strictWarnings, err := cmd.Flags().GetBool("strict-warnings"); err == nil && strictWarnings {
+		opts = append(opts, enforcer.WithStrictWarnings(strictWarnings))
+	}
+
+	if exitCodes, err := cmd.Flags().GetStringToInt("exit-code"); err == nil && len(exitCodes) != 0 {
+		codes := make(map[enforcer.Outcome]int, len(exitCodes))
+		for outcome, code := range exitCodes {
+			codes[enforcer.Outcome(outcome)] = code
+		}
+		opts = append(opts, enforcer.WithExitCodes(codes))
+	}
+
+	if strict, err := cmd.Flags().GetBool("strict"); err == nil && strict {
+		opts = append(opts, enforcer.WithStrict(strict))
+	}
+
+	if !noColor && terminal.ColorEnabled(os.Stdout) {
+		opts = append(opts, enforcer.WithTheme(theme))
+	}
+
+	return opts
+}
+
+// branch returns the branch enforced: that of the --ref flag, or that built by
+// the CI service, or that HEAD points to, or empty if it is not known.
+func branch(cmd *cobra.Command) string {
+	if ref := cmd.Flags().Lookup("ref").Value.String(); ref != "" {
+		return ref
+	}
+	if b := provider.Branch(os.LookupEnv); b != "" {
+		return b
+	}
+	g, err := git.NewGit()
+	if err != nil {
+		return ""
+	}
+	b, err := g.Branch()
+	if err != nil {
+		logging.Debug("failed to read the branch of HEAD", "error", err)
+	}
+
+	return b
+}
+
+// exitConfigError prints the error loading the configuration and exits with
+// the exit code of configuration errors.
+func exitConfigError(cmd *cobra.Command, err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(enforcer.NewDefaultOptions(enforcerOptions(cmd)...).ExitCode(enforcer.OutcomeConfigError))
+}
+
+// maxFetches is the number of times the history missing from a clone is
+// fetched, e.g. the base branch and then the history of its merge base.
+const maxFetches = 3
+
+// withHistory runs fn, which fails if the history it needs is missing from the
+// clone. With --fetch, the missing base branch is fetched or the clone is
+// deepened, and fn is run again.
+func withHistory(g *git.Git, fetch bool, fn func() error) error {
+	for i := 0; ; i++ {
+		err := fn()
+		if !fetch || i == maxFetches {
+			return err
+		}
+		switch e := errors.Cause(err).(type) {
+		case *git.ShallowError:
+			logging.Info("deepening shallow clone", "depth", e.Depth, "need", e.Need)
+			err = g.Fetch(e.Depth)
+		case *git.MissingRevisionError:
+			logging.Info("fetching missing revision", "rev", e.Rev)
+			err = g.FetchRevision(e)
+		default:
+			return err
+		}
+		if err != nil {
+			return errors.Errorf("failed to fetch the missing history: %v", err)
+		}
+	}
+}
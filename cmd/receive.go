@@ -0,0 +1,149 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/autonomy/conform/internal/enforcer"
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/logging"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// preReceiveCmd represents the pre-receive command
+var preReceiveCmd = &cobra.Command{
+	Use:   "pre-receive",
+	Short: "Enforce the policies of the branches pushed to a bare repository",
+	Long: `Enforce the policies of the branches pushed to a bare repository, as the
+pre-receive hook of a git server. The updated refs are read from stdin, and
+the files of each branch are read from the tree of the commit it is updated to,
+in a temporary repository sharing the objects of the bare repository. The
+configuration is that of the server, given by --config-file, so that a push
+cannot change it. The push is rejected if any policy fails.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			err := errors.Errorf("The pre-receive command does not take arguments")
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(preReceive(cmd))
+	},
+}
+
+func init() {
+	addEnforcerFlags(preReceiveCmd)
+	preReceiveCmd.Flags().Bool("strict", false, "promote warnings to errors")
+	preReceiveCmd.Flags().StringToInt("exit-code", nil, "override the exit codes of outcomes (pass, failure, config, warnings, error), e.g. warnings=0")
+	preReceiveCmd.Flags().Bool("dry-run", false, "report the results without rejecting the push")
+	preReceiveCmd.Flags().BoolP("quiet", "q", false, "only report violations")
+	preReceiveCmd.Flags().StringSlice("policy", nil, "only enforce the policies of the specified types")
+	preReceiveCmd.Flags().StringSlice("check", nil, "only enforce the checks with the specified names")
+	preReceiveCmd.Flags().StringSlice("skip", nil, "skip the checks with the specified names, or the policies of the specified types (also read from "+SkipEnv+")")
+	RootCmd.AddCommand(preReceiveCmd)
+}
+
+// preReceive enforces the policies of each branch updated by the push read
+// from stdin, and returns the exit code of the combined report. Deleted refs
+// and refs other than branches, such as tags, are not enforced.
+func preReceive(cmd *cobra.Command) int {
+	r, err := enforcer.OpenReceiveRepo()
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	updates, err := enforcer.ParseRefUpdates(os.Stdin)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	// The configuration files are those of the server: the configuration
+	// of a push could run any command through the policies that run
+	// commands, such as exec and generate, or through plugins.
+	configFiles, err := cmd.Flags().GetStringSlice("config-file")
+	if err != nil || len(configFiles) == 0 {
+		fmt.Println(errors.Errorf("The pre-receive command requires the --config-file of the server"))
+		return 1
+	}
+	for i, name := range configFiles {
+		if configFiles[i], err = filepath.Abs(name); err != nil {
+			fmt.Println(err)
+			return 1
+		}
+	}
+	enforcerOpts := append(enforcerOptions(cmd), enforcer.WithConfigFiles(configFiles))
+
+	wd, err := os.Getwd()
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	b := enforcer.NewBatch(enforcerOpts...)
+	enforced := 0
+	for _, u := range updates {
+		if u.Deletes() || u.Branch() == "" {
+			logging.Info("not enforcing ref update", "ref", u.Ref, "old", u.Old, "new", u.New)
+			continue
+		}
+		enforced++
+		if err = enforceUpdate(cmd, b, r, u, enforcerOpts); err != nil {
+			b.Fail(u.Branch(), err)
+		}
+		// nolint: errcheck
+		os.Chdir(wd)
+	}
+	if enforced == 0 {
+		return 0
+	}
+
+	return b.Finish()
+}
+
+// enforceUpdate enforces the policies of the commit the branch is updated to,
+// reading its files from its tree in a temporary repository that is removed
+// once enforced. The changes of the branch are those made since the commit it
+// is updated from, or, for a created branch, those of the commits that are not
+// in the repository yet.
+func enforceUpdate(cmd *cobra.Command, b *enforcer.Batch, r *enforcer.ReceiveRepo, u enforcer.RefUpdate, enforcerOpts []enforcer.Option) error {
+	dir, base, err := r.Repository(u)
+	if err != nil {
+		return err
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+	if err = os.Chdir(dir); err != nil {
+		return err
+	}
+
+	g, err := git.NewGit()
+	if err != nil {
+		return err
+	}
+	tree, err := g.Tree(u.New)
+	if err != nil {
+		return errors.Errorf("failed to read the tree of %s: %v", u.New, err)
+	}
+	opts, err := policyOptions(cmd)
+	if err != nil {
+		return err
+	}
+	opts = append(opts, policy.WithTree(tree), policy.WithBaseBranch(&base))
+	branch := u.Branch()
+	opts = append(opts, policy.WithRef(&branch))
+
+	e, err := enforcer.New(enforcerOpts...)
+	if err != nil {
+		return err
+	}
+	b.Enforce(branch, e, opts...)
+
+	return nil
+}
@@ -7,8 +7,14 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/autonomy/conform/internal/logging"
+	"github.com/autonomy/conform/internal/progress"
+	"github.com/autonomy/conform/internal/terminal"
 	homedir "github.com/mitchellh/go-homedir"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -16,7 +22,13 @@ import (
 var cfgFile string
 
 var (
-	debug bool
+	debug      bool
+	verbose    bool
+	noColor    bool
+	theme      string
+	noProgress bool
+	logLevel   string
+	logFormat  string
 )
 
 // RootCmd represents the base command when called without any subcommands
@@ -38,32 +50,144 @@ func Execute() {
 func init() {
 	cobra.OnInitialize(initConfig)
 
-	RootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is .conform.yaml)")
-	RootCmd.Flags().BoolVar(&debug, "debug", false, "Debug rendering")
+	RootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "the user configuration file of preferences (default is $XDG_CONFIG_HOME/conform/config.yaml)")
+	RootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "log the configuration loaded and the time taken by each policy")
+	RootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "log the files walked and the patterns matched, in addition to --verbose")
+	RootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "do not color the output (also disabled by "+terminal.NoColorEnv+" and when not writing to a terminal)")
+	RootCmd.PersistentFlags().BoolVar(&noProgress, "no-progress", false, "do not report the progress of enforcement (also disabled when not writing to a terminal)")
+	RootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "the minimum level of the logs written to standard error (debug, info, warn, or error), overriding --verbose and --debug")
+	RootCmd.PersistentFlags().StringVar(&logFormat, "log-format", string(logging.FormatText), "the format of the logs written to standard error (text or json)")
+	RootCmd.PersistentFlags().StringVar(&theme, "theme", terminal.DefaultTheme, "the colors of the output ("+strings.Join(terminal.ThemeNames(), " or ")+")")
 }
 
-// initConfig reads in config file and ENV variables if set.
+// UserConfigEnv is the environment variable set by CI services, in which the
+// user configuration is not read so that it cannot affect enforcement.
+const UserConfigEnv = "CI"
+
+// initConfig reads in the user configuration and ENV variables if set.
 func initConfig() {
+	switch {
+	case debug:
+		logging.SetLevel(logging.LevelDebug)
+	case verbose:
+		logging.SetLevel(logging.LevelInfo)
+	}
+
+	readUserConfig()
+
+	if err := initLogging(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	// The logs are also written to standard error, and would garble the
+	// progress line.
+	if !noProgress && !logging.Enabled(logging.LevelInfo) && terminal.IsTerminal(os.Stderr) {
+		progress.Enable(os.Stderr)
+	}
+}
+
+// initLogging sets the level and format of the logs from the flags, or else
+// from the preferences of the user, e.g. CONFORM_LOG_LEVEL.
+func initLogging() error {
+	if logLevel != "" {
+		l, err := logging.ParseLevel(logLevel)
+		if err != nil {
+			return err
+		}
+		logging.SetLevel(l)
+	}
+	f, err := logging.ParseFormat(logFormat)
+	if err != nil {
+		return err
+	}
+	logging.SetFormat(f)
+
+	return nil
+}
+
+// readUserConfig reads the preferences of the user from the user
+// configuration, which default the flags that are not set, and from the
+// environment variables of the preferences, e.g. CONFORM_THEME.
+func readUserConfig() {
+	viper.SetEnvPrefix("conform")
+	// The preferences of flags with dashes, e.g. log-level, are read from
+	// variables with underscores, e.g. CONFORM_LOG_LEVEL.
+	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	viper.AutomaticEnv() // read in environment variables that match
+
+	if _, ok := os.LookupEnv(UserConfigEnv); ok {
+		logging.Debug("ignored user configuration", "env", UserConfigEnv)
+	} else if err := readUserConfigFile(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	flags := RootCmd.PersistentFlags()
+	if !flags.Changed("no-color") && viper.IsSet("color") {
+		noColor = !viper.GetBool("color")
+	}
+	if !flags.Changed("theme") && viper.IsSet("theme") {
+		theme = viper.GetString("theme")
+	}
+	if !flags.Changed("no-progress") && viper.IsSet("progress") {
+		noProgress = !viper.GetBool("progress")
+	}
+	// --verbose and --debug take precedence over the preferred log level.
+	if !flags.Changed("log-level") && !debug && !verbose && viper.IsSet("log-level") {
+		logLevel = viper.GetString("log-level")
+	}
+	if !flags.Changed("log-format") && viper.IsSet("log-format") {
+		logFormat = viper.GetString("log-format")
+	}
+}
+
+// readUserConfigFile reads the user configuration file, if it exists.
+func readUserConfigFile() error {
 	if cfgFile != "" {
 		// Use config file from the flag.
 		viper.SetConfigFile(cfgFile)
 	} else {
-		// Find home directory.
-		home, err := homedir.Dir()
+		dir, err := userConfigDir()
 		if err != nil {
-			fmt.Println(home)
-			os.Exit(1)
+			return err
 		}
+		viper.SetConfigFile(filepath.Join(dir, "conform", "config.yaml"))
+	}
 
-		// Search config in home directory with name ".cobra" (without extension).
-		viper.AddConfigPath(home)
-		viper.SetConfigName(".cobra")
+	// If a config file is found, read it in.
+	err := viper.ReadInConfig()
+	if err == nil {
+		logging.Info("read user configuration", "file", viper.ConfigFileUsed())
+		return nil
+	}
+	if _, statErr := os.Stat(viper.ConfigFileUsed()); statErr == nil || cfgFile != "" {
+		return errors.Errorf("failed to read %s: %v", viper.ConfigFileUsed(), err)
 	}
 
-	viper.AutomaticEnv() // read in environment variables that match
+	return nil
+}
 
-	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+// userConfigDir returns $XDG_CONFIG_HOME, or ~/.config if it is not set.
+func userConfigDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir, nil
+	}
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
 	}
+
+	return filepath.Join(home, ".config"), nil
+}
+
+// userPreference returns the preference of the user configuration defaulting
+// the flag of the command, or the value of the flag if it is set.
+func userPreference(cmd *cobra.Command, flag, key string) string {
+	value := cmd.Flags().Lookup(flag).Value.String()
+	if !cmd.Flags().Changed(flag) && viper.IsSet(key) {
+		value = viper.GetString(key)
+	}
+
+	return value
 }
@@ -0,0 +1,69 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/autonomy/conform/internal/enforcer"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// validateConfigCmd represents the validate-config command
+var validateConfigCmd = &cobra.Command{
+	Use:   "validate-config",
+	Short: "Validate the configuration without running any checks",
+	Long: `Loads the configuration and validates it against the schema, compiles the
+regular expressions of the policies, and warns about deprecated keys, and path
+patterns and suffixes that match no tracked files. No checks are run.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			err := errors.Errorf("The validate-config command does not take arguments")
+
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		e, err := enforcer.New(enforcerOptions(cmd)...)
+		if err != nil {
+			exitConfigError(cmd, err)
+		}
+		problems, err := e.Validate()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if len(problems) == 0 {
+			fmt.Println("Configuration is valid")
+			return
+		}
+
+		const padding = 8
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, padding, ' ', 0)
+		fmt.Fprintln(w, "POLICY\tSEVERITY\tMESSAGE\t")
+		invalid := false
+		for _, p := range problems {
+			if p.Severity == policy.SeverityError {
+				invalid = true
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t\n", p.Policy, p.Severity, p.Message)
+		}
+		// nolint: errcheck
+		w.Flush()
+		if invalid {
+			os.Exit(enforcer.NewDefaultOptions(enforcerOptions(cmd)...).ExitCode(enforcer.OutcomeConfigError))
+		}
+	},
+}
+
+func init() {
+	validateConfigCmd.Flags().StringSlice("config-file", nil, "the configuration files, merged in order with later files taking precedence (default is .conform.yaml and .conform.local.yaml)")
+	validateConfigCmd.Flags().String("profile", "", profileUsage)
+	RootCmd.AddCommand(validateConfigCmd)
+}
@@ -6,16 +6,26 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"html/template"
+	"net/http"
+	"os"
 	"runtime"
+	"time"
 
 	"github.com/autonomy/conform/internal/constants"
+	"github.com/autonomy/conform/internal/update"
+	"github.com/google/go-github/github"
 	"github.com/spf13/cobra"
 )
 
+// updateTimeout is the time allowed to query the latest release.
+const updateTimeout = 10 * time.Second
+
 var (
 	shortVersion bool
+	checkUpdate  bool
 	// Tag is set at build time.
 	Tag string
 	// SHA is set at build time.
@@ -53,11 +63,18 @@ var versionCmd = &cobra.Command{
 		} else {
 			PrintLongVersion()
 		}
+		if checkUpdate {
+			if err := PrintUpdate(); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
 	},
 }
 
 func init() {
 	versionCmd.Flags().BoolVar(&shortVersion, "short", false, "Print the short version")
+	versionCmd.Flags().BoolVar(&checkUpdate, "check-update", false, "Query the latest release and report whether it is newer")
 	RootCmd.AddCommand(versionCmd)
 }
 
@@ -90,3 +107,26 @@ func PrintLongVersion() {
 func PrintShortVersion() {
 	fmt.Println(fmt.Sprintf("%s %s-%s", constants.AppName, Tag, SHA))
 }
+
+// PrintUpdate queries the latest release, and prints whether it is newer
+// than the running version.
+func PrintUpdate() error {
+	ctx, cancel := context.WithTimeout(context.Background(), updateTimeout)
+	defer cancel()
+
+	release, err := update.Latest(ctx, github.NewClient(http.DefaultClient))
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case update.Newer(Tag, release.Tag):
+		fmt.Printf("A newer version of %s is available: %s (current %s)\n%s\n", constants.AppName, release.Tag, Tag, release.URL)
+	case Tag == release.Tag:
+		fmt.Printf("%s %s is the latest release\n", constants.AppName, Tag)
+	default:
+		fmt.Printf("The latest release of %s is %s\n", constants.AppName, release.Tag)
+	}
+
+	return nil
+}
@@ -1,15 +1,33 @@
 module github.com/autonomy/conform
 
+go 1.21
+
+require (
+	github.com/fsnotify/fsnotify v1.4.7
+	github.com/google/go-github v17.0.0+incompatible
+	github.com/mitchellh/go-homedir v0.0.0-20161203194507-b8bc1bf76747
+	github.com/mitchellh/mapstructure v0.0.0-20170523030023-d0303fe80992
+	github.com/pelletier/go-toml v1.0.0
+	github.com/pkg/errors v0.8.1
+	github.com/spf13/cobra v0.0.3
+	github.com/spf13/pflag v1.0.3
+	github.com/spf13/viper v0.0.0-20170619124313-c1de95864d73
+	github.com/tetratelabs/wazero v1.5.0
+	go.starlark.net v0.0.0-20201006213952-227f4aabceb5
+	gopkg.in/jdkato/prose.v2 v2.0.0-20180825173540-767a23049b9e
+	gopkg.in/src-d/go-billy.v4 v4.0.1
+	gopkg.in/src-d/go-git.v4 v4.0.0
+	gopkg.in/yaml.v2 v2.2.2
+)
+
 require (
 	github.com/alcortesm/tgz v0.0.0-20161220082320-9c5fe88206d7 // indirect
 	github.com/anmitsu/go-shlex v0.0.0-20161002113705-648efa622239 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/deckarep/golang-set v1.7.1 // indirect
 	github.com/flynn/go-shlex v0.0.0-20150515145356-3f9db97f8568 // indirect
-	github.com/fsnotify/fsnotify v1.4.7 // indirect
 	github.com/gliderlabs/ssh v0.1.1 // indirect
 	github.com/google/go-cmp v0.3.0 // indirect
-	github.com/google/go-github v17.0.0+incompatible
 	github.com/google/go-querystring v1.0.0 // indirect
 	github.com/hashicorp/hcl v0.0.0-20170509225359-392dba7d905e // indirect
 	github.com/inconshreveable/mousetrap v1.0.0 // indirect
@@ -18,36 +36,25 @@ require (
 	github.com/kr/pretty v0.1.0 // indirect
 	github.com/magiconair/properties v1.7.2 // indirect
 	github.com/mingrammer/commonregex v1.0.0 // indirect
-	github.com/mitchellh/go-homedir v0.0.0-20161203194507-b8bc1bf76747
-	github.com/mitchellh/mapstructure v0.0.0-20170523030023-d0303fe80992
 	github.com/montanaflynn/stats v0.5.0 // indirect
 	github.com/neurosnap/sentences v1.0.6 // indirect
 	github.com/pelletier/go-buffruneio v0.2.0 // indirect
-	github.com/pelletier/go-toml v1.0.0 // indirect
-	github.com/pkg/errors v0.8.1
 	github.com/sergi/go-diff v0.0.0-20170409071739-feef008d51ad // indirect
 	github.com/spf13/afero v1.2.0 // indirect
 	github.com/spf13/cast v1.1.0 // indirect
-	github.com/spf13/cobra v0.0.3
 	github.com/spf13/jwalterweatherman v0.0.0-20170523133247-0efa5202c046 // indirect
-	github.com/spf13/pflag v1.0.3 // indirect
-	github.com/spf13/viper v0.0.0-20170619124313-c1de95864d73
 	github.com/src-d/gcfg v1.3.0 // indirect
 	github.com/stretchr/testify v1.3.0 // indirect
 	github.com/xanzy/ssh-agent v0.1.0 // indirect
 	golang.org/x/crypto v0.0.0-20190506204251-e1dfcc566284 // indirect
 	golang.org/x/exp v0.0.0-20190121172915-509febef88a4 // indirect
 	golang.org/x/net v0.0.0-20190503192946-f4e77d36d62c // indirect
-	golang.org/x/sys v0.0.0-20190508220229-2d0786266e9c // indirect
+	golang.org/x/sys v0.0.0-20200625212154-ddb9806d33ae // indirect
 	golang.org/x/text v0.3.2 // indirect
 	gonum.org/v1/gonum v0.0.0-20190119014124-d54847ab4dca // indirect
 	gonum.org/v1/netlib v0.0.0-20190119082159-9be13e02fd56 // indirect
 	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
-	gopkg.in/jdkato/prose.v2 v2.0.0-20180825173540-767a23049b9e
 	gopkg.in/neurosnap/sentences.v1 v1.0.6 // indirect
-	gopkg.in/src-d/go-billy.v4 v4.0.1 // indirect
 	gopkg.in/src-d/go-git-fixtures.v3 v3.1.1 // indirect
-	gopkg.in/src-d/go-git.v4 v4.0.0
 	gopkg.in/warnings.v0 v0.1.1 // indirect
-	gopkg.in/yaml.v2 v2.2.2
 )
@@ -0,0 +1,256 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+// Package doctor diagnoses the environment conform runs in.
+package doctor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/autonomy/conform/internal/enforcer"
+	"github.com/autonomy/conform/internal/provider"
+)
+
+// Status is the status of a diagnosis.
+type Status string
+
+const (
+	// StatusOK reports that nothing needs to be done.
+	StatusOK Status = "OK"
+	// StatusWarn reports that some features will not work.
+	StatusWarn Status = "WARN"
+	// StatusFail reports that conform cannot run.
+	StatusFail Status = "FAIL"
+)
+
+// Diagnosis is the result of checking one aspect of the environment.
+type Diagnosis struct {
+	Name    string
+	Status  Status
+	Message string
+	// Remedy describes how to fix a warning or failure.
+	Remedy string
+}
+
+// MinimumGitVersion is the oldest version of git known to support all the
+// commands conform and its hooks run.
+var MinimumGitVersion = [2]int{2, 15}
+
+var gitVersionRegex = regexp.MustCompile(`(\d+)\.(\d+)`)
+
+// Diagnose checks the git installation, the state of the repository in the
+// current directory, the hooks, the configuration, and the credentials of
+// the CI provider, reading the environment with lookupEnv.
+func Diagnose(lookupEnv func(string) (string, bool)) []Diagnosis {
+	diagnoses := []Diagnosis{Git()}
+	if diagnoses[0].Status == StatusFail {
+		return diagnoses
+	}
+	repository := Repository()
+	diagnoses = append(diagnoses, repository...)
+	if repository[0].Status != StatusFail {
+		diagnoses = append(diagnoses, Hooks())
+	}
+	diagnoses = append(diagnoses, Config())
+
+	return append(diagnoses, Credentials(lookupEnv)...)
+}
+
+// Git checks that a recent enough version of git is installed.
+func Git() Diagnosis {
+	d := Diagnosis{Name: "Git"}
+
+	out, err := exec.Command("git", "--version").Output()
+	if err != nil {
+		d.Status, d.Message = StatusFail, fmt.Sprintf("git is not installed: %v", err)
+		d.Remedy = "Install git and add it to the PATH"
+		return d
+	}
+	version := strings.TrimSpace(strings.TrimPrefix(string(out), "git version"))
+	groups := gitVersionRegex.FindStringSubmatch(version)
+	if groups == nil {
+		d.Status, d.Message = StatusWarn, fmt.Sprintf("Unknown git version %q", version)
+		return d
+	}
+	major, _ := strconv.Atoi(groups[1])
+	minor, _ := strconv.Atoi(groups[2])
+	if major < MinimumGitVersion[0] || major == MinimumGitVersion[0] && minor < MinimumGitVersion[1] {
+		d.Status, d.Message = StatusWarn, fmt.Sprintf("git %s is older than %d.%d", version, MinimumGitVersion[0], MinimumGitVersion[1])
+		d.Remedy = "Upgrade git"
+		return d
+	}
+	d.Status, d.Message = StatusOK, "git "+version
+
+	return d
+}
+
+// Repository checks that the current directory is in a git repository, and
+// whether the repository is shallow, HEAD is detached, or the checkout is
+// sparse, any of which may hide the history or files being enforced.
+func Repository() []Diagnosis {
+	root, err := gitOutput("rev-parse", "--show-toplevel")
+	if err != nil {
+		return []Diagnosis{{
+			Name:    "Repository",
+			Status:  StatusFail,
+			Message: "Not in a git repository",
+			Remedy:  "Run conform in a git repository, or initialize one with git init",
+		}}
+	}
+	diagnoses := []Diagnosis{{Name: "Repository", Status: StatusOK, Message: root}}
+
+	shallow := Diagnosis{Name: "Shallow Clone", Status: StatusOK, Message: "The history is complete"}
+	if out, _ := gitOutput("rev-parse", "--is-shallow-repository"); out == "true" {
+		shallow.Status, shallow.Message = StatusWarn, "The history is truncated"
+		shallow.Remedy = "Fetch the full history with git fetch --unshallow, or fetch-depth: 0 in GitHub Actions, so that the merge base of the base branch is found, or enforce with --fetch to fetch the history needed"
+	}
+	diagnoses = append(diagnoses, shallow)
+
+	head := Diagnosis{Name: "HEAD", Status: StatusOK}
+	if branch, err := gitOutput("symbolic-ref", "-q", "--short", "HEAD"); err == nil {
+		head.Message = "On branch " + branch
+	} else {
+		head.Status, head.Message = StatusWarn, "HEAD is detached"
+		head.Remedy = "Check out a branch, or pass it with --ref, for policies that depend on the branch"
+	}
+	diagnoses = append(diagnoses, head)
+
+	sparse := Diagnosis{Name: "Sparse Checkout", Status: StatusOK, Message: "All files are checked out"}
+	if out, _ := gitOutput("config", "--bool", "core.sparseCheckout"); out == "true" {
+		sparse.Status, sparse.Message = StatusWarn, "Some files are not checked out"
+		sparse.Remedy = "Disable the sparse checkout with git sparse-checkout disable, since policies only scan the files checked out"
+	}
+
+	return append(diagnoses, sparse)
+}
+
+// Hooks checks whether the commit-msg hook that enforces the policies is
+// installed.
+func Hooks() Diagnosis {
+	d := Diagnosis{Name: "Hooks"}
+
+	hook, err := gitOutput("rev-parse", "--git-path", "hooks/commit-msg")
+	if err != nil {
+		d.Status, d.Message = StatusWarn, fmt.Sprintf("Failed to find the hooks: %v", err)
+		return d
+	}
+	contents, err := ioutil.ReadFile(hook)
+	switch {
+	case os.IsNotExist(err):
+		d.Status, d.Message = StatusWarn, "The commit-msg hook is not installed"
+		d.Remedy = "Install it with conform init --hooks, to enforce the policies when committing"
+	case err != nil:
+		d.Status, d.Message = StatusWarn, fmt.Sprintf("Failed to read the commit-msg hook: %v", err)
+	case !strings.Contains(string(contents), "conform"):
+		d.Status, d.Message = StatusWarn, "The commit-msg hook does not run conform"
+		d.Remedy = "Add conform enforce --commit-msg-file \"$1\" to " + hook
+	default:
+		d.Status, d.Message = StatusOK, "The commit-msg hook runs conform"
+		if info, err := os.Stat(hook); err == nil && info.Mode()&0111 == 0 {
+			d.Status, d.Message = StatusWarn, "The commit-msg hook is not executable"
+			d.Remedy = "Make it executable with chmod +x " + hook
+		}
+	}
+
+	return d
+}
+
+// Config checks that the configuration is found and loads. The working
+// directory is restored after loading.
+func Config() Diagnosis {
+	d := Diagnosis{Name: "Configuration"}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		d.Status, d.Message = StatusFail, err.Error()
+		return d
+	}
+	dir, files, err := enforcer.Locate()
+	if err != nil {
+		d.Status, d.Message = StatusFail, err.Error()
+		return d
+	}
+	var existing []string
+	for _, file := range files {
+		if _, err = os.Stat(filepath.Join(dir, file)); err == nil {
+			existing = append(existing, filepath.Join(dir, file))
+		}
+	}
+	if len(existing) == 0 {
+		d.Status, d.Message = StatusFail, "No configuration found in "+dir+" or its parents"
+		d.Remedy = "Write a configuration with conform init"
+		return d
+	}
+
+	// Statuses are not posted in dry run mode.
+	c, err := enforcer.New(enforcer.WithDryRun(true))
+	// nolint: errcheck
+	os.Chdir(wd)
+	if err != nil {
+		d.Status, d.Message = StatusFail, strings.Join(existing, " < ")+": "+err.Error()
+		d.Remedy = "Fix the configuration, which conform validate-config checks"
+		return d
+	}
+	d.Status = StatusOK
+	d.Message = fmt.Sprintf("%s: %d policies", strings.Join(existing, " < "), len(c.Policies))
+
+	return d
+}
+
+// Credentials checks that the credentials of the CI provider are set when
+// running in CI, and reports the base branch detected.
+func Credentials(lookupEnv func(string) (string, bool)) []Diagnosis {
+	set := func(name string) bool {
+		value, _ := lookupEnv(name)
+		return value != ""
+	}
+
+	d := Diagnosis{Name: "Credentials", Status: StatusOK}
+	switch {
+	case set("GITHUB_EVENT_PATH"):
+		d.Message = "GitHub Actions: GITHUB_TOKEN is set"
+		if !set("GITHUB_TOKEN") {
+			d.Status, d.Message = StatusWarn, "GitHub Actions: GITHUB_TOKEN is not set"
+			d.Remedy = "Set GITHUB_TOKEN to ${{ secrets.GITHUB_TOKEN }}, to post statuses and enforce pull request policies"
+		}
+	case set("GITLAB_CI"):
+		d.Message = "GitLab CI: the job token is set"
+		if set("GITLAB_TOKEN") {
+			d.Message = "GitLab CI: GITLAB_TOKEN is set"
+		} else if !set("CI_JOB_TOKEN") {
+			d.Status, d.Message = StatusWarn, "GitLab CI: neither GITLAB_TOKEN nor CI_JOB_TOKEN is set"
+			d.Remedy = "Set GITLAB_TOKEN to a token with the read_api scope, to enforce merge request policies"
+		}
+	default:
+		d.Message = "Not running in CI, pull request policies are skipped"
+	}
+	diagnoses := []Diagnosis{d}
+
+	if branch, env := provider.BaseBranch(lookupEnv); branch != "" {
+		diagnoses = append(diagnoses, Diagnosis{
+			Name:    "Base Branch",
+			Status:  StatusOK,
+			Message: fmt.Sprintf("%s, read from %s", branch, env),
+		})
+	}
+
+	return diagnoses
+}
+
+// gitOutput runs git and returns its trimmed output.
+func gitOutput(args ...string) (string, error) {
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,63 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package doctor
+
+import "testing"
+
+func TestCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		environ  map[string]string
+		expected []Status
+	}{
+		{
+			name:     "Local",
+			environ:  map[string]string{},
+			expected: []Status{StatusOK},
+		},
+		{
+			name:     "GitHubWithoutToken",
+			environ:  map[string]string{"GITHUB_EVENT_PATH": "/event.json", "GITHUB_BASE_REF": "main"},
+			expected: []Status{StatusWarn, StatusOK},
+		},
+		{
+			name:     "GitHub",
+			environ:  map[string]string{"GITHUB_EVENT_PATH": "/event.json", "GITHUB_TOKEN": "x"},
+			expected: []Status{StatusOK},
+		},
+		{
+			name:     "GitLabJobToken",
+			environ:  map[string]string{"GITLAB_CI": "true", "CI_JOB_TOKEN": "x"},
+			expected: []Status{StatusOK},
+		},
+		{
+			name:     "GitLabWithoutToken",
+			environ:  map[string]string{"GITLAB_CI": "true"},
+			expected: []Status{StatusWarn},
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(tt *testing.T) {
+			diagnoses := Credentials(func(name string) (string, bool) {
+				value, ok := test.environ[name]
+				return value, ok
+			})
+			if len(diagnoses) != len(test.expected) {
+				tt.Fatalf("Expected %d diagnoses, got %+v", len(test.expected), diagnoses)
+			}
+			for i, d := range diagnoses {
+				if d.Status != test.expected[i] {
+					tt.Errorf("Expected %s to be %s, got %s: %s", d.Name, test.expected[i], d.Status, d.Message)
+				}
+				if d.Status != StatusOK && d.Remedy == "" {
+					tt.Errorf("Expected a remedy for %s", d.Name)
+				}
+			}
+		})
+	}
+}
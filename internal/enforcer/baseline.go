@@ -0,0 +1,105 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+)
+
+// DefaultBaselineFile is the default path of the baseline file, relative to
+// the configuration.
+const DefaultBaselineFile = ".conform.baseline.json"
+
+// Violation identifies a violation of a check. Violations are matched by
+// policy, check, message, and location, so that a violation of a file or
+// commit does not match the same violation of another one. The line is not
+// part of the location, since it changes as the file is edited.
+type Violation struct {
+	Policy  string `json:"policy"`
+	Check   string `json:"check"`
+	Message string `json:"message"`
+	// File is the slash separated path, relative to the root of the
+	// repository, of the file of the violation, if any.
+	File string `json:"file,omitempty"`
+	// Commit is the SHA of the commit of the violation, if any.
+	Commit string `json:"commit,omitempty"`
+}
+
+// Baseline is a snapshot of existing violations, which are reported without
+// failing enforcement so that only new violations fail.
+type Baseline struct {
+	Violations []Violation `json:"violations"`
+}
+
+// readBaseline reads a baseline file. A missing file results in an empty
+// baseline.
+func readBaseline(name string) (*Baseline, error) {
+	b := &Baseline{}
+
+	contents, err := ioutil.ReadFile(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return b, nil
+		}
+		return nil, err
+	}
+	if err = json.Unmarshal(contents, b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// contains reports whether the violation is in the baseline.
+func (b *Baseline) contains(v Violation) bool {
+	if b == nil {
+		return false
+	}
+	for _, candidate := range b.Violations {
+		if candidate == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// write writes the violations to the baseline file, sorted and deduplicated
+// so that the file is stable across runs.
+func (b *Baseline) write(name string) error {
+	seen := map[Violation]bool{}
+	violations := make([]Violation, 0, len(b.Violations))
+	for _, v := range b.Violations {
+		if !seen[v] {
+			seen[v] = true
+			violations = append(violations, v)
+		}
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		if violations[i].Policy != violations[j].Policy {
+			return violations[i].Policy < violations[j].Policy
+		}
+		if violations[i].Check != violations[j].Check {
+			return violations[i].Check < violations[j].Check
+		}
+		if violations[i].File != violations[j].File {
+			return violations[i].File < violations[j].File
+		}
+		if violations[i].Commit != violations[j].Commit {
+			return violations[i].Commit < violations[j].Commit
+		}
+		return violations[i].Message < violations[j].Message
+	})
+
+	contents, err := json.MarshalIndent(&Baseline{Violations: violations}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(name, append(contents, '\n'), 0644)
+}
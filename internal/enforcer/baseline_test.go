@@ -0,0 +1,65 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBaseline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, DefaultBaselineFile)
+
+	b, err := readBaseline(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Violations) != 0 {
+		t.Errorf("Expected an empty baseline, got %v", b.Violations)
+	}
+
+	header := Violation{Policy: "commit", Check: "Header Length", Message: "Commit header is 90 characters"}
+	license := Violation{Policy: "license", Check: "File Header", Message: "File main.go does not contain a license header", File: "a/main.go"}
+	b = &Baseline{Violations: []Violation{license, header, license}}
+	if err = b.write(name); err != nil {
+		t.Fatal(err)
+	}
+
+	if b, err = readBaseline(name); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []Violation{header, license}; !reflect.DeepEqual(b.Violations, expected) {
+		t.Errorf("Expected sorted and deduplicated violations %v, got %v", expected, b.Violations)
+	}
+	if !b.contains(header) {
+		t.Error("Expected the baseline to contain the header violation")
+	}
+	if b.contains(Violation{Policy: "commit", Check: "Header Length", Message: "Commit header is 91 characters"}) {
+		t.Error("Expected a new violation not to be in the baseline")
+	}
+	if !b.contains(license) {
+		t.Error("Expected the baseline to contain the license violation")
+	}
+	// The same violation of another file is new.
+	other := license
+	other.File = "b/main.go"
+	if b.contains(other) {
+		t.Error("Expected the violation of another file not to be in the baseline")
+	}
+	commit := header
+	commit.Commit = "0123456789abcdef"
+	if b.contains(commit) {
+		t.Error("Expected the violation of another commit not to be in the baseline")
+	}
+}
@@ -0,0 +1,129 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/autonomy/conform/internal/logging"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/autonomy/conform/internal/reporter"
+)
+
+// RepositoryCheck is the name of the check failed by the repositories of a
+// batch that cannot be enforced, e.g. because their configuration is invalid.
+const RepositoryCheck = "Repository"
+
+// Batch enforces the policies of several repositories, such as the checkouts
+// of an organization, and combines their results into a single report. The
+// policies of each repository are prefixed by the name of the repository, in
+// the same way as those of subdirectories.
+type Batch struct {
+	options *Options
+	t       *table
+	r       *result
+}
+
+// NewBatch returns a batch that writes its report to stdout. The options
+// should be the same as those the repositories are loaded with.
+func NewBatch(setters ...Option) *Batch {
+	opts := NewDefaultOptions(setters...)
+	t := opts.newTable(os.Stdout, "POLICY", "CHECK", "STATUS", "MESSAGE")
+	t.span = opts.Tracer.Start("conform enforce", "conform.batch", true)
+
+	return &Batch{
+		options: opts,
+		t:       t,
+		r:       &result{},
+	}
+}
+
+// Enforce enforces the policies of the repository of the specified name.
+func (b *Batch) Enforce(name string, c *Conform, setters ...policy.Option) {
+	logging.Info("enforcing repository", "repository", name)
+	root := b.t.span
+	b.t.span = root.Start("repository "+name, "conform.repository", name)
+	b.r.add(c.run(b.t, name+":", c.policyOptions(setters...), b.r.stopped))
+	b.t.span.End()
+	b.t.span = root
+}
+
+// Fail reports that the policies of the repository of the specified name
+// could not be enforced, e.g. because its configuration is invalid. The
+// enforcement of the batch fails with the exit code of configuration
+// errors.
+func (b *Batch) Fail(name string, err error) {
+	b.r.invalid = true
+	// Errors, such as those of validation, may span several lines.
+	message := strings.Join(strings.Fields(err.Error()), " ")
+	span := b.t.span.Start("repository "+name, "conform.repository", name)
+	span.SetError(message)
+	span.End()
+	b.t.row(name, RepositoryCheck, reporter.StatusFailed, message)
+	b.t.report.Add(&reporter.Check{
+		Policy:      name,
+		Name:        RepositoryCheck,
+		Description: "The policies of the repository can be enforced",
+		Severity:    policy.SeverityError,
+		Message:     "The repository could not be enforced",
+		Violations:  []*reporter.Violation{{Message: message, Status: reporter.StatusFailed}},
+	})
+}
+
+// Finish writes the combined report, and returns the exit code of the
+// outcome. A batch in which any repository could not be enforced exits with
+// the exit code of configuration errors, even in dry run mode, since the
+// report is incomplete.
+func (b *Batch) Finish() int {
+	return b.options.finish(b.t, b.r)
+}
+
+// ReadRepos reads a file listing the paths of repositories, one per line.
+// Blank lines and lines starting with # are ignored, and relative paths are
+// relative to the directory of the file.
+func ReadRepos(name string) ([]string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	// nolint: errcheck
+	defer f.Close()
+
+	repos, err := ParseRepos(f)
+	if err != nil {
+		return nil, err
+	}
+	for i, repo := range repos {
+		if !filepath.IsAbs(repo) {
+			repos[i] = filepath.Join(filepath.Dir(name), repo)
+		}
+	}
+
+	return repos, nil
+}
+
+// ParseRepos parses the contents of a file listing the paths of
+// repositories.
+func ParseRepos(r io.Reader) ([]string, error) {
+	var repos []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		repos = append(repos, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return repos, nil
+}
@@ -0,0 +1,48 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRepos(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		expected []string
+	}{
+		{
+			name:     "Paths",
+			contents: "api\n/src/web\n",
+			expected: []string{"api", "/src/web"},
+		},
+		{
+			name:     "Comments",
+			contents: "# Services\napi\n\n  # Frontends\n  web  \n",
+			expected: []string{"api", "web"},
+		},
+		{
+			name:     "Empty",
+			contents: "",
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			repos, err := ParseRepos(strings.NewReader(test.contents))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(repos, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, repos)
+			}
+		})
+	}
+}
@@ -0,0 +1,370 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"reflect"
+	"sort"
+)
+
+// CheckDescription describes a check of a policy.
+type CheckDescription struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Enabled     bool   `json:"enabled"`
+	// Options are the keys of the spec that enable the check. A check without
+	// options is enabled by declaring the policy.
+	Options []string `json:"-"`
+}
+
+// PolicyDescription describes a policy, and whether the loaded configuration
+// enables it.
+type PolicyDescription struct {
+	Type        string             `json:"type"`
+	Description string             `json:"description,omitempty"`
+	Plugin      bool               `json:"plugin,omitempty"`
+	Enabled     bool               `json:"enabled"`
+	Checks      []CheckDescription `json:"checks"`
+}
+
+// catalog describes the builtin policies, keyed by type. Every type in
+// policyMap must be described.
+var catalog = map[string]PolicyDescription{
+	"binary": {
+		Description: "Reject binary files added by a change unless allowed or tracked with Git LFS",
+		Checks: []CheckDescription{
+			{Name: "Binary Files", Description: "Added files are not binary"},
+		},
+	},
+	"changelog": {
+		Description: "Enforce that changes to source files add a changelog fragment",
+		Checks: []CheckDescription{
+			{Name: "Changelog Fragment", Description: "A changelog fragment is added or the change is exempted"},
+		},
+	},
+	"codeowners": {
+		Description: "Enforce that every changed file is covered by a CODEOWNERS rule",
+		Checks: []CheckDescription{
+			{Name: "Code Owners", Description: "Changed files have owners"},
+		},
+	},
+	"commit": {
+		Description: "Enforce commit message and signature policies",
+		Checks: []CheckDescription{
+			{Name: "Header Length", Description: "The commit header does not exceed the maximum length", Options: []string{"headerLength"}},
+			{Name: "DCO", Description: "The commit is signed off", Options: []string{"dco"}},
+			{Name: "GPG", Description: "The commit has a GPG signature", Options: []string{"gpg"}},
+			{Name: "Imperative Mood", Description: "The first word of the commit is an imperative verb", Options: []string{"imperative"}},
+			{Name: "Conventional Commit", Description: "The commit is a valid conventional commit", Options: []string{"conventional"}},
+			{Name: "Number of Commits", Description: "HEAD is at most one commit ahead of the base branch", Options: []string{"maximumOfOneCommit"}},
+			{Name: "Commit Body", Description: "The commit has a body", Options: []string{"requireCommitBody"}},
+		},
+	},
+	"cue": {
+		Description: "Validate the commit, refs, and changed files against CUE constraints",
+		Checks: []CheckDescription{
+			{Name: "CUE", Description: "The input satisfies the constraints"},
+		},
+	},
+	"dependency": {
+		Description: "Enforce that the licenses of Go module dependencies are allowed",
+		Checks: []CheckDescription{
+			{Name: "Dependency Licenses", Description: "Dependencies have allowed licenses"},
+		},
+	},
+	"diffsize": {
+		Description: "Limit the number of lines and files changed",
+		Checks: []CheckDescription{
+			{Name: "Diff Size", Description: "The change does not exceed the limits"},
+		},
+	},
+	"dockerfile": {
+		Description: "Enforce Dockerfile policies",
+		Checks: []CheckDescription{
+			{Name: "Base Image Digest", Description: "Base images are pinned by digest", Options: []string{"requireDigest"}},
+			{Name: "Forbidden Base Images", Description: "Base images are not forbidden", Options: []string{"forbiddenImages"}},
+			{Name: "Non-Root User", Description: "The final stage sets a non-root user", Options: []string{"requireNonRootUser"}},
+			{Name: "Labels", Description: "Required labels are set and follow the naming convention", Options: []string{"requiredLabels", "labelPattern"}},
+		},
+	},
+	"eol": {
+		Description: "Enforce line endings consistent with .gitattributes",
+		Checks: []CheckDescription{
+			{Name: "Line Endings", Description: "Text files use the expected line endings"},
+		},
+	},
+	"exec": {
+		Description: "Run an external command as a check",
+		Checks: []CheckDescription{
+			{Name: "Exec", Description: "The command succeeds"},
+		},
+	},
+	"executable": {
+		Description: "Enforce the executable bit of scripts and other files",
+		Checks: []CheckDescription{
+			{Name: "Executable Bit", Description: "Only scripts are executable"},
+		},
+	},
+	"filename": {
+		Description: "Enforce filename policies",
+		Checks: []CheckDescription{
+			{Name: "Case Conflict", Description: "No tracked paths differ only by case", Options: []string{"caseConflicts"}},
+			{Name: "Path Length", Description: "Paths do not exceed the maximum length", Options: []string{"maximumPathLength"}},
+			{Name: "Path Depth", Description: "Paths do not exceed the maximum depth", Options: []string{"maximumDepth"}},
+		},
+	},
+	"frontmatter": {
+		Description: "Validate the YAML frontmatter of Markdown files",
+		Checks: []CheckDescription{
+			{Name: "Frontmatter", Description: "Frontmatter has the required keys and formats"},
+		},
+	},
+	"frozen": {
+		Description: "Reject commits modifying or deleting frozen files",
+		Checks: []CheckDescription{
+			{Name: "Frozen Paths", Description: "Frozen files are unchanged or the change is overridden"},
+		},
+	},
+	"generate": {
+		Description: "Enforce that generated files are up to date",
+		Checks: []CheckDescription{
+			{Name: "Generated Code", Description: "Running the generators does not change any files"},
+		},
+	},
+	"gitattributes": {
+		Description: "Enforce .gitattributes rules",
+		Checks: []CheckDescription{
+			{Name: "Required Attributes", Description: "Required rules are present", Options: []string{"required"}},
+			{Name: "Attribute Consistency", Description: "Tracked files agree with their attributes", Options: []string{"consistency"}},
+		},
+	},
+	"gomod": {
+		Description: "Enforce go.mod hygiene",
+		Checks: []CheckDescription{
+			{Name: "Replace Directives", Description: "Only allowed replace directives are used", Options: []string{"forbidReplace"}},
+			{Name: "Go Version", Description: "The go directive is at least the minimum version", Options: []string{"minimumGoVersion"}},
+			{Name: "Pseudo Versions", Description: "No dependencies use pseudo-versions on release branches", Options: []string{"releaseBranches"}},
+		},
+	},
+	"kubernetes": {
+		Description: "Validate Kubernetes manifests and Helm charts",
+		Checks: []CheckDescription{
+			{Name: "API Deprecations", Description: "Manifests do not use removed API versions"},
+			{Name: "Required Metadata", Description: "Manifests have the required labels and annotations", Options: []string{"requiredLabels", "requiredAnnotations"}},
+			{Name: "Helm Chart", Description: "Charts render and are valid"},
+		},
+	},
+	"license": {
+		Description: "Enforce license headers on source code files",
+		Checks: []CheckDescription{
+			{Name: "File Header", Description: "Files start with the license header"},
+		},
+	},
+	"newline": {
+		Description: "Enforce that text files end with exactly one newline",
+		Checks: []CheckDescription{
+			{Name: "EOF Newline", Description: "Files end with exactly one newline"},
+		},
+	},
+	"notice": {
+		Description: "Enforce that a NOTICE file exists and contains required attributions",
+		Checks: []CheckDescription{
+			{Name: "NOTICE File", Description: "The NOTICE file contains the required attributions"},
+		},
+	},
+	"pullrequest": {
+		Description: "Enforce pull request and merge request policies",
+		Checks: []CheckDescription{
+			{Name: "Labels", Description: "The pull request has the required labels", Options: []string{"requiredLabels"}},
+			{Name: "Description", Description: "The description has the required sections and completed tasks", Options: []string{"requiredSections", "requireCompletedTasks"}},
+			{Name: "Approvals", Description: "The pull request has the required approvals", Options: []string{"minimumApprovals", "requireCodeOwnerApproval"}},
+		},
+	},
+	"rego": {
+		Description: "Evaluate Rego rules against the commit, refs, and changed files",
+		Checks: []CheckDescription{
+			{Name: "Rego", Description: "The rules report no violations"},
+		},
+	},
+	"schema": {
+		Description: "Validate YAML and JSON files against JSON Schemas",
+		Checks: []CheckDescription{
+			{Name: "Schema", Description: "Files are valid against their schemas"},
+		},
+	},
+	"script": {
+		Description: "Run custom rules written in Starlark",
+		Checks: []CheckDescription{
+			{Name: "Script", Description: "The script reports no violations"},
+		},
+	},
+	"security": {
+		Description: "Enforce that a SECURITY.md file exists and declares a security contact",
+		Checks: []CheckDescription{
+			{Name: "Security Policy", Description: "The security policy declares a valid contact"},
+		},
+	},
+	"shebang": {
+		Description: "Enforce approved shebangs on scripts",
+		Checks: []CheckDescription{
+			{Name: "Shebang", Description: "Scripts start with an approved shebang"},
+			{Name: "Script Executable Bit", Description: "Scripts and the executable bit agree", Options: []string{"executable"}},
+		},
+	},
+	"submodule": {
+		Description: "Forbid or restrict submodules",
+		Checks: []CheckDescription{
+			{Name: "No Submodules", Description: "The repository has no submodules", Options: []string{"forbid"}},
+			{Name: "Submodule URL", Description: "Submodule URLs are allowed", Options: []string{"allowedURLs"}},
+			{Name: "Submodule Commit", Description: "Submodules are pinned to commits on their default branch", Options: []string{"requireOnDefaultBranch"}},
+		},
+	},
+	"symlink": {
+		Description: "Forbid or restrict symlinks",
+		Checks: []CheckDescription{
+			{Name: "Symlinks", Description: "Symlinks are allowed and stay in the repository"},
+		},
+	},
+	"wasm": {
+		Description: "Run custom policies compiled to WebAssembly",
+		Checks: []CheckDescription{
+			{Name: "WASM", Description: "The module reports no violations"},
+		},
+	},
+	"whitespace": {
+		Description: "Enforce that added lines have no trailing whitespace",
+		Checks: []CheckDescription{
+			{Name: "Trailing Whitespace", Description: "Added lines have no trailing whitespace"},
+		},
+	},
+}
+
+// namedChecks are the policies whose check is named by the "name" key of the
+// spec.
+var namedChecks = map[string]bool{
+	"cue":    true,
+	"exec":   true,
+	"rego":   true,
+	"script": true,
+	"wasm":   true,
+}
+
+// List describes the builtin policies and the plugins of the configuration,
+// ordered by type. A policy is enabled if the configuration declares it, and
+// a check is enabled if a declaration of its policy sets one of its options.
+// The checks of policies declared with a custom check name are listed by that
+// name.
+func (c *Conform) List() []PolicyDescription {
+	declared := map[string][]*PolicyDeclaration{}
+	for _, p := range c.Policies {
+		declared[p.Type] = append(declared[p.Type], p)
+	}
+
+	descriptions := make([]PolicyDescription, 0, len(catalog)+len(c.Plugins))
+	for t, d := range catalog {
+		d.Type = t
+		d.Enabled = len(declared[t]) != 0
+		if namedChecks[t] {
+			d.Checks = namedCheckDescriptions(d.Checks[0], declared[t])
+		} else {
+			checks := make([]CheckDescription, 0, len(d.Checks))
+			for _, check := range d.Checks {
+				for _, p := range declared[t] {
+					check.Enabled = check.Enabled || enables(p.Spec, check.Options)
+				}
+				checks = append(checks, check)
+			}
+			d.Checks = checks
+		}
+		descriptions = append(descriptions, d)
+	}
+
+	for _, p := range c.Plugins {
+		descriptions = append(descriptions, PolicyDescription{
+			Type:    p.Name,
+			Plugin:  true,
+			Enabled: len(declared[p.Name]) != 0,
+			Checks:  []CheckDescription{},
+		})
+	}
+
+	sort.Slice(descriptions, func(i, j int) bool {
+		return descriptions[i].Type < descriptions[j].Type
+	})
+
+	return descriptions
+}
+
+// describeCheck returns the description of the check of the policy of the
+// type, or an empty string if the catalog does not describe it, such as the
+// checks of plugins.
+func describeCheck(t, name string) string {
+	d, ok := catalog[t]
+	if !ok {
+		return ""
+	}
+	if namedChecks[t] {
+		return d.Checks[0].Description
+	}
+	for _, check := range d.Checks {
+		if check.Name == name {
+			return check.Description
+		}
+	}
+
+	return ""
+}
+
+func namedCheckDescriptions(check CheckDescription, declarations []*PolicyDeclaration) []CheckDescription {
+	if len(declarations) == 0 {
+		return []CheckDescription{check}
+	}
+
+	checks := make([]CheckDescription, 0, len(declarations))
+	for _, p := range declarations {
+		c := check
+		c.Enabled = true
+		if name, ok := specName(p.Spec).(string); ok && name != "" {
+			c.Name = name
+		}
+		checks = append(checks, c)
+	}
+
+	return checks
+}
+
+// enables reports whether the spec sets any of the options to a value other
+// than the zero value of its type.
+func enables(spec interface{}, options []string) bool {
+	if len(options) == 0 {
+		return true
+	}
+	m, ok := spec.(map[interface{}]interface{})
+	if !ok {
+		return false
+	}
+	for _, option := range options {
+		v, ok := m[option]
+		if !ok || v == nil {
+			continue
+		}
+		switch rv := reflect.ValueOf(v); rv.Kind() {
+		case reflect.Map:
+			// Maps are decoded into pointers, which enable their check
+			// even when empty.
+			return true
+		case reflect.Slice:
+			if rv.Len() != 0 {
+				return true
+			}
+		default:
+			if !rv.IsZero() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
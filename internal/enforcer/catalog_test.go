@@ -0,0 +1,83 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCatalog(t *testing.T) {
+	for name := range policyMap {
+		d, ok := catalog[name]
+		if !ok {
+			t.Errorf("Expected policy %q to be described", name)
+			continue
+		}
+		if len(d.Checks) == 0 {
+			t.Errorf("Expected policy %q to describe its checks", name)
+		}
+	}
+	for name := range catalog {
+		if _, ok := policyMap[name]; !ok {
+			t.Errorf("Expected described policy %q to exist", name)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	c := &Conform{
+		Policies: []*PolicyDeclaration{
+			{Type: "commit", Spec: map[interface{}]interface{}{
+				"headerLength": 72,
+				"gpg":          false,
+				"conventional": map[interface{}]interface{}{},
+			}},
+			{Type: "exec", Spec: map[interface{}]interface{}{"name": "Lint"}},
+			{Type: "exec", Spec: map[interface{}]interface{}{"command": []interface{}{"true"}}},
+			{Type: "custom"},
+		},
+		Plugins: []*PluginDeclaration{{Name: "custom"}},
+	}
+
+	descriptions := map[string]PolicyDescription{}
+	for _, d := range c.List() {
+		descriptions[d.Type] = d
+	}
+
+	if !descriptions["commit"].Enabled {
+		t.Errorf("Expected commit to be enabled")
+	}
+	enabled := map[string]bool{}
+	for _, check := range descriptions["commit"].Checks {
+		enabled[check.Name] = check.Enabled
+	}
+	expected := map[string]bool{
+		"Header Length":       true,
+		"DCO":                 false,
+		"GPG":                 false,
+		"Imperative Mood":     false,
+		"Conventional Commit": true,
+		"Number of Commits":   false,
+		"Commit Body":         false,
+	}
+	if !reflect.DeepEqual(enabled, expected) {
+		t.Errorf("Expected commit checks %v, got %v", expected, enabled)
+	}
+	if descriptions["license"].Enabled || descriptions["license"].Checks[0].Enabled {
+		t.Errorf("Expected license to be disabled")
+	}
+	if d := descriptions["custom"]; !d.Plugin || !d.Enabled {
+		t.Errorf("Expected custom to be an enabled plugin, got %+v", d)
+	}
+
+	var checks []string
+	for _, check := range descriptions["exec"].Checks {
+		checks = append(checks, check.Name)
+	}
+	if expected := []string{"Lint", "Exec"}; !reflect.DeepEqual(checks, expected) {
+		t.Errorf("Expected exec checks %v, got %v", expected, checks)
+	}
+}
@@ -0,0 +1,116 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/autonomy/conform/internal/policy"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// deprecatedKey is a key of the spec of a policy that was renamed or removed.
+// The old name of a renamed key is still read, and a removed key is ignored,
+// and both are reported as a warning, so that configurations keep working
+// until they are updated.
+type deprecatedKey struct {
+	// types are the policy types whose spec declared the key.
+	types []string
+	from  string
+	// to is the new name of the key, or empty if it was removed.
+	to string
+	// reason explains why a removed key is no longer needed.
+	reason string
+}
+
+// deprecatedKeys are the renamed and removed keys of the specs of the
+// policies.
+var deprecatedKeys = []deprecatedKey{
+	{types: []string{"newline"}, from: "fix", reason: "violations are always reported, and conform fix repairs them"},
+}
+
+// renameDeprecated renames the deprecated keys of the specs of the policies of
+// a configuration, and of its profiles, returning the configuration with the
+// current keys and a warning for each renamed key. The configuration is
+// returned as is if it has no deprecated keys.
+func renameDeprecated(source string, configBytes []byte) ([]byte, []Problem, error) {
+	var doc map[interface{}]interface{}
+	if err := yaml.Unmarshal(configBytes, &doc); err != nil {
+		// Invalid configurations are reported when they are decoded.
+		return configBytes, nil, nil
+	}
+
+	var problems []Problem
+	rename := func(where string, policies interface{}) {
+		list, ok := policies.([]interface{})
+		if !ok {
+			return
+		}
+		for i, item := range list {
+			declaration, ok := item.(map[interface{}]interface{})
+			if !ok {
+				continue
+			}
+			t, _ := declaration["type"].(string)
+			spec, ok := declaration["spec"].(map[interface{}]interface{})
+			if !ok {
+				continue
+			}
+			for _, k := range deprecatedKeys {
+				value, ok := spec[k.from]
+				if !ok || !k.applies(t) {
+					continue
+				}
+				message := fmt.Sprintf("%s: %s/%d/spec: %q is deprecated, rename it to %q", source, where, i, k.from, k.to)
+				if k.to == "" {
+					message = fmt.Sprintf("%s: %s/%d/spec: %q is deprecated and ignored: %s", source, where, i, k.from, k.reason)
+				} else if _, ok = spec[k.to]; ok {
+					message = fmt.Sprintf("%s: %s/%d/spec: %q is deprecated and ignored, since %q is set", source, where, i, k.from, k.to)
+				} else {
+					spec[k.to] = value
+				}
+				delete(spec, k.from)
+				problems = append(problems, Problem{Policy: t, Severity: policy.SeverityWarn, Message: message})
+			}
+		}
+	}
+
+	rename("/policies", doc["policies"])
+	if profiles, ok := doc["profiles"].(map[interface{}]interface{}); ok {
+		names := make([]string, 0, len(profiles))
+		for name := range profiles {
+			if s, ok := name.(string); ok {
+				names = append(names, s)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if p, ok := profiles[name].(map[interface{}]interface{}); ok {
+				rename("/profiles/"+name+"/policies", p["policies"])
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		return configBytes, nil, nil
+	}
+	renamed, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return renamed, problems, nil
+}
+
+func (k deprecatedKey) applies(t string) bool {
+	for _, s := range k.types {
+		if s == t {
+			return true
+		}
+	}
+
+	return false
+}
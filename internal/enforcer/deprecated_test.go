@@ -0,0 +1,104 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestRenameDeprecated(t *testing.T) {
+	defer func(keys []deprecatedKey) { deprecatedKeys = keys }(deprecatedKeys)
+	deprecatedKeys = []deprecatedKey{
+		{types: []string{"commit"}, from: "maxHeaderLength", to: "headerLength"},
+		{types: []string{"commit"}, from: "signOff", to: "dco"},
+		{types: []string{"license", "newline"}, from: "excludePaths", to: "skipPaths"},
+		{types: []string{"newline"}, from: "fix", reason: "conform fix repairs them"},
+	}
+
+	tests := []struct {
+		name     string
+		config   string
+		expected *Conform
+		messages []string
+	}{
+		{
+			name:     "Current",
+			config:   "policies:\n  - type: commit\n    spec:\n      headerLength: 72\n",
+			expected: &Conform{Policies: []*PolicyDeclaration{{Type: "commit", Spec: map[interface{}]interface{}{"headerLength": 72}}}},
+		},
+		{
+			name:     "Renamed",
+			config:   "policies:\n  - type: commit\n    spec:\n      maxHeaderLength: 72\n      signOff: true\n",
+			expected: &Conform{Policies: []*PolicyDeclaration{{Type: "commit", Spec: map[interface{}]interface{}{"headerLength": 72, "dco": true}}}},
+			messages: []string{
+				`.conform.yaml: /policies/0/spec: "maxHeaderLength" is deprecated, rename it to "headerLength"`,
+				`.conform.yaml: /policies/0/spec: "signOff" is deprecated, rename it to "dco"`,
+			},
+		},
+		{
+			name:     "Both",
+			config:   "policies:\n  - type: commit\n    spec:\n      maxHeaderLength: 72\n      headerLength: 89\n",
+			expected: &Conform{Policies: []*PolicyDeclaration{{Type: "commit", Spec: map[interface{}]interface{}{"headerLength": 89}}}},
+			messages: []string{
+				`.conform.yaml: /policies/0/spec: "maxHeaderLength" is deprecated and ignored, since "headerLength" is set`,
+			},
+		},
+		{
+			name:     "Removed",
+			config:   "policies:\n  - type: newline\n    spec:\n      fix: true\n",
+			expected: &Conform{Policies: []*PolicyDeclaration{{Type: "newline", Spec: map[interface{}]interface{}{}}}},
+			messages: []string{
+				`.conform.yaml: /policies/0/spec: "fix" is deprecated and ignored: conform fix repairs them`,
+			},
+		},
+		{
+			name:     "OtherPolicy",
+			config:   "policies:\n  - type: exec\n    spec:\n      signOff: true\n",
+			expected: &Conform{Policies: []*PolicyDeclaration{{Type: "exec", Spec: map[interface{}]interface{}{"signOff": true}}}},
+		},
+		{
+			name:   "Profile",
+			config: "profiles:\n  ci:\n    policies:\n      - type: license\n        spec:\n          excludePaths: [vendor/]\n",
+			expected: &Conform{Profiles: map[string]*Profile{"ci": {Policies: []*PolicyDeclaration{
+				{Type: "license", Spec: map[interface{}]interface{}{"skipPaths": []interface{}{"vendor/"}}},
+			}}}},
+			messages: []string{
+				`.conform.yaml: /profiles/ci/policies/0/spec: "excludePaths" is deprecated, rename it to "skipPaths"`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(tt *testing.T) {
+			configBytes, problems, err := renameDeprecated(".conform.yaml", []byte(test.config))
+			if err != nil {
+				tt.Fatal(err)
+			}
+			c := &Conform{}
+			if err = yaml.Unmarshal(configBytes, c); err != nil {
+				tt.Fatal(err)
+			}
+			if !reflect.DeepEqual(c, test.expected) {
+				tt.Errorf("Expected %+v, got %+v", test.expected, c)
+			}
+			var messages []string
+			for _, p := range problems {
+				if p.Severity != policy.SeverityWarn {
+					tt.Errorf("Expected a warning, got %s", p.Severity)
+				}
+				messages = append(messages, p.Message)
+			}
+			if !reflect.DeepEqual(messages, test.messages) {
+				tt.Errorf("Expected warnings %q, got %q", test.messages, messages)
+			}
+		})
+	}
+}
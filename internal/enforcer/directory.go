@@ -0,0 +1,168 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/logging"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// directory is the configuration of a subdirectory, declared in a nested
+// configuration file. Its policies are only enforced when the changes being
+// enforced touch the subdirectory, and are enforced from within it.
+type directory struct {
+	// name is the slash separated path of the subdirectory, relative to the
+	// root configuration.
+	name string
+	// repoPath is the slash separated path of the subdirectory, relative to
+	// the root of the repository.
+	repoPath string
+	// path is the absolute path of the subdirectory.
+	path string
+	// root is the absolute path of the root configuration's directory.
+	root    string
+	conform *Conform
+}
+
+// loadDirectories loads the nested configuration files tracked below the
+// current directory.
+func loadDirectories(untrusted bool) ([]*directory, error) {
+	g, err := git.NewGit()
+	if err != nil {
+		// Nested configurations are only supported in git repositories.
+		return nil, nil
+	}
+	repoRoot, err := g.Root()
+	if err != nil {
+		return nil, err
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return nil, err
+	}
+	if repoRoot, err = filepath.EvalSymlinks(repoRoot); err != nil {
+		return nil, err
+	}
+	prefix, err := filepath.Rel(repoRoot, root)
+	if err != nil {
+		return nil, err
+	}
+	prefix = filepath.ToSlash(prefix)
+
+	files, err := g.TrackedFiles()
+	if err != nil {
+		return nil, errors.Errorf("failed to list tracked files: %v", err)
+	}
+	sort.Strings(files)
+
+	// A directory with more than one configuration file is configured by
+	// the preferred one.
+	configFiles := map[string]string{}
+	for _, file := range files {
+		dir := path.Dir(file)
+		if rank := configRank(path.Base(file)); rank >= 0 {
+			if current, ok := configFiles[dir]; !ok || rank < configRank(path.Base(current)) {
+				configFiles[dir] = file
+			}
+		}
+	}
+
+	var directories []*directory
+	for _, file := range files {
+		dir := path.Dir(file)
+		if configFiles[dir] != file {
+			continue
+		}
+		name := dir
+		if prefix != "." {
+			if !strings.HasPrefix(dir, prefix+"/") {
+				continue
+			}
+			name = strings.TrimPrefix(dir, prefix+"/")
+		}
+		if dir == "." || dir == prefix {
+			continue
+		}
+
+		d := &directory{
+			name:     name,
+			repoPath: dir,
+			path:     filepath.Join(repoRoot, filepath.FromSlash(dir)),
+			root:     root,
+		}
+		if d.conform, err = load([]string{filepath.Join(d.path, path.Base(file))}, untrusted); err != nil {
+			return nil, errors.Errorf("%s: %v", file, err)
+		}
+		logging.Info("found nested configuration", "directory", d.name)
+		directories = append(directories, d)
+	}
+
+	return directories, nil
+}
+
+// configRank returns the preference of the name of a configuration file, or
+// -1 if it is not one.
+func configRank(name string) int {
+	for i, n := range configNames {
+		if n == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// changed reports whether any of the slash separated paths, relative to the
+// root of the repository, is in the directory.
+func (d *directory) changed(paths []string) bool {
+	for _, p := range paths {
+		if strings.HasPrefix(p, d.repoPath+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// changedPaths returns the paths of the files changed by the changes being
+// enforced, relative to the root of the repository.
+func changedPaths(opts *policy.Options) ([]string, error) {
+	g, err := git.NewGit()
+	if err != nil {
+		return nil, errors.Errorf("failed to open git repo: %v", err)
+	}
+
+	var base string
+	if opts.BaseBranch != nil {
+		base = *opts.BaseBranch
+	}
+	diffs, err := g.Diff(base)
+	if err != nil {
+		return nil, errors.Errorf("failed to get diff: %v", err)
+	}
+
+	paths := make([]string, 0, len(diffs))
+	for _, diff := range diffs {
+		if diff.From != "" {
+			paths = append(paths, diff.From)
+		}
+		if diff.To != "" {
+			paths = append(paths, diff.To)
+		}
+	}
+
+	return paths, nil
+}
@@ -5,26 +5,90 @@
 package enforcer
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
-	"log"
+	"net/http"
 	"os"
-	"text/tabwriter"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
 
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/logging"
 	"github.com/autonomy/conform/internal/policy"
+	"github.com/autonomy/conform/internal/policy/binary"
+	"github.com/autonomy/conform/internal/policy/changelog"
+	"github.com/autonomy/conform/internal/policy/codeowners"
 	"github.com/autonomy/conform/internal/policy/commit"
+	"github.com/autonomy/conform/internal/policy/cue"
+	"github.com/autonomy/conform/internal/policy/dependency"
+	"github.com/autonomy/conform/internal/policy/diffsize"
+	"github.com/autonomy/conform/internal/policy/dockerfile"
+	"github.com/autonomy/conform/internal/policy/eol"
+	execpolicy "github.com/autonomy/conform/internal/policy/exec"
+	"github.com/autonomy/conform/internal/policy/executable"
+	"github.com/autonomy/conform/internal/policy/filename"
+	"github.com/autonomy/conform/internal/policy/frontmatter"
+	"github.com/autonomy/conform/internal/policy/frozen"
+	"github.com/autonomy/conform/internal/policy/generate"
+	"github.com/autonomy/conform/internal/policy/gitattributes"
+	"github.com/autonomy/conform/internal/policy/gomod"
+	"github.com/autonomy/conform/internal/policy/kubernetes"
 	"github.com/autonomy/conform/internal/policy/license"
+	"github.com/autonomy/conform/internal/policy/newline"
+	"github.com/autonomy/conform/internal/policy/notice"
+	"github.com/autonomy/conform/internal/policy/pullrequest"
+	"github.com/autonomy/conform/internal/policy/rego"
+	"github.com/autonomy/conform/internal/policy/schema"
+	"github.com/autonomy/conform/internal/policy/script"
+	"github.com/autonomy/conform/internal/policy/security"
+	"github.com/autonomy/conform/internal/policy/shebang"
+	"github.com/autonomy/conform/internal/policy/submodule"
+	"github.com/autonomy/conform/internal/policy/symlink"
+	"github.com/autonomy/conform/internal/policy/wasm"
+	"github.com/autonomy/conform/internal/policy/whitespace"
+	"github.com/autonomy/conform/internal/progress"
+	"github.com/autonomy/conform/internal/reporter"
 	"github.com/autonomy/conform/internal/summarizer"
+	"github.com/autonomy/conform/internal/terminal"
 	"github.com/mitchellh/mapstructure"
+	toml "github.com/pelletier/go-toml"
 	"github.com/pkg/errors"
-
 	yaml "gopkg.in/yaml.v2"
 )
 
 // Conform is a struct that conform.yaml gets decoded into.
 type Conform struct {
-	Policies   []*PolicyDeclaration `yaml:"policies"`
+	// APIVersion is the version of the configuration schema. Configurations
+	// without a version predate versioning.
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	// Templates expands the Go templates of the string values of the
+	// configuration file, which are otherwise taken literally.
+	Templates bool `yaml:"templates"`
+	// Timeout is the timeout of the policies that do not declare their own,
+	// as a duration such as 30s or 5m.
+	Timeout  string               `yaml:"timeout"`
+	Extends  []*ExtendDeclaration `yaml:"extends"`
+	Policies []*PolicyDeclaration `yaml:"policies"`
+	Plugins  []*PluginDeclaration `yaml:"plugins"`
+	Profiles map[string]*Profile  `yaml:"profiles"`
+	// Reporters are the formats the results are written in, when the output
+	// format is not set by the options.
+	Reporters  []*ReporterDeclaration `yaml:"reporters"`
 	summarizer summarizer.Summarizer
+
+	directories []*directory
+	options     *Options
+	baseline    *Baseline
+	ignore      *git.Ignore
+	// deprecated are the warnings of the deprecated keys renamed when the
+	// configuration was loaded.
+	deprecated []Problem
 }
 
 // PolicyDeclaration allows a user to declare an arbitrary type along with a
@@ -32,29 +96,200 @@ type Conform struct {
 type PolicyDeclaration struct {
 	Type string      `yaml:"type"`
 	Spec interface{} `yaml:"spec"`
+	// Severity is the severity of the violations of the policy. Defaults to
+	// error.
+	Severity policy.Severity `yaml:"severity"`
+	// CheckSeverity overrides the severity of individual checks, keyed by
+	// check name.
+	CheckSeverity map[string]policy.Severity `yaml:"checkSeverity"`
+	// Timeout is the timeout of the policy, as a duration such as 30s or 5m.
+	// It overrides the timeout of the configuration.
+	Timeout string `yaml:"timeout"`
+}
+
+// severity returns the severity of the violations of a check of the policy.
+func (p *PolicyDeclaration) severity(check string) policy.Severity {
+	if severity, ok := p.CheckSeverity[check]; ok {
+		return severity
+	}
+	if p.Severity != "" {
+		return p.Severity
+	}
+
+	return policy.SeverityError
 }
 
 // policyMap defines the set of policies allowed within Conform.
 var policyMap = map[string]policy.Policy{
-	"commit":  &commit.Commit{},
-	"license": &license.License{},
+	"binary":        &binary.Binary{},
+	"changelog":     &changelog.Changelog{},
+	"codeowners":    &codeowners.CodeOwners{},
+	"commit":        &commit.Commit{},
+	"cue":           &cue.CUE{},
+	"dependency":    &dependency.Dependency{},
+	"diffsize":      &diffsize.DiffSize{},
+	"dockerfile":    &dockerfile.Dockerfile{},
+	"eol":           &eol.EOL{},
+	"exec":          &execpolicy.Exec{},
+	"executable":    &executable.Executable{},
+	"filename":      &filename.Filename{},
+	"frontmatter":   &frontmatter.Frontmatter{},
+	"frozen":        &frozen.Frozen{},
+	"generate":      &generate.Generate{},
+	"gitattributes": &gitattributes.GitAttributes{},
+	"gomod":         &gomod.GoMod{},
+	"kubernetes":    &kubernetes.Kubernetes{},
+	"license":       &license.License{},
+	"newline":       &newline.Newline{},
+	"notice":        &notice.Notice{},
+	"pullrequest":   &pullrequest.PullRequest{},
+	"rego":          &rego.Rego{},
+	"schema":        &schema.Schema{},
+	"script":        &script.Script{},
+	"security":      &security.Security{},
+	"shebang":       &shebang.Shebang{},
+	"submodule":     &submodule.Submodule{},
+	"symlink":       &symlink.Symlink{},
+	"wasm":          &wasm.WASM{},
+	"whitespace":    &whitespace.Whitespace{},
 	// "version":    &version.Version{},
 }
 
-// New loads the conform.yaml file and unmarshals it into a Conform struct.
-func New() (*Conform, error) {
-	configBytes, err := ioutil.ReadFile(".conform.yaml")
+// New loads the configuration files and unmarshals them into a Conform
+// struct. By default, the configuration is read from .conform.yaml, or
+// exported from .conform.cue if there is no .conform.yaml file, and merged
+// with .conform.local.yaml if it exists. The default configuration is
+// searched for in the current directory and its parents, up to the root of
+// the git repository, and the working directory is changed to the directory
+// it is found in so that the paths of policies are relative to it.
+func New(setters ...Option) (*Conform, error) {
+	opts := NewDefaultOptions(setters...)
+
+	files := opts.ConfigFiles
+	if len(files) == 0 {
+		dir, defaults, err := Locate()
+		if err != nil {
+			return nil, err
+		}
+		if err = os.Chdir(dir); err != nil {
+			return nil, err
+		}
+		files = defaults
+		logging.Info("found configuration", "dir", dir, "files", files)
+	}
+
+	c, err := load(files, opts.Untrusted)
 	if err != nil {
 		return nil, err
 	}
-	c := &Conform{}
-	err = yaml.Unmarshal(configBytes, c)
+	if _, ok := c.Profiles[opts.PreferredProfile]; ok && opts.Profile == "" {
+		opts.Profile = opts.PreferredProfile
+	}
+	if opts.Profile != "" {
+		p, ok := c.Profiles[opts.Profile]
+		if !ok {
+			return nil, errors.Errorf("Profile %q is not defined", opts.Profile)
+		}
+		c = c.applyProfile(opts.Profile)
+		opts.Skip = append(opts.Skip, p.Skip...)
+		logging.Info("applied profile", "name", opts.Profile)
+	}
+
+	if _, ok := terminal.Themes[opts.Theme]; opts.Theme != "" && !ok {
+		return nil, errors.Errorf("Unknown theme %q: must be one of %s", opts.Theme, strings.Join(terminal.ThemeNames(), ", "))
+	}
+
+	// The output format of the options overrides the reporters of the
+	// configuration.
+	if opts.Output == reporter.Table && opts.OutputFile == "" {
+		opts.Reporters = c.Reporters
+	}
+	if err = validateReporters(opts.reporters()); err != nil {
+		return nil, err
+	}
+	if opts.WebhookURL != "" {
+		if err = reporter.ValidateWebhookURL(opts.WebhookURL); err != nil {
+			return nil, err
+		}
+	}
+	if err = opts.validateNotify(); err != nil {
+		return nil, err
+	}
+	if opts.MinSeverity != "" && !validSeverity(opts.MinSeverity) {
+		return nil, errors.Errorf("Invalid minimum severity %q: must be one of error, warn, or info", opts.MinSeverity)
+	}
+
+	for outcome := range opts.ExitCodes {
+		if _, ok := DefaultExitCodes[outcome]; !ok {
+			return nil, errors.Errorf("Unknown outcome %q: must be one of pass, failure, config, warnings, or error", outcome)
+		}
+	}
+
+	for _, t := range opts.Policies {
+		if !c.defines(t) {
+			return nil, errors.Errorf("Policy %q is not defined", t)
+		}
+	}
+
+	if c.directories, err = loadDirectories(opts.Untrusted); err != nil {
+		return nil, err
+	}
+
+	for _, d := range c.directories {
+		d.conform = d.conform.applyProfile(opts.Profile)
+	}
+
+	if opts.Untrusted {
+		if err = refuseUntrusted(c); err != nil {
+			return nil, err
+		}
+		for _, d := range c.directories {
+			if err = refuseUntrusted(d.conform); err != nil {
+				return nil, errors.Errorf("%s: %v", d.name, err)
+			}
+		}
+	}
+
+	if err = applyEnv(c, os.Environ()); err != nil {
+		return nil, err
+	}
+	for _, d := range c.directories {
+		if err = applyEnv(d.conform, os.Environ()); err != nil {
+			return nil, errors.Errorf("%s: %v", d.name, err)
+		}
+	}
+
+	sets, err := parseSet(opts.Set)
 	if err != nil {
 		return nil, err
 	}
+	for _, o := range sets {
+		declared := o.declares(c)
+		for _, d := range c.directories {
+			declared = declared || o.declares(d.conform)
+		}
+		if !declared {
+			return nil, errors.Errorf("%s: policy %q is not declared", o.name, o.segments[0])
+		}
+	}
+	if err = applyOverrides(c, sets, "overrides"); err != nil {
+		return nil, err
+	}
+	for _, d := range c.directories {
+		if err = applyOverrides(d.conform, sets, "overrides"); err != nil {
+			return nil, errors.Errorf("%s: %v", d.name, err)
+		}
+	}
+	c.options = opts
+	if c.baseline, err = readBaseline(opts.BaselineFile); err != nil {
+		return nil, errors.Errorf("failed to read baseline: %v", err)
+	}
+	if c.ignore, err = readIgnore(); err != nil {
+		return nil, errors.Errorf("failed to read %s: %v", git.IgnoreFile, err)
+	}
 
 	token, ok := os.LookupEnv("GITHUB_TOKEN")
-	if ok {
+	if ok && !opts.DryRun {
 		s, err := summarizer.NewGitHubSummarizer(token)
 		if err != nil {
 			return nil, err
@@ -64,60 +299,673 @@ func New() (*Conform, error) {
 		c.summarizer = &summarizer.Noop{}
 	}
 
+	for _, d := range c.directories {
+		d.conform.options = opts
+		d.conform.baseline = c.baseline
+		d.conform.summarizer = c.summarizer
+		d.conform.ignore = c.ignore
+		if d.conform.Timeout == "" {
+			d.conform.Timeout = c.Timeout
+		}
+	}
+
+	return c, nil
+}
+
+// CommitPolicy returns the first commit policy of the configuration, or nil
+// if none is declared.
+func (c *Conform) CommitPolicy() (*commit.Commit, error) {
+	for _, p := range c.Policies {
+		if p.Type != "commit" {
+			continue
+		}
+		policy := &commit.Commit{}
+		if err := mapstructure.Decode(p.Spec, policy); err != nil {
+			return nil, errors.Errorf("Internal error: %v", err)
+		}
+		return policy, nil
+	}
+
+	return nil, nil
+}
+
+// load loads and merges the configuration files, in order of increasing
+// precedence, along with the shared configurations they extend. The
+// configuration of an untrusted repository cannot extend remote
+// configurations or read the environment in its templates.
+func load(files []string, untrusted bool) (*Conform, error) {
+	e, err := newExtender()
+	if err != nil {
+		return nil, err
+	}
+	e.data = newTemplateData()
+	e.untrusted = untrusted
+
+	c := &Conform{}
+	loaded := make([]extendedConfig, 0, len(files))
+	var deprecated []Problem
+	for _, file := range files {
+		configBytes, err := readConfig(file)
+		if err != nil {
+			return nil, err
+		}
+		var renamed []Problem
+		if configBytes, renamed, err = renameDeprecated(file, configBytes); err != nil {
+			return nil, errors.Errorf("%s: %v", file, err)
+		}
+		deprecated = append(deprecated, renamed...)
+		logging.Debug("read configuration", "file", file)
+		// The configuration is validated as written, and decoded expanded.
+		expanded, err := expandConfig(configBytes, e.data, e.funcs())
+		if err != nil {
+			return nil, errors.Errorf("%s: %v", file, err)
+		}
+		fc := &Conform{}
+		if err = yaml.Unmarshal(expanded, fc); err != nil {
+			return nil, err
+		}
+		if err = checkVersion(fc); err != nil {
+			return nil, errors.Errorf("%s: %v", file, err)
+		}
+		if fc, err = e.extend(fc, filepath.Dir(file), nil); err != nil {
+			return nil, err
+		}
+		c = mergeConfig(c, fc)
+		loaded = append(loaded, extendedConfig{source: file, bytes: configBytes})
+	}
+
+	plugins := append([]*PluginDeclaration{}, c.Plugins...)
+	for _, p := range c.Profiles {
+		plugins = append(plugins, p.Plugins...)
+	}
+	for _, p := range plugins {
+		if _, ok := policyMap[p.Name]; ok {
+			return nil, errors.Errorf("Plugin %q conflicts with a builtin policy", p.Name)
+		}
+	}
+	if err = validateTimeouts(c); err != nil {
+		return nil, err
+	}
+
+	for _, ext := range e.loaded {
+		if err = validateConfig(ext.bytes, c); err != nil {
+			return nil, errors.Errorf("%s: %v", ext.source, err)
+		}
+	}
+	for _, file := range loaded {
+		if err = validateConfig(file.bytes, c); err != nil {
+			if len(loaded) > 1 {
+				return nil, errors.Errorf("%s: %v", file.source, err)
+			}
+			return nil, err
+		}
+	}
+	c.deprecated = append(e.deprecated, deprecated...)
+
 	return c, nil
 }
 
-// Enforce enforces all policies defined in the conform.yaml file.
-func (c *Conform) Enforce(setters ...policy.Option) {
-	opts := policy.NewDefaultOptions(setters...)
+// findConfigDir returns the closest directory containing a configuration
+// file, starting from the current directory and stopping at the root of the
+// git repository. If none is found, the current directory is returned.
+func findConfigDir() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
 
-	const padding = 8
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, padding, ' ', 0)
-	fmt.Fprintln(w, "POLICY\tCHECK\tSTATUS\tMESSAGE\t")
+	for dir := wd; ; {
+		for _, name := range configNames {
+			if _, err = os.Stat(filepath.Join(dir, name)); err == nil {
+				return dir, nil
+			}
+		}
+		if _, err = os.Stat(filepath.Join(dir, ".git")); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
 
-	pass := true
-	for _, p := range c.Policies {
-		report, err := c.enforce(p, opts)
+	return wd, nil
+}
+
+// configNames are the names of the configuration file, in order of
+// preference when a directory has more than one.
+var configNames = []string{".conform.yaml", ".conform.toml", ".conform.json", ".conform.cue"}
+
+// localConfigNames are the names of the local override of the configuration
+// file, in order of preference.
+var localConfigNames = []string{".conform.local.yaml", ".conform.local.toml", ".conform.local.json"}
+
+// defaultConfigFiles returns the names of the configuration files of the
+// directory, in order of increasing precedence.
+func defaultConfigFiles(dir string) []string {
+	// Without a configuration file, the error of reading .conform.yaml is
+	// reported.
+	file := firstExisting(dir, configNames)
+	if file == "" {
+		file = configNames[0]
+	}
+	files := []string{file}
+	if local := firstExisting(dir, localConfigNames); local != "" {
+		files = append(files, local)
+	}
+
+	return files
+}
+
+// firstExisting returns the first of the files of the directory that exists,
+// or an empty string if none does.
+func firstExisting(dir string, names []string) string {
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// Locate returns the directory of the default configuration, searched for in
+// the same way as by New, and the names of its configuration files in order
+// of increasing precedence. The files may not exist.
+func Locate() (dir string, files []string, err error) {
+	if dir, err = findConfigDir(); err != nil {
+		return "", nil, err
+	}
+
+	return dir, defaultConfigFiles(dir), nil
+}
+
+// readIgnore reads the .conformignore file at the root of the git repository.
+func readIgnore() (*git.Ignore, error) {
+	g, err := git.NewGit()
+	if err != nil {
+		// Paths are only ignored in git repositories.
+		return nil, nil
+	}
+	root, err := g.Root()
+	if err != nil {
+		return nil, err
+	}
+
+	return git.ReadIgnore(root)
+}
+
+// policyOptions returns the options passed to the policies, including the
+// paths ignored by the .conformignore file.
+func (c *Conform) policyOptions(setters ...policy.Option) *policy.Options {
+	return policy.NewDefaultOptions(append([]policy.Option{policy.WithIgnore(c.ignore)}, setters...)...)
+}
+
+// readConfig reads a configuration file, converted to YAML. CUE files are
+// exported to YAML, and the format of other files is selected by extension.
+func readConfig(name string) ([]byte, error) {
+	if filepath.Ext(name) != ".cue" {
+		configBytes, err := ioutil.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		return convertConfig(name, configBytes)
+	}
+
+	cmd := exec.Command("cue", "export", "--out", "yaml", name)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	configBytes, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Errorf("failed to export %s: %v: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return configBytes, nil
+}
+
+// convertConfig converts a configuration to YAML, according to the extension
+// of its name. TOML is converted, while JSON, as a subset of YAML, and YAML
+// are returned as is.
+func convertConfig(name string, configBytes []byte) ([]byte, error) {
+	if path.Ext(name) != ".toml" {
+		return configBytes, nil
+	}
+
+	tree, err := toml.LoadReader(bytes.NewReader(configBytes))
+	if err != nil {
+		return nil, errors.Errorf("failed to parse %s: %v", name, err)
+	}
+
+	return yaml.Marshal(tree.ToMap())
+}
+
+// Enforce enforces all policies defined in the conform.yaml file, and returns
+// the exit code of the outcome. In dry run mode, the outcome is always a pass.
+func (c *Conform) Enforce(setters ...policy.Option) int {
+	opts := c.policyOptions(setters...)
+
+	t := c.newTable(os.Stdout, "POLICY", "CHECK", "STATUS", "MESSAGE")
+	t.span = c.options.Tracer.Start("conform enforce")
+	r := c.run(t, "", opts, false)
+
+	return c.options.finish(t, r)
+}
+
+// MetricsJob is the job of the metrics pushed to a Pushgateway.
+const MetricsJob = "conform"
+
+// metricsPushTimeout is the timeout of pushing the metrics, so that an
+// unavailable Pushgateway does not hang enforcement.
+const metricsPushTimeout = 30 * time.Second
+
+// webhookTimeout is the timeout of each request to the webhook.
+const webhookTimeout = 30 * time.Second
+
+// publish pushes the metrics of the report to the Pushgateway of the options,
+// if any, publishes the JSON report to the webhook of the options, if any, and
+// notifies failures of protected branches to the chat webhook of the options,
+// if any.
+func (o *Options) publish(outcome Outcome, report *reporter.Report) {
+	if o.MetricsPushURL != "" {
+		client := &http.Client{Timeout: metricsPushTimeout}
+		if err := reporter.Push(client, o.MetricsPushURL, MetricsJob, o.MetricsLabels, report); err != nil {
+			logging.Error("failed to push the metrics", "url", o.MetricsPushURL, "error", err)
+		}
+	}
+
+	if o.WebhookURL != "" {
+		client := &http.Client{Timeout: webhookTimeout}
+		if err := reporter.Publish(client, o.WebhookURL, o.WebhookSecret, report); err != nil {
+			logging.Error("failed to publish the report", "url", o.WebhookURL, "error", err)
+		}
+	}
+
+	if outcome == OutcomeFailure && o.notifies() {
+		client := &http.Client{Timeout: webhookTimeout}
+		if err := reporter.Notify(client, o.NotifyFormat, o.NotifyURL, o.Branch, report); err != nil {
+			logging.Error("failed to send the notification", "format", o.NotifyFormat, "error", err)
+		}
+	}
+}
+
+// finish writes the results of the enforcement, along with their summary, and
+// returns the exit code of the outcome. The results are written by each of
+// the reporters of the options, the text table by default, followed by the
+// statistics of the enforcement, unless quiet, and GitHub Actions annotations
+// if enabled. A Markdown summary is also appended to the step summary file of
+// the options, if any, the results are published to the external services of
+// the options unless this is a dry run, and the spans of the enforcement are
+// exported by the tracer of the options, if any.
+func (o *Options) finish(t *table, r *result) int {
+	progress.Clear()
+
+	outcome := o.outcome(r)
+	t.report.Outcome = string(outcome)
+	t.report.CommitURL = o.CommitURL
+	t.report.SlowThreshold = o.SlowThreshold
+	t.report.MaxIssues = o.MaxIssues
+	t.report.Duration = time.Since(t.start)
+	// The checks less severe than the minimum severity are omitted from every
+	// output.
+	report := t.report.Filter(o.minSeverity())
+	t.span.SetAttributes("conform.outcome", string(outcome))
+	if outcome != OutcomePass {
+		t.span.SetError(string(outcome))
+	}
+	t.span.End()
+	if err := o.Tracer.Export(); err != nil {
+		logging.Error("failed to export the spans", "error", err)
+	}
+	if o.StepSummary != "" {
+		if err := writeStepSummary(o.StepSummary, report); err != nil {
+			logging.Error("failed to write the step summary", "file", o.StepSummary, "error", err)
+		}
+	}
+
+	if !o.DryRun {
+		o.publish(outcome, report)
+	}
+
+	table := false
+	for _, d := range o.reporters() {
+		rep, ok := reporter.Get(d.Format)
+		if !ok {
+			table = true
+			continue
+		}
+		if err := writeReport(rep, report, d.File); err != nil {
+			logging.Error("failed to write the report", "format", d.Format, "file", d.File, "error", err)
+		}
+	}
+	if !table {
+		return o.ExitCode(outcome)
+	}
+
+	// nolint: errcheck
+	t.flush()
+
+	if !o.Quiet {
+		// nolint: errcheck
+		writeSummary(os.Stdout, report.Summary())
+	}
+	if o.Annotations {
+		if err := (reporter.GitHub{}).Write(os.Stdout, report); err != nil {
+			logging.Error("failed to write the annotations", "error", err)
+		}
+	}
+	if r.stopped && r.notRun != 0 {
+		fmt.Println(notRunSummary(r.notRun))
+	}
+	if check := r.explainable(); check != "" && (r.failed || r.warned) && !o.Quiet {
+		fmt.Printf("Run conform explain %q to see how to fix the violations of a check\n", check)
+	}
+
+	return o.ExitCode(outcome)
+}
+
+// writeStepSummary appends the Markdown summary of the report to the step
+// summary file. Every step of a job appends to the same file.
+func writeStepSummary(name string, r *reporter.Report) error {
+	f, err := os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if err = (reporter.Markdown{}).Write(f, r); err != nil {
+		// nolint: errcheck
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// Baseline enforces all policies, ignoring the current baseline, and writes
+// the violations found to the baseline file.
+func (c *Conform) Baseline(setters ...policy.Option) error {
+	opts := c.policyOptions(setters...)
+
+	c.baseline = nil
+	for _, d := range c.directories {
+		d.conform.baseline = nil
+	}
+
+	t := c.newTable(os.Stdout, "POLICY", "CHECK", "STATUS", "MESSAGE")
+	r := c.run(t, "", opts, false)
+	progress.Clear()
+
+	// nolint: errcheck
+	t.flush()
+
+	return (&Baseline{Violations: r.violations}).write(c.options.BaselineFile)
+}
+
+// result is the result of enforcing policies.
+type result struct {
+	// failed is true if a violation fails enforcement.
+	failed bool
+	// warned is true if a violation of a warn check is reported.
+	warned     bool
+	violations []Violation
+	// stopped is true if enforcement stopped at the first failure, in fail
+	// fast mode.
+	stopped bool
+	// notRun is the number of checks not run because enforcement stopped.
+	notRun int
+	// invalid is true if the configuration of a repository of a batch could
+	// not be loaded, so that the results are incomplete.
+	invalid bool
+	// errored is true if a policy failed to run, so that the results are
+	// incomplete.
+	errored bool
+}
+
+// add adds the result of enforcing other policies.
+func (r *result) add(other *result) {
+	r.failed = r.failed || other.failed
+	r.warned = r.warned || other.warned
+	r.violations = append(r.violations, other.violations...)
+	r.stopped = r.stopped || other.stopped
+	r.notRun += other.notRun
+	r.invalid = r.invalid || other.invalid
+	r.errored = r.errored || other.errored
+}
+
+// explainable returns the name of the first check violated that is
+// explained by the explain command, if any.
+func (r *result) explainable() string {
+	for _, v := range r.violations {
+		// The policies of subdirectories are prefixed with the name of the
+		// subdirectory.
+		policyType := v.Policy[strings.LastIndex(v.Policy, ":")+1:]
+		if Explains(policyType, v.Check) {
+			return v.Check
+		}
+	}
+
+	return ""
+}
+
+// run enforces the policies of the configuration, and of the subdirectories
+// touched by the changes being enforced, writing the results prefixed by
+// prefix to w. If stopped, enforcement already stopped at a failure.
+func (c *Conform) run(t *table, prefix string, opts *policy.Options, stopped bool) *result {
+	r := c.enforcePolicies(t, prefix, opts, stopped)
+
+	if len(c.directories) != 0 {
+		changed, err := changedPaths(opts)
 		if err != nil {
-			log.Fatal(err)
+			logging.Error("failed to find the changed paths", "error", err)
+			r.errored = true
+			return r
 		}
-		for _, check := range report.Checks() {
+		for _, d := range c.directories {
+			if !d.changed(changed) {
+				continue
+			}
+			if err = os.Chdir(d.path); err != nil {
+				logging.Error("failed to change directory", "dir", d.path, "error", err)
+				r.errored = true
+				continue
+			}
+			r.add(d.conform.enforcePolicies(t, prefix+d.name+":", opts, r.stopped))
+			if err = os.Chdir(d.root); err != nil {
+				logging.Error("failed to change directory", "dir", d.root, "error", err)
+				r.errored = true
+				return r
+			}
+		}
+	}
+
+	return r
+}
+
+// enforcePolicies enforces the policies, writing the results prefixed by
+// prefix to w. Violations of checks with a severity other than error do not fail,
+// unless warnings are promoted to errors in strict mode, and neither do
+// suppressed violations or violations in the baseline. In quiet mode, only
+// the violations that are not suppressed or in the baseline are written. The
+// checks less severe than the minimum severity are not written, but their
+// violations still fail. In fail fast mode, the policies after the first
+// failure, or all of them if enforcement already stopped, are not run and
+// their checks are reported as skipped.
+func (c *Conform) enforcePolicies(t *table, prefix string, opts *policy.Options, stopped bool) *result {
+	s, err := newSuppressor(opts)
+	if err != nil {
+		logging.Error("failed to read the suppressions", "error", err)
+		return &result{errored: true}
+	}
+
+	l, err := newLocator()
+	if err != nil {
+		logging.Error("failed to locate the working directory", "error", err)
+		return &result{errored: true}
+	}
+	r, err := c.lint(t, prefix, opts)
+	if err != nil {
+		logging.Error("failed to lint the configuration", "error", err)
+		return &result{errored: true}
+	}
+	r.stopped = stopped || (r.failed && c.options.FailFast)
+	for i, p := range c.Policies {
+		name := prefix + p.Type
+		if !c.options.selectsPolicy(p.Type) {
+			logging.Debug("policy not selected", "policy", name)
+			continue
+		}
+		if r.stopped {
+			logging.Debug("policy not run after failure", "policy", name)
+			r.notRun += c.skipNotRun(t, name, p)
+			continue
+		}
+		progress.Policy(name, i+1, len(c.Policies))
+		span := t.span.Start("policy "+name, "conform.policy.name", name, "conform.policy.type", p.Type)
+		start := time.Now()
+		report, err := c.enforceTimeout(p, opts)
+		if err != nil {
+			// The other policies are still enforced, but the run is
+			// incomplete.
+			logging.Error("failed to enforce the policy", "policy", name, "error", err)
+			t.row(name, "<none>", "ERROR", err.Error())
+			r.errored = true
+			span.SetError(err.Error())
+			span.End()
+			continue
+		}
+		duration := time.Since(start)
+		logging.Info("enforced policy", "policy", name, "checks", len(report.Checks()), "duration", duration)
+		t.report.Policies = append(t.report.Policies, &reporter.Policy{Name: name, Type: p.Type, Duration: duration})
+		// checks are the results of the checks of the report, nil for the
+		// checks that are not selected.
+		checks := make([]*reporter.Check, len(report.Checks()))
+		durations := report.Durations(start)
+		for j, check := range report.Checks() {
+			// A policy that times out fails even if its other checks are
+			// selected.
+			if _, timedOut := check.(timeoutCheck); !timedOut && !c.options.selectsCheck(check.Name()) {
+				continue
+			}
+			severity := p.severity(check.Name())
+			if c.options.Strict && severity == policy.SeverityWarn {
+				severity = policy.SeverityError
+			}
+			rc := &reporter.Check{
+				Policy:      name,
+				Name:        check.Name(),
+				Description: describeCheck(p.Type, check.Name()),
+				Severity:    severity,
+				Message:     check.Message(),
+				Duration:    durations[j],
+			}
+			rc.Slow = c.options.SlowThreshold != 0 && rc.Duration > c.options.SlowThreshold
+			checks[j] = rc
+			if c.options.skips(p.Type, check.Name()) {
+				if !c.options.Quiet && c.options.shows(severity) {
+					t.row(name, check.Name(), "SKIPPED", "<none>")
+				}
+				rc.Status = reporter.StatusSkipped
+				t.report.Add(rc)
+				continue
+			}
 			if len(check.Errors()) != 0 {
+				failed := false
 				for _, err := range check.Errors() {
-					fmt.Fprintf(w, "%s\t%s\t%s\t%v\t\n", p.Type, check.Name(), "FAILED", err)
+					if directive := s.suppressed(p.Type, check.Name(), err); directive != "" {
+						logging.Debug("suppressed violation", "policy", name, "check", check.Name(), "directive", directive)
+						rc.Violations = append(rc.Violations, l.violation(err, reporter.StatusSuppressed, directive))
+						continue
+					}
+					rv := l.violation(err, severity.Status(), "")
+					v := Violation{Policy: name, Check: check.Name(), Message: rv.Message, File: rv.File, Commit: rv.Commit}
+					r.violations = append(r.violations, v)
+					if c.baseline.contains(v) {
+						rv.Status = reporter.StatusBaseline
+					} else if severity == policy.SeverityError {
+						failed = true
+					} else if severity == policy.SeverityWarn {
+						r.warned = true
+					}
+					rc.Violations = append(rc.Violations, rv)
 				}
-				if err := c.summarizer.SetStatus("failure", p.Type, check.Name(), check.Message()); err != nil {
-					log.Printf("WARNING: summary failed: %+v", err)
+				t.report.Add(rc)
+				if c.options.shows(severity) {
+					t.violations(rc, c.options.Quiet, c.options.MaxIssues)
+				}
+				state := "success"
+				if failed {
+					state = "failure"
+					r.failed = true
+					r.stopped = c.options.FailFast
+				}
+				if err := c.summarizer.SetStatus(state, name, check.Name(), check.Message()); err != nil {
+					logging.Warn("failed to set the status", "policy", name, "check", check.Name(), "error", err)
 				}
-				pass = false
 			} else {
-				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\n", p.Type, check.Name(), "PASS", "<none>")
-				if err := c.summarizer.SetStatus("success", p.Type, check.Name(), check.Message()); err != nil {
-					log.Printf("WARNING: summary failed: %+v", err)
+				if !c.options.Quiet && c.options.shows(severity) {
+					t.row(name, check.Name(), "PASS", "<none>")
+				}
+				t.report.Add(rc)
+				if err := c.summarizer.SetStatus("success", name, check.Name(), check.Message()); err != nil {
+					logging.Warn("failed to set the status", "policy", name, "check", check.Name(), "error", err)
 				}
 			}
 		}
+		if c.options.patches() {
+			c.patch(t, l, name, p, opts, checks)
+		}
+		traceChecks(span, start, durations, checks)
+		span.End()
 	}
 
-	// nolint: errcheck
-	w.Flush()
+	return r
+}
 
-	if !pass {
-		os.Exit(1)
+// defines reports whether the policy type is builtin or implemented by a
+// plugin of the configuration.
+func (c *Conform) defines(t string) bool {
+	if _, ok := policyMap[t]; ok {
+		return true
+	}
+	for _, p := range c.Plugins {
+		if p.Name == t {
+			return true
+		}
 	}
+
+	return false
 }
 
 func (c *Conform) enforce(declaration *PolicyDeclaration, opts *policy.Options) (*policy.Report, error) {
-	if _, ok := policyMap[declaration.Type]; !ok {
-		return nil, errors.Errorf("Policy %q is not defined", declaration.Type)
+	for _, p := range c.Plugins {
+		if p.Name == declaration.Type {
+			return (&pluginPolicy{plugin: p, spec: declaration.Spec}).Compliance(opts)
+		}
 	}
 
-	p := policyMap[declaration.Type]
-
-	err := mapstructure.Decode(declaration.Spec, p)
+	p, ok, err := newPolicy(declaration)
 	if err != nil {
-		return nil, errors.Errorf("Internal error: %v", err)
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.Errorf("Policy %q is not defined", declaration.Type)
 	}
 
 	return p.Compliance(opts)
 }
+
+// newPolicy returns a new builtin policy of the type of the declaration,
+// decoded from its spec, and whether the type is builtin. The policies of
+// policyMap are never decoded into, since they would keep the fields of the
+// previous specs of their type.
+func newPolicy(declaration *PolicyDeclaration) (policy.Policy, bool, error) {
+	p, ok := policyMap[declaration.Type]
+	if !ok {
+		return nil, false, nil
+	}
+	p = reflect.New(reflect.TypeOf(p).Elem()).Interface().(policy.Policy)
+	if err := mapstructure.Decode(declaration.Spec, p); err != nil {
+		return nil, true, errors.Errorf("Internal error: %v", err)
+	}
+
+	return p, true, nil
+}
@@ -0,0 +1,436 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"strings"
+	"time"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/autonomy/conform/internal/reporter"
+	"github.com/autonomy/conform/internal/tracing"
+)
+
+// Option is a functional option used to pass in arguments to the enforcer.
+type Option func(*Options)
+
+// Options defines the set of options available to the enforcer.
+type Options struct {
+	ConfigFiles      []string
+	Strict           bool
+	BaselineFile     string
+	Policies         []string
+	Checks           []string
+	Skip             []string
+	DryRun           bool
+	Quiet            bool
+	StrictWarnings   bool
+	ExitCodes        map[Outcome]int
+	Theme            string
+	Profile          string
+	PreferredProfile string
+	Set              []string
+	Timeout          time.Duration
+	FailFast         bool
+	Output           string
+	Annotations      bool
+	StepSummary      string
+	OutputFile       string
+	CommitURL        string
+	MetricsPushURL   string
+	MetricsLabels    map[string]string
+	Tracer           *tracing.Tracer
+	SlowThreshold    time.Duration
+	WebhookURL       string
+	WebhookSecret    string
+	NotifyURL        string
+	NotifyFormat     string
+	MaxIssues        int
+	MinSeverity      policy.Severity
+	// NotifyBranches are the patterns of the protected branches whose
+	// failures are notified, DefaultNotifyBranches if nil.
+	NotifyBranches []string
+	// Branch is the branch enforced, if known.
+	Branch string
+	// Reporters are the reporters of the configuration, which replace the
+	// output format and file when set.
+	Reporters []*ReporterDeclaration
+	// Untrusted refuses the configurations that declare policies that run
+	// commands, or plugins.
+	Untrusted bool
+}
+
+// WithConfigFiles sets the configuration files, in order of increasing
+// precedence, replacing the default configuration files.
+func WithConfigFiles(o []string) Option {
+	return func(args *Options) {
+		args.ConfigFiles = o
+	}
+}
+
+// WithStrict promotes warnings to errors.
+func WithStrict(o bool) Option {
+	return func(args *Options) {
+		args.Strict = o
+	}
+}
+
+// WithBaselineFile sets the path of the baseline file.
+func WithBaselineFile(o string) Option {
+	return func(args *Options) {
+		args.BaselineFile = o
+	}
+}
+
+// WithPolicies restricts enforcement to the policies of the specified types.
+func WithPolicies(o []string) Option {
+	return func(args *Options) {
+		args.Policies = o
+	}
+}
+
+// WithChecks restricts enforcement to the checks with the specified names.
+func WithChecks(o []string) Option {
+	return func(args *Options) {
+		args.Checks = o
+	}
+}
+
+// WithSkip skips the checks with the specified names, or all checks of the
+// policies of the specified types.
+func WithSkip(o []string) Option {
+	return func(args *Options) {
+		args.Skip = o
+	}
+}
+
+// WithDryRun reports the results of all policies without failing, and
+// without posting statuses, metrics, reports, or notifications.
+func WithDryRun(o bool) Option {
+	return func(args *Options) {
+		args.DryRun = o
+	}
+}
+
+// WithQuiet only reports violations, omitting the checks that pass.
+func WithQuiet(o bool) Option {
+	return func(args *Options) {
+		args.Quiet = o
+	}
+}
+
+// WithStrictWarnings distinguishes runs in which warnings are reported, but
+// no violation fails, by the exit code of OutcomeWarnings.
+func WithStrictWarnings(o bool) Option {
+	return func(args *Options) {
+		args.StrictWarnings = o
+	}
+}
+
+// WithExitCodes overrides the exit codes of outcomes.
+func WithExitCodes(o map[Outcome]int) Option {
+	return func(args *Options) {
+		args.ExitCodes = o
+	}
+}
+
+// WithTheme colors the results with the theme of the specified name, and
+// groups them by policy. Results are not colored when the name is empty.
+func WithTheme(o string) Option {
+	return func(args *Options) {
+		args.Theme = o
+	}
+}
+
+// WithProfile applies the profile of the specified name of the
+// configuration.
+func WithProfile(o string) Option {
+	return func(args *Options) {
+		args.Profile = o
+	}
+}
+
+// WithPreferredProfile applies the profile of the specified name, when no
+// profile is set and the configuration defines it, e.g. the default profile
+// of the user.
+func WithPreferredProfile(o string) Option {
+	return func(args *Options) {
+		args.PreferredProfile = o
+	}
+}
+
+// WithSet overrides keys of the policies for a single run, as
+// <type>.<key>=<value>.
+func WithSet(o []string) Option {
+	return func(args *Options) {
+		args.Set = o
+	}
+}
+
+// WithTimeout sets the timeout of the policies that do not declare their own,
+// overriding the timeout of the configuration.
+func WithTimeout(o time.Duration) Option {
+	return func(args *Options) {
+		args.Timeout = o
+	}
+}
+
+// WithFailFast stops enforcement after the policy of the first failing check.
+// The checks of the policies that are not run are reported as skipped.
+func WithFailFast(o bool) Option {
+	return func(args *Options) {
+		args.FailFast = o
+	}
+}
+
+// WithOutput writes the results in the format of the specified name, rather
+// than as a text table.
+func WithOutput(o string) Option {
+	return func(args *Options) {
+		args.Output = o
+	}
+}
+
+// WithOutputFile writes the results to the file at the specified path,
+// rather than to stdout.
+func WithOutputFile(o string) Option {
+	return func(args *Options) {
+		args.OutputFile = o
+	}
+}
+
+// WithCommitURL sets the URL of the commits of the repository, to which the
+// SHA of a commit is appended to link to it in reports.
+func WithCommitURL(o string) Option {
+	return func(args *Options) {
+		args.CommitURL = o
+	}
+}
+
+// WithMetricsPushURL pushes the metrics of the enforcement to the Prometheus
+// Pushgateway at the specified URL.
+func WithMetricsPushURL(o string) Option {
+	return func(args *Options) {
+		args.MetricsPushURL = o
+	}
+}
+
+// WithMetricsLabels sets the labels of the group the metrics are pushed to,
+// in addition to the job.
+func WithMetricsLabels(o map[string]string) Option {
+	return func(args *Options) {
+		args.MetricsLabels = o
+	}
+}
+
+// WithTracer records the spans of the enforcement, of each policy, and of
+// each check with the tracer, which exports them once enforcement is done.
+func WithTracer(o *tracing.Tracer) Option {
+	return func(args *Options) {
+		args.Tracer = o
+	}
+}
+
+// WithSlowThreshold highlights the checks that take longer than the
+// specified duration in the results.
+func WithSlowThreshold(o time.Duration) Option {
+	return func(args *Options) {
+		args.SlowThreshold = o
+	}
+}
+
+// WithWebhookURL publishes the JSON report of the enforcement to the webhook
+// at the specified URL.
+func WithWebhookURL(o string) Option {
+	return func(args *Options) {
+		args.WebhookURL = o
+	}
+}
+
+// WithWebhookSecret sets the secret the requests to the webhook are signed
+// with.
+func WithWebhookSecret(o string) Option {
+	return func(args *Options) {
+		args.WebhookSecret = o
+	}
+}
+
+// WithNotifyURL notifies the Slack or Microsoft Teams incoming webhook at the
+// specified URL of the failures of the enforcement of protected branches.
+func WithNotifyURL(o string) Option {
+	return func(args *Options) {
+		args.NotifyURL = o
+	}
+}
+
+// WithNotifyFormat sets the format of the notifications, slack or teams,
+// rather than detecting it from the URL of the webhook.
+func WithNotifyFormat(o string) Option {
+	return func(args *Options) {
+		args.NotifyFormat = o
+	}
+}
+
+// WithNotifyBranches sets the patterns of the protected branches whose
+// failures are notified.
+func WithNotifyBranches(o []string) Option {
+	return func(args *Options) {
+		args.NotifyBranches = o
+	}
+}
+
+// WithBranch sets the branch enforced.
+func WithBranch(o string) Option {
+	return func(args *Options) {
+		args.Branch = o
+	}
+}
+
+// WithMaxIssues caps the number of entries written for each check by the
+// table and the formats read in CI logs, each entry grouping identical
+// violations. Zero does not cap them.
+func WithMaxIssues(o int) Option {
+	return func(args *Options) {
+		args.MaxIssues = o
+	}
+}
+
+// WithMinSeverity omits the results of the checks less severe than the
+// severity from the output. Enforcement still fails on the violations that are
+// omitted.
+func WithMinSeverity(o policy.Severity) Option {
+	return func(args *Options) {
+		args.MinSeverity = o
+	}
+}
+
+// WithAnnotations writes the violations as GitHub Actions workflow commands
+// after the text table, so that they annotate the pull request.
+func WithAnnotations(o bool) Option {
+	return func(args *Options) {
+		args.Annotations = o
+	}
+}
+
+// WithStepSummary appends the results, as a Markdown summary, to the GitHub
+// Actions step summary file at the specified path.
+func WithStepSummary(o string) Option {
+	return func(args *Options) {
+		args.StepSummary = o
+	}
+}
+
+// WithUntrusted refuses the configurations that declare policies that run
+// commands, or plugins, such as that of a remote repository.
+func WithUntrusted(o bool) Option {
+	return func(args *Options) {
+		args.Untrusted = o
+	}
+}
+
+// NewDefaultOptions initializes a Options struct with default values.
+func NewDefaultOptions(setters ...Option) *Options {
+	opts := &Options{
+		ConfigFiles:      nil,
+		Strict:           false,
+		BaselineFile:     DefaultBaselineFile,
+		Policies:         nil,
+		Checks:           nil,
+		Skip:             nil,
+		DryRun:           false,
+		Quiet:            false,
+		StrictWarnings:   false,
+		ExitCodes:        nil,
+		Theme:            "",
+		Profile:          "",
+		PreferredProfile: "",
+		Set:              nil,
+		Timeout:          0,
+		FailFast:         false,
+		Output:           reporter.Table,
+		Annotations:      false,
+		StepSummary:      "",
+		OutputFile:       "",
+		CommitURL:        "",
+		MetricsPushURL:   "",
+		MetricsLabels:    nil,
+		Tracer:           nil,
+		SlowThreshold:    0,
+		WebhookURL:       "",
+		WebhookSecret:    "",
+		NotifyURL:        "",
+		NotifyFormat:     "",
+		NotifyBranches:   nil,
+		Branch:           "",
+		MaxIssues:        0,
+		MinSeverity:      "",
+		Reporters:        nil,
+	}
+
+	for _, setter := range setters {
+		setter(opts)
+	}
+
+	return opts
+}
+
+// selectsPolicy reports whether the policy of the specified type should be
+// enforced. Policies of builtin types are skipped when none of their checks
+// are selected.
+func (o *Options) selectsPolicy(t string) bool {
+	if len(o.Policies) != 0 && !containsFold(o.Policies, t) {
+		return false
+	}
+	d, ok := catalog[t]
+	if len(o.Checks) == 0 || !ok || namedChecks[t] {
+		return true
+	}
+	for _, check := range d.Checks {
+		if containsFold(o.Checks, check.Name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// selectsCheck reports whether the results of the check with the specified
+// name should be reported.
+func (o *Options) selectsCheck(name string) bool {
+	return len(o.Checks) == 0 || containsFold(o.Checks, name)
+}
+
+// skips reports whether the check of the policy of the specified type should
+// be skipped.
+func (o *Options) skips(t, check string) bool {
+	return containsFold(o.Skip, t) || containsFold(o.Skip, check)
+}
+
+// shows reports whether the results of the checks of the severity are
+// written.
+func (o *Options) shows(severity policy.Severity) bool {
+	return severity.AtLeast(o.minSeverity())
+}
+
+// minSeverity returns the severity of the least severe checks written. The
+// checks that decide the outcome are never omitted, so that with strict
+// warnings the warn checks are written whatever the minimum severity.
+func (o *Options) minSeverity() policy.Severity {
+	if o.StrictWarnings && !policy.SeverityWarn.AtLeast(o.MinSeverity) {
+		return policy.SeverityWarn
+	}
+
+	return o.MinSeverity
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
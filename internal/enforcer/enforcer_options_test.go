@@ -0,0 +1,129 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestSelectsPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  *Options
+		policy   string
+		expected bool
+	}{
+		{
+			name:     "All",
+			options:  NewDefaultOptions(),
+			policy:   "commit",
+			expected: true,
+		},
+		{
+			name:     "SelectedPolicy",
+			options:  NewDefaultOptions(WithPolicies([]string{"commit", "license"})),
+			policy:   "license",
+			expected: true,
+		},
+		{
+			name:     "UnselectedPolicy",
+			options:  NewDefaultOptions(WithPolicies([]string{"commit"})),
+			policy:   "license",
+			expected: false,
+		},
+		{
+			name:     "SelectedCheck",
+			options:  NewDefaultOptions(WithChecks([]string{"header length"})),
+			policy:   "commit",
+			expected: true,
+		},
+		{
+			name:     "UnselectedCheck",
+			options:  NewDefaultOptions(WithChecks([]string{"Header Length"})),
+			policy:   "license",
+			expected: false,
+		},
+		{
+			name:     "NamedCheck",
+			options:  NewDefaultOptions(WithChecks([]string{"Lint"})),
+			policy:   "exec",
+			expected: true,
+		},
+		{
+			name:     "Plugin",
+			options:  NewDefaultOptions(WithChecks([]string{"Lint"})),
+			policy:   "custom",
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(tt *testing.T) {
+			if selected := test.options.selectsPolicy(test.policy); selected != test.expected {
+				tt.Errorf("Expected %v, got %v", test.expected, selected)
+			}
+		})
+	}
+}
+
+func TestSkips(t *testing.T) {
+	options := NewDefaultOptions(WithSkip([]string{"dco", "license"}))
+
+	tests := []struct {
+		policy   string
+		check    string
+		expected bool
+	}{
+		{policy: "commit", check: "DCO", expected: true},
+		{policy: "commit", check: "Header Length", expected: false},
+		{policy: "license", check: "File Header", expected: true},
+	}
+
+	for _, test := range tests {
+		if skipped := options.skips(test.policy, test.check); skipped != test.expected {
+			t.Errorf("Expected %s %q to be skipped: %v, got %v", test.policy, test.check, test.expected, skipped)
+		}
+	}
+}
+
+func TestShows(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  *Options
+		severity policy.Severity
+		expected bool
+	}{
+		{name: "All", options: NewDefaultOptions(), severity: policy.SeverityInfo, expected: true},
+		{name: "LessSevere", options: NewDefaultOptions(WithMinSeverity(policy.SeverityWarn)), severity: policy.SeverityInfo, expected: false},
+		{name: "AsSevere", options: NewDefaultOptions(WithMinSeverity(policy.SeverityWarn)), severity: policy.SeverityWarn, expected: true},
+		{name: "Warnings", options: NewDefaultOptions(WithMinSeverity(policy.SeverityError)), severity: policy.SeverityWarn, expected: false},
+		{
+			name:     "StrictWarnings",
+			options:  NewDefaultOptions(WithMinSeverity(policy.SeverityError), WithStrictWarnings(true)),
+			severity: policy.SeverityWarn,
+			expected: true,
+		},
+		{
+			name:     "StrictWarningsInfo",
+			options:  NewDefaultOptions(WithMinSeverity(policy.SeverityError), WithStrictWarnings(true)),
+			severity: policy.SeverityInfo,
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if shown := test.options.shows(test.severity); shown != test.expected {
+				t.Errorf("Expected %s checks to be shown: %v, got %v", test.severity, test.expected, shown)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/autonomy/conform/internal/reporter"
+)
+
+func TestEnforceDeclarationsOfOneType(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.Chdir(wd)
+
+	if err = ioutil.WriteFile("a.go", []byte("package a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The second declaration must not keep the skipPaths of the first.
+	declarations := []*PolicyDeclaration{
+		{Type: "license", Spec: map[interface{}]interface{}{
+			"includeSuffixes": []interface{}{".go"},
+			"skipPaths":       []interface{}{"a.go"},
+			"header":          "// Copyright Acme\n",
+		}},
+		{Type: "license", Spec: map[interface{}]interface{}{
+			"includeSuffixes": []interface{}{".go"},
+			"header":          "// Copyright Acme\n",
+		}},
+	}
+	expected := []int{0, 1}
+
+	c := &Conform{Policies: declarations, options: NewDefaultOptions()}
+	for i, declaration := range declarations {
+		report, err := c.enforce(declaration, policy.NewDefaultOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		violations := 0
+		for _, check := range report.Checks() {
+			violations += len(check.Errors())
+		}
+		if violations != expected[i] {
+			t.Errorf("Expected %d violations of declaration %d, got %d", expected[i], i, violations)
+		}
+	}
+}
+
+func TestFinishDryRun(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r.URL.Path)
+	}))
+	defer server.Close()
+
+	for _, test := range []struct {
+		Name     string
+		DryRun   bool
+		Expected int
+	}{
+		{"Enforce", false, 3},
+		{"DryRun", true, 0},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			requests = nil
+			o := NewDefaultOptions(
+				WithDryRun(test.DryRun),
+				WithMetricsPushURL(server.URL+"/metrics"),
+				WithWebhookURL(server.URL+"/webhook"),
+				WithNotifyURL(server.URL+"/notify"),
+				WithNotifyFormat(reporter.NotifySlack),
+				WithBranch("main"),
+				WithQuiet(true),
+			)
+			o.finish(newTable(ioutil.Discard, nil), &result{failed: true})
+			if len(requests) != test.Expected {
+				tt.Errorf("Expected %d requests, got %v", test.Expected, requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/autonomy/conform/internal/logging"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// EnvPrefix is the prefix of the environment variables that override the
+// configuration.
+const EnvPrefix = "CONFORM_"
+
+// applyEnv overrides the configuration with the environment variables named
+// CONFORM_<TYPE>_<KEY>, where the keys of nested specs are separated by
+// underscores (e.g. CONFORM_COMMIT_CONVENTIONAL_TYPES). Names are matched
+// ignoring case, and the override applies to every policy of the type. The
+// key SEVERITY overrides the severity of the policies. Values are parsed as
+// YAML, except that values of string keys are used as is, and values of
+// string list keys may also be comma separated. Variables that do not name a
+// declared policy are ignored. The policies overridden are validated against
+// the schema.
+func applyEnv(c *Conform, environ []string) error {
+	sort.Strings(environ)
+
+	var overrides []specOverride
+	for _, kv := range environ {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[0], EnvPrefix) {
+			continue
+		}
+		segments := strings.Split(strings.TrimPrefix(parts[0], EnvPrefix), "_")
+		if len(segments) < 2 {
+			continue
+		}
+		overrides = append(overrides, specOverride{name: parts[0], segments: segments, value: parts[1]})
+	}
+
+	return applyOverrides(c, overrides, "environment overrides")
+}
+
+// parseSet parses the values of the --set flag, of the form
+// <type>.<key>=<value>, where the keys of nested specs are separated by dots
+// (e.g. commit.conventional.types=chore,docs). They are otherwise applied as
+// the environment variables of applyEnv.
+func parseSet(values []string) ([]specOverride, error) {
+	overrides := make([]specOverride, 0, len(values))
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		segments := strings.Split(parts[0], ".")
+		if len(parts) != 2 || len(segments) < 2 {
+			return nil, errors.Errorf("Invalid override %q: must be <type>.<key>=<value>", value)
+		}
+		overrides = append(overrides, specOverride{name: "--set " + parts[0], segments: segments, value: parts[1]})
+	}
+
+	return overrides, nil
+}
+
+// specOverride is an override of a key of the specs of the policies of a type.
+type specOverride struct {
+	// name identifies the override in errors, e.g. the name of the
+	// environment variable.
+	name string
+	// segments are the type of the policies followed by the path of the key.
+	segments []string
+	value    string
+}
+
+// declares reports whether the configuration declares a policy of the type
+// overridden.
+func (o specOverride) declares(c *Conform) bool {
+	for _, p := range c.Policies {
+		if strings.EqualFold(p.Type, o.segments[0]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applyOverrides applies the overrides to the policies of the configuration,
+// in order, and validates the policies overridden against the schema.
+// Overrides of types that are not declared are ignored.
+func applyOverrides(c *Conform, overrides []specOverride, source string) error {
+	var overridden []*PolicyDeclaration
+	for _, o := range overrides {
+		var declarations []*PolicyDeclaration
+		for _, p := range c.Policies {
+			if strings.EqualFold(p.Type, o.segments[0]) {
+				declarations = append(declarations, p)
+			}
+		}
+		if len(declarations) == 0 {
+			logging.Debug("ignored override", "name", o.name)
+			continue
+		}
+
+		if len(o.segments) == 2 && strings.EqualFold(o.segments[1], "severity") {
+			severity := policy.Severity(strings.ToLower(o.value))
+			if !validSeverity(severity) {
+				return errors.Errorf("%s: invalid severity %q", o.name, o.value)
+			}
+			for _, p := range declarations {
+				p.Severity = severity
+			}
+			logging.Info("overrode configuration", "name", o.name)
+			continue
+		}
+
+		for _, p := range declarations {
+			keys, v, err := envValue(p.Type, o.segments[1:], o.value)
+			if err != nil {
+				return errors.Errorf("%s: %v", o.name, err)
+			}
+			p.Spec = setValue(p.Spec, keys, v)
+			overridden = append(overridden, p)
+		}
+		logging.Info("overrode configuration", "name", o.name)
+	}
+
+	if len(overridden) == 0 {
+		return nil
+	}
+
+	declarations := make([]map[string]interface{}, 0, len(overridden))
+	for _, p := range overridden {
+		declarations = append(declarations, map[string]interface{}{"type": p.Type, "spec": p.Spec})
+	}
+	configBytes, err := yaml.Marshal(map[string]interface{}{"policies": declarations})
+	if err != nil {
+		return err
+	}
+	if err = validateConfig(configBytes, c); err != nil {
+		return errors.Errorf("%s: %v", source, err)
+	}
+
+	return nil
+}
+
+// envValue resolves the segments of the name of an environment variable to
+// the keys of the spec of the policy type, and parses the value according to
+// the type of the key.
+func envValue(policyType string, segments []string, value string) ([]interface{}, interface{}, error) {
+	p, ok := policyMap[policyType]
+	if !ok {
+		// The spec of a plugin is unknown, so that keys are lower case.
+		keys := make([]interface{}, 0, len(segments))
+		for _, segment := range segments {
+			keys = append(keys, strings.ToLower(segment))
+		}
+		v, err := parseValue(nil, value)
+		return keys, v, err
+	}
+
+	t := reflect.TypeOf(p)
+	keys := make([]interface{}, 0, len(segments))
+	for i := 0; i < len(segments); {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			n, key, field, ok := matchField(t, segments[i:])
+			if !ok {
+				return nil, nil, errors.Errorf("unknown key %q", segments[i])
+			}
+			keys = append(keys, key)
+			t = field
+			i += n
+		case reflect.Map:
+			keys = append(keys, strings.ToLower(segments[i]))
+			t = t.Elem()
+			i++
+		default:
+			return nil, nil, errors.Errorf("key %q is not an object", segments[i])
+		}
+	}
+
+	v, err := parseValue(t, value)
+	return keys, v, err
+}
+
+// matchField matches the longest run of segments that names a field of the
+// struct, so that the words of a key may be separated like nested keys (e.g.
+// header.length for headerLength). It returns the number of segments
+// matched, and the key and type of the field.
+func matchField(t reflect.Type, segments []string) (int, string, reflect.Type, bool) {
+	for n := len(segments); n > 0; n-- {
+		if key, field, ok := findField(t, strings.Join(segments[:n], "")); ok {
+			return n, key, field, true
+		}
+	}
+
+	return 0, "", nil, false
+}
+
+// findField returns the key and type of the field of the struct whose
+// mapstructure tag matches the name, ignoring case.
+func findField(t reflect.Type, name string) (string, reflect.Type, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		parts := strings.Split(field.Tag.Get("mapstructure"), ",")
+		if len(parts) > 1 && parts[1] == "squash" {
+			if key, ft, ok := findField(field.Type, name); ok {
+				return key, ft, true
+			}
+			continue
+		}
+		if parts[0] != "" && parts[0] != "-" && strings.EqualFold(parts[0], name) {
+			return parts[0], field.Type, true
+		}
+	}
+
+	return "", nil, false
+}
+
+func parseValue(t reflect.Type, value string) (interface{}, error) {
+	if t != nil {
+		switch {
+		case t.Kind() == reflect.String:
+			return value, nil
+		case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String && !strings.HasPrefix(strings.TrimSpace(value), "["):
+			values := []interface{}{}
+			for _, v := range strings.Split(value, ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					values = append(values, v)
+				}
+			}
+			return values, nil
+		}
+	}
+
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(value), &v); err != nil {
+		return nil, errors.Errorf("invalid value %q: %v", value, err)
+	}
+
+	return v, nil
+}
+
+// setValue sets the value at the path of keys in the spec, creating maps as
+// needed, and returns the spec.
+func setValue(spec interface{}, keys []interface{}, v interface{}) interface{} {
+	if len(keys) == 0 {
+		return v
+	}
+	m, ok := spec.(map[interface{}]interface{})
+	if !ok {
+		m = map[interface{}]interface{}{}
+	}
+	m[keys[0]] = setValue(m[keys[0]], keys[1:], v)
+
+	return m
+}
+
+func validSeverity(severity policy.Severity) bool {
+	for _, s := range policy.Severities {
+		if s == severity {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,173 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestApplyEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		environ  []string
+		expected *PolicyDeclaration
+		err      bool
+	}{
+		{
+			name:    "Integer",
+			environ: []string{"CONFORM_COMMIT_HEADERLENGTH=72"},
+			expected: &PolicyDeclaration{Type: "commit", Spec: map[interface{}]interface{}{
+				"dco":          true,
+				"headerLength": 72,
+			}},
+		},
+		{
+			name:    "Words",
+			environ: []string{"CONFORM_COMMIT_HEADER_LENGTH=72"},
+			expected: &PolicyDeclaration{Type: "commit", Spec: map[interface{}]interface{}{
+				"dco":          true,
+				"headerLength": 72,
+			}},
+		},
+		{
+			name:    "Nested",
+			environ: []string{"CONFORM_COMMIT_CONVENTIONAL_TYPES=chore, docs"},
+			expected: &PolicyDeclaration{Type: "commit", Spec: map[interface{}]interface{}{
+				"dco": true,
+				"conventional": map[interface{}]interface{}{
+					"types": []interface{}{"chore", "docs"},
+				},
+			}},
+		},
+		{
+			name:    "Severity",
+			environ: []string{"CONFORM_COMMIT_SEVERITY=WARN"},
+			expected: &PolicyDeclaration{Type: "commit", Severity: policy.SeverityWarn, Spec: map[interface{}]interface{}{
+				"dco": true,
+			}},
+		},
+		{
+			name:    "Ignored",
+			environ: []string{"CONFORM_SKIP=DCO", "CONFORM_LICENSE_HEADER=x", "HOME=/root"},
+			expected: &PolicyDeclaration{Type: "commit", Spec: map[interface{}]interface{}{
+				"dco": true,
+			}},
+		},
+		{
+			name:    "UnknownKey",
+			environ: []string{"CONFORM_COMMIT_HEADERLENGHT=72"},
+			err:     true,
+		},
+		{
+			name:    "InvalidValue",
+			environ: []string{"CONFORM_COMMIT_DCO=maybe"},
+			err:     true,
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(tt *testing.T) {
+			c := &Conform{
+				Policies: []*PolicyDeclaration{
+					{Type: "commit", Spec: map[interface{}]interface{}{"dco": true}},
+				},
+			}
+			err := applyEnv(c, test.environ)
+			if test.err {
+				if err == nil {
+					tt.Error("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				tt.Fatal(err)
+			}
+			if !reflect.DeepEqual(c.Policies[0], test.expected) {
+				tt.Errorf("Expected %+v, got %+v", test.expected, c.Policies[0])
+			}
+		})
+	}
+}
+
+func TestApplySet(t *testing.T) {
+	tests := []struct {
+		name     string
+		set      []string
+		expected *PolicyDeclaration
+		err      bool
+	}{
+		{
+			name: "Words",
+			set:  []string{"commit.header.length=100"},
+			expected: &PolicyDeclaration{Type: "commit", Spec: map[interface{}]interface{}{
+				"dco":          true,
+				"headerLength": 100,
+			}},
+		},
+		{
+			name: "Order",
+			set:  []string{"commit.dco=false", "commit.conventional.types=chore,docs", "commit.dco=true"},
+			expected: &PolicyDeclaration{Type: "commit", Spec: map[interface{}]interface{}{
+				"dco": true,
+				"conventional": map[interface{}]interface{}{
+					"types": []interface{}{"chore", "docs"},
+				},
+			}},
+		},
+		{
+			name: "Value with equal sign",
+			set:  []string{"commit.conventional.scopes=a=b"},
+			expected: &PolicyDeclaration{Type: "commit", Spec: map[interface{}]interface{}{
+				"dco": true,
+				"conventional": map[interface{}]interface{}{
+					"scopes": []interface{}{"a=b"},
+				},
+			}},
+		},
+		{
+			name: "NoKey",
+			set:  []string{"commit=100"},
+			err:  true,
+		},
+		{
+			name: "NoValue",
+			set:  []string{"commit.headerLength"},
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(tt *testing.T) {
+			c := &Conform{
+				Policies: []*PolicyDeclaration{
+					{Type: "commit", Spec: map[interface{}]interface{}{"dco": true}},
+				},
+			}
+			sets, err := parseSet(test.set)
+			if err == nil {
+				err = applyOverrides(c, sets, "overrides")
+			}
+			if test.err {
+				if err == nil {
+					tt.Error("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				tt.Fatal(err)
+			}
+			if !reflect.DeepEqual(c.Policies[0], test.expected) {
+				tt.Errorf("Expected %+v, got %+v", test.expected, c.Policies[0])
+			}
+		})
+	}
+}
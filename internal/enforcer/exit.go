@@ -0,0 +1,62 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+// Outcome is the outcome of a run, which is mapped to the exit code.
+type Outcome string
+
+const (
+	// OutcomePass is the outcome when no violation fails enforcement.
+	OutcomePass Outcome = "pass"
+	// OutcomeFailure is the outcome when a violation of an error check fails
+	// enforcement.
+	OutcomeFailure Outcome = "failure"
+	// OutcomeConfigError is the outcome when the configuration is invalid.
+	OutcomeConfigError Outcome = "config"
+	// OutcomeWarnings is the outcome when violations of warn checks are
+	// reported, but none fail enforcement. It is only used when warnings are
+	// strict.
+	OutcomeWarnings Outcome = "warnings"
+	// OutcomeError is the outcome when a policy fails to run, so that the
+	// results are incomplete.
+	OutcomeError Outcome = "error"
+)
+
+// DefaultExitCodes are the default exit codes of the outcomes.
+var DefaultExitCodes = map[Outcome]int{
+	OutcomePass:        0,
+	OutcomeFailure:     1,
+	OutcomeConfigError: 2,
+	OutcomeWarnings:    3,
+	OutcomeError:       4,
+}
+
+// ExitCode returns the exit code of the outcome.
+func (o *Options) ExitCode(outcome Outcome) int {
+	if code, ok := o.ExitCodes[outcome]; ok {
+		return code
+	}
+
+	return DefaultExitCodes[outcome]
+}
+
+// outcome returns the outcome of the result. A dry run always passes, and an
+// incomplete result neither passes nor fails.
+func (o *Options) outcome(r *result) Outcome {
+	switch {
+	case o.DryRun:
+		return OutcomePass
+	case r.invalid:
+		return OutcomeConfigError
+	case r.errored:
+		return OutcomeError
+	case r.failed:
+		return OutcomeFailure
+	case r.warned && o.StrictWarnings:
+		return OutcomeWarnings
+	}
+
+	return OutcomePass
+}
@@ -0,0 +1,55 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import "testing"
+
+func TestExitCode(t *testing.T) {
+	options := NewDefaultOptions(WithExitCodes(map[Outcome]int{OutcomeWarnings: 0, OutcomeFailure: 10}))
+
+	tests := []struct {
+		outcome  Outcome
+		expected int
+	}{
+		{outcome: OutcomePass, expected: 0},
+		{outcome: OutcomeFailure, expected: 10},
+		{outcome: OutcomeConfigError, expected: 2},
+		{outcome: OutcomeWarnings, expected: 0},
+		{outcome: OutcomeError, expected: 4},
+	}
+
+	for _, test := range tests {
+		if code := options.ExitCode(test.outcome); code != test.expected {
+			t.Errorf("Expected exit code %d for %s, got %d", test.expected, test.outcome, code)
+		}
+	}
+}
+
+func TestOutcome(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  *Options
+		r        *result
+		expected Outcome
+	}{
+		{name: "Pass", options: NewDefaultOptions(), r: &result{}, expected: OutcomePass},
+		{name: "Failure", options: NewDefaultOptions(), r: &result{failed: true, warned: true}, expected: OutcomeFailure},
+		{name: "Warnings", options: NewDefaultOptions(WithStrictWarnings(true)), r: &result{warned: true}, expected: OutcomeWarnings},
+		{name: "NotStrictWarnings", options: NewDefaultOptions(), r: &result{warned: true}, expected: OutcomePass},
+		{name: "Error", options: NewDefaultOptions(), r: &result{errored: true, failed: true}, expected: OutcomeError},
+		{name: "Config", options: NewDefaultOptions(), r: &result{invalid: true, errored: true}, expected: OutcomeConfigError},
+		{name: "DryRun", options: NewDefaultOptions(WithDryRun(true)), r: &result{invalid: true, errored: true, failed: true}, expected: OutcomePass},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if outcome := test.options.outcome(test.r); outcome != test.expected {
+				t.Errorf("Expected outcome %s, got %s", test.expected, outcome)
+			}
+		})
+	}
+}
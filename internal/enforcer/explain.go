@@ -0,0 +1,435 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Explanation explains a check of a builtin policy in detail.
+type Explanation struct {
+	// Policy is the type of the policy of the check.
+	Policy string
+	CheckDescription
+	// Details describe what the check enforces, and why.
+	Details string
+	// Pass and Fail are examples of input that pass and fail the check.
+	Pass string
+	Fail string
+	// Remedy is how to fix the violations of the check.
+	Remedy string
+	// Keys are the configuration keys of the policy.
+	Keys []ConfigKey
+}
+
+// ConfigKey is a key of the spec of a policy.
+type ConfigKey struct {
+	Name string
+	// Type is the type of the value of the key, as in the schema.
+	Type string
+	// Enables is true if setting the key enables the check.
+	Enables bool
+}
+
+// explanation is the text of an Explanation.
+type explanation struct {
+	details string
+	pass    string
+	fail    string
+	remedy  string
+}
+
+// explanations explain the checks of the builtin policies, keyed by
+// <type>/<check>. Every check in the catalog must be explained.
+var explanations = map[string]explanation{
+	"binary/Binary Files": {
+		details: "Binary files bloat the history of a repository forever, since every version is kept. Added files that git considers binary are rejected, unless they match allowedPaths or are tracked with Git LFS.",
+		pass:    "A change adding docs/diagram.svg, or a PNG tracked with Git LFS.",
+		fail:    "A change adding bin/tool, a compiled executable.",
+		remedy:  "Remove the file from the change, track it with Git LFS, or add its path to allowedPaths.",
+	},
+	"changelog/Changelog Fragment": {
+		details: "Changes to the source files matched by paths must describe themselves for the release notes, by adding a fragment to the changelog directory (changelog.d by default). Changes that users do not notice can be exempted.",
+		pass:    "A change to cmd/root.go that adds changelog.d/123.feature.md.",
+		fail:    "A change to cmd/root.go without a new file in changelog.d.",
+		remedy:  "Add a fragment describing the change to the changelog directory, or exempt the change with the Skip-Changelog trailer, or the skip-changelog label of the pull request.",
+	},
+	"codeowners/Code Owners": {
+		details: "Every changed file must be covered by a rule of the CODEOWNERS file, so that someone is responsible for reviewing it.",
+		pass:    "A change to internal/api/api.go with the rule: /internal/ @api-team",
+		fail:    "A change to a new directory tools/ that no rule covers.",
+		remedy:  "Add a rule covering the files to the CODEOWNERS file, or add their paths to excludePaths.",
+	},
+	"commit/Header Length": {
+		details: "The header, the first line of the commit message, is shown by git log --oneline, in pull requests, and in emails. A header longer than headerLength characters is truncated, or wraps.",
+		pass:    "feat: add the explain command",
+		fail:    "feat: add the explain command which prints the details of a check along with examples and remediation",
+		remedy:  "Shorten the header, and move the details to the body of the message, e.g. with git commit --amend.",
+	},
+	"commit/DCO": {
+		details: "The Developer Certificate of Origin is a statement that the author has the right to submit the change. The commit message must end with a Signed-off-by trailer.",
+		pass:    "fix: handle empty input\n\nSigned-off-by: Jane Doe <jane@example.com>",
+		fail:    "fix: handle empty input",
+		remedy:  "Sign off the commit with git commit --amend --signoff, or commit with --signoff.",
+	},
+	"commit/GPG": {
+		details: "A GPG signature proves that the commit was made by the owner of the key.",
+		pass:    "A commit made with git commit --gpg-sign.",
+		fail:    "A commit made without a signature.",
+		remedy:  "Sign the commit with git commit --amend --gpg-sign, and set commit.gpgsign in the git configuration to sign every commit.",
+	},
+	"commit/Imperative Mood": {
+		details: "The header describes what applying the commit does, like a command, which reads consistently with the messages generated by git, such as \"Merge branch\" and \"Revert\".",
+		pass:    "feat: add the explain command",
+		fail:    "feat: added the explain command",
+		remedy:  "Start the header, or the description of a conventional commit, with a verb in the imperative mood, e.g. \"add\" instead of \"added\" or \"adds\".",
+	},
+	"commit/Conventional Commit": {
+		details: "Conventional commits have a header of the form <type>[(<scope>)]: <description>, so that tools can derive release notes and versions from the history. The type, and the scope if any, must be allowed by the policy, and the description must be at most 72 characters.",
+		pass:    "fix(cli): handle empty input",
+		fail:    "Handle empty input",
+		remedy:  "Reword the header as <type>[(<scope>)]: <description>, using an allowed type and scope, e.g. with git commit --amend.",
+	},
+	"commit/Number of Commits": {
+		details: "Enforcing a single commit ahead of the base branch keeps the history linear, with one commit per change.",
+		pass:    "A branch one commit ahead of main.",
+		fail:    "A branch three commits ahead of main.",
+		remedy:  "Squash the commits into one, e.g. with git rebase --interactive main.",
+	},
+	"commit/Commit Body": {
+		details: "The body of the commit message explains what changed and why, which the header is too short to do.",
+		pass:    "fix: handle empty input\n\nThe parser panicked on empty files, which are valid.",
+		fail:    "fix: handle empty input",
+		remedy:  "Add a body after a blank line below the header, e.g. with git commit --amend.",
+	},
+	"cue/CUE": {
+		details: "The commit, refs, and changed files are unified with the CUE constraints of the schemas, or validated against the definition. The check fails when the input does not satisfy the constraints.",
+		pass:    "A commit whose input satisfies: commit: message: =~\"^(feat|fix)\"",
+		fail:    "A commit with the message \"Update\", which does not match the constraint.",
+		remedy:  "Change the commit or files to satisfy the reported constraints, or relax the constraints of the schemas.",
+	},
+	"dependency/Dependency Licenses": {
+		details: "The licenses of the Go module dependencies are detected from their license files, and must be one of the allowed SPDX identifiers, so that the legal obligations of the project are known.",
+		pass:    "A dependency licensed under MIT, with allowed: [MIT, Apache-2.0].",
+		fail:    "A dependency licensed under GPL-3.0, or whose license cannot be detected.",
+		remedy:  "Replace the dependency, allow its license, or add its module path to exceptions after a review.",
+	},
+	"diffsize/Diff Size": {
+		details: "Large changes are hard to review. The numbers of lines added and removed, and of files changed, since the base branch must not exceed the limits. Generated and vendored files can be excluded.",
+		pass:    "A change adding 120 lines, with maximumAdded: 400.",
+		fail:    "A change adding 2000 lines, with maximumAdded: 400.",
+		remedy:  "Split the change into smaller ones, or add the paths of generated files to excludePaths.",
+	},
+	"dockerfile/Base Image Digest": {
+		details: "A tag of a base image can be moved to another image, so that builds are not reproducible. Base images must be pinned by digest.",
+		pass:    "FROM golang:1.21@sha256:4bd8ad4b7a3e1c1a...",
+		fail:    "FROM golang:1.21",
+		remedy:  "Append the digest of the image to the FROM instruction, e.g. as printed by docker buildx imagetools inspect.",
+	},
+	"dockerfile/Forbidden Base Images": {
+		details: "Base images matching one of forbiddenImages are not allowed, e.g. images without security support.",
+		pass:    "FROM gcr.io/distroless/static, with forbiddenImages: [^ubuntu]",
+		fail:    "FROM ubuntu:18.04, with forbiddenImages: [^ubuntu]",
+		remedy:  "Use an allowed base image.",
+	},
+	"dockerfile/Non-Root User": {
+		details: "Containers running as root make escaping the container easier. The final stage must set a USER other than root.",
+		pass:    "FROM alpine\nUSER 65534",
+		fail:    "FROM alpine\nUSER root",
+		remedy:  "Add a USER instruction with an unprivileged user to the final stage.",
+	},
+	"dockerfile/Labels": {
+		details: "The final stage must declare the required labels, and every label key must match labelPattern, so that images can be traced to their sources.",
+		pass:    "LABEL org.opencontainers.image.source=https://github.com/acme/app",
+		fail:    "LABEL source=https://github.com/acme/app, with labelPattern: ^org\\.opencontainers\\.image\\.",
+		remedy:  "Add the missing labels to the final stage, and rename the keys that do not match the pattern.",
+	},
+	"eol/Line Endings": {
+		details: "Mixed line endings make diffs noisy. Text files must use LF line endings, except the files matching crlf, which must use CRLF, and the eol attributes of .gitattributes must agree. Files with the -text attribute are not checked.",
+		pass:    "A shell script with LF line endings.",
+		fail:    "A shell script with CRLF line endings, e.g. saved by an editor on Windows.",
+		remedy:  "Convert the line endings, e.g. with dos2unix, and set the eol attribute in .gitattributes so that git converts them on checkout.",
+	},
+	"exec/Exec": {
+		details: "The command is run with the commit, refs, and changed files as JSON on its standard input. It fails the check by exiting with a non-zero status, or by printing a result with errors.",
+		pass:    "A command that prints {\"pass\": true} and exits with status 0.",
+		fail:    "A command that exits with status 1, or prints {\"pass\": false, \"errors\": [\"...\"]}.",
+		remedy:  "Fix the violations reported by the command.",
+	},
+	"executable/Executable Bit": {
+		details: "Only scripts should be executable. Files with one of nonExecutableSuffixes must not be executable and, with shebang, files beginning with a shebang must be executable. Modes are read from the index, so that they are the same on every platform.",
+		pass:    "A hack/build.sh script with mode 100755, and a README.md with mode 100644.",
+		fail:    "A main.go with mode 100755.",
+		remedy:  "Change the mode in the index with git update-index --chmod=-x <file>, or --chmod=+x for scripts.",
+	},
+	"filename/Case Conflict": {
+		details: "Paths that differ only by case cannot both be checked out on case-insensitive file systems, such as the defaults of macOS and Windows.",
+		pass:    "README.md and docs/readme.md",
+		fail:    "README.md and readme.md",
+		remedy:  "Rename or remove one of the files with git mv or git rm.",
+	},
+	"filename/Path Length": {
+		details: "Long paths exceed the limits of some file systems and tools, e.g. the 260 characters of Windows.",
+		pass:    "internal/policy/filename/filename.go, with maximumPathLength: 100",
+		fail:    "A path of 300 characters, with maximumPathLength: 100",
+		remedy:  "Shorten the names of the file or its directories.",
+	},
+	"filename/Path Depth": {
+		details: "Deeply nested files are hard to find. Files must be nested in at most maximumDepth directories.",
+		pass:    "internal/policy/filename.go, with maximumDepth: 4",
+		fail:    "a/b/c/d/e/f.go, with maximumDepth: 4",
+		remedy:  "Move the file to a shallower directory.",
+	},
+	"frontmatter/Frontmatter": {
+		details: "The YAML frontmatter of Markdown files, e.g. the pages of a static site, must declare the required keys, their values must match the formats, and the frontmatter must conform to the schema.",
+		pass:    "---\ntitle: Getting Started\ndate: 2019-07-01\n---",
+		fail:    "---\ndate: July 1st\n---, with required: [title] and formats: {date: ^\\d{4}-\\d{2}-\\d{2}$}",
+		remedy:  "Add the missing keys, and fix the values reported, in the frontmatter of the files.",
+	},
+	"frozen/Frozen Paths": {
+		details: "Frozen files, e.g. released API definitions and applied database migrations, must not be modified or deleted. Adding new files is allowed.",
+		pass:    "A change adding migrations/0002_users.sql, with paths: [migrations/]",
+		fail:    "A change modifying migrations/0001_init.sql, with paths: [migrations/]",
+		remedy:  "Revert the changes to the frozen files, or add a Frozen-Override trailer with the reason to the commit message.",
+	},
+	"generate/Generated Code": {
+		details: "The generator commands are run in a temporary worktree of HEAD. If they change any of the generated files, those committed are out of date.",
+		pass:    "A change to api.proto that commits the regenerated api.pb.go.",
+		fail:    "A change to api.proto that does not regenerate api.pb.go.",
+		remedy:  "Run the generator commands, and commit the generated files.",
+	},
+	"gitattributes/Required Attributes": {
+		details: "The .gitattributes file must contain rules with the same pattern, and at least the same attributes, as each required line.",
+		pass:    "*.png binary, with required: [\"*.png binary\"]",
+		fail:    "No rule for *.png, with required: [\"*.png binary\"]",
+		remedy:  "Add the required lines to .gitattributes.",
+	},
+	"gitattributes/Attribute Consistency": {
+		details: "Tracked files must not contradict their attributes: files with the binary attribute must not be text, and files with the text attribute must not be binary.",
+		pass:    "A PNG image matching *.png binary.",
+		fail:    "A text file matching *.dat binary.",
+		remedy:  "Fix the pattern of the rule in .gitattributes, or the contents of the file.",
+	},
+	"gomod/Replace Directives": {
+		details: "Replace directives are ignored when the module is a dependency of another, and local ones break builds elsewhere. Only the modules of allowedReplaces may be replaced.",
+		pass:    "A go.mod without replace directives.",
+		fail:    "replace github.com/acme/lib => ../lib",
+		remedy:  "Remove the replace directive and require a released version, or add the module to allowedReplaces.",
+	},
+	"gomod/Go Version": {
+		details: "The go directive of go.mod must be at least minimumGoVersion, so that the language features and module behavior of that version are available.",
+		pass:    "go 1.21, with minimumGoVersion: 1.19",
+		fail:    "go 1.16, with minimumGoVersion: 1.19",
+		remedy:  "Raise the go directive, e.g. with go mod edit -go=1.19.",
+	},
+	"gomod/Pseudo Versions": {
+		details: "Pseudo-versions refer to unreleased commits of dependencies, which releases should not depend on. They are not allowed on the branches matching releaseBranches.",
+		pass:    "require github.com/acme/lib v1.2.0",
+		fail:    "require github.com/acme/lib v0.0.0-20190702223751-32f345186213, on release-1.0",
+		remedy:  "Require a released version of the dependency, e.g. with go get github.com/acme/lib@v1.2.0.",
+	},
+	"kubernetes/API Deprecations": {
+		details: "API versions removed from Kubernetes are no longer served, so that manifests using them fail to apply. Only the removals up to version are reported when it is set.",
+		pass:    "apiVersion: apps/v1\nkind: Deployment",
+		fail:    "apiVersion: extensions/v1beta1\nkind: Deployment",
+		remedy:  "Migrate the manifests to the replacement API version reported, e.g. with kubectl convert.",
+	},
+	"kubernetes/Required Metadata": {
+		details: "Every resource must declare the required labels and annotations, e.g. to identify its owner.",
+		pass:    "metadata:\n  labels:\n    app.kubernetes.io/name: api",
+		fail:    "metadata:\n  name: api",
+		remedy:  "Add the missing labels and annotations to the metadata of the resources.",
+	},
+	"kubernetes/Helm Chart": {
+		details: "Helm charts must have a valid Chart.yaml, and their templates must render.",
+		pass:    "A chart whose templates render with helm template.",
+		fail:    "A chart with a syntax error in a template.",
+		remedy:  "Fix the errors reported by helm lint and helm template.",
+	},
+	"license/File Header": {
+		details: "Files matching includeSuffixes, and not excludeSuffixes or skipPaths, must start with the license header.",
+		pass:    "// This Source Code Form is subject to the terms of the Mozilla Public\n// License, v. 2.0.\npackage main",
+		fail:    "package main",
+		remedy:  "Prepend the license header to the files, e.g. with conform fix.",
+	},
+	"newline/EOF Newline": {
+		details: "Files must end with exactly one newline. A missing newline makes git report \"No newline at end of file\", and extra blank lines are noise in diffs.",
+		pass:    "package main\n",
+		fail:    "package main, or package main followed by blank lines",
+		remedy:  "End the files with exactly one newline, e.g. with conform fix.",
+	},
+	"notice/NOTICE File": {
+		details: "The NOTICE file must exist and match each of the required patterns, e.g. the attributions required by the licenses of bundled software.",
+		pass:    "A NOTICE file containing \"This product includes software developed by Acme\".",
+		fail:    "A missing NOTICE file, or one without the required attribution.",
+		remedy:  "Create the NOTICE file, and add the missing attributions to it.",
+	},
+	"pullrequest/Labels": {
+		details: "Each of requiredLabels must match at least one label of the pull request, e.g. to categorize it for release notes.",
+		pass:    "A pull request labeled semver:minor, with requiredLabels: [^semver:]",
+		fail:    "A pull request without labels.",
+		remedy:  "Add the required labels to the pull request.",
+	},
+	"pullrequest/Description": {
+		details: "The description of the pull request must contain each of requiredSections as a Markdown heading followed by some content and, with requireCompletedTasks, every task of its task lists must be checked.",
+		pass:    "## Testing\nRan the unit tests.\n- [x] Update the docs",
+		fail:    "## Testing\n- [ ] Update the docs",
+		remedy:  "Fill in the missing sections of the description, and complete or remove the unchecked tasks.",
+	},
+	"pullrequest/Approvals": {
+		details: "The pull request must have at least minimumApprovals approving reviews and, with requireCodeOwnerApproval, each changed file with an owner must be approved by one of its owners.",
+		pass:    "A pull request approved by two reviewers, with minimumApprovals: 2",
+		fail:    "A pull request approved by one reviewer, with minimumApprovals: 2",
+		remedy:  "Request reviews from more reviewers, or from the owners of the files reported.",
+	},
+	"rego/Rego": {
+		details: "The query, data.conform.deny by default, is evaluated against the commit, refs, and changed files with the Rego modules. Each message of the resulting set is a violation, and a false result fails the check.",
+		pass:    "deny[msg] { false; msg := \"\" }",
+		fail:    "deny[msg] { not startswith(input.commit.message, \"feat\"); msg := \"not a feature\" }",
+		remedy:  "Fix the violations reported by the rules.",
+	},
+	"schema/Schema": {
+		details: "The YAML and JSON files matching the paths of each rule must be valid against the JSON Schema of the rule, e.g. CI workflows against their published schema.",
+		pass:    "A .github/workflows/ci.yml valid against the GitHub workflow schema.",
+		fail:    "A workflow with a misspelled key, such as runs_on.",
+		remedy:  "Fix the errors reported at the JSON pointers of the files.",
+	},
+	"script/Script": {
+		details: "The Starlark script defines a check function, called with the commit, refs, and changed files, that returns the violations found.",
+		pass:    "def check(input):\n    return []",
+		fail:    "def check(input):\n    return [\"the header is too long\"]",
+		remedy:  "Fix the violations reported by the script.",
+	},
+	"security/Security Policy": {
+		details: "A SECURITY.md file must exist in one of paths, and declare an email address or https URL to report vulnerabilities to, matching one of contacts if set.",
+		pass:    "A SECURITY.md asking to report vulnerabilities to security@example.com.",
+		fail:    "A missing SECURITY.md, or one without a contact.",
+		remedy:  "Add a SECURITY.md file declaring how to report vulnerabilities.",
+	},
+	"shebang/Shebang": {
+		details: "Scripts matching paths must start with one of the allowed shebangs, optionally followed by arguments, so that they run with a known interpreter.",
+		pass:    "#!/usr/bin/env bash",
+		fail:    "#!/bin/bash, with the default allowed shebangs",
+		remedy:  "Replace the shebang of the scripts with an allowed one, or allow it.",
+	},
+	"shebang/Script Executable Bit": {
+		details: "Scripts must be executable, and executable files must have a shebang, so that they can be run directly.",
+		pass:    "A hack/build.sh script with mode 100755.",
+		fail:    "A hack/build.sh script with mode 100644.",
+		remedy:  "Change the mode in the index with git update-index --chmod=+x <file>, or add a shebang to the executable files.",
+	},
+	"submodule/No Submodules": {
+		details: "Submodules complicate cloning and updating the repository. With forbid, the repository must not have any.",
+		pass:    "A repository without a .gitmodules file.",
+		fail:    "A repository with a submodule in third_party/lib.",
+		remedy:  "Remove the submodule with git rm, and vendor or depend on its contents instead.",
+	},
+	"submodule/Submodule URL": {
+		details: "The URL of every submodule must match one of allowedURLs, e.g. the organization's hosting.",
+		pass:    "https://github.com/acme/lib, with allowedURLs: [^https://github\\.com/acme/]",
+		fail:    "https://example.com/lib.git, with allowedURLs: [^https://github\\.com/acme/]",
+		remedy:  "Point the submodule at an allowed URL with git submodule set-url, or allow its URL.",
+	},
+	"submodule/Submodule Commit": {
+		details: "The commit pinned by each submodule must be reachable from the default branch of its remote, so that it is not lost when a feature branch is deleted.",
+		pass:    "A submodule pinned to a commit of main.",
+		fail:    "A submodule pinned to a commit only on a feature branch.",
+		remedy:  "Merge the commit into the default branch of the submodule, or pin the submodule to a commit of the default branch.",
+	},
+	"symlink/Symlinks": {
+		details: "Symlinks are not supported on every platform, and those pointing outside of the repository break when it is cloned elsewhere. With forbid, symlinks are not allowed; with forbidEscape, they must stay in the repository; and with allowedPaths, they must match one of the patterns.",
+		pass:    "docs/README.md -> ../README.md",
+		fail:    "config -> /etc/app/config, with forbidEscape: true",
+		remedy:  "Replace the symlink with a copy of its target, or point it at a path in the repository.",
+	},
+	"wasm/WASM": {
+		details: "The WebAssembly module is run with the commit, refs, and changed files as JSON on its standard input, and reports its result as the exec policy does.",
+		pass:    "A module that prints {\"pass\": true}.",
+		fail:    "A module that prints {\"pass\": false, \"errors\": [\"...\"]}.",
+		remedy:  "Fix the violations reported by the module.",
+	},
+	"whitespace/Trailing Whitespace": {
+		details: "Trailing whitespace is invisible noise in diffs. The lines added by the change, since the base branch, must not end with spaces or tabs.",
+		pass:    "return nil",
+		fail:    "return nil followed by spaces",
+		remedy:  "Remove the trailing whitespace of the lines reported, e.g. with conform fix.",
+	},
+}
+
+// Explain returns the explanations of the checks of the builtin policies with
+// the name, ignoring case. The name may also be the type of a policy, which
+// explains all of its checks, or <type>/<check>.
+func Explain(name string) ([]Explanation, error) {
+	types := make([]string, 0, len(catalog))
+	for t := range catalog {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	var matches []Explanation
+	for _, t := range types {
+		for _, check := range catalog[t].Checks {
+			if !strings.EqualFold(name, t) && !strings.EqualFold(name, check.Name) && !strings.EqualFold(name, t+"/"+check.Name) {
+				continue
+			}
+			e := explanations[t+"/"+check.Name]
+			matches = append(matches, Explanation{
+				Policy:           t,
+				CheckDescription: check,
+				Details:          e.details,
+				Pass:             e.pass,
+				Fail:             e.fail,
+				Remedy:           e.remedy,
+				Keys:             configKeys(t, check.Options),
+			})
+		}
+	}
+	if len(matches) == 0 {
+		return nil, errors.Errorf("Unknown check %q: run conform list to see the available checks", name)
+	}
+
+	return matches, nil
+}
+
+// Explains reports whether Explain explains the check of the policy.
+func Explains(policyType, check string) bool {
+	_, ok := explanations[policyType+"/"+check]
+	return ok
+}
+
+// configKeys returns the configuration keys of the spec of the builtin
+// policy, ordered by name, marking those that enable the check.
+func configKeys(policyType string, options []string) []ConfigKey {
+	properties, _ := reflectSchema(reflect.TypeOf(policyMap[policyType]))["properties"].(map[string]interface{})
+
+	keys := make([]ConfigKey, 0, len(properties))
+	for name, property := range properties {
+		key := ConfigKey{Name: name, Type: schemaType(property)}
+		for _, option := range options {
+			key.Enables = key.Enables || option == name
+		}
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+
+	return keys
+}
+
+// schemaType describes the type of a schema generated by reflectSchema.
+func schemaType(schema interface{}) string {
+	m, _ := schema.(map[string]interface{})
+	switch t, _ := m["type"].(string); t {
+	case "array":
+		return "list of " + schemaType(m["items"]) + "s"
+	case "":
+		return "any"
+	default:
+		return t
+	}
+}
@@ -0,0 +1,89 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"testing"
+)
+
+func TestExplanations(t *testing.T) {
+	for name, d := range catalog {
+		for _, check := range d.Checks {
+			e, ok := explanations[name+"/"+check.Name]
+			if !ok {
+				t.Errorf("Expected check %q of policy %q to be explained", check.Name, name)
+				continue
+			}
+			if e.details == "" || e.pass == "" || e.fail == "" || e.remedy == "" {
+				t.Errorf("Expected check %q of policy %q to be fully explained", check.Name, name)
+			}
+		}
+	}
+	for key := range explanations {
+		found := false
+		for name, d := range catalog {
+			for _, check := range d.Checks {
+				found = found || key == name+"/"+check.Name
+			}
+		}
+		if !found {
+			t.Errorf("Expected explained check %q to exist", key)
+		}
+	}
+}
+
+func TestExplain(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Expected []string
+		Err      bool
+	}{
+		{"header length", []string{"commit/Header Length"}, false},
+		{"Labels", []string{"dockerfile/Labels", "pullrequest/Labels"}, false},
+		{"pullrequest/labels", []string{"pullrequest/Labels"}, false},
+		{"filename", []string{"filename/Case Conflict", "filename/Path Length", "filename/Path Depth"}, false},
+		{"Header", nil, true},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			explanations, err := Explain(test.Name)
+			if test.Err {
+				if err == nil {
+					tt.Error("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				tt.Fatal(err)
+			}
+			actual := make([]string, 0, len(explanations))
+			for _, e := range explanations {
+				actual = append(actual, e.Policy+"/"+e.Name)
+			}
+			if len(actual) != len(test.Expected) {
+				tt.Fatalf("Expected %v, got %v", test.Expected, actual)
+			}
+			for i := range actual {
+				if actual[i] != test.Expected[i] {
+					tt.Errorf("Expected %v, got %v", test.Expected, actual)
+				}
+			}
+		})
+	}
+
+	explanations, err := Explain("Header Length")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range explanations[0].Keys {
+		if key.Enables != (key.Name == "headerLength") {
+			t.Errorf("Expected only headerLength to enable the check, got %+v", key)
+		}
+		if key.Name == "conventional" && key.Type != "object" {
+			t.Errorf("Expected conventional to be an object, got %q", key.Type)
+		}
+	}
+}
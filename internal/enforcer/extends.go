@@ -0,0 +1,346 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/autonomy/conform/internal/logging"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ExtendDeclaration declares a shared configuration that the configuration
+// inherits from. The policies of the configuration take precedence over the
+// inherited ones, so that only the differences need to be declared.
+type ExtendDeclaration struct {
+	// Source is the location of the shared configuration:
+	//
+	//	base.yaml                                     a path relative to the configuration
+	//	https://example.com/conform/base.yaml         an HTTPS URL
+	//	git+https://github.com/org/repo.git//base.yaml@v1
+	//	                                              a file of a git repository, at an
+	//	                                              optional branch, tag, or commit
+	Source string `yaml:"source"`
+	// Checksum pins the contents of the shared configuration, in the form
+	// sha256:<hex>.
+	Checksum string `yaml:"checksum"`
+}
+
+// extendedConfig is a configuration loaded by an extender.
+type extendedConfig struct {
+	source string
+	bytes  []byte
+}
+
+// extender resolves the shared configurations inherited by a configuration.
+// Remote configurations are cached, so that pinned configurations are only
+// downloaded once and enforcement keeps working offline.
+type extender struct {
+	cacheDir string
+	client   *http.Client
+	loaded   []extendedConfig
+	// deprecated are the warnings of the deprecated keys renamed in the
+	// configurations loaded.
+	deprecated []Problem
+	// data is the data that the templates of the configurations are
+	// executed with.
+	data templateData
+	// untrusted refuses remote configurations and the env function of
+	// templates, since the configuration of an untrusted repository could
+	// send the secrets of the environment to a server of its choosing.
+	untrusted bool
+}
+
+// extendsTimeout is the timeout of downloading a shared configuration.
+const extendsTimeout = 30 * time.Second
+
+func newExtender() (*extender, error) {
+	dir, ok := os.LookupEnv("CONFORM_CACHE_DIR")
+	if !ok {
+		cache, err := os.UserCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(cache, "conform")
+	}
+
+	return &extender{cacheDir: dir, client: &http.Client{Timeout: extendsTimeout}}, nil
+}
+
+// funcs returns the functions of the templates of the configurations.
+func (e *extender) funcs() template.FuncMap {
+	if e.untrusted {
+		return untrustedFuncs
+	}
+
+	return templateFuncs
+}
+
+// extend returns the configuration resulting from the configuration
+// overriding the configurations it extends, recursively. Relative sources
+// are resolved against parent, the location of the configuration.
+func (e *extender) extend(c *Conform, parent string, stack []string) (*Conform, error) {
+	merged := &Conform{}
+	for _, ext := range c.Extends {
+		if ext.Source == "" {
+			return nil, errors.New("Extended configurations must have a source")
+		}
+		source := resolveSource(ext.Source, parent)
+		for _, s := range stack {
+			if s == source {
+				return nil, errors.Errorf("Configuration %s extends itself", source)
+			}
+		}
+
+		if e.untrusted && strings.Contains(source, "://") {
+			return nil, errors.Errorf("Configuration %s is remote, which the configuration of an untrusted repository cannot extend", source)
+		}
+
+		configBytes, location, err := e.fetch(source, ext.Checksum)
+		if err != nil {
+			return nil, errors.Errorf("failed to load %s: %v", source, err)
+		}
+		if err = verifyChecksum(configBytes, ext.Checksum); err != nil {
+			return nil, errors.Errorf("%s: %v", source, err)
+		}
+		if configBytes, err = convertConfig(sourceName(source), configBytes); err != nil {
+			return nil, err
+		}
+		var renamed []Problem
+		if configBytes, renamed, err = renameDeprecated(source, configBytes); err != nil {
+			return nil, errors.Errorf("%s: %v", source, err)
+		}
+		e.deprecated = append(e.deprecated, renamed...)
+		e.loaded = append(e.loaded, extendedConfig{source: source, bytes: configBytes})
+		logging.Info("extended configuration", "source", source, "location", location)
+
+		expanded, err := expandConfig(configBytes, e.data, e.funcs())
+		if err != nil {
+			return nil, errors.Errorf("%s: %v", source, err)
+		}
+		base := &Conform{}
+		if err = yaml.Unmarshal(expanded, base); err != nil {
+			return nil, errors.Errorf("%s: %v", source, err)
+		}
+		if err = checkVersion(base); err != nil {
+			return nil, errors.Errorf("%s: %v", source, err)
+		}
+		if base, err = e.extend(base, location, append(stack, source)); err != nil {
+			return nil, err
+		}
+		merged = mergeConfig(merged, base)
+	}
+
+	return mergeConfig(merged, c), nil
+}
+
+// sourceName returns the name of the file of the source, without the ref of
+// git sources, so that its format can be selected by extension.
+func sourceName(source string) string {
+	if strings.HasPrefix(source, "git+") {
+		if i := strings.LastIndex(source, "@"); i > strings.LastIndex(source, "/") {
+			return source[:i]
+		}
+	}
+
+	return source
+}
+
+// resolveSource resolves a relative source against the location of the
+// configuration declaring it, which is a local directory or a URL.
+func resolveSource(source, parent string) string {
+	if strings.Contains(source, "://") || filepath.IsAbs(source) {
+		return source
+	}
+	if strings.HasPrefix(parent, "https://") {
+		if u, err := url.Parse(parent); err == nil {
+			if ref, err := url.Parse(source); err == nil {
+				return u.ResolveReference(ref).String()
+			}
+		}
+	}
+
+	return filepath.Join(parent, filepath.FromSlash(source))
+}
+
+// fetch returns the contents of the source, and the location that sources
+// relative to it are resolved against.
+func (e *extender) fetch(source, checksum string) ([]byte, string, error) {
+	switch {
+	case strings.HasPrefix(source, "git+"):
+		return e.fetchGit(strings.TrimPrefix(source, "git+"))
+	case strings.HasPrefix(source, "https://"):
+		configBytes, err := e.fetchHTTPS(source, checksum)
+		return configBytes, source, err
+	case strings.Contains(source, "://"):
+		return nil, "", errors.Errorf("unsupported source %q", source)
+	default:
+		configBytes, err := ioutil.ReadFile(source)
+		return configBytes, filepath.Dir(source), err
+	}
+}
+
+func (e *extender) fetchHTTPS(source, checksum string) ([]byte, error) {
+	cached := filepath.Join(e.cacheDir, "https", cacheKey(source))
+	if checksum != "" {
+		if configBytes, err := ioutil.ReadFile(cached); err == nil && verifyChecksum(configBytes, checksum) == nil {
+			logging.Debug("using cached configuration", "source", source, "cache", cached)
+			return configBytes, nil
+		}
+	}
+
+	configBytes, err := e.download(source)
+	if err != nil {
+		// Fall back to the last downloaded version when offline, which is
+		// stale if the source changed since.
+		if cachedBytes, cacheErr := ioutil.ReadFile(cached); cacheErr == nil {
+			logging.Warn("download failed, using the cached configuration, which may be stale", "source", source, "cached", cacheTime(cached), "error", err)
+			return cachedBytes, nil
+		}
+		return nil, err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(cached), 0755); err != nil {
+		return nil, err
+	}
+	if err = ioutil.WriteFile(cached, configBytes, 0644); err != nil {
+		return nil, err
+	}
+
+	return configBytes, nil
+}
+
+func (e *extender) download(source string) ([]byte, error) {
+	resp, err := e.client.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	// nolint: errcheck
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("GET %s: %s", source, resp.Status)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// fetchGit reads a file of a git repository, in the form <url>//<path>@<ref>.
+// The repository is cloned into the cache, and only fetched again when the
+// ref is not a commit that is already present.
+func (e *extender) fetchGit(source string) ([]byte, string, error) {
+	sep := -1
+	if scheme := strings.Index(source, "://"); scheme >= 0 {
+		if sep = strings.Index(source[scheme+len("://"):], "//"); sep >= 0 {
+			sep += scheme + len("://")
+		}
+	}
+	if sep < 0 {
+		return nil, "", errors.Errorf("git source %q must be in the form <url>//<path>[@<ref>]", source)
+	}
+	repo, name := source[:sep], source[sep+len("//"):]
+	ref := "HEAD"
+	if i := strings.LastIndex(name, "@"); i >= 0 {
+		name, ref = name[:i], name[i+1:]
+	}
+	// The ref is passed to git, which would read it as an option.
+	if ref == "" || strings.HasPrefix(ref, "-") {
+		return nil, "", errors.Errorf("git source %q has an invalid ref %q", source, ref)
+	}
+
+	dir := filepath.Join(e.cacheDir, "git", cacheKey(repo))
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err = os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+			return nil, "", err
+		}
+		if err = runGit("", "clone", "--quiet", "--no-checkout", "--", repo, dir); err != nil {
+			return nil, "", err
+		}
+	} else if err = runGit(dir, "cat-file", "-e", ref+"^{commit}"); err != nil || !isCommitHash(ref) {
+		// Fall back to the last fetched version of the ref when offline,
+		// which is stale if the ref moved since.
+		if err = runGit(dir, "fetch", "--quiet", "--tags", "--force", "origin", "+refs/heads/*:refs/remotes/origin/*"); err != nil {
+			logging.Warn("fetch failed, using the cached configuration, which may be stale", "source", source, "cached", cacheTime(filepath.Join(dir, ".git", "FETCH_HEAD")), "error", err)
+		}
+	}
+
+	rev := ref
+	if ref == "HEAD" {
+		rev = "origin/HEAD"
+	} else if runGit(dir, "rev-parse", "--verify", "--quiet", "origin/"+ref+"^{commit}") == nil {
+		rev = "origin/" + ref
+	}
+	if err := runGit(dir, "checkout", "--quiet", "--force", "--detach", rev); err != nil {
+		return nil, "", err
+	}
+
+	file := filepath.Join(dir, filepath.FromSlash(name))
+	configBytes, err := ioutil.ReadFile(file)
+
+	return configBytes, filepath.Dir(file), err
+}
+
+func runGit(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return errors.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// cacheTime returns the time the cached file was last written, empty if it
+// is unknown.
+func cacheTime(name string) string {
+	info, err := os.Stat(name)
+	if err != nil {
+		return ""
+	}
+
+	return info.ModTime().Format(time.RFC3339)
+}
+
+func isCommitHash(ref string) bool {
+	if len(ref) != 40 {
+		return false
+	}
+	_, err := hex.DecodeString(ref)
+
+	return err == nil
+}
+
+func cacheKey(source string) string {
+	sum := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(sum[:])
+}
+
+func verifyChecksum(configBytes []byte, checksum string) error {
+	if checksum == "" {
+		return nil
+	}
+	if !strings.HasPrefix(checksum, "sha256:") {
+		return errors.Errorf("unsupported checksum %q, expected sha256:<hex>", checksum)
+	}
+	sum := sha256.Sum256(configBytes)
+	if actual := "sha256:" + hex.EncodeToString(sum[:]); actual != checksum {
+		return errors.Errorf("checksum mismatch: expected %s, got %s", checksum, actual)
+	}
+
+	return nil
+}
@@ -0,0 +1,268 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const baseConfig = `policies:
+  - type: commit
+    spec:
+      headerLength: 72
+      dco: true
+  - type: license
+    spec:
+      includeSuffixes: [.go]
+`
+
+func checksum(contents string) string {
+	sum := sha256.Sum256([]byte(contents))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func extendConfig(t *testing.T, e *extender, parent, config string) *Conform {
+	c := &Conform{}
+	if err := yaml.Unmarshal([]byte(config), c); err != nil {
+		t.Fatal(err)
+	}
+	merged, err := e.extend(c, parent, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return merged
+}
+
+func expectBase(t *testing.T, c *Conform) {
+	if len(c.Policies) != 3 {
+		t.Fatalf("Expected 3 policies, got %d", len(c.Policies))
+	}
+	expected := map[interface{}]interface{}{"headerLength": 89, "dco": true}
+	if spec := c.Policies[0].Spec; !reflect.DeepEqual(spec, expected) {
+		t.Errorf("Expected merged commit spec %v, got %v", expected, spec)
+	}
+	if c.Policies[2].Type != "whitespace" {
+		t.Errorf("Expected whitespace policy to be appended, got %s", c.Policies[2].Type)
+	}
+}
+
+const override = `
+policies:
+  - type: commit
+    spec:
+      headerLength: 89
+  - type: whitespace
+`
+
+func TestExtendLocal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+	if err = ioutil.WriteFile(filepath.Join(dir, "base.yaml"), []byte(baseConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := &extender{cacheDir: filepath.Join(dir, "cache"), client: http.DefaultClient}
+	expectBase(t, extendConfig(t, e, dir, "extends:\n  - source: base.yaml\n    checksum: "+checksum(baseConfig)+override))
+
+	c := &Conform{Extends: []*ExtendDeclaration{{Source: "base.yaml", Checksum: checksum("other")}}}
+	if _, err = e.extend(c, dir, nil); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Expected a checksum mismatch, got %v", err)
+	}
+
+	if err = ioutil.WriteFile(filepath.Join(dir, "loop.yaml"), []byte("extends:\n  - source: loop.yaml\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c = &Conform{Extends: []*ExtendDeclaration{{Source: "loop.yaml"}}}
+	if _, err = e.extend(c, dir, nil); err == nil || !strings.Contains(err.Error(), "extends itself") {
+		t.Errorf("Expected a cycle error, got %v", err)
+	}
+}
+
+func TestExtendHTTPS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+
+	requests := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/org/conform.yaml":
+			fmt.Fprint(w, "extends:\n  - source: base.yaml\n")
+		case "/org/base.yaml":
+			fmt.Fprint(w, baseConfig)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	e := &extender{cacheDir: dir, client: server.Client()}
+	expectBase(t, extendConfig(t, e, ".", "extends:\n  - source: "+server.URL+"/org/conform.yaml"+override))
+
+	// Pinned configurations are served from the cache.
+	requests = 0
+	config := "extends:\n  - source: " + server.URL + "/org/base.yaml\n    checksum: " + checksum(baseConfig) + override
+	expectBase(t, extendConfig(t, e, ".", config))
+	if requests != 0 {
+		t.Errorf("Expected the pinned configuration to be cached, got %d requests", requests)
+	}
+}
+
+func TestExtendGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+
+	repo := filepath.Join(dir, "policies")
+	if err = os.MkdirAll(filepath.Join(repo, "conform"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(repo, "conform", "base.yaml"), []byte(baseConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "base"},
+		{"tag", "v1"},
+	} {
+		if err = runGit(repo, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	e := &extender{cacheDir: filepath.Join(dir, "cache"), client: http.DefaultClient}
+	source := "git+file://" + filepath.ToSlash(repo) + "//conform/base.yaml"
+	expectBase(t, extendConfig(t, e, ".", "extends:\n  - source: "+source+"@v1"+override))
+
+	// Refs are not read as options of git.
+	c := &Conform{Extends: []*ExtendDeclaration{{Source: source + "@--upload-pack=false"}}}
+	if _, err = e.extend(c, ".", nil); err == nil || !strings.Contains(err.Error(), "invalid ref") {
+		t.Errorf("Expected the ref to be invalid, got %v", err)
+	}
+
+	// The cached repository is used when it cannot be fetched.
+	if err = os.RemoveAll(repo); err != nil {
+		t.Fatal(err)
+	}
+	expectBase(t, extendConfig(t, e, ".", "extends:\n  - source: "+source+"@v1"+override))
+}
+
+func TestMergeConfig(t *testing.T) {
+	base := &Conform{
+		Policies: []*PolicyDeclaration{
+			{Type: "exec", Spec: map[interface{}]interface{}{"name": "A", "command": "a"}},
+			{Type: "exec", Spec: map[interface{}]interface{}{"name": "B", "command": "b"}, Severity: policy.SeverityWarn},
+		},
+		Plugins: []*PluginDeclaration{{Name: "custom", Path: "./old"}},
+	}
+	override := &Conform{
+		Policies: []*PolicyDeclaration{
+			{Type: "exec", Spec: map[interface{}]interface{}{"name": "B", "args": []interface{}{"-v"}}},
+		},
+		Plugins: []*PluginDeclaration{{Name: "custom", Path: "./new"}},
+	}
+
+	merged := mergeConfig(base, override)
+	expected := map[interface{}]interface{}{"name": "B", "command": "b", "args": []interface{}{"-v"}}
+	if len(merged.Policies) != 2 || !reflect.DeepEqual(merged.Policies[1].Spec, expected) {
+		t.Errorf("Expected exec policy B to be merged, got %v", merged.Policies)
+	}
+	if severity := merged.Policies[1].Severity; severity != policy.SeverityWarn {
+		t.Errorf("Expected inherited severity warn, got %q", severity)
+	}
+	if len(merged.Plugins) != 1 || merged.Plugins[0].Path != "./new" {
+		t.Errorf("Expected plugin to be replaced, got %v", merged.Plugins)
+	}
+}
+
+func TestConvertConfig(t *testing.T) {
+	type testDesc struct {
+		Name     string
+		Contents string
+	}
+
+	for _, test := range []testDesc{
+		{
+			Name:     ".conform.yaml",
+			Contents: baseConfig,
+		},
+		{
+			Name:     ".conform.json",
+			Contents: `{"policies": [{"type": "commit", "spec": {"headerLength": 72, "dco": true}}, {"type": "license", "spec": {"includeSuffixes": [".go"]}}]}`,
+		},
+		{
+			Name: ".conform.toml",
+			Contents: `[[policies]]
+type = "commit"
+[policies.spec]
+headerLength = 72
+dco = true
+
+[[policies]]
+type = "license"
+[policies.spec]
+includeSuffixes = [".go"]
+`,
+		},
+		{
+			Name:     "git+https://example.com/org/repo.git//.conform.toml@v1",
+			Contents: "[[policies]]\ntype = \"commit\"\n[policies.spec]\nheaderLength = 72\ndco = true\n\n[[policies]]\ntype = \"license\"\n[policies.spec]\nincludeSuffixes = [\".go\"]\n",
+		},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			configBytes, err := convertConfig(sourceName(test.Name), []byte(test.Contents))
+			if err != nil {
+				tt.Fatalf("Unexpected error: %v", err)
+			}
+			c := &Conform{}
+			if err = yaml.Unmarshal(configBytes, c); err != nil {
+				tt.Fatalf("Unexpected error: %v", err)
+			}
+			if len(c.Policies) != 2 || c.Policies[0].Type != "commit" || c.Policies[1].Type != "license" {
+				tt.Fatalf("Expected commit and license policies, got %+v", c.Policies)
+			}
+			spec, ok := c.Policies[0].Spec.(map[interface{}]interface{})
+			if !ok || fmt.Sprint(spec["headerLength"]) != "72" || spec["dco"] != true {
+				tt.Errorf("Expected the commit spec to be converted, got %v", c.Policies[0].Spec)
+			}
+		})
+	}
+
+	if _, err := convertConfig(".conform.toml", []byte("policies = [")); err == nil {
+		t.Errorf("Expected invalid TOML to be an error")
+	}
+}
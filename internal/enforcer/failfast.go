@@ -0,0 +1,85 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"fmt"
+
+	"github.com/autonomy/conform/internal/reporter"
+)
+
+// notRunMessage is the message of the checks that are not run because
+// enforcement stopped at the first failure.
+const notRunMessage = "Not run, stopped at the first failure"
+
+// plannedChecks returns the names of the checks the policy runs, as described
+// by the catalog. It returns nil for plugins, whose checks are only known once
+// they run.
+func plannedChecks(p *PolicyDeclaration) []string {
+	d, ok := catalog[p.Type]
+	if !ok {
+		return nil
+	}
+	if namedChecks[p.Type] {
+		if name, ok := specName(p.Spec).(string); ok && name != "" {
+			return []string{name}
+		}
+		return []string{d.Checks[0].Name}
+	}
+
+	var names []string
+	for _, check := range d.Checks {
+		if len(check.Options) == 0 || enables(p.Spec, check.Options) {
+			names = append(names, check.Name)
+		}
+	}
+
+	return names
+}
+
+// skipNotRun reports the checks of a policy that is not run because
+// enforcement stopped at the first failure, and returns their number. In
+// quiet mode, the checks are only counted.
+func (c *Conform) skipNotRun(t *table, name string, p *PolicyDeclaration) int {
+	checks := plannedChecks(p)
+	if checks == nil {
+		if !c.options.Quiet && c.options.shows(p.severity("")) {
+			t.row(name, "<all>", "SKIPPED", notRunMessage)
+		}
+		t.report.Add(&reporter.Check{Policy: name, Name: "<all>", Severity: p.severity(""), Status: reporter.StatusSkipped, Message: notRunMessage})
+		return 1
+	}
+
+	n := 0
+	for _, check := range checks {
+		if !c.options.selectsCheck(check) || c.options.skips(p.Type, check) {
+			continue
+		}
+		if !c.options.Quiet && c.options.shows(p.severity(check)) {
+			t.row(name, check, "SKIPPED", notRunMessage)
+		}
+		t.report.Add(&reporter.Check{
+			Policy:      name,
+			Name:        check,
+			Description: describeCheck(p.Type, check),
+			Severity:    p.severity(check),
+			Status:      reporter.StatusSkipped,
+			Message:     notRunMessage,
+		})
+		n++
+	}
+
+	return n
+}
+
+// notRunSummary summarizes the checks that were not run because enforcement
+// stopped at the first failure.
+func notRunSummary(n int) string {
+	if n == 1 {
+		return "Stopped at the first failure: 1 check was not run"
+	}
+
+	return fmt.Sprintf("Stopped at the first failure: %d checks were not run", n)
+}
@@ -0,0 +1,57 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlannedChecks(t *testing.T) {
+	tests := []struct {
+		name     string
+		p        *PolicyDeclaration
+		expected []string
+	}{
+		{
+			name: "Options",
+			p: &PolicyDeclaration{Type: "commit", Spec: map[interface{}]interface{}{
+				"headerLength": 72,
+				"dco":          true,
+				"gpg":          false,
+			}},
+			expected: []string{"Header Length", "DCO"},
+		},
+		{
+			name:     "WithoutOptions",
+			p:        &PolicyDeclaration{Type: "diffsize"},
+			expected: []string{"Diff Size"},
+		},
+		{
+			name:     "Named",
+			p:        &PolicyDeclaration{Type: "exec", Spec: map[interface{}]interface{}{"name": "Lint"}},
+			expected: []string{"Lint"},
+		},
+		{
+			name:     "DefaultName",
+			p:        &PolicyDeclaration{Type: "cue"},
+			expected: []string{"CUE"},
+		},
+		{
+			name: "Plugin",
+			p:    &PolicyDeclaration{Type: "custom"},
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if checks := plannedChecks(test.p); !reflect.DeepEqual(checks, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, checks)
+			}
+		})
+	}
+}
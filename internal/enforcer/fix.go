@@ -0,0 +1,126 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/autonomy/conform/internal/logging"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/autonomy/conform/internal/progress"
+	"github.com/pkg/errors"
+)
+
+// fixSummary counts the fixes of the policies.
+type fixSummary struct {
+	applied   int
+	suggested int
+	failed    int
+}
+
+// Fix asks the selected policies that implement policy.Fixer to repair their
+// violations, including those of the subdirectories, and writes the fixes
+// applied and suggested. In dry run mode, the fixes are written without being
+// applied. An error is returned if a fix fails.
+func (c *Conform) Fix(setters ...policy.Option) error {
+	opts := c.policyOptions(setters...)
+
+	t := c.newTable(os.Stdout, "POLICY", "FILE", "STATUS", "DESCRIPTION")
+
+	summary := &fixSummary{}
+	err := c.fixPolicies(t, "", opts, summary)
+	for _, d := range c.directories {
+		if err != nil {
+			break
+		}
+		if err = os.Chdir(d.path); err != nil {
+			break
+		}
+		err = d.conform.fixPolicies(t, d.name+":", opts, summary)
+		if chdirErr := os.Chdir(d.root); err == nil {
+			err = chdirErr
+		}
+	}
+
+	progress.Clear()
+	// nolint: errcheck
+	t.flush()
+	if err != nil {
+		return err
+	}
+
+	verb := "Applied"
+	if c.options.DryRun {
+		verb = "Would apply"
+	}
+	fmt.Printf("%s %d fixes, suggested %d\n", verb, summary.applied, summary.suggested)
+	if summary.failed != 0 {
+		return errors.Errorf("%d fixes failed", summary.failed)
+	}
+
+	return nil
+}
+
+func (c *Conform) fixPolicies(t *table, prefix string, opts *policy.Options, summary *fixSummary) error {
+	for i, declaration := range c.Policies {
+		name := prefix + declaration.Type
+		if !c.options.selectsPolicy(declaration.Type) || c.options.skips(declaration.Type, "") {
+			continue
+		}
+		progress.Policy(name, i+1, len(c.Policies))
+		fixer, err := newFixer(declaration)
+		if err != nil {
+			return err
+		}
+		if fixer == nil {
+			continue
+		}
+
+		fixes, err := fixer.Fixes(opts)
+		if err != nil {
+			return errors.Errorf("%s: %v", name, err)
+		}
+		for _, fix := range fixes {
+			file := fix.File
+			if file == "" {
+				file = "<none>"
+			}
+			status := "FIXED"
+			switch {
+			case fix.Apply == nil:
+				status = "SUGGESTED"
+				summary.suggested++
+			case c.options.DryRun:
+				status = "WOULD FIX"
+				summary.applied++
+			default:
+				if err = fix.Apply(); err != nil {
+					t.row(name, file, "FAILED", fmt.Sprintf("%s: %v", fix.Description, err))
+					summary.failed++
+					continue
+				}
+				logging.Info("applied fix", "policy", name, "file", fix.File)
+				summary.applied++
+			}
+			t.row(name, file, status, fix.Description)
+		}
+	}
+
+	return nil
+}
+
+// newFixer returns the fixer of the policy, or nil if its type cannot fix its
+// violations.
+func newFixer(declaration *PolicyDeclaration) (policy.Fixer, error) {
+	p, ok, err := newPolicy(declaration)
+	if err != nil || !ok {
+		// Plugins cannot fix their violations.
+		return nil, err
+	}
+	fixer, _ := p.(policy.Fixer)
+
+	return fixer, nil
+}
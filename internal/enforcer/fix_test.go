@@ -0,0 +1,89 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestFixPolicies(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.Chdir(wd)
+
+	files := map[string]string{
+		"a.go": "package a\n\n\n",
+		"b.go": "// Copyright Acme\npackage b",
+		"c.go": "// Copyright Acme\npackage c\n",
+	}
+	for name, contents := range files {
+		if err = ioutil.WriteFile(name, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := &Conform{
+		Policies: []*PolicyDeclaration{
+			{Type: "license", Spec: map[interface{}]interface{}{
+				"includeSuffixes": []interface{}{".go"},
+				"header":          "// Copyright Acme\n",
+			}},
+			{Type: "newline", Spec: map[interface{}]interface{}{"includeSuffixes": []interface{}{".go"}}},
+		},
+		options: NewDefaultOptions(),
+	}
+
+	for _, dryRun := range []bool{true, false} {
+		c.options.DryRun = dryRun
+		var b bytes.Buffer
+		summary := &fixSummary{}
+		table := newTable(&b, nil, "POLICY", "FILE", "STATUS", "DESCRIPTION")
+		if err = c.fixPolicies(table, "", policy.NewDefaultOptions(), summary); err != nil {
+			t.Fatal(err)
+		}
+		if err = table.flush(); err != nil {
+			t.Fatal(err)
+		}
+		if summary.applied != 3 || summary.suggested != 0 || summary.failed != 0 {
+			t.Errorf("Expected 3 fixes, got %+v:\n%s", summary, b.String())
+		}
+		if dryRun && !strings.Contains(b.String(), "WOULD FIX") {
+			t.Errorf("Expected fixes not to be applied in dry run mode, got:\n%s", b.String())
+		}
+	}
+
+	expected := map[string]string{
+		"a.go": "// Copyright Acme\npackage a\n",
+		"b.go": "// Copyright Acme\npackage b\n",
+		"c.go": "// Copyright Acme\npackage c\n",
+	}
+	for name, contents := range expected {
+		actual, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(actual) != contents {
+			t.Errorf("Expected %s to be fixed to %q, got %q", name, contents, actual)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import "github.com/autonomy/conform/internal/policy"
+
+// mergeConfig returns the configuration resulting from override taking
+// precedence over base. A policy overrides the policy of base with the same
+// type, and the same name for policies naming their check (e.g. exec), by
+// merging their specs recursively and overriding their severities. Other policies are appended. A plugin
+// replaces the plugin of base with the same name, and profiles with the same
+// name are merged. The reporters of override replace those of base.
+func mergeConfig(base, override *Conform) *Conform {
+	merged := &Conform{}
+
+	merged.Policies = append(merged.Policies, base.Policies...)
+	for _, p := range override.Policies {
+		i := indexPolicy(merged.Policies, p)
+		if i < 0 {
+			merged.Policies = append(merged.Policies, p)
+			continue
+		}
+		merged.Policies[i] = mergePolicy(merged.Policies[i], p)
+	}
+
+	merged.Plugins = append(merged.Plugins, base.Plugins...)
+	for _, p := range override.Plugins {
+		replaced := false
+		for i := range merged.Plugins {
+			if merged.Plugins[i].Name == p.Name {
+				merged.Plugins[i] = p
+				replaced = true
+			}
+		}
+		if !replaced {
+			merged.Plugins = append(merged.Plugins, p)
+		}
+	}
+
+	merged.Profiles = mergeProfiles(base.Profiles, override.Profiles)
+
+	merged.Timeout = base.Timeout
+	if override.Timeout != "" {
+		merged.Timeout = override.Timeout
+	}
+
+	merged.Reporters = base.Reporters
+	if len(override.Reporters) != 0 {
+		merged.Reporters = override.Reporters
+	}
+
+	return merged
+}
+
+func mergePolicy(base, override *PolicyDeclaration) *PolicyDeclaration {
+	merged := &PolicyDeclaration{
+		Type:     override.Type,
+		Spec:     mergeValues(base.Spec, override.Spec),
+		Severity: base.Severity,
+	}
+	if override.Severity != "" {
+		merged.Severity = override.Severity
+	}
+	merged.Timeout = base.Timeout
+	if override.Timeout != "" {
+		merged.Timeout = override.Timeout
+	}
+	if len(base.CheckSeverity) != 0 || len(override.CheckSeverity) != 0 {
+		merged.CheckSeverity = map[string]policy.Severity{}
+		for check, severity := range base.CheckSeverity {
+			merged.CheckSeverity[check] = severity
+		}
+		for check, severity := range override.CheckSeverity {
+			merged.CheckSeverity[check] = severity
+		}
+	}
+
+	return merged
+}
+
+func indexPolicy(policies []*PolicyDeclaration, p *PolicyDeclaration) int {
+	for i, candidate := range policies {
+		if candidate.Type == p.Type && specName(candidate.Spec) == specName(p.Spec) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func specName(spec interface{}) interface{} {
+	if m, ok := spec.(map[interface{}]interface{}); ok {
+		return m["name"]
+	}
+
+	return nil
+}
+
+// mergeValues merges override into base. Maps are merged recursively, and
+// any other value of override replaces the value of base.
+func mergeValues(base, override interface{}) interface{} {
+	b, ok := base.(map[interface{}]interface{})
+	if !ok {
+		return override
+	}
+	o, ok := override.(map[interface{}]interface{})
+	if !ok {
+		if override == nil {
+			return base
+		}
+		return override
+	}
+
+	merged := make(map[interface{}]interface{}, len(b)+len(o))
+	for k, v := range b {
+		merged[k] = v
+	}
+	for k, v := range o {
+		merged[k] = mergeValues(merged[k], v)
+	}
+
+	return merged
+}
@@ -0,0 +1,65 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"path"
+
+	"github.com/autonomy/conform/internal/reporter"
+	"github.com/pkg/errors"
+)
+
+// DefaultNotifyBranches are the protected branches whose failures are
+// notified by default.
+var DefaultNotifyBranches = []string{"main", "master"}
+
+// validateNotify validates the notification webhook of the options, if any,
+// and detects its format from its URL unless it is set.
+func (o *Options) validateNotify() error {
+	if o.NotifyURL == "" {
+		return nil
+	}
+	if err := reporter.ValidateWebhookURL(o.NotifyURL); err != nil {
+		return err
+	}
+	switch o.NotifyFormat {
+	case "":
+		format, ok := reporter.NotifyFormat(o.NotifyURL)
+		if !ok {
+			return errors.Errorf("Unknown notification webhook %q: set its format to %s or %s", o.NotifyURL, reporter.NotifySlack, reporter.NotifyTeams)
+		}
+		o.NotifyFormat = format
+	case reporter.NotifySlack, reporter.NotifyTeams:
+	default:
+		return errors.Errorf("Unknown notification format %q: must be %s or %s", o.NotifyFormat, reporter.NotifySlack, reporter.NotifyTeams)
+	}
+	for _, pattern := range o.NotifyBranches {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return errors.Errorf("Invalid branch pattern %q: %v", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// notifies reports whether a failure of the enforcement is notified: the
+// options have a notification webhook, and the branch enforced matches one
+// of their protected branches.
+func (o *Options) notifies() bool {
+	if o.NotifyURL == "" || o.Branch == "" {
+		return false
+	}
+	branches := o.NotifyBranches
+	if branches == nil {
+		branches = DefaultNotifyBranches
+	}
+	for _, pattern := range branches {
+		if ok, _ := path.Match(pattern, o.Branch); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,89 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"testing"
+
+	"github.com/autonomy/conform/internal/reporter"
+)
+
+func TestValidateNotify(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Options  []Option
+		Expected string
+		Err      bool
+	}{
+		{Name: "None"},
+		{
+			Name:     "Detected",
+			Options:  []Option{WithNotifyURL("https://hooks.slack.com/services/T000/B000/XXXX")},
+			Expected: reporter.NotifySlack,
+		},
+		{
+			Name:     "Explicit",
+			Options:  []Option{WithNotifyURL("https://chat.example.com/hooks/XXXX"), WithNotifyFormat(reporter.NotifyTeams)},
+			Expected: reporter.NotifyTeams,
+		},
+		{
+			Name:    "Unknown",
+			Options: []Option{WithNotifyURL("https://chat.example.com/hooks/XXXX")},
+			Err:     true,
+		},
+		{
+			Name:    "HTTP",
+			Options: []Option{WithNotifyURL("http://hooks.slack.com/services/T000/B000/XXXX")},
+			Err:     true,
+		},
+		{
+			Name:    "InvalidPattern",
+			Options: []Option{WithNotifyURL("https://hooks.slack.com/services/T000/B000/XXXX"), WithNotifyBranches([]string{"release/["})},
+			Err:     true,
+		},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			opts := NewDefaultOptions(test.Options...)
+			err := opts.validateNotify()
+			if test.Err {
+				if err == nil {
+					tt.Fatal("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				tt.Fatal(err)
+			}
+			if opts.NotifyFormat != test.Expected {
+				tt.Errorf("Expected format %q, got %q", test.Expected, opts.NotifyFormat)
+			}
+		})
+	}
+}
+
+func TestNotifies(t *testing.T) {
+	const url = "https://hooks.slack.com/services/T000/B000/XXXX"
+	for _, test := range []struct {
+		Name     string
+		Options  []Option
+		Expected bool
+	}{
+		{"Default", []Option{WithNotifyURL(url), WithBranch("main")}, true},
+		{"Unprotected", []Option{WithNotifyURL(url), WithBranch("feature")}, false},
+		{"Pattern", []Option{WithNotifyURL(url), WithBranch("release/1.0"), WithNotifyBranches([]string{"release/*"})}, true},
+		{"UnknownBranch", []Option{WithNotifyURL(url)}, false},
+		{"NoWebhook", []Option{WithBranch("main")}, false},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			if notifies := NewDefaultOptions(test.Options...).notifies(); notifies != test.Expected {
+				tt.Errorf("Expected notifies to be %v, got %v", test.Expected, notifies)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic writes the file of the specified name with write, creating
+// its directory if needed. The file is written to a temporary file that
+// replaces it once complete, so that a report archived by a CI system is
+// never partially written, even if enforcement is interrupted.
+func writeFileAtomic(name string, write func(io.Writer) error) error {
+	dir := filepath.Dir(name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(dir, "."+filepath.Base(name)+".")
+	if err != nil {
+		return err
+	}
+	// The temporary file is removed unless it is renamed.
+	// nolint: errcheck
+	defer os.Remove(f.Name())
+
+	if err = write(f); err != nil {
+		// nolint: errcheck
+		f.Close()
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		// nolint: errcheck
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	// Temporary files are only readable by their owner.
+	if err = os.Chmod(f.Name(), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), name)
+}
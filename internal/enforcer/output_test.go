@@ -0,0 +1,66 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+
+	name := filepath.Join(dir, "reports", "conform.json")
+	err = writeFileAtomic(name, func(w io.Writer) error {
+		_, err := io.WriteString(w, "{}\n")
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents, err := ioutil.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(contents) != "{}\n" {
+		t.Errorf("Expected the report to be written, got %q", contents)
+	}
+	info, err := os.Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("Expected mode 0644, got %v", info.Mode().Perm())
+	}
+
+	// A report that fails to be written leaves the previous one in place.
+	err = writeFileAtomic(name, func(w io.Writer) error {
+		// nolint: errcheck
+		io.WriteString(w, "{")
+		return errors.New("interrupted")
+	})
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if contents, err = ioutil.ReadFile(name); err != nil || string(contents) != "{}\n" {
+		t.Errorf("Expected the previous report to be kept, got %q, %v", contents, err)
+	}
+	files, err := ioutil.ReadDir(filepath.Dir(name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("Expected the temporary file to be removed, got %d files", len(files))
+	}
+}
@@ -0,0 +1,89 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"github.com/autonomy/conform/internal/logging"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/autonomy/conform/internal/reporter"
+)
+
+// patches reports whether a reporter writes the patch format, for which the
+// fixes of the violations are gathered.
+func (o *Options) patches() bool {
+	for _, d := range o.reporters() {
+		if d.Format == reporter.PatchFormat {
+			return true
+		}
+	}
+
+	return false
+}
+
+// patch adds the repairs of the files with violations of the checks of the
+// policy to the fixes of the report, combined with those of the other
+// policies of the same files. Only the fixes that know the correct contents
+// of their file are repaired, and suppressed violations, violations in the
+// baseline, and those of checks less severe than the minimum severity are
+// not.
+func (c *Conform) patch(t *table, l *locator, name string, p *PolicyDeclaration, opts *policy.Options, checks []*reporter.Check) {
+	files := map[string]bool{}
+	for _, rc := range checks {
+		if rc == nil || !c.options.shows(rc.Severity) {
+			continue
+		}
+		for _, v := range rc.Violations {
+			if v.File != "" && v.Status != reporter.StatusSuppressed && v.Status != reporter.StatusBaseline {
+				files[v.File] = true
+			}
+		}
+	}
+	if len(files) == 0 {
+		return
+	}
+
+	fixer, err := newFixer(p)
+	if err != nil {
+		logging.Error("failed to fix the violations", "policy", name, "error", err)
+		return
+	}
+	if fixer == nil {
+		return
+	}
+	fixes, err := fixer.Fixes(opts)
+	if err != nil {
+		logging.Error("failed to fix the violations", "policy", name, "error", err)
+		return
+	}
+	for _, fix := range fixes {
+		file := l.file(fix.File)
+		if fix.Repair == nil || !files[file] {
+			continue
+		}
+		fileFix := t.fix(file)
+		if fileFix == nil {
+			contents, err := policy.ReadFile(opts.Tree, fix.File)
+			if err != nil {
+				logging.Error("failed to read the file", "policy", name, "file", fix.File, "error", err)
+				continue
+			}
+			fileFix = &reporter.FileFix{File: file, Contents: contents, Fixed: contents}
+			t.report.Fixes = append(t.report.Fixes, fileFix)
+		}
+		fileFix.Fixed = fix.Repair(fileFix.Fixed)
+		logging.Debug("repaired file", "policy", name, "file", file, "fix", fix.Description)
+	}
+}
+
+// fix returns the fix of the file of the report, if any.
+func (t *table) fix(file string) *reporter.FileFix {
+	for _, fix := range t.report.Fixes {
+		if fix.File == file {
+			return fix
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/autonomy/conform/internal/reporter"
+)
+
+func TestPatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.Chdir(wd)
+
+	files := map[string]string{
+		"a.go": "package a",
+		"b.go": "package b\n",
+		"c.go": "package c\n\n",
+	}
+	for name, contents := range files {
+		if err = ioutil.WriteFile(name, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	license := &PolicyDeclaration{Type: "license", Spec: map[interface{}]interface{}{
+		"includeSuffixes": []interface{}{".go"},
+		"header":          "// Copyright Acme\n",
+	}}
+	newline := &PolicyDeclaration{Type: "newline", Spec: map[interface{}]interface{}{"includeSuffixes": []interface{}{".go"}}}
+	c := &Conform{options: NewDefaultOptions()}
+	table := newTable(ioutil.Discard, nil)
+	l := &locator{}
+	c.patch(table, l, "license", license, policy.NewDefaultOptions(), []*reporter.Check{{
+		Name:     "File Header",
+		Severity: policy.SeverityError,
+		Violations: []*reporter.Violation{
+			{Status: reporter.StatusFailed, File: "a.go"},
+			{Status: reporter.StatusSuppressed, File: "b.go"},
+		},
+	}})
+	c.patch(table, l, "newline", newline, policy.NewDefaultOptions(), []*reporter.Check{{
+		Name:     "EOF Newline",
+		Severity: policy.SeverityError,
+		Violations: []*reporter.Violation{
+			{Status: reporter.StatusFailed, File: "a.go"},
+			{Status: reporter.StatusFailed, File: "c.go"},
+		},
+	}})
+
+	expected := map[string]string{
+		"a.go": "// Copyright Acme\npackage a\n",
+		"c.go": "package c\n",
+	}
+	if len(table.report.Fixes) != len(expected) {
+		t.Fatalf("Expected fixes of %d files, got %d", len(expected), len(table.report.Fixes))
+	}
+	for _, fix := range table.report.Fixes {
+		if !bytes.Equal(fix.Contents, []byte(files[fix.File])) {
+			t.Errorf("Expected the contents of %s to be %q, got %q", fix.File, files[fix.File], fix.Contents)
+		}
+		if !bytes.Equal(fix.Fixed, []byte(expected[fix.File])) {
+			t.Errorf("Expected %s to be fixed to %q, got %q", fix.File, expected[fix.File], fix.Fixed)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/autonomy/conform/internal/jsonschema"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/autonomy/conform/pkg/plugin"
+	"github.com/pkg/errors"
+)
+
+// PluginDeclaration registers a third-party policy implemented by a plugin.
+// Policies declared with the name of the plugin as their type are enforced by
+// the plugin.
+type PluginDeclaration struct {
+	Name string   `yaml:"name"`
+	Path string   `yaml:"path"`
+	Args []string `yaml:"args"`
+}
+
+// pluginPolicy implements the policy.Policy interface by calling a plugin.
+type pluginPolicy struct {
+	plugin *PluginDeclaration
+	spec   interface{}
+}
+
+// pluginCheck is a check reported by a plugin.
+type pluginCheck struct {
+	result plugin.Result
+}
+
+// Name returns the name of the check.
+func (p pluginCheck) Name() string {
+	return p.result.Name
+}
+
+// Message returns to check message.
+func (p pluginCheck) Message() string {
+	return p.result.Message
+}
+
+// Errors returns any violations of the check.
+func (p pluginCheck) Errors() []error {
+	errs := make([]error, 0, len(p.result.Errors))
+	for _, msg := range p.result.Errors {
+		errs = append(errs, errors.New(msg))
+	}
+
+	return errs
+}
+
+// Compliance implements the policy.Policy.Compliance function. The plugin is
+// started for the duration of the call.
+func (p *pluginPolicy) Compliance(options *policy.Options) (*policy.Report, error) {
+	report := &policy.Report{}
+
+	opts := &plugin.Options{}
+	if options.CommitMsgFile != nil {
+		opts.CommitMsgFile = *options.CommitMsgFile
+	}
+	if options.BaseBranch != nil {
+		opts.BaseBranch = *options.BaseBranch
+	}
+	if options.AuthorName != nil {
+		opts.AuthorName = *options.AuthorName
+	}
+	if options.AuthorEmail != nil {
+		opts.AuthorEmail = *options.AuthorEmail
+	}
+	if options.Ref != nil {
+		opts.Ref = *options.Ref
+	}
+	if p.spec != nil {
+		spec, ok := jsonschema.Normalize(p.spec).(map[string]interface{})
+		if !ok {
+			return report, errors.Errorf("spec of plugin %q must be a map", p.plugin.Name)
+		}
+		opts.Spec = spec
+	}
+
+	cmd := exec.CommandContext(options.Context, p.plugin.Path, p.plugin.Args...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return report, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return report, err
+	}
+	if err = cmd.Start(); err != nil {
+		return report, errors.Errorf("failed to start plugin %q: %v", p.plugin.Name, err)
+	}
+	// nolint: errcheck
+	defer cmd.Wait()
+
+	client, err := plugin.NewClient(struct {
+		io.Reader
+		io.Writer
+		io.Closer
+	}{stdout, stdin, stdin})
+	if err != nil {
+		return report, errors.Errorf("plugin %q: %v", p.plugin.Name, err)
+	}
+	// nolint: errcheck
+	defer client.Close()
+
+	results, err := client.Compliance(opts)
+	if err != nil {
+		return report, errors.Errorf("plugin %q: %v", p.plugin.Name, err)
+	}
+	for _, result := range results {
+		report.AddCheck(&pluginCheck{result: result})
+	}
+
+	return report, nil
+}
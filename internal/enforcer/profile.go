@@ -0,0 +1,63 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+// Profile is a named set of overrides of the configuration, applied with
+// --profile, so that e.g. local runs can be more lenient than CI.
+type Profile struct {
+	// Policies override the policies of the configuration in the same way as
+	// the policies of .conform.local.yaml.
+	Policies []*PolicyDeclaration `yaml:"policies"`
+	// Plugins replace the plugins of the configuration with the same name.
+	Plugins []*PluginDeclaration `yaml:"plugins"`
+	// Skip skips the checks with the specified names, or the policies of the
+	// specified types, in addition to those of --skip.
+	Skip []string `yaml:"skip"`
+}
+
+// applyProfile returns the configuration with the policies and plugins of the
+// profile of the specified name applied. The configuration is returned as is
+// if it does not define the profile.
+func (c *Conform) applyProfile(name string) *Conform {
+	p, ok := c.Profiles[name]
+	if !ok {
+		return c
+	}
+
+	merged := mergeConfig(c, &Conform{Policies: p.Policies, Plugins: p.Plugins})
+	merged.Profiles = c.Profiles
+	merged.deprecated = c.deprecated
+
+	return merged
+}
+
+// mergeProfiles returns the profiles resulting from override taking
+// precedence over base. Profiles with the same name are merged in the same
+// way as configurations, and skip the checks of both.
+func mergeProfiles(base, override map[string]*Profile) map[string]*Profile {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+
+	merged := map[string]*Profile{}
+	for name, p := range base {
+		merged[name] = p
+	}
+	for name, p := range override {
+		b, ok := merged[name]
+		if !ok {
+			merged[name] = p
+			continue
+		}
+		c := mergeConfig(&Conform{Policies: b.Policies, Plugins: b.Plugins}, &Conform{Policies: p.Policies, Plugins: p.Plugins})
+		merged[name] = &Profile{
+			Policies: c.Policies,
+			Plugins:  c.Plugins,
+			Skip:     append(append([]string{}, b.Skip...), p.Skip...),
+		}
+	}
+
+	return merged
+}
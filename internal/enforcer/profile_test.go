@@ -0,0 +1,100 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestApplyProfile(t *testing.T) {
+	config := `
+policies:
+  - type: commit
+    spec:
+      dco: true
+      headerLength: 72
+profiles:
+  local:
+    policies:
+      - type: commit
+        severity: warn
+        spec:
+          dco: false
+    skip:
+      - GPG
+`
+	// The local configuration adds to the profile of the same name.
+	local := `
+profiles:
+  local:
+    policies:
+      - type: license
+        spec:
+          header: x
+    skip:
+      - DCO
+`
+	tests := []struct {
+		name     string
+		profile  string
+		expected []*PolicyDeclaration
+	}{
+		{
+			name:    "Local",
+			profile: "local",
+			expected: []*PolicyDeclaration{
+				{Type: "commit", Severity: policy.SeverityWarn, Spec: map[interface{}]interface{}{
+					"dco":          false,
+					"headerLength": 72,
+				}},
+				{Type: "license", Spec: map[interface{}]interface{}{"header": "x"}},
+			},
+		},
+		{
+			name:    "Undefined",
+			profile: "ci",
+			expected: []*PolicyDeclaration{
+				{Type: "commit", Spec: map[interface{}]interface{}{
+					"dco":          true,
+					"headerLength": 72,
+				}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(tt *testing.T) {
+			base, override := &Conform{}, &Conform{}
+			if err := yaml.Unmarshal([]byte(config), base); err != nil {
+				tt.Fatal(err)
+			}
+			if err := yaml.Unmarshal([]byte(local), override); err != nil {
+				tt.Fatal(err)
+			}
+			c := mergeConfig(base, override).applyProfile(test.profile)
+			if !reflect.DeepEqual(c.Policies, test.expected) {
+				tt.Errorf("Expected policies %s, got %s", marshal(tt, test.expected), marshal(tt, c.Policies))
+			}
+			if skip := c.Profiles["local"].Skip; !reflect.DeepEqual(skip, []string{"GPG", "DCO"}) {
+				tt.Errorf("Expected the profile to skip GPG and DCO, got %v", skip)
+			}
+		})
+	}
+}
+
+func marshal(t *testing.T, v interface{}) string {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(b)
+}
@@ -0,0 +1,280 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/logging"
+	"github.com/pkg/errors"
+	gogit "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// zeroSHA is the SHA of the updates that create or delete refs.
+const zeroSHA = "0000000000000000000000000000000000000000"
+
+// receiveEnv are the environment variables git sets for the hooks of a
+// repository that receives a push. They point git at the bare repository,
+// and are unset so that the git commands of policies operate on the checkout.
+var receiveEnv = []string{
+	"GIT_DIR",
+	"GIT_OBJECT_DIRECTORY",
+	"GIT_ALTERNATE_OBJECT_DIRECTORIES",
+	"GIT_QUARANTINE_PATH",
+}
+
+// RefUpdate is the update of a ref by a push, as read by the pre-receive hook.
+type RefUpdate struct {
+	Old string
+	New string
+	Ref string
+}
+
+// Deletes reports whether the update deletes the ref.
+func (u RefUpdate) Deletes() bool {
+	return u.New == zeroSHA
+}
+
+// Creates reports whether the update creates the ref.
+func (u RefUpdate) Creates() bool {
+	return u.Old == zeroSHA
+}
+
+// Branch returns the short name of the branch updated, or "" if the ref is
+// not a branch, e.g. a tag.
+func (u RefUpdate) Branch() string {
+	if !strings.HasPrefix(u.Ref, "refs/heads/") {
+		return ""
+	}
+
+	return strings.TrimPrefix(u.Ref, "refs/heads/")
+}
+
+// ParseRefUpdates parses the updates of a push, one "<old> <new> <ref>" line
+// per ref, as written to the standard input of the pre-receive hook.
+func ParseRefUpdates(r io.Reader) ([]RefUpdate, error) {
+	var updates []RefUpdate
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 3 || !isCommitHash(fields[0]) || !isCommitHash(fields[1]) {
+			return nil, errors.Errorf("Invalid ref update %q: expected <old> <new> <ref>", line)
+		}
+		updates = append(updates, RefUpdate{Old: fields[0], New: fields[1], Ref: fields[2]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return updates, nil
+}
+
+// ReceiveRepo is the bare repository of a git server receiving a push.
+type ReceiveRepo struct {
+	// dir is the directory of the repository.
+	dir string
+	// objects are the object directories of the repository, those of the
+	// quarantine of the objects being pushed first.
+	objects []string
+}
+
+// OpenReceiveRepo opens the bare repository that runs the pre-receive hook,
+// that of GIT_DIR or else the working directory. The environment variables
+// git sets for the hook are unset.
+func OpenReceiveRepo() (*ReceiveRepo, error) {
+	dir := os.Getenv("GIT_DIR")
+	if dir == "" {
+		dir = "."
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !git.IsBare(dir) {
+		return nil, errors.Errorf("%s is not a bare repository", dir)
+	}
+
+	r := &ReceiveRepo{dir: dir}
+	// The objects of a push are quarantined until the hook accepts them.
+	if quarantine := os.Getenv("GIT_QUARANTINE_PATH"); quarantine != "" {
+		r.objects = append(r.objects, quarantine)
+	}
+	r.objects = append(r.objects, filepath.Join(dir, "objects"))
+	for _, env := range receiveEnv {
+		if err = os.Unsetenv(env); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// Repository creates a temporary repository whose HEAD is the branch updated,
+// at the commit it is updated to, and returns its directory, along with the
+// base revision the changes of the update are made since. The repository
+// shares the objects of the bare repository rather than copying them, and
+// nothing is checked out: the policies read the files of the tree of the
+// commit. The caller removes the directory once done.
+//
+// The base of an update is the commit the branch is updated from. A created
+// branch has none, so that its base is a commit of the temporary repository
+// whose parents are the commits of the refs of the bare repository, as git
+// rev-list --not --all excludes them: the commits of the branch already in
+// the repository are not enforced again, and all of them are if it has no
+// refs yet.
+func (r *ReceiveRepo) Repository(u RefUpdate) (dir, base string, err error) {
+	if dir, err = ioutil.TempDir("", "conform-receive"); err != nil {
+		return "", "", err
+	}
+	if base, err = r.repository(dir, u); err != nil {
+		// nolint: errcheck
+		os.RemoveAll(dir)
+		return "", "", err
+	}
+
+	return dir, base, nil
+}
+
+func (r *ReceiveRepo) repository(dir string, u RefUpdate) (string, error) {
+	if _, err := gogit.PlainInit(dir, false); err != nil {
+		return "", err
+	}
+
+	// Alternates name object directories, but go-git reads the "objects"
+	// directory of their parent, which the quarantine directory is not
+	// named. Each directory is linked as the "objects" directory of its own
+	// parent in the repository.
+	alternates := make([]string, 0, len(r.objects))
+	for i, objects := range r.objects {
+		parent := filepath.Join(dir, ".git", "conform", "alternates", strconv.Itoa(i))
+		if err := os.MkdirAll(parent, 0755); err != nil {
+			return "", err
+		}
+		if err := os.Symlink(objects, filepath.Join(parent, "objects")); err != nil {
+			return "", err
+		}
+		alternates = append(alternates, filepath.Join(parent, "objects"))
+	}
+	info := filepath.Join(dir, ".git", "objects", "info")
+	if err := os.MkdirAll(info, 0755); err != nil {
+		return "", err
+	}
+	if err := ioutil.WriteFile(filepath.Join(info, "alternates"), []byte(strings.Join(alternates, "\n")+"\n"), 0644); err != nil {
+		return "", err
+	}
+	// The repository is opened again so that the objects of the alternates
+	// are read.
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		return "", err
+	}
+
+	logging.Info("reading pushed commit", "ref", u.Ref, "sha", u.New, "dir", dir)
+	sha := plumbing.NewHash(u.New)
+	if _, err = repo.CommitObject(sha); err != nil {
+		return "", errors.Errorf("failed to read the commit %s of %s: %v", u.New, u.Ref, err)
+	}
+	name := plumbing.ReferenceName(u.Ref)
+	if err = repo.Storer.SetReference(plumbing.NewHashReference(name, sha)); err != nil {
+		return "", err
+	}
+	if err = repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, name)); err != nil {
+		return "", err
+	}
+
+	if !u.Creates() {
+		return u.Old, nil
+	}
+	refs, err := r.refs()
+	if err != nil {
+		return "", errors.Errorf("failed to list the refs of the repository: %v", err)
+	}
+	base, err := commitParents(repo, refs)
+	if err != nil {
+		return "", err
+	}
+
+	return base.String(), nil
+}
+
+// refs returns the commits of the refs of the repository, peeling annotated
+// tags. The refs of other objects are skipped.
+func (r *ReceiveRepo) refs() ([]plumbing.Hash, error) {
+	repo, err := gogit.PlainOpen(r.dir)
+	if err != nil {
+		return nil, err
+	}
+	iter, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+	var hashes []plumbing.Hash
+	seen := map[plumbing.Hash]bool{}
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+		hash := ref.Hash()
+		if tag, err := repo.TagObject(hash); err == nil {
+			c, err := tag.Commit()
+			if err != nil {
+				return nil
+			}
+			hash = c.Hash
+		} else if _, err = repo.CommitObject(hash); err != nil {
+			return nil
+		}
+		if !seen[hash] {
+			seen[hash] = true
+			hashes = append(hashes, hash)
+		}
+		return nil
+	})
+
+	return hashes, err
+}
+
+// commitParents stores a commit of an empty tree whose parents are the
+// commits, and returns its SHA.
+func commitParents(repo *gogit.Repository, parents []plumbing.Hash) (plumbing.Hash, error) {
+	obj := repo.Storer.NewEncodedObject()
+	if err := (&object.Tree{}).Encode(obj); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	tree, err := repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	signature := object.Signature{Name: "conform", Email: "conform@localhost", When: time.Unix(0, 0).UTC()}
+	commit := &object.Commit{
+		Author:       signature,
+		Committer:    signature,
+		Message:      "The refs of the repository\n",
+		TreeHash:     tree,
+		ParentHashes: parents,
+	}
+	obj = repo.Storer.NewEncodedObject()
+	if err = commit.Encode(obj); err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	return repo.Storer.SetEncodedObject(obj)
+}
@@ -0,0 +1,303 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/autonomy/conform/internal/git"
+)
+
+func TestParseRefUpdates(t *testing.T) {
+	old := strings.Repeat("a", 40)
+	updated := strings.Repeat("b", 40)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []RefUpdate
+		err      bool
+	}{
+		{
+			name:  "Updates",
+			input: old + " " + updated + " refs/heads/main\n\n" + zeroSHA + " " + updated + " refs/tags/v1\n",
+			expected: []RefUpdate{
+				{Old: old, New: updated, Ref: "refs/heads/main"},
+				{Old: zeroSHA, New: updated, Ref: "refs/tags/v1"},
+			},
+		},
+		{
+			name:  "Empty",
+			input: "",
+		},
+		{
+			name:  "MissingRef",
+			input: old + " " + updated + "\n",
+			err:   true,
+		},
+		{
+			name:  "InvalidSHA",
+			input: "main " + updated + " refs/heads/main\n",
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			updates, err := ParseRefUpdates(strings.NewReader(test.input))
+			if test.err != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", test.err, err)
+			}
+			if !reflect.DeepEqual(updates, test.expected) {
+				t.Errorf("Expected %v, got %v", test.expected, updates)
+			}
+		})
+	}
+}
+
+func TestRefUpdate(t *testing.T) {
+	sha := strings.Repeat("a", 40)
+
+	tests := []struct {
+		name    string
+		update  RefUpdate
+		creates bool
+		deletes bool
+		branch  string
+	}{
+		{name: "Update", update: RefUpdate{Old: sha, New: sha, Ref: "refs/heads/feature/x"}, branch: "feature/x"},
+		{name: "Create", update: RefUpdate{Old: zeroSHA, New: sha, Ref: "refs/heads/main"}, creates: true, branch: "main"},
+		{name: "Delete", update: RefUpdate{Old: sha, New: zeroSHA, Ref: "refs/heads/main"}, deletes: true, branch: "main"},
+		{name: "Tag", update: RefUpdate{Old: zeroSHA, New: sha, Ref: "refs/tags/v1"}, creates: true},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if creates := test.update.Creates(); creates != test.creates {
+				t.Errorf("Expected creates %v, got %v", test.creates, creates)
+			}
+			if deletes := test.update.Deletes(); deletes != test.deletes {
+				t.Errorf("Expected deletes %v, got %v", test.deletes, deletes)
+			}
+			if branch := test.update.Branch(); branch != test.branch {
+				t.Errorf("Expected branch %q, got %q", test.branch, branch)
+			}
+		})
+	}
+}
+
+func TestReceiveRepository(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+
+	bare := filepath.Join(dir, "server.git")
+	work := filepath.Join(dir, "work")
+	if err = os.Mkdir(work, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(work, "README.md"), []byte("# Pushed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	identity := []string{"-c", "user.name=test", "-c", "user.email=test@example.com"}
+	for _, args := range [][]string{
+		{"init", "--quiet", "--bare", bare},
+		{"init", "--quiet", work},
+		{"-C", work, "commit", "--quiet", "--allow-empty", "-m", "base"},
+		{"-C", work, "push", "--quiet", bare, "HEAD:refs/heads/main"},
+		{"-C", work, "add", "README.md"},
+		{"-C", work, "commit", "--quiet", "-m", "pushed"},
+		{"-C", work, "push", "--quiet", bare, "HEAD:refs/heads/main"},
+	} {
+		if err = runGit("", append(identity, args...)...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = work
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sha := strings.TrimSpace(string(out))
+
+	// nolint: errcheck
+	defer os.Unsetenv("GIT_DIR")
+	if err = os.Setenv("GIT_DIR", bare); err != nil {
+		t.Fatal(err)
+	}
+	r, err := OpenReceiveRepo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := os.LookupEnv("GIT_DIR"); ok {
+		t.Error("Expected GIT_DIR to be unset")
+	}
+
+	repo, _, err := r.Repository(RefUpdate{Old: zeroSHA, New: sha, Ref: "refs/heads/main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(repo)
+
+	if _, err = os.Stat(filepath.Join(repo, "README.md")); !os.IsNotExist(err) {
+		t.Errorf("Expected the pushed commit to not be checked out, got %v", err)
+	}
+	cmd = exec.Command("git", "log", "-1", "--format=%D %s")
+	cmd.Dir = repo
+	out, err = cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if log := strings.TrimSpace(string(out)); log != "HEAD -> main pushed" {
+		t.Errorf("Expected HEAD to be main at the pushed commit, got %q", log)
+	}
+
+	// The files of the pushed commit are read from its tree.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(repo); err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.Chdir(wd)
+	g, err := git.NewGit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, err := g.Tree("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contents, err := tree.ReadFile("README.md"); err != nil || string(contents) != "# Pushed\n" {
+		t.Errorf("Expected to read README.md from the tree, got %q, %v", contents, err)
+	}
+	if err = os.Chdir(wd); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = OpenReceiveRepo(); err == nil {
+		t.Error("Expected the working directory to not be a bare repository")
+	}
+}
+
+func TestReceiveRepositoryBase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+
+	bare := filepath.Join(dir, "server.git")
+	work := filepath.Join(dir, "work")
+	identity := []string{"-c", "user.name=test", "-c", "user.email=test@example.com"}
+	for _, args := range [][]string{
+		{"init", "--quiet", "--bare", bare},
+		{"init", "--quiet", work},
+		{"-C", work, "commit", "--quiet", "--allow-empty", "-m", "base"},
+		{"-C", work, "tag", "-a", "-m", "v1", "v1"},
+		{"-C", work, "push", "--quiet", bare, "HEAD:refs/heads/main", "v1"},
+		{"-C", work, "checkout", "--quiet", "-b", "feature"},
+		{"-C", work, "commit", "--quiet", "--allow-empty", "-m", "first"},
+		{"-C", work, "commit", "--quiet", "--allow-empty", "-m", "second"},
+		// The objects of the branch are in the repository, as those of a
+		// push are while the pre-receive hook runs, but not its ref.
+		{"-C", work, "push", "--quiet", bare, "HEAD:refs/heads/feature"},
+		{"-C", bare, "update-ref", "-d", "refs/heads/feature"},
+	} {
+		if err = runGit("", append(identity, args...)...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := exec.Command("git", "rev-parse", "HEAD", "HEAD~2")
+	cmd.Dir = work
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	shas := strings.Fields(string(out))
+
+	// nolint: errcheck
+	defer os.Unsetenv("GIT_DIR")
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.Chdir(wd)
+
+	for _, test := range []struct {
+		name     string
+		update   RefUpdate
+		deleted  []string
+		expected []string
+	}{
+		{name: "Update", update: RefUpdate{Old: shas[1], New: shas[0], Ref: "refs/heads/feature"}, expected: []string{"second", "first"}},
+		{name: "Create", update: RefUpdate{Old: zeroSHA, New: shas[0], Ref: "refs/heads/feature"}, expected: []string{"second", "first"}},
+		{name: "Tag", update: RefUpdate{Old: zeroSHA, New: shas[0], Ref: "refs/heads/feature"}, deleted: []string{"refs/heads/main"}, expected: []string{"second", "first"}},
+		{name: "Empty", update: RefUpdate{Old: zeroSHA, New: shas[0], Ref: "refs/heads/feature"}, deleted: []string{"refs/tags/v1"}, expected: []string{"second", "first", "base"}},
+	} {
+		// The refs are deleted for the following tests too.
+		for _, ref := range test.deleted {
+			if err = runGit("", "-C", bare, "update-ref", "-d", ref); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err = os.Setenv("GIT_DIR", bare); err != nil {
+			t.Fatal(err)
+		}
+		r, err := OpenReceiveRepo()
+		if err != nil {
+			t.Fatal(err)
+		}
+		repo, base, err := r.Repository(test.update)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// nolint: errcheck
+		defer os.RemoveAll(repo)
+		if err = os.Chdir(repo); err != nil {
+			t.Fatal(err)
+		}
+		g, err := git.NewGit()
+		if err != nil {
+			t.Fatal(err)
+		}
+		commits, err := g.Commits(base)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var subjects []string
+		for _, c := range commits {
+			subjects = append(subjects, strings.TrimSpace(c.Message))
+		}
+		if !reflect.DeepEqual(subjects, test.expected) {
+			t.Errorf("%s: expected the commits %v, got %v", test.name, test.expected, subjects)
+		}
+		if _, err = g.Diff(base); err != nil {
+			t.Errorf("%s: expected the changes since the base, got %v", test.name, err)
+		}
+		if err = os.Chdir(wd); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/autonomy/conform/internal/logging"
+)
+
+// CloneRepo makes a shallow clone of the branch or tag of the repository at
+// the URL into a temporary directory, and returns the directory. The ref
+// defaults to the default branch of the repository. The clone has a history
+// of two commits, so that the changes of HEAD are those from its parent. The
+// caller removes the directory once done.
+func CloneRepo(url, ref string) (string, error) {
+	dir, err := ioutil.TempDir("", "conform-repo")
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"clone", "--quiet", "--depth", "2", "--single-branch"}
+	if ref != "" {
+		args = append(args, "--branch", ref)
+	}
+	logging.Info("cloning repository", "url", url, "ref", ref, "dir", dir)
+	if err = runGit("", append(args, "--", url, dir)...); err != nil {
+		// nolint: errcheck
+		os.RemoveAll(dir)
+		return "", err
+	}
+
+	return dir, nil
+}
@@ -0,0 +1,106 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCloneRepo(t *testing.T) {
+	repo, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(repo)
+
+	identity := []string{"-c", "user.name=test", "-c", "user.email=test@example.com"}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		append(identity, "commit", "--quiet", "--allow-empty", "-m", "initial"),
+		append(identity, "commit", "--quiet", "--allow-empty", "-m", "parent"),
+		append(identity, "commit", "--quiet", "--allow-empty", "-m", "first"),
+		{"tag", "v1"},
+		{"checkout", "--quiet", "-b", "feature"},
+		append(identity, "commit", "--quiet", "--allow-empty", "-m", "second"),
+	} {
+		if err = runGit(repo, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		ref      string
+		expected string
+		err      bool
+	}{
+		{name: "Branch", ref: "feature", expected: "second"},
+		{name: "Tag", ref: "v1", expected: "first"},
+		{name: "Missing", ref: "missing", err: true},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := CloneRepo("file://"+filepath.ToSlash(repo), test.ref)
+			if test.err != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", test.err, err)
+			}
+			if err != nil {
+				return
+			}
+			// nolint: errcheck
+			defer os.RemoveAll(dir)
+
+			out, err := ioutil.ReadFile(filepath.Join(dir, ".git", "shallow"))
+			if err != nil || len(out) == 0 {
+				t.Errorf("Expected a shallow clone, got %v", err)
+			}
+			// The history is HEAD and its parent.
+			cmd := exec.Command("git", "rev-list", "--count", "HEAD")
+			cmd.Dir = dir
+			if out, err = cmd.Output(); err != nil {
+				t.Fatal(err)
+			}
+			if count := strings.TrimSpace(string(out)); count != "2" {
+				t.Errorf("Expected a history of 2 commits, got %s", count)
+			}
+			cmd = exec.Command("git", "log", "-1", "--format=%s")
+			cmd.Dir = dir
+			out, err = cmd.Output()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if subject := strings.TrimSpace(string(out)); subject != test.expected {
+				t.Errorf("Expected HEAD to be %q, got %q", test.expected, subject)
+			}
+		})
+	}
+}
+
+func TestCloneRepoOption(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+
+	// A URL that looks like an option is not passed to git clone as one.
+	marker := filepath.Join(dir, "marker")
+	if _, err = CloneRepo("--upload-pack=touch "+marker, ""); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if _, err = os.Stat(marker); !os.IsNotExist(err) {
+		t.Errorf("Expected the URL not to be an option, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/autonomy/conform/internal/reporter"
+	"github.com/pkg/errors"
+)
+
+// ReporterDeclaration declares a format the results are written in, and where
+// they are written to.
+type ReporterDeclaration struct {
+	// Format is the name of the format, e.g. table or sarif.
+	Format string `yaml:"format"`
+	// File is the path of the file the results are written to, relative to
+	// the configuration. The results are written to stdout if it is empty.
+	File string `yaml:"file"`
+}
+
+// reporters returns the reporters the results are written with: those of
+// the configuration, or else the single reporter of the output format and
+// file of the options.
+func (o *Options) reporters() []*ReporterDeclaration {
+	if len(o.Reporters) != 0 {
+		return o.Reporters
+	}
+
+	return []*ReporterDeclaration{{Format: o.Output, File: o.OutputFile}}
+}
+
+// validateReporters validates the declarations of reporters. Each must name
+// a known format, the table may only be written to stdout, and only one
+// reporter may write to stdout, so that their results are not interleaved.
+func validateReporters(reporters []*ReporterDeclaration) error {
+	stdout := ""
+	for _, d := range reporters {
+		if _, ok := reporter.Get(d.Format); !ok && d.Format != reporter.Table {
+			return errors.Errorf("Unknown output format %q: must be one of %s", d.Format, strings.Join(reporter.Formats(), ", "))
+		}
+		if d.File != "" {
+			if d.Format == reporter.Table {
+				return errors.Errorf("The output file requires an output format other than %s", reporter.Table)
+			}
+			continue
+		}
+		if stdout != "" {
+			return errors.Errorf("The %s and %s reporters both write to stdout: set the file of one of them", stdout, d.Format)
+		}
+		stdout = d.Format
+	}
+
+	return nil
+}
+
+// writeReport writes the report with the reporter to the file, or to stdout
+// if file is empty.
+func writeReport(rep reporter.Reporter, r *reporter.Report, file string) error {
+	if file == "" {
+		return rep.Write(os.Stdout, r)
+	}
+
+	return writeFileAtomic(file, func(w io.Writer) error {
+		return rep.Write(w, r)
+	})
+}
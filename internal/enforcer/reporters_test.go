@@ -0,0 +1,72 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateReporters(t *testing.T) {
+	tests := []struct {
+		name      string
+		reporters []*ReporterDeclaration
+		expected  string
+	}{
+		{
+			name: "Valid",
+			reporters: []*ReporterDeclaration{
+				{Format: "table"},
+				{Format: "sarif", File: "conform.sarif"},
+				{Format: "junit", File: "reports/conform.xml"},
+			},
+		},
+		{
+			name:      "UnknownFormat",
+			reporters: []*ReporterDeclaration{{Format: "yaml"}},
+			expected:  `Unknown output format "yaml": must be one of checkstyle, codequality, github, html, json, junit, markdown, openmetrics, patch, sarif, table, tap`,
+		},
+		{
+			name:      "TableFile",
+			reporters: []*ReporterDeclaration{{Format: "table", File: "conform.txt"}},
+			expected:  "The output file requires an output format other than table",
+		},
+		{
+			name:      "Stdout",
+			reporters: []*ReporterDeclaration{{Format: "table"}, {Format: "json"}},
+			expected:  "The table and json reporters both write to stdout: set the file of one of them",
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(tt *testing.T) {
+			err := validateReporters(test.reporters)
+			if test.expected == "" {
+				if err != nil {
+					tt.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.expected {
+				tt.Errorf("Expected error %q, got %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestOptionsReporters(t *testing.T) {
+	opts := NewDefaultOptions(WithOutput("sarif"), WithOutputFile("conform.sarif"))
+	expected := []*ReporterDeclaration{{Format: "sarif", File: "conform.sarif"}}
+	if reporters := opts.reporters(); !reflect.DeepEqual(reporters, expected) {
+		t.Errorf("Expected the reporter of the output format, got %+v", reporters)
+	}
+
+	opts.Reporters = []*ReporterDeclaration{{Format: "table"}, {Format: "json", File: "conform.json"}}
+	if reporters := opts.reporters(); !reflect.DeepEqual(reporters, opts.Reporters) {
+		t.Errorf("Expected the reporters of the configuration, got %+v", reporters)
+	}
+}
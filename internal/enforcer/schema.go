@@ -0,0 +1,211 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	// Required to embed the configuration schema.
+	_ "embed"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/autonomy/conform/internal/jsonschema"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/autonomy/conform/internal/reporter"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// SchemaJSON is the JSON Schema of .conform.yaml. It is generated from the
+// policies by GenerateSchema, and must be regenerated when a policy changes
+// with:
+//
+//	go test ./internal/enforcer -run TestSchema -update
+//
+//go:embed conform.schema.json
+var SchemaJSON []byte
+
+// GenerateSchema generates the JSON Schema of the configuration. The spec of
+// each builtin policy is derived from the mapstructure tags of its fields,
+// and may not contain unknown keys.
+func GenerateSchema() map[string]interface{} {
+	root := reflectSchema(reflect.TypeOf(Conform{}))
+	root["$schema"] = "http://json-schema.org/draft-07/schema#"
+	root["title"] = "Conform configuration"
+
+	types := make([]string, 0, len(policyMap))
+	for t := range policyMap {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	definitions := map[string]interface{}{}
+	conditions := make([]interface{}, 0, len(types))
+	for _, t := range types {
+		definitions[t] = reflectSchema(reflect.TypeOf(policyMap[t]))
+		conditions = append(conditions, map[string]interface{}{
+			"if": map[string]interface{}{
+				"properties": map[string]interface{}{
+					"type": map[string]interface{}{"const": t},
+				},
+			},
+			"then": map[string]interface{}{
+				"properties": map[string]interface{}{
+					"spec": map[string]interface{}{"$ref": "#/definitions/" + t},
+				},
+			},
+		})
+	}
+	root["definitions"] = definitions
+
+	// Unsupported versions are rejected before the configuration is
+	// validated, so that the constants only help editors.
+	root["properties"].(map[string]interface{})["apiVersion"] = map[string]interface{}{"const": APIVersion}
+	root["properties"].(map[string]interface{})["kind"] = map[string]interface{}{"const": Kind}
+
+	policies := root["properties"].(map[string]interface{})["policies"].(map[string]interface{})
+	declaration := policies["items"].(map[string]interface{})
+	declaration["required"] = []interface{}{"type"}
+	severities := make([]interface{}, 0, len(policy.Severities))
+	for _, severity := range policy.Severities {
+		severities = append(severities, string(severity))
+	}
+	properties := declaration["properties"].(map[string]interface{})
+	properties["severity"] = map[string]interface{}{"enum": severities}
+	properties["checkSeverity"] = map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": map[string]interface{}{"enum": severities},
+	}
+	declaration["allOf"] = conditions
+
+	// The policies of profiles are declared in the same way.
+	profiles := root["properties"].(map[string]interface{})["profiles"].(map[string]interface{})
+	profile := profiles["additionalProperties"].(map[string]interface{})
+	profile["properties"].(map[string]interface{})["policies"] = map[string]interface{}{"$ref": "#/properties/policies"}
+
+	reporters := root["properties"].(map[string]interface{})["reporters"].(map[string]interface{})
+	declaration = reporters["items"].(map[string]interface{})
+	declaration["required"] = []interface{}{"format"}
+	formats := make([]interface{}, 0, len(reporter.Formats()))
+	for _, format := range reporter.Formats() {
+		formats = append(formats, format)
+	}
+	declaration["properties"].(map[string]interface{})["format"] = map[string]interface{}{"enum": formats}
+
+	return root
+}
+
+// validateConfig validates the configuration against the embedded schema,
+// and checks that each policy type is a builtin policy or a plugin of c.
+func validateConfig(configBytes []byte, c *Conform) error {
+	schema, err := jsonschema.Parse(SchemaJSON)
+	if err != nil {
+		return errors.Errorf("Internal error: invalid schema: %v", err)
+	}
+
+	var doc interface{}
+	if err = yaml.Unmarshal(configBytes, &doc); err != nil {
+		return err
+	}
+
+	var messages []string
+	for _, err := range schema.Validate(jsonschema.Normalize(doc)) {
+		messages = append(messages, err.Error())
+	}
+
+	own := &Conform{}
+	if err = yaml.Unmarshal(configBytes, own); err != nil {
+		return err
+	}
+
+	plugins := map[string]bool{}
+	for _, p := range c.Plugins {
+		plugins[p.Name] = true
+	}
+	for i, p := range own.Policies {
+		if _, ok := policyMap[p.Type]; !ok && !plugins[p.Type] {
+			messages = append(messages, fmt.Sprintf("/policies/%d/type: policy %q is not defined", i, p.Type))
+		}
+	}
+	names := make([]string, 0, len(own.Profiles))
+	for name := range own.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		profilePlugins := map[string]bool{}
+		for _, p := range own.Profiles[name].Plugins {
+			profilePlugins[p.Name] = true
+		}
+		for i, p := range own.Profiles[name].Policies {
+			if _, ok := policyMap[p.Type]; !ok && !plugins[p.Type] && !profilePlugins[p.Type] {
+				messages = append(messages, fmt.Sprintf("/profiles/%s/policies/%d/type: policy %q is not defined", name, i, p.Type))
+			}
+		}
+	}
+
+	if len(messages) != 0 {
+		return errors.Errorf("Invalid configuration:\n  %s", strings.Join(messages, "\n  "))
+	}
+
+	return nil
+}
+
+func reflectSchema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": reflectSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": reflectSchema(t.Elem())}
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+		reflectProperties(t, properties)
+		return map[string]interface{}{"type": "object", "additionalProperties": false, "properties": properties}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// reflectProperties adds the properties of the struct's fields, using the
+// mapstructure tags of policies, or the yaml tags of the configuration.
+func reflectProperties(t reflect.Type, properties map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag, ok := field.Tag.Lookup("mapstructure")
+		if !ok {
+			tag = field.Tag.Get("yaml")
+		}
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+		if name == "-" {
+			continue
+		}
+		if len(parts) > 1 && (parts[1] == "squash" || parts[1] == "inline") {
+			reflectProperties(field.Type, properties)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = reflectSchema(field.Type)
+	}
+}
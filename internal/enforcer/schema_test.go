@@ -0,0 +1,119 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+var update = flag.Bool("update", false, "update the embedded configuration schema")
+
+func TestSchema(t *testing.T) {
+	generated, err := json.MarshalIndent(GenerateSchema(), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated = append(generated, '\n')
+
+	if *update {
+		if err = ioutil.WriteFile("conform.schema.json", generated, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	if !bytes.Equal(generated, SchemaJSON) {
+		t.Error("conform.schema.json is out of date, run: go test ./internal/enforcer -run TestSchema -update")
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	type testDesc struct {
+		Name     string
+		Config   string
+		Expected []string
+	}
+
+	repo, err := ioutil.ReadFile("../../.conform.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []testDesc{
+		{
+			Name:   "Repository",
+			Config: string(repo),
+		},
+		{
+			Name:     "Unknown Key",
+			Config:   "policies:\n  - type: commit\n    spec:\n      headerLenght: 89\n",
+			Expected: []string{`/policies/0/spec/headerLenght: unknown property "headerLenght"`},
+		},
+		{
+			Name:     "Invalid Type",
+			Config:   "policies:\n  - type: commit\n    spec:\n      dco: yes please\n",
+			Expected: []string{"/policies/0/spec/dco: expected boolean, got string"},
+		},
+		{
+			Name:     "Nested",
+			Config:   "policies:\n  - type: commit\n    spec:\n      conventional:\n        type: [feat]\n",
+			Expected: []string{`/policies/0/spec/conventional/type: unknown property "type"`},
+		},
+		{
+			Name:     "Undefined Policy",
+			Config:   "policies:\n  - type: nope\n",
+			Expected: []string{`/policies/0/type: policy "nope" is not defined`},
+		},
+		{
+			Name:   "Plugin",
+			Config: "plugins:\n  - name: custom\n    path: ./custom\npolicies:\n  - type: custom\n    spec:\n      anything: true\n",
+		},
+		{
+			Name:   "Severity",
+			Config: "policies:\n  - type: commit\n    severity: warn\n    checkSeverity:\n      DCO: info\n",
+		},
+		{
+			Name:     "Invalid Severity",
+			Config:   "policies:\n  - type: commit\n    severity: fatal\n",
+			Expected: []string{"/policies/0/severity"},
+		},
+		{
+			Name:     "Unknown Top Level Key",
+			Config:   "policy: []\n",
+			Expected: []string{`/policy: unknown property "policy"`},
+		},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			c := &Conform{}
+			if err := yaml.Unmarshal([]byte(test.Config), c); err != nil {
+				tt.Fatal(err)
+			}
+			err := validateConfig([]byte(test.Config), c)
+			if len(test.Expected) == 0 {
+				if err != nil {
+					tt.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				tt.Fatal("Expected an error")
+			}
+			for _, expected := range test.Expected {
+				if !strings.Contains(err.Error(), expected) {
+					tt.Errorf("Expected %q in %v", expected, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/autonomy/conform/internal/reporter"
+)
+
+// writeSummary writes the summary of the enforcement after the text table:
+//
+//	Checks:      5 (2 passed, 1 failed, 1 warned, 1 skipped)
+//	Violations:  3 (commit 2, license 1)
+//	Duration:    1.5s
+//	Slow:        license: File Header (1.2s)
+//
+// Only the statuses of checks and the policies with violations are listed,
+// and the checks slower than the slow threshold, if any.
+func writeSummary(w io.Writer, s *reporter.Summary) error {
+	var statuses []string
+	for _, count := range []struct {
+		n    int
+		word string
+	}{
+		{s.Passed, "passed"},
+		{s.Failed, "failed"},
+		{s.Warned, "warned"},
+		{s.Info, "info"},
+		{s.Skipped, "skipped"},
+	} {
+		if count.n != 0 {
+			statuses = append(statuses, fmt.Sprintf("%d %s", count.n, count.word))
+		}
+	}
+	checks := fmt.Sprint(s.Checks)
+	if len(statuses) != 0 {
+		checks += " (" + strings.Join(statuses, ", ") + ")"
+	}
+
+	var policies []string
+	for _, p := range s.Policies {
+		if p.Violations != 0 {
+			policies = append(policies, fmt.Sprintf("%s %d", p.Policy, p.Violations))
+		}
+	}
+	violations := fmt.Sprint(s.Violations())
+	if len(policies) != 0 {
+		violations += " (" + strings.Join(policies, ", ") + ")"
+	}
+
+	if _, err := fmt.Fprintf(w, "\n%-12s %s\n%-12s %s\n%-12s %s\n",
+		"Checks:", checks,
+		"Violations:", violations,
+		"Duration:", s.Duration.Round(time.Millisecond),
+	); err != nil {
+		return err
+	}
+	if len(s.Slow) == 0 {
+		return nil
+	}
+
+	slow := make([]string, 0, len(s.Slow))
+	for _, c := range s.Slow {
+		slow = append(slow, fmt.Sprintf("%s: %s (%s)", c.Policy, c.Name, c.Duration.Round(time.Millisecond)))
+	}
+	_, err := fmt.Fprintf(w, "%-12s %s\n", "Slow:", strings.Join(slow, ", "))
+
+	return err
+}
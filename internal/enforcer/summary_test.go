@@ -0,0 +1,78 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/autonomy/conform/internal/reporter"
+)
+
+func TestWriteSummary(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Summary  *reporter.Summary
+		Expected string
+	}{
+		{
+			Name: "Violations",
+			Summary: &reporter.Summary{
+				Checks:   5,
+				Passed:   2,
+				Failed:   1,
+				Warned:   1,
+				Skipped:  1,
+				Policies: []*reporter.PolicySummary{{Policy: "commit", Violations: 2}, {Policy: "docs:license", Violations: 0}, {Policy: "license", Violations: 1}},
+				Duration: 1500400 * time.Microsecond,
+			},
+			Expected: `
+Checks:      5 (2 passed, 1 failed, 1 warned, 1 skipped)
+Violations:  3 (commit 2, license 1)
+Duration:    1.5s
+`,
+		},
+		{
+			Name: "Slow",
+			Summary: &reporter.Summary{
+				Checks: 2,
+				Passed: 2,
+				Slow: []*reporter.Check{
+					{Policy: "commit", Name: "Spelling", Duration: 800 * time.Millisecond},
+					{Policy: "license", Name: "File Header", Duration: 1200 * time.Millisecond},
+				},
+				Duration: 2 * time.Second,
+			},
+			Expected: `
+Checks:      2 (2 passed)
+Violations:  0
+Duration:    2s
+Slow:        commit: Spelling (800ms), license: File Header (1.2s)
+`,
+		},
+		{
+			Name:    "Empty",
+			Summary: &reporter.Summary{},
+			Expected: `
+Checks:      0
+Violations:  0
+Duration:    0s
+`,
+		},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			var b bytes.Buffer
+			if err := writeSummary(&b, test.Summary); err != nil {
+				tt.Fatal(err)
+			}
+			if b.String() != test.Expected {
+				tt.Errorf("Expected:\n%s\ngot:\n%s", test.Expected, b.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// SkipTrailer is the commit message trailer suppressing violations of checks
+// of the commit (e.g. "Conform-Skip: Header Length").
+const SkipTrailer = "Conform-Skip"
+
+// ignoreRegex matches a conform:ignore directive in a comment of a file
+// (e.g. "// conform:ignore File Header"). The directive must follow the
+// opening delimiter of a comment, so that mentions of it in the code or in
+// the prose of a file are not directives.
+var ignoreRegex = regexp.MustCompile(`(?://|#|/\*|<!--|--|;|%|\{#|\{%|\(\*)\s*conform:ignore\s+(.*)$`)
+
+// commitScoped are the types of the policies whose checks are of the commit,
+// rather than of the files, which the trailers of its message can suppress.
+var commitScoped = map[string]bool{
+	"changelog": true,
+	"commit":    true,
+	"diffsize":  true,
+}
+
+// suppressor finds the directives suppressing violations. Directives in files
+// suppress the violations located in the file, and trailers of a commit
+// message suppress the violations of the checks of a commit scoped policy
+// located in that commit.
+type suppressor struct {
+	directives map[string][]string
+	// skips are the checks named by the trailers of the commits, by SHA.
+	skips map[string][]string
+	// unlocated are the checks named by the trailers of the message that the
+	// violations without a commit are of, if any.
+	unlocated []string
+	git       *git.Git
+}
+
+// newSuppressor returns a suppressor for the policies enforced from the
+// current directory.
+func newSuppressor(opts *policy.Options) (*suppressor, error) {
+	s := &suppressor{directives: map[string][]string{}, skips: map[string][]string{}}
+
+	g, err := git.NewGit()
+	if err != nil {
+		// Trailers are only supported in git repositories.
+		return s, nil
+	}
+	s.git = g
+
+	// Violations without a commit are of the commit message file, or of the
+	// changes of HEAD when no range of commits is enforced. The trailers of
+	// HEAD never suppress the violations of the changes of earlier commits.
+	switch {
+	case opts.CommitMsgFile != nil:
+		var contents []byte
+		if contents, err = ioutil.ReadFile(*opts.CommitMsgFile); err != nil {
+			return nil, errors.Errorf("failed to read commit message file: %v", err)
+		}
+		s.unlocated = trailerSkips(string(contents))
+	case opts.BaseBranch == nil && opts.CommitCount <= 1 && opts.Tree == nil:
+		var msg string
+		if msg, err = g.Message(); err != nil {
+			return nil, errors.Errorf("failed to get commit message: %v", err)
+		}
+		s.unlocated = trailerSkips(msg)
+	}
+
+	return s, nil
+}
+
+// suppressed returns the directive suppressing the violation of the error,
+// or an empty string if it is not suppressed. Directives name either the
+// check or the type of the policy.
+func (s *suppressor) suppressed(policyType, check string, err error) string {
+	loc, ok := policy.LocationOf(err)
+	if commitScoped[policyType] {
+		skips := s.unlocated
+		if ok && loc.Commit != "" {
+			skips = s.commitSkips(loc.Commit)
+		}
+		for _, skip := range skips {
+			if matchCheck(skip, policyType, check) {
+				return SkipTrailer + " trailer"
+			}
+		}
+	}
+
+	if !ok || loc.File == "" {
+		return ""
+	}
+	for _, directive := range s.fileDirectives(loc.File) {
+		if matchCheck(directive, policyType, check) {
+			return "conform:ignore in " + loc.File
+		}
+	}
+
+	return ""
+}
+
+// commitSkips returns the checks named by the trailers of the message of the
+// commit.
+func (s *suppressor) commitSkips(sha string) []string {
+	if skips, ok := s.skips[sha]; ok {
+		return skips
+	}
+
+	var skips []string
+	if s.git != nil {
+		if msg, err := s.git.CommitMessage(sha); err == nil {
+			skips = trailerSkips(msg)
+		}
+	}
+	s.skips[sha] = skips
+
+	return skips
+}
+
+// fileDirectives returns the checks named by the directives of the file,
+// relative to the current directory.
+func (s *suppressor) fileDirectives(file string) []string {
+	if directives, ok := s.directives[file]; ok {
+		return directives
+	}
+
+	var directives []string
+	if f, err := os.Open(filepath.FromSlash(file)); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if m := ignoreRegex.FindStringSubmatch(scanner.Text()); m != nil {
+				directives = append(directives, splitChecks(trimComment(m[1]))...)
+			}
+		}
+		// nolint: errcheck
+		f.Close()
+	}
+	s.directives[file] = directives
+
+	return directives
+}
+
+// trimComment removes the closing delimiter of a comment.
+func trimComment(s string) string {
+	s = strings.TrimSpace(s)
+	for _, delimiter := range []string{"*/", "-->", "#}", "%}", "*)"} {
+		s = strings.TrimSpace(strings.TrimSuffix(s, delimiter))
+	}
+
+	return s
+}
+
+// trailerSkips returns the checks named by the trailers of the message.
+func trailerSkips(msg string) []string {
+	var skips []string
+	for _, value := range git.Trailers(msg)[strings.ToLower(SkipTrailer)] {
+		skips = append(skips, splitChecks(value)...)
+	}
+
+	return skips
+}
+
+func splitChecks(s string) []string {
+	var checks []string
+	for _, check := range strings.Split(s, ",") {
+		if check = strings.TrimSpace(check); check != "" {
+			checks = append(checks, check)
+		}
+	}
+
+	return checks
+}
+
+func matchCheck(directive, policyType, check string) bool {
+	return strings.EqualFold(directive, check) || strings.EqualFold(directive, policyType)
+}
@@ -0,0 +1,142 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+func TestSuppressed(t *testing.T) {
+	s := &suppressor{
+		directives: map[string][]string{
+			"internal/generated/types.go": splitChecks(trimComment("File Header, whitespace */")),
+			"main.go":                     nil,
+		},
+		unlocated: []string{"Header Length", "File Header"},
+	}
+	generated := func(msg string) error {
+		return policy.FileError("internal/generated/types.go", 0, errors.New(msg))
+	}
+	for _, test := range []struct {
+		Name     string
+		Policy   string
+		Check    string
+		Err      error
+		Expected string
+	}{
+		{"Check", "license", "File Header", generated("File internal/generated/types.go does not contain a license header"), "conform:ignore in internal/generated/types.go"},
+		{"Policy type", "whitespace", "Trailing Whitespace", generated("File internal/generated/types.go has trailing whitespace on line 3"), "conform:ignore in internal/generated/types.go"},
+		{"Other check", "newline", "EOF Newline", generated("File internal/generated/types.go does not end with a newline"), ""},
+		{"Other file", "license", "File Header", policy.FileError("main.go", 0, errors.New("File main.go does not contain a license header")), ""},
+		{"Mentioned file", "exec", "File Header", errors.New("File internal/generated/types.go does not contain a license header"), ""},
+		{"Trailer", "commit", "Header Length", errors.New("Commit header is 90 characters"), "Conform-Skip trailer"},
+		{"Trailer of files", "license", "File Header", policy.FileError("main.go", 0, errors.New("File main.go does not contain a license header")), ""},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			if directive := s.suppressed(test.Policy, test.Check, test.Err); directive != test.Expected {
+				tt.Errorf("Expected %q, got %q", test.Expected, directive)
+			}
+		})
+	}
+}
+
+func TestFileDirectives(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "main.go")
+	contents := `// conform:ignore File Header
+package main
+
+const directive = "conform:ignore whitespace"
+
+/* conform:ignore EOF Newline */
+# conform:ignore eol
+<!-- conform:ignore Trailing Whitespace -->
+`
+	if err = ioutil.WriteFile(file, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &suppressor{directives: map[string][]string{}}
+	expected := []string{"File Header", "EOF Newline", "eol", "Trailing Whitespace"}
+	if directives := s.fileDirectives(filepath.ToSlash(file)); !reflect.DeepEqual(directives, expected) {
+		t.Errorf("Expected %q, got %q", expected, directives)
+	}
+}
+
+func TestCommitSkips(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+
+	identity := []string{"-c", "user.name=test", "-c", "user.email=test@example.com"}
+	for _, args := range [][]string{
+		{"init", "--quiet", dir},
+		{"-C", dir, "commit", "--quiet", "--allow-empty", "-m", "Older commit"},
+		{"-C", dir, "commit", "--quiet", "--allow-empty", "-m", "feat: newer commit\n\nConform-Skip: Conventional Commit"},
+	} {
+		if err = runGit("", append(identity, args...)...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := exec.Command("git", "rev-parse", "HEAD", "HEAD~1")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	shas := strings.Fields(string(out))
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.Chdir(wd)
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := newSuppressor(&policy.Options{CommitCount: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		Name     string
+		Err      error
+		Expected string
+	}{
+		{"Commit of the trailer", policy.CommitError(shas[0], errors.New("Invalid type")), "Conform-Skip trailer"},
+		{"Earlier commit", policy.CommitError(shas[1], errors.New("Invalid type")), ""},
+		{"No commit", errors.New("Invalid type"), ""},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			if directive := s.suppressed("commit", "Conventional Commit", test.Err); directive != test.Expected {
+				tt.Errorf("Expected %q, got %q", test.Expected, directive)
+			}
+		})
+	}
+}
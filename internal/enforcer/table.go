@@ -0,0 +1,213 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/autonomy/conform/internal/reporter"
+	"github.com/autonomy/conform/internal/terminal"
+	"github.com/autonomy/conform/internal/tracing"
+)
+
+// table writes results as a table whose first column is the policy and
+// third column is the status. When colored, the statuses are color coded,
+// and the rows are grouped by policy, with the policy named only on the
+// first row of its group.
+type table struct {
+	w     *tabwriter.Writer
+	theme *terminal.Theme
+	last  string
+	// report is the report of the checks whose results are written, for
+	// the reporters of other formats.
+	report *reporter.Report
+	// start is the time enforcement started at.
+	start time.Time
+	// span is the span of the enforcement, or of the repository enforced in
+	// a batch, nil if enforcement is not traced.
+	span *tracing.Span
+}
+
+// newTable returns a table that writes to w, and writes the headers. The
+// rows are not colored if theme is nil.
+func newTable(w io.Writer, theme *terminal.Theme, headers ...string) *table {
+	const padding = 8
+	t := &table{w: tabwriter.NewWriter(w, 0, 0, padding, ' ', 0), theme: theme, report: &reporter.Report{}, start: time.Now()}
+	if theme == nil {
+		t.write(headers)
+		return t
+	}
+
+	colored := make([]string, 0, len(headers))
+	for _, header := range headers {
+		colored = append(colored, terminal.Paint(theme.Header, header))
+	}
+	t.write(colored)
+
+	return t
+}
+
+// newTable returns a table writing to w, colored with the theme of the
+// options.
+func (c *Conform) newTable(w io.Writer, headers ...string) *table {
+	return c.options.newTable(w, headers...)
+}
+
+// newTable returns a table writing to w, colored with the theme of the
+// options.
+func (o *Options) newTable(w io.Writer, headers ...string) *table {
+	var theme *terminal.Theme
+	if t, ok := terminal.Themes[o.Theme]; ok {
+		theme = &t
+	}
+
+	return newTable(w, theme, headers...)
+}
+
+// groupSamples is the number of locations of a group of identical violations
+// written in its row.
+const groupSamples = 3
+
+// violations writes the rows of the violations of the check, with a single
+// row for each group of identical violations. At most max rows are written,
+// unless max is 0, followed by a row counting the violations of the others.
+// In quiet mode, suppressed violations and violations in the baseline are not
+// written.
+func (t *table) violations(c *reporter.Check, quiet bool, max int) {
+	var written []*reporter.Violation
+	for _, v := range c.Violations {
+		if quiet && (v.Status == reporter.StatusSuppressed || v.Status == reporter.StatusBaseline) {
+			continue
+		}
+		written = append(written, v)
+	}
+	groups, omitted := reporter.Capped(reporter.GroupViolations(written), max)
+	for _, g := range groups {
+		t.row(c.Policy, c.Name, g.Status, g.Text(groupSamples))
+	}
+	if omitted != 0 {
+		t.row(c.Policy, c.Name, c.Status, fmt.Sprintf("... and %d more violations", omitted))
+	}
+}
+
+// row writes a row of cells.
+func (t *table) row(policy, second, status, message string) {
+	if t.theme == nil {
+		t.write([]string{policy, second, status, message})
+		return
+	}
+
+	// Every cell is colored, so that the columns stay aligned.
+	name := policy
+	if policy == t.last {
+		name = ""
+	} else if t.last != "" {
+		t.write([]string{
+			terminal.Paint(terminal.Plain, ""),
+			terminal.Paint(terminal.Plain, ""),
+			terminal.Paint(terminal.Plain, ""),
+			terminal.Paint(terminal.Plain, ""),
+		})
+	}
+	t.last = policy
+	t.write([]string{
+		terminal.Paint(t.theme.Name, name),
+		terminal.Paint(t.theme.Text, second),
+		terminal.Paint(t.statusColor(status), status),
+		terminal.Paint(t.theme.Text, message),
+	})
+}
+
+func (t *table) statusColor(status string) terminal.Color {
+	switch status {
+	case "PASS", "FIXED":
+		return t.theme.Pass
+	case "FAILED":
+		return t.theme.Fail
+	case "WARNING", "WOULD FIX":
+		return t.theme.Warn
+	case "INFO", "SUGGESTED":
+		return t.theme.Info
+	default:
+		return t.theme.Muted
+	}
+}
+
+func (t *table) write(cells []string) {
+	// nolint: errcheck
+	io.WriteString(t.w, strings.Join(cells, "\t")+"\t\n")
+}
+
+// flush writes the buffered rows.
+func (t *table) flush() error {
+	return t.w.Flush()
+}
+
+// locator locates the violations of the checks enforced in the working
+// directory, relative to the root of the repository.
+type locator struct {
+	// dir is the slash separated path of the working directory, relative to
+	// the root of the repository, or empty outside of a git repository.
+	dir string
+}
+
+func newLocator() (*locator, error) {
+	g, err := git.NewGit()
+	if err != nil {
+		// Files are located relative to the working directory outside of
+		// git repositories.
+		return &locator{}, nil
+	}
+	root, err := g.Root()
+	if err != nil {
+		return nil, err
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return nil, err
+	}
+	if wd, err = filepath.EvalSymlinks(wd); err != nil {
+		return nil, err
+	}
+	dir, err := filepath.Rel(root, wd)
+	if err != nil {
+		return nil, err
+	}
+
+	return &locator{dir: filepath.ToSlash(dir)}, nil
+}
+
+// file returns the slash separated path, relative to the root of the
+// repository, of the file at the path relative to the working directory.
+func (l *locator) file(name string) string {
+	return path.Clean(path.Join(l.dir, filepath.ToSlash(name)))
+}
+
+// violation returns the violation of the error with the status, located at
+// the location of the error, if any.
+func (l *locator) violation(err error, status, directive string) *reporter.Violation {
+	v := &reporter.Violation{Message: err.Error(), Status: status, Directive: directive}
+	if loc, ok := policy.LocationOf(err); ok {
+		v.Line = loc.Line
+		v.Commit = loc.Commit
+		if loc.File != "" {
+			v.File = l.file(loc.File)
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,111 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/autonomy/conform/internal/reporter"
+	"github.com/autonomy/conform/internal/terminal"
+)
+
+var sgrRegex = regexp.MustCompile("\x1b\\[[0-9]+m")
+
+func TestTable(t *testing.T) {
+	theme := terminal.Themes[terminal.DefaultTheme]
+
+	type testDesc struct {
+		Name     string
+		Theme    *terminal.Theme
+		Expected string
+	}
+
+	for _, test := range []testDesc{
+		{
+			Name:  "Plain",
+			Theme: nil,
+			Expected: `POLICY         CHECK                STATUS        MESSAGE
+commit         Header Length        FAILED        Commit header is 80 characters
+commit         DCO                  PASS          <none>
+license        File Header          PASS          <none>
+`,
+		},
+		{
+			Name:  "Colored",
+			Theme: &theme,
+			Expected: `POLICY         CHECK                STATUS        MESSAGE
+commit         Header Length        FAILED        Commit header is 80 characters
+               DCO                  PASS          <none>
+
+license        File Header          PASS          <none>
+`,
+		},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			var b bytes.Buffer
+			table := newTable(&b, test.Theme, "POLICY", "CHECK", "STATUS", "MESSAGE")
+			table.row("commit", "Header Length", "FAILED", "Commit header is 80 characters")
+			table.row("commit", "DCO", "PASS", "<none>")
+			table.row("license", "File Header", "PASS", "<none>")
+			if err := table.flush(); err != nil {
+				tt.Fatal(err)
+			}
+
+			if test.Theme != nil && !strings.Contains(b.String(), terminal.Paint(theme.Fail, "FAILED")) {
+				tt.Errorf("Expected the failure to be colored, got %q", b.String())
+			}
+			// The trailing padding of the last column is ignored.
+			lines := strings.Split(sgrRegex.ReplaceAllString(b.String(), ""), "\n")
+			for i := range lines {
+				lines[i] = strings.TrimRight(lines[i], " ")
+			}
+			if actual := strings.Join(lines, "\n"); actual != test.Expected {
+				tt.Errorf("Expected:\n%s\ngot:\n%s", test.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestTableViolations(t *testing.T) {
+	var violations []*reporter.Violation
+	for _, name := range []string{"a.go", "b.go", "c.go", "d.go"} {
+		violations = append(violations, &reporter.Violation{
+			Message: "File " + name + " does not contain a license header",
+			Status:  reporter.StatusFailed,
+			File:    "pkg/" + name,
+		})
+	}
+	violations = append(violations,
+		&reporter.Violation{Message: "File e.go is empty", Status: reporter.StatusFailed, File: "e.go"},
+		&reporter.Violation{Message: "File f.go is empty", Status: reporter.StatusSuppressed, File: "f.go"},
+		&reporter.Violation{Message: "File g.go is not formatted", Status: reporter.StatusFailed, File: "g.go"},
+	)
+	c := &reporter.Check{Policy: "license", Name: "File Header", Status: reporter.StatusFailed, Violations: violations}
+
+	var b bytes.Buffer
+	table := newTable(&b, nil, "POLICY", "CHECK", "STATUS", "MESSAGE")
+	table.violations(c, true, 2)
+	if err := table.flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"license        File Header        FAILED        File <file> does not contain a license header (4 times: pkg/a.go, pkg/b.go, pkg/c.go, ...)",
+		"license        File Header        FAILED        File e.go is empty",
+		"license        File Header        FAILED        ... and 1 more violations",
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")[1:]
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+	if actual := strings.Join(lines, "\n"); actual != strings.Join(expected, "\n") {
+		t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(expected, "\n"), actual)
+	}
+}
@@ -0,0 +1,145 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// templateData is the data that the templates of configuration values are
+// executed with.
+type templateData struct {
+	Git gitContext
+}
+
+// gitContext describes the repository that the configuration is loaded in.
+// Its fields are empty outside of a git repository.
+type gitContext struct {
+	// Branch is the short name of the current branch, empty if HEAD is
+	// detached.
+	Branch string
+	// SHA is the sha of the current commit.
+	SHA string
+	// Root is the absolute path of the root of the working tree.
+	Root string
+	// Name is the name of the directory of the root of the working tree.
+	Name string
+}
+
+// newTemplateData returns the data of the repository of the current
+// directory.
+func newTemplateData() templateData {
+	var data templateData
+	g, err := git.NewGit()
+	if err != nil {
+		return data
+	}
+	// The context is best effort, since a new repository has no commits.
+	// nolint: errcheck
+	data.Git.Branch, _ = g.Branch()
+	// nolint: errcheck
+	data.Git.SHA, _ = g.SHA()
+	if root, err := g.Root(); err == nil {
+		data.Git.Root = root
+		data.Git.Name = filepath.Base(root)
+	}
+
+	return data
+}
+
+var templateFuncs = template.FuncMap{
+	"env": os.Getenv,
+	"default": func(def, value string) string {
+		if value == "" {
+			return def
+		}
+		return value
+	},
+}
+
+// untrustedFuncs are the functions of the templates of the configuration of
+// an untrusted repository, which must not read the environment, since it
+// holds the secrets of CI.
+var untrustedFuncs = template.FuncMap{
+	"env": func(string) (string, error) {
+		return "", errors.New("env is not available to the configuration of an untrusted repository")
+	},
+	"default": templateFuncs["default"],
+}
+
+// expandConfig expands the Go templates in the string values of a YAML
+// configuration that sets templates, e.g. {{ env "COPYRIGHT_HOLDER" }} or
+// {{ .Git.Branch }}, so that a shared configuration can be parameterized per
+// repository. Templates always expand to strings. The configuration is
+// returned as is if it does not set templates, since sources of scripts and
+// license headers may contain braces of their own.
+func expandConfig(configBytes []byte, data templateData, funcs template.FuncMap) ([]byte, error) {
+	if !bytes.Contains(configBytes, []byte("{{")) {
+		return configBytes, nil
+	}
+
+	var opt struct {
+		Templates bool `yaml:"templates"`
+	}
+	if err := yaml.Unmarshal(configBytes, &opt); err != nil || !opt.Templates {
+		// Invalid configurations are reported when they are decoded.
+		return configBytes, nil
+	}
+
+	var v interface{}
+	if err := yaml.Unmarshal(configBytes, &v); err != nil {
+		return nil, err
+	}
+	v, err := expandValue(v, data, funcs)
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(v)
+}
+
+func expandValue(v interface{}, data templateData, funcs template.FuncMap) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		if !strings.Contains(v, "{{") {
+			return v, nil
+		}
+		t, err := template.New("").Option("missingkey=error").Funcs(funcs).Parse(v)
+		if err != nil {
+			return nil, errors.Errorf("invalid template %q: %v", v, err)
+		}
+		var b strings.Builder
+		if err = t.Execute(&b, data); err != nil {
+			return nil, errors.Errorf("failed to expand template %q: %v", v, err)
+		}
+		return b.String(), nil
+	case map[interface{}]interface{}:
+		for key, value := range v {
+			expanded, err := expandValue(value, data, funcs)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = expanded
+		}
+	case []interface{}:
+		for i, value := range v {
+			expanded, err := expandValue(value, data, funcs)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = expanded
+		}
+	}
+
+	return v, nil
+}
@@ -0,0 +1,106 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestExpandConfig(t *testing.T) {
+	if err := os.Setenv("CONFORM_TEST_HOLDER", "Acme"); err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.Unsetenv("CONFORM_TEST_HOLDER")
+
+	data := templateData{Git: gitContext{Branch: "main", Name: "repo"}}
+
+	type testDesc struct {
+		Name     string
+		Config   string
+		Expected interface{}
+	}
+
+	for _, test := range []testDesc{
+		{
+			Name:     "Env",
+			Config:   "templates: true\n" + `holder: '{{ env "CONFORM_TEST_HOLDER" }}'`,
+			Expected: "Acme",
+		},
+		{
+			Name:     "Default",
+			Config:   "templates: true\n" + `holder: '{{ env "CONFORM_TEST_UNSET" | default "Nobody" }}'`,
+			Expected: "Nobody",
+		},
+		{
+			Name:     "Git",
+			Config:   "templates: true\n" + `holder: ['{{ .Git.Name }}@{{ .Git.Branch }}']`,
+			Expected: []interface{}{"repo@main"},
+		},
+		{
+			Name:     "Untemplated",
+			Config:   "templates: true\nholder: 72",
+			Expected: 72,
+		},
+		{
+			Name:     "Not enabled",
+			Config:   `holder: '{{ .Git.Unknown }}'`,
+			Expected: "{{ .Git.Unknown }}",
+		},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			configBytes, err := expandConfig([]byte(test.Config), data, templateFuncs)
+			if err != nil {
+				tt.Fatalf("Unexpected error: %v", err)
+			}
+			var v map[string]interface{}
+			if err = yaml.Unmarshal(configBytes, &v); err != nil {
+				tt.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(v["holder"], test.Expected) {
+				tt.Errorf("Expected %#v, got %#v", test.Expected, v["holder"])
+			}
+		})
+	}
+
+	for _, config := range []string{`holder: '{{ .Git.Unknown }}'`, `holder: '{{ env }'`} {
+		if _, err := expandConfig([]byte("templates: true\n"+config), data, templateFuncs); err == nil {
+			t.Errorf("Expected %s to be an error", config)
+		}
+	}
+}
+
+func TestLoadUntemplated(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+
+	// Configurations that do not set templates are taken literally, such as
+	// the braces of a license header.
+	file := filepath.Join(dir, ".conform.yaml")
+	config := "policies:\n  - type: license\n    spec:\n      header: '// {{ .Year }} The Authors'\n"
+	if err = ioutil.WriteFile(file, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := load([]string{file}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[interface{}]interface{}{"header": "// {{ .Year }} The Authors"}
+	if spec := c.Policies[0].Spec; !reflect.DeepEqual(spec, expected) {
+		t.Errorf("Expected the spec %v, got %v", expected, spec)
+	}
+}
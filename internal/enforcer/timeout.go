@@ -0,0 +1,138 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"context"
+	"time"
+
+	"github.com/autonomy/conform/internal/logging"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// TimeoutCheck is the name of the check failed by policies that do not
+// complete within their timeout.
+const TimeoutCheck = "Timeout"
+
+// timeoutGrace is how long a policy that timed out is waited for to stop once
+// its context is canceled, before its timeout is reported regardless.
+var timeoutGrace = 5 * time.Second
+
+// timeoutCheck reports that a policy timed out.
+type timeoutCheck struct {
+	timeout time.Duration
+}
+
+// Name returns the name of the check.
+func (t timeoutCheck) Name() string {
+	return TimeoutCheck
+}
+
+// Message returns the check message.
+func (t timeoutCheck) Message() string {
+	return "Policy timed out"
+}
+
+// Errors returns any violations of the check.
+func (t timeoutCheck) Errors() []error {
+	return []error{errors.Errorf("Policy did not complete within %s", t.timeout)}
+}
+
+// parseTimeout parses a timeout of the configuration. An empty timeout is no
+// timeout.
+func parseTimeout(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(s)
+	if err != nil || timeout <= 0 {
+		return 0, errors.Errorf("Invalid timeout %q: must be a positive duration such as 30s or 5m", s)
+	}
+
+	return timeout, nil
+}
+
+// validateTimeouts checks that the timeouts of the configuration, of its
+// policies, and of the policies of its profiles are valid.
+func validateTimeouts(c *Conform) error {
+	if _, err := parseTimeout(c.Timeout); err != nil {
+		return err
+	}
+	policies := append([]*PolicyDeclaration{}, c.Policies...)
+	for _, p := range c.Profiles {
+		policies = append(policies, p.Policies...)
+	}
+	for _, p := range policies {
+		if _, err := parseTimeout(p.Timeout); err != nil {
+			return errors.Errorf("Policy %q: %v", p.Type, err)
+		}
+	}
+
+	return nil
+}
+
+// timeout returns the timeout of the policy: its own, or else the timeout of
+// the --timeout flag or, failing that, of the configuration. It is zero if the
+// policy has no timeout.
+func (c *Conform) timeout(p *PolicyDeclaration) time.Duration {
+	// The timeouts were validated when the configuration was loaded.
+	if timeout, _ := parseTimeout(p.Timeout); timeout != 0 {
+		return timeout
+	}
+	if c.options.Timeout != 0 {
+		return c.options.Timeout
+	}
+	timeout, _ := parseTimeout(c.Timeout)
+
+	return timeout
+}
+
+// enforceTimeout enforces the policy, canceling the context of its options
+// once its timeout elapses. A policy that times out fails its Timeout check,
+// and its other checks are not reported. The policy is waited for to stop for
+// up to timeoutGrace, so that the next policy does not run alongside it, e.g.
+// in the same working directory, but a policy that does not stop is abandoned
+// rather than allowed to outlast its timeout.
+func (c *Conform) enforceTimeout(p *PolicyDeclaration, opts *policy.Options) (*policy.Report, error) {
+	timeout := c.timeout(p)
+	if timeout == 0 {
+		return c.enforce(p, opts)
+	}
+
+	ctx, cancel := context.WithTimeout(opts.Context, timeout)
+	defer cancel()
+	timed := *opts
+	timed.Context = ctx
+
+	type outcome struct {
+		report *policy.Report
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		report, err := c.enforce(p, &timed)
+		done <- outcome{report: report, err: err}
+	}()
+
+	select {
+	case o := <-done:
+		if ctx.Err() == nil {
+			return o.report, o.err
+		}
+	case <-ctx.Done():
+		select {
+		case <-done:
+		case <-time.After(timeoutGrace):
+			logging.Warn("abandoning the policy, which did not stop after timing out", "policy", p.Type, "grace", timeoutGrace)
+		}
+	}
+	logging.Info("policy timed out", "policy", p.Type, "timeout", timeout)
+
+	report := &policy.Report{}
+	report.AddCheck(timeoutCheck{timeout: timeout})
+
+	return report, nil
+}
@@ -0,0 +1,126 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+// sleepPolicy is a policy that completes after its duration, unless its
+// context is done first.
+type sleepPolicy struct {
+	Duration time.Duration `mapstructure:"duration"`
+}
+
+func (s *sleepPolicy) Compliance(options *policy.Options) (*policy.Report, error) {
+	select {
+	case <-time.After(s.Duration):
+	case <-options.Context.Done():
+	}
+
+	return &policy.Report{}, nil
+}
+
+// stuckPolicy is a policy that ignores its context, and only completes after
+// its duration.
+type stuckPolicy struct {
+	Duration time.Duration `mapstructure:"duration"`
+}
+
+func (s *stuckPolicy) Compliance(options *policy.Options) (*policy.Report, error) {
+	time.Sleep(s.Duration)
+
+	return &policy.Report{}, nil
+}
+
+func TestEnforceTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeout  string
+		global   time.Duration
+		duration time.Duration
+		expected bool
+	}{
+		{name: "NoTimeout", duration: 10 * time.Millisecond},
+		{name: "Completed", timeout: "1m", duration: 10 * time.Millisecond},
+		{name: "TimedOut", timeout: "10ms", duration: time.Minute, expected: true},
+		{name: "Global", global: 10 * time.Millisecond, duration: time.Minute, expected: true},
+		{name: "PolicyOverridesGlobal", timeout: "1m", global: time.Millisecond, duration: 10 * time.Millisecond},
+	}
+
+	policyMap["sleep"] = &sleepPolicy{}
+	defer delete(policyMap, "sleep")
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			c := &Conform{options: NewDefaultOptions(WithTimeout(test.global))}
+			report, err := c.enforceTimeout(&PolicyDeclaration{
+				Type:    "sleep",
+				Spec:    map[interface{}]interface{}{"duration": test.duration},
+				Timeout: test.timeout,
+			}, policy.NewDefaultOptions())
+			if err != nil {
+				t.Fatal(err)
+			}
+			timedOut := len(report.Checks()) == 1 && report.Checks()[0].Name() == TimeoutCheck
+			if timedOut != test.expected {
+				t.Errorf("Expected timed out %v, got %v", test.expected, timedOut)
+			}
+		})
+	}
+}
+
+func TestEnforceTimeoutAbandons(t *testing.T) {
+	policyMap["stuck"] = &stuckPolicy{}
+	defer delete(policyMap, "stuck")
+	defer func(grace time.Duration) { timeoutGrace = grace }(timeoutGrace)
+	timeoutGrace = 10 * time.Millisecond
+
+	c := &Conform{options: NewDefaultOptions()}
+	start := time.Now()
+	report, err := c.enforceTimeout(&PolicyDeclaration{
+		Type:    "stuck",
+		Spec:    map[interface{}]interface{}{"duration": time.Minute},
+		Timeout: "10ms",
+	}, policy.NewDefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Expected the policy to be abandoned after its grace, took %s", elapsed)
+	}
+	if len(report.Checks()) != 1 || report.Checks()[0].Name() != TimeoutCheck {
+		t.Errorf("Expected the policy to time out, got %v", report.Checks())
+	}
+}
+
+func TestValidateTimeouts(t *testing.T) {
+	tests := []struct {
+		name string
+		c    *Conform
+		err  bool
+	}{
+		{name: "Valid", c: &Conform{Timeout: "5m", Policies: []*PolicyDeclaration{{Type: "commit", Timeout: "30s"}}}},
+		{name: "InvalidGlobal", c: &Conform{Timeout: "5"}, err: true},
+		{name: "Negative", c: &Conform{Policies: []*PolicyDeclaration{{Type: "commit", Timeout: "-1s"}}}, err: true},
+		{name: "Profile", c: &Conform{Profiles: map[string]*Profile{"ci": {Policies: []*PolicyDeclaration{{Type: "commit", Timeout: "soon"}}}}}, err: true},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			err := validateTimeouts(test.c)
+			if test.err != (err != nil) {
+				t.Errorf("Expected error %v, got %v", test.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/autonomy/conform/internal/reporter"
+	"github.com/autonomy/conform/internal/tracing"
+)
+
+// traceChecks records the spans of the checks of the policy of span, whose
+// enforcement started at start, with their results. Each check is timed by
+// its duration, and the checks whose result is nil, since they are not
+// selected, are not recorded. The span of the policy is marked as failed if
+// any check failed.
+func traceChecks(span *tracing.Span, start time.Time, durations []time.Duration, checks []*reporter.Check) {
+	span.SetAttributes("conform.policy.checks", len(checks))
+	failed := 0
+	for i, c := range checks {
+		end := start.Add(durations[i])
+		if c == nil {
+			start = end
+			continue
+		}
+		s := span.Record("check "+c.Name, start, end,
+			"conform.check.name", c.Name,
+			"conform.check.status", c.Status,
+			"conform.check.violations", len(c.Violations),
+		)
+		if c.Status == reporter.StatusFailed {
+			s.SetError(c.Message)
+			failed++
+		}
+		start = end
+	}
+	if failed != 0 {
+		span.SetError(fmt.Sprintf("%d checks failed", failed))
+	}
+}
@@ -0,0 +1,81 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"strings"
+
+	"github.com/autonomy/conform/internal/policy/schema"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+// commandTypes are the types of the policies that run commands, or code, of
+// their configuration.
+var commandTypes = map[string]bool{
+	"cue":      true,
+	"exec":     true,
+	"generate": true,
+	"script":   true,
+}
+
+// refuseUntrusted returns an error if the configuration declares what the
+// configuration of an untrusted repository must not: policies that run
+// commands, plugins, or schemas fetched from URLs, which could send the
+// secrets of the environment to a server of its choosing.
+func refuseUntrusted(c *Conform) error {
+	if err := refuseCommands(c); err != nil {
+		return err
+	}
+
+	return refuseRemoteSchemas(c)
+}
+
+// refuseCommands returns an error if the configuration, or any of its
+// profiles, declares a policy that runs commands or a plugin, which the
+// configuration of an untrusted repository must not.
+func refuseCommands(c *Conform) error {
+	policies := append([]*PolicyDeclaration{}, c.Policies...)
+	plugins := append([]*PluginDeclaration{}, c.Plugins...)
+	for _, p := range c.Profiles {
+		policies = append(policies, p.Policies...)
+		plugins = append(plugins, p.Plugins...)
+	}
+	for _, p := range policies {
+		if commandTypes[p.Type] {
+			return errors.Errorf("Policy %q runs commands, which the configuration of an untrusted repository cannot declare", p.Type)
+		}
+	}
+	if len(plugins) != 0 {
+		return errors.Errorf("Plugin %q runs a command, which the configuration of an untrusted repository cannot declare", plugins[0].Name)
+	}
+
+	return nil
+}
+
+// refuseRemoteSchemas returns an error if the configuration, or any of its
+// profiles, declares a schema policy with a schema fetched from a URL.
+func refuseRemoteSchemas(c *Conform) error {
+	policies := append([]*PolicyDeclaration{}, c.Policies...)
+	for _, p := range c.Profiles {
+		policies = append(policies, p.Policies...)
+	}
+	for _, p := range policies {
+		if p.Type != "schema" {
+			continue
+		}
+		spec := &schema.Schema{}
+		if err := mapstructure.Decode(p.Spec, spec); err != nil {
+			return errors.Errorf("Internal error: %v", err)
+		}
+		for _, rule := range spec.Rules {
+			if strings.HasPrefix(rule.Schema, "http://") || strings.HasPrefix(rule.Schema, "https://") {
+				return errors.Errorf("Schema %s is remote, which the configuration of an untrusted repository cannot declare", rule.Schema)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRefuseUntrusted(t *testing.T) {
+	tests := []struct {
+		name string
+		conf *Conform
+		err  bool
+	}{
+		{
+			name: "Files",
+			conf: &Conform{Policies: []*PolicyDeclaration{{Type: "license"}, {Type: "commit"}}},
+		},
+		{
+			name: "Exec",
+			conf: &Conform{Policies: []*PolicyDeclaration{{Type: "license"}, {Type: "exec"}}},
+			err:  true,
+		},
+		{
+			name: "Profile",
+			conf: &Conform{Profiles: map[string]*Profile{"ci": {Policies: []*PolicyDeclaration{{Type: "generate"}}}}},
+			err:  true,
+		},
+		{
+			name: "Plugin",
+			conf: &Conform{Plugins: []*PluginDeclaration{{Name: "lint", Path: "./lint"}}},
+			err:  true,
+		},
+		{
+			name: "Local schema",
+			conf: &Conform{Policies: []*PolicyDeclaration{{Type: "schema", Spec: map[interface{}]interface{}{
+				"rules": []interface{}{map[interface{}]interface{}{"paths": []interface{}{"*.json"}, "schema": "schemas/config.json"}},
+			}}}},
+		},
+		{
+			name: "Remote schema",
+			conf: &Conform{Profiles: map[string]*Profile{"ci": {Policies: []*PolicyDeclaration{{Type: "schema", Spec: map[interface{}]interface{}{
+				"rules": []interface{}{map[interface{}]interface{}{"paths": []interface{}{"*.json"}, "schema": "https://example.com/config.json"}},
+			}}}}}},
+			err: true,
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if err := refuseUntrusted(test.conf); test.err != (err != nil) {
+				t.Errorf("Expected error %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestLoadUntrusted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+
+	// nolint: errcheck
+	defer os.Unsetenv("CONFORM_CACHE_DIR")
+	if err = os.Setenv("CONFORM_CACHE_DIR", filepath.Join(dir, "cache")); err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.Unsetenv("CONFORM_TEST_SECRET")
+	if err = os.Setenv("CONFORM_TEST_SECRET", "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+
+	var requests []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	for _, test := range []struct {
+		Name   string
+		Config string
+		Err    string
+	}{
+		{"Remote source", "extends:\n  - source: " + server.URL + "/base.yaml\n", "is remote"},
+		{"Env in source", "templates: true\nextends:\n  - source: '" + server.URL + `/{{ env "CONFORM_TEST_SECRET" }}'` + "\n", "env is not available"},
+		{"Env in spec", "templates: true\npolicies:\n  - type: license\n    spec:\n      header: '{{ env \"CONFORM_TEST_SECRET\" }}'\n", "env is not available"},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			file := filepath.Join(dir, ".conform.yaml")
+			if err := ioutil.WriteFile(file, []byte(test.Config), 0644); err != nil {
+				tt.Fatal(err)
+			}
+			if _, err := load([]string{file}, true); err == nil || !strings.Contains(err.Error(), test.Err) {
+				tt.Errorf("Expected an error containing %q, got %v", test.Err, err)
+			}
+		})
+	}
+	if len(requests) != 0 {
+		t.Errorf("Expected no requests, got %v", requests)
+	}
+}
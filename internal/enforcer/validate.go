@@ -0,0 +1,195 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/autonomy/conform/internal/reporter"
+	"github.com/pkg/errors"
+)
+
+// Problem is a problem with the configuration of a policy.
+type Problem struct {
+	Policy   string
+	Severity policy.Severity
+	Message  string
+}
+
+// Validate validates the specs of the policies, and of the subdirectories,
+// without running any checks. This is in addition to the validation against
+// the schema that happens when the configuration is loaded. Invalid regular
+// expressions are errors, and deprecated keys, and path patterns and suffixes
+// that match no tracked file, are warnings, since they are likely to be
+// mistakes.
+func (c *Conform) Validate() ([]Problem, error) {
+	var files []string
+	if g, err := git.NewGit(); err == nil {
+		if files, err = policy.TrackedFiles(nil, g); err != nil {
+			return nil, errors.Errorf("failed to list tracked files: %v", err)
+		}
+	}
+
+	problems, err := c.validate("", files)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range c.directories {
+		// The patterns of a subdirectory are relative to it.
+		var dirFiles []string
+		for _, file := range files {
+			if strings.HasPrefix(file, d.name+"/") {
+				dirFiles = append(dirFiles, strings.TrimPrefix(file, d.name+"/"))
+			}
+		}
+		dirProblems, err := d.conform.validate(d.name+":", dirFiles)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, dirProblems...)
+	}
+
+	return problems, nil
+}
+
+func (c *Conform) validate(prefix string, files []string) ([]Problem, error) {
+	var problems []Problem
+	for _, p := range c.deprecated {
+		p.Policy = prefix + p.Policy
+		problems = append(problems, p)
+	}
+	for _, declaration := range c.Policies {
+		p, ok, err := newPolicy(declaration)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			// Plugins validate their own specs.
+			continue
+		}
+
+		name := prefix + declaration.Type
+		if v, ok := p.(policy.Validator); ok {
+			for _, err := range v.Validate() {
+				problems = append(problems, Problem{Policy: name, Severity: policy.SeverityError, Message: err.Error()})
+			}
+		}
+		if m, ok := p.(policy.PathMatcher); ok && files != nil {
+			for _, pattern := range m.PathPatterns() {
+				if !matchesAny(pattern, files) {
+					problems = append(problems, Problem{
+						Policy:   name,
+						Severity: policy.SeverityWarn,
+						Message:  fmt.Sprintf("Pattern %q matches no tracked files", pattern),
+					})
+				}
+			}
+		}
+		if m, ok := p.(policy.SuffixMatcher); ok && files != nil {
+			for _, suffix := range m.Suffixes() {
+				if !hasSuffix(suffix, files) {
+					problems = append(problems, Problem{
+						Policy:   name,
+						Severity: policy.SeverityWarn,
+						Message:  fmt.Sprintf("Suffix %q matches no tracked files", suffix),
+					})
+				}
+			}
+		}
+	}
+
+	return problems, nil
+}
+
+// lint writes the warnings of the validation of the policies, such as their
+// deprecated keys, as violations of their Configuration check, so that
+// mistakes of the configuration are seen without running validate-config.
+// Patterns are matched against the files of the tree of the options, if any.
+func (c *Conform) lint(t *table, prefix string, opts *policy.Options) (*result, error) {
+	var files []string
+	if g, err := git.NewGit(); err == nil {
+		if files, err = policy.TrackedFiles(opts.Tree, g); err != nil {
+			return nil, errors.Errorf("failed to list tracked files: %v", err)
+		}
+	}
+	problems, err := c.validate(prefix, files)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &result{}
+	checks := map[string]*reporter.Check{}
+	var names []string
+	for _, p := range problems {
+		policyType := strings.TrimPrefix(p.Policy, prefix)
+		if p.Severity != policy.SeverityWarn || !c.options.selectsPolicy(policyType) ||
+			!c.options.selectsCheck(configurationCheck) || c.options.skips(policyType, configurationCheck) {
+			continue
+		}
+		severity := p.Severity
+		if c.options.Strict {
+			severity = policy.SeverityError
+		}
+		rc, ok := checks[p.Policy]
+		if !ok {
+			rc = &reporter.Check{
+				Policy:      p.Policy,
+				Name:        configurationCheck,
+				Description: "The configuration of the policy has no warnings",
+				Severity:    severity,
+				Message:     "The configuration of the policy has warnings",
+			}
+			checks[p.Policy] = rc
+			names = append(names, p.Policy)
+		}
+		v := Violation{Policy: p.Policy, Check: configurationCheck, Message: p.Message}
+		r.violations = append(r.violations, v)
+		status := severity.Status()
+		if c.baseline.contains(v) {
+			status = reporter.StatusBaseline
+		} else if severity == policy.SeverityError {
+			r.failed = true
+		} else {
+			r.warned = true
+		}
+		rc.Violations = append(rc.Violations, &reporter.Violation{Message: p.Message, Status: status})
+		if (status == reporter.StatusBaseline && c.options.Quiet) || !c.options.shows(severity) {
+			continue
+		}
+		t.row(p.Policy, configurationCheck, status, p.Message)
+	}
+	for _, name := range names {
+		t.report.Add(checks[name])
+	}
+
+	return r, nil
+}
+
+// configurationCheck is the name of the check that the warnings of the
+// validation of a policy are reported as.
+const configurationCheck = "Configuration"
+
+func hasSuffix(suffix string, files []string) bool {
+	for _, file := range files {
+		if strings.HasSuffix(file, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesAny(pattern string, files []string) bool {
+	for _, file := range files {
+		if git.MatchPattern(pattern, file) {
+			return true
+		}
+	}
+
+	return false
+}
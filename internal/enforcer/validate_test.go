@@ -0,0 +1,83 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies []*PolicyDeclaration
+		expected []Problem
+	}{
+		{
+			name: "Valid",
+			policies: []*PolicyDeclaration{
+				{Type: "binary", Spec: map[interface{}]interface{}{"allowedPaths": []interface{}{"*.png"}}},
+				{Type: "pullrequest", Spec: map[interface{}]interface{}{"requiredLabels": []interface{}{"^kind/"}}},
+			},
+		},
+		{
+			name: "InvalidRegexp",
+			policies: []*PolicyDeclaration{
+				{Type: "pullrequest", Spec: map[interface{}]interface{}{"requiredLabels": []interface{}{"kind/("}}},
+			},
+			expected: []Problem{
+				{
+					Policy:   "pullrequest",
+					Severity: policy.SeverityError,
+					Message:  "Invalid label pattern \"kind/(\": error parsing regexp: missing closing ): `kind/(`",
+				},
+			},
+		},
+		{
+			name: "UnreachablePattern",
+			policies: []*PolicyDeclaration{
+				{Type: "frozen", Spec: map[interface{}]interface{}{"paths": []interface{}{"api/v1/**", "api/v2/**"}}},
+			},
+			expected: []Problem{
+				{
+					Policy:   "frozen",
+					Severity: policy.SeverityWarn,
+					Message:  "Pattern \"api/v2/**\" matches no tracked files",
+				},
+			},
+		},
+		{
+			name: "UnmatchedSuffix",
+			policies: []*PolicyDeclaration{
+				{Type: "license", Spec: map[interface{}]interface{}{"includeSuffixes": []interface{}{".go", ".rs"}}},
+			},
+			expected: []Problem{
+				{
+					Policy:   "license",
+					Severity: policy.SeverityWarn,
+					Message:  "Suffix \".rs\" matches no tracked files",
+				},
+			},
+		},
+	}
+
+	files := []string{"api/v1/types.go", "docs/logo.png"}
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(tt *testing.T) {
+			c := &Conform{Policies: test.policies}
+			problems, err := c.validate("", files)
+			if err != nil {
+				tt.Fatal(err)
+			}
+			if !reflect.DeepEqual(problems, test.expected) {
+				tt.Errorf("Expected problems %+v, got %+v", test.expected, problems)
+			}
+		})
+	}
+}
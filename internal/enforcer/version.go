@@ -0,0 +1,174 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// APIVersion is the version of the configuration schema supported.
+	APIVersion = "conform.autonomy.io/v1"
+	// Kind is the kind of configuration files.
+	Kind = "Conform"
+)
+
+// checkVersion checks that the configuration is of a supported version. A
+// configuration without a version predates versioning, and is read as the
+// supported version.
+func checkVersion(c *Conform) error {
+	switch c.APIVersion {
+	case APIVersion:
+	case "":
+		if c.Kind != "" {
+			return errors.Errorf("The apiVersion of kind %q is missing: must be %s", c.Kind, APIVersion)
+		}
+		return nil
+	default:
+		for _, m := range migrations {
+			if m.from == c.APIVersion {
+				return errors.Errorf("Unsupported apiVersion %q: run conform migrate-config to upgrade the configuration to %s", c.APIVersion, APIVersion)
+			}
+		}
+		return errors.Errorf("Unsupported apiVersion %q: this version of conform supports %s, upgrade conform to read newer configurations", c.APIVersion, APIVersion)
+	}
+	if c.Kind != Kind {
+		return errors.Errorf("Unsupported kind %q: must be %s", c.Kind, Kind)
+	}
+
+	return nil
+}
+
+// migration upgrades a configuration file from a version to the next one.
+type migration struct {
+	// from is the version migrated from. The empty version is that of the
+	// configurations that predate versioning.
+	from string
+	to   string
+	// migrate migrates the contents of a configuration file of the format,
+	// identified by extension.
+	migrate func(configBytes []byte, ext string) ([]byte, error)
+}
+
+// migrations are the migrations of configurations, in order. A change to the
+// schema that breaks existing configurations must bump APIVersion, and add a
+// migration from the previous version.
+var migrations = []migration{
+	{from: "", to: APIVersion, migrate: addVersion},
+}
+
+// Migrate upgrades the contents of a configuration file, named by its name,
+// to the supported version. Migrations preserve comments and formatting
+// wherever possible. It returns the versions migrated from, which are empty
+// if the configuration is up to date.
+func Migrate(name string, configBytes []byte) ([]byte, []string, error) {
+	ext := filepath.Ext(name)
+	var migrated []string
+	for {
+		version, err := readVersion(configBytes, ext)
+		if err != nil {
+			return nil, nil, errors.Errorf("%s: %v", name, err)
+		}
+		if version == APIVersion {
+			return configBytes, migrated, nil
+		}
+
+		found := false
+		for _, m := range migrations {
+			if m.from != version {
+				continue
+			}
+			if configBytes, err = m.migrate(configBytes, ext); err != nil {
+				return nil, nil, errors.Errorf("%s: failed to migrate from %q to %s: %v", name, version, m.to, err)
+			}
+			migrated = append(migrated, version)
+			found = true
+			break
+		}
+		if !found {
+			return nil, nil, errors.Errorf("%s: %v", name, checkVersion(&Conform{APIVersion: version, Kind: Kind}))
+		}
+	}
+}
+
+// readVersion returns the apiVersion of the contents of a configuration
+// file of the format.
+func readVersion(configBytes []byte, ext string) (string, error) {
+	if ext == ".cue" {
+		// CUE files are not exported, so that the version is matched
+		// textually.
+		for _, line := range strings.Split(string(configBytes), "\n") {
+			fields := strings.SplitN(strings.TrimSpace(line), ":", 2)
+			if len(fields) == 2 && strings.TrimSpace(fields[0]) == "apiVersion" {
+				return strings.Trim(strings.TrimSpace(fields[1]), `"`), nil
+			}
+		}
+		return "", nil
+	}
+
+	converted, err := convertConfig("config"+ext, configBytes)
+	if err != nil {
+		return "", err
+	}
+	c := &Conform{}
+	if err = yaml.Unmarshal(converted, c); err != nil {
+		return "", err
+	}
+
+	return c.APIVersion, nil
+}
+
+// addVersion adds the apiVersion and kind to a configuration that predates
+// versioning, which is otherwise unchanged. The fields are inserted after
+// the leading comments of the file, or after the package clause of CUE
+// files.
+func addVersion(configBytes []byte, ext string) ([]byte, error) {
+	var header string
+	switch ext {
+	case ".json":
+		i := bytes.IndexByte(configBytes, '{')
+		if i < 0 {
+			return nil, errors.New("expected an object")
+		}
+		header = "\n  \"apiVersion\": \"" + APIVersion + "\",\n  \"kind\": \"" + Kind + "\","
+		if len(bytes.TrimSpace(configBytes[i+1:])) != 0 && bytes.TrimSpace(configBytes[i+1:])[0] == '}' {
+			header = strings.TrimSuffix(header, ",") + "\n"
+		}
+		return append(append(append([]byte{}, configBytes[:i+1]...), header...), configBytes[i+1:]...), nil
+	case ".toml":
+		header = "apiVersion = \"" + APIVersion + "\"\nkind = \"" + Kind + "\"\n"
+	case ".cue":
+		header = "apiVersion: \"" + APIVersion + "\"\nkind:       \"" + Kind + "\"\n"
+	default:
+		header = "apiVersion: " + APIVersion + "\nkind: " + Kind + "\n"
+	}
+
+	lines := strings.SplitAfter(string(configBytes), "\n")
+	i := 0
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") || line == "---" {
+			continue
+		}
+		if ext == ".cue" && strings.HasPrefix(line, "package ") {
+			i++
+			header = "\n" + header
+		}
+		break
+	}
+	if i > 0 && lines[i-1] != "" && !strings.HasSuffix(lines[i-1], "\n") {
+		lines[i-1] += "\n"
+	}
+	if i < len(lines) && strings.TrimSpace(lines[i]) != "" {
+		header += "\n"
+	}
+
+	return []byte(strings.Join(lines[:i], "") + header + strings.Join(lines[i:], "")), nil
+}
@@ -0,0 +1,127 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package enforcer
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestCheckVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiVersion string
+		kind       string
+		err        bool
+	}{
+		{name: "Current", apiVersion: APIVersion, kind: Kind},
+		{name: "Legacy"},
+		{name: "MissingVersion", kind: Kind, err: true},
+		{name: "Unknown", apiVersion: "conform.autonomy.io/v2", kind: Kind, err: true},
+		{name: "WrongKind", apiVersion: APIVersion, kind: "Policy", err: true},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			err := checkVersion(&Conform{APIVersion: test.apiVersion, Kind: test.kind})
+			if test.err != (err != nil) {
+				t.Errorf("Expected error %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		config   string
+		expected string
+		migrated bool
+		err      bool
+	}{
+		{
+			name:     "YAML",
+			file:     ".conform.yaml",
+			config:   "# Policies of the project.\n---\npolicies:\n  - type: commit\n",
+			expected: "# Policies of the project.\n---\napiVersion: " + APIVersion + "\nkind: Conform\n\npolicies:\n  - type: commit\n",
+			migrated: true,
+		},
+		{
+			name:     "Empty",
+			file:     ".conform.yaml",
+			config:   "",
+			expected: "apiVersion: " + APIVersion + "\nkind: Conform\n",
+			migrated: true,
+		},
+		{
+			name:     "JSON",
+			file:     ".conform.json",
+			config:   "{\n  \"policies\": []\n}\n",
+			expected: "{\n  \"apiVersion\": \"" + APIVersion + "\",\n  \"kind\": \"Conform\",\n  \"policies\": []\n}\n",
+			migrated: true,
+		},
+		{
+			name:     "TOML",
+			file:     ".conform.toml",
+			config:   "[[policies]]\ntype = \"commit\"\n",
+			expected: "apiVersion = \"" + APIVersion + "\"\nkind = \"Conform\"\n\n[[policies]]\ntype = \"commit\"\n",
+			migrated: true,
+		},
+		{
+			name:     "CUE",
+			file:     ".conform.cue",
+			config:   "package conform\n\npolicies: []\n",
+			expected: "package conform\n\napiVersion: \"" + APIVersion + "\"\nkind:       \"Conform\"\n\npolicies: []\n",
+			migrated: true,
+		},
+		{
+			name:     "UpToDate",
+			file:     ".conform.yaml",
+			config:   "apiVersion: " + APIVersion + "\nkind: Conform\n",
+			expected: "apiVersion: " + APIVersion + "\nkind: Conform\n",
+		},
+		{
+			name:   "Unknown",
+			file:   ".conform.yaml",
+			config: "apiVersion: conform.autonomy.io/v2\nkind: Conform\n",
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			migrated, from, err := Migrate(test.file, []byte(test.config))
+			if test.err != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", test.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if string(migrated) != test.expected {
+				t.Errorf("Expected %q, got %q", test.expected, migrated)
+			}
+			if test.migrated != (len(from) != 0) {
+				t.Errorf("Expected migrated %v, got %v", test.migrated, from)
+			}
+			if test.file == ".conform.cue" {
+				return
+			}
+			// The migrated configuration is of the supported version.
+			version, err := readVersion(migrated, test.file[len(".conform"):])
+			if err != nil {
+				t.Fatal(errors.Wrap(err, "failed to read the migrated version"))
+			}
+			if version != APIVersion {
+				t.Errorf("Expected version %q, got %q", APIVersion, version)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/autonomy/conform/internal/logging"
+)
+
+const (
+	// AttributeSet is the value of an attribute that is set (e.g. "text").
+	AttributeSet = "true"
+	// AttributeUnset is the value of an attribute that is unset (e.g. "-text").
+	AttributeUnset = "false"
+)
+
+// AttributeRule is a single line of a .gitattributes file.
+type AttributeRule struct {
+	// Line is the 1-indexed line number of the rule.
+	Line int
+	// Pattern is the path pattern the rule applies to.
+	Pattern string
+	// Attributes are the attributes assigned by the rule.
+	Attributes map[string]string
+}
+
+// Attributes is the set of rules declared in a .gitattributes file.
+type Attributes struct {
+	Rules []AttributeRule
+}
+
+// ReadAttributes reads the .gitattributes file at the specified path. A
+// missing file results in an empty set of rules.
+func ReadAttributes(name string) (*Attributes, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Attributes{}, nil
+		}
+		return nil, err
+	}
+	// nolint: errcheck
+	defer f.Close()
+
+	return ParseAttributes(f)
+}
+
+// ParseAttributes parses the contents of a .gitattributes file.
+func ParseAttributes(r io.Reader) (*Attributes, error) {
+	a := &Attributes{}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		rule := AttributeRule{
+			Line:       n,
+			Pattern:    fields[0],
+			Attributes: map[string]string{},
+		}
+		for _, attr := range fields[1:] {
+			switch {
+			case attr == "binary":
+				// binary is a builtin macro for "-diff -merge -text".
+				rule.Attributes["binary"] = AttributeSet
+				rule.Attributes["diff"] = AttributeUnset
+				rule.Attributes["merge"] = AttributeUnset
+				rule.Attributes["text"] = AttributeUnset
+			case strings.HasPrefix(attr, "-"):
+				rule.Attributes[attr[1:]] = AttributeUnset
+			case strings.HasPrefix(attr, "!"):
+				rule.Attributes[attr[1:]] = ""
+			case strings.Contains(attr, "="):
+				kv := strings.SplitN(attr, "=", 2)
+				rule.Attributes[kv[0]] = kv[1]
+			default:
+				rule.Attributes[attr] = AttributeSet
+			}
+		}
+		a.Rules = append(a.Rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Get returns the attributes that apply to the slash separated path. When
+// several rules assign the same attribute, the last one wins.
+func (a *Attributes) Get(p string) map[string]string {
+	attrs := map[string]string{}
+	for _, rule := range a.Rules {
+		if !matchPattern(rule.Pattern, p, false) {
+			continue
+		}
+		for k, v := range rule.Attributes {
+			if v == "" {
+				delete(attrs, k)
+				continue
+			}
+			attrs[k] = v
+		}
+	}
+
+	return attrs
+}
+
+// IsBinary uses the same heuristic as git to detect binary contents: they are
+// binary if they contain a NUL byte within the first 8000 bytes.
+func IsBinary(contents []byte) bool {
+	if len(contents) > 8000 {
+		contents = contents[:8000]
+	}
+
+	return bytes.IndexByte(contents, 0) != -1
+}
+
+// MatchPattern reports whether the slash separated path matches a gitignore
+// style pattern. Patterns without a slash match the base name of the path at
+// any depth, while patterns with a slash are anchored to the root. A leading
+// "**/" matches in all directories, and a trailing "/**" matches everything
+// inside a directory. A pattern matching a directory matches everything inside
+// of it.
+func MatchPattern(pattern, p string) bool {
+	return matchPattern(pattern, p, true)
+}
+
+// MatchAny reports whether the slash separated path matches any of the
+// patterns.
+func MatchAny(patterns []string, p string) bool {
+	for _, pattern := range patterns {
+		if MatchPattern(pattern, p) {
+			logging.Debug("matched pattern", "pattern", pattern, "path", p)
+			return true
+		}
+	}
+
+	return false
+}
+
+// nolint: gocyclo
+func matchPattern(pattern, p string, recursive bool) bool {
+	pattern = strings.TrimSuffix(pattern, "/")
+	if pattern == "" {
+		return false
+	}
+
+	if strings.HasPrefix(pattern, "**/") {
+		pattern = pattern[len("**/"):]
+		for sub := p; ; {
+			if matchPattern("/"+pattern, sub, recursive) {
+				return true
+			}
+			i := strings.Index(sub, "/")
+			if i < 0 {
+				return false
+			}
+			sub = sub[i+1:]
+		}
+	}
+
+	if strings.HasSuffix(pattern, "/**") {
+		dir := strings.TrimPrefix(strings.TrimSuffix(pattern, "/**"), "/")
+		for parent := path.Dir(p); parent != "." && parent != "/"; parent = path.Dir(parent) {
+			if matches, err := path.Match(dir, parent); err == nil && matches {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !strings.Contains(pattern, "/") {
+		// Match the base name of the path, and of each parent directory.
+		for sub := p; sub != "." && sub != "/"; sub = path.Dir(sub) {
+			if matches, err := path.Match(pattern, path.Base(sub)); err == nil && matches {
+				return true
+			}
+			if !recursive {
+				break
+			}
+		}
+		return false
+	}
+
+	pattern = strings.TrimPrefix(pattern, "/")
+	for sub := p; sub != "." && sub != "/"; sub = path.Dir(sub) {
+		if matches, err := path.Match(pattern, sub); err == nil && matches {
+			return true
+		}
+		if !recursive {
+			break
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,107 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package git
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CodeOwnersPaths are the locations searched for a CODEOWNERS file, in order.
+var CodeOwnersPaths = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+	".gitlab/CODEOWNERS",
+}
+
+// CodeOwnersRule is a single line of a CODEOWNERS file.
+type CodeOwnersRule struct {
+	// Line is the 1-indexed line number of the rule.
+	Line int
+	// Pattern is the path pattern the rule applies to.
+	Pattern string
+	// Owners are the users (@user), teams (@org/team), or email addresses
+	// owning the matching paths. A rule without owners removes ownership.
+	Owners []string
+}
+
+// CodeOwners is the set of rules declared in a CODEOWNERS file.
+type CodeOwners struct {
+	// Path is the path of the file the rules were read from. It is empty if
+	// no CODEOWNERS file exists.
+	Path  string
+	Rules []CodeOwnersRule
+}
+
+// ReadCodeOwners reads the first CODEOWNERS file found in CodeOwnersPaths,
+// relative to dir. A missing file results in an empty set of rules.
+func ReadCodeOwners(dir string) (*CodeOwners, error) {
+	for _, name := range CodeOwnersPaths {
+		f, err := os.Open(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		// nolint: errcheck
+		defer f.Close()
+
+		c, err := ParseCodeOwners(f)
+		if err != nil {
+			return nil, err
+		}
+		c.Path = name
+
+		return c, nil
+	}
+
+	return &CodeOwners{}, nil
+}
+
+// ParseCodeOwners parses the contents of a CODEOWNERS file. GitLab section
+// headers (e.g. "[Docs]") are ignored.
+func ParseCodeOwners(r io.Reader) (*CodeOwners, error) {
+	c := &CodeOwners{}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "[") {
+			continue
+		}
+		c.Rules = append(c.Rules, CodeOwnersRule{
+			Line:    n,
+			Pattern: fields[0],
+			Owners:  fields[1:],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Owners returns the owners of the slash separated path. The last matching
+// rule wins.
+func (c *CodeOwners) Owners(p string) []string {
+	var owners []string
+	for _, rule := range c.Rules {
+		if MatchPattern(rule.Pattern, p) {
+			owners = rule.Owners
+		}
+	}
+
+	return owners
+}
@@ -0,0 +1,404 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/autonomy/conform/internal/progress"
+	"github.com/pkg/errors"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	fdiff "gopkg.in/src-d/go-git.v4/plumbing/format/diff"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/plumbing/storer"
+)
+
+// Line is a single line of a file.
+type Line struct {
+	// Number is the 1-indexed line number in the new version of the file.
+	Number int
+	// Text is the contents of the line without the line ending.
+	Text string
+}
+
+// FileDiff describes the changes made to a single file.
+type FileDiff struct {
+	// From is the path of the file before the change. It is empty if the file
+	// was added.
+	From string
+	// To is the path of the file after the change. It is empty if the file
+	// was deleted.
+	To string
+	// Binary is true if either version of the file is binary.
+	Binary bool
+	// Added are the lines added to the file.
+	Added []Line
+	// Removed is the number of lines removed from the file.
+	Removed int
+}
+
+// Path returns the path of the file after the change, or the path before the
+// change if the file was deleted.
+func (d *FileDiff) Path() string {
+	if d.To != "" {
+		return d.To
+	}
+	return d.From
+}
+
+// Diff returns the changes made between the merge base of HEAD and the
+// specified base revision, and HEAD. If base is empty, the changes introduced
+// by the HEAD commit are returned.
+func (g *Git) Diff(base string) (diffs []*FileDiff, err error) {
+	ref, err := g.repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	head, err := g.repo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	var from *object.Commit
+	if base != "" {
+		if from, err = g.mergeBase(base, head); err != nil {
+			return nil, err
+		}
+	} else if head.NumParents() > 0 {
+		if from, err = g.parent(head, 2, "the changes of HEAD"); err != nil {
+			return nil, err
+		}
+	}
+
+	var fromTree *object.Tree
+	if from != nil {
+		if fromTree, err = from.Tree(); err != nil {
+			return nil, err
+		}
+	}
+	toTree, err := head.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := object.DiffTree(fromTree, toTree)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := changes.Patch()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fp := range patch.FilePatches() {
+		diffs = append(diffs, fileDiff(fp))
+	}
+
+	return diffs, nil
+}
+
+// Commit is a commit and the changes it introduced.
+type Commit struct {
+	SHA     string
+	Message string
+	// Signed is true if the commit has a GPG signature.
+	Signed bool
+	Diffs  []*FileDiff
+}
+
+// Commits returns the commits reachable from HEAD but not from the specified
+// base revision, newest first. If base is empty, only the HEAD commit is
+// returned. Merge commits are skipped, since their changes are introduced by
+// the commits being merged.
+func (g *Git) Commits(base string) (commits []*Commit, err error) {
+	head, err := g.head()
+	if err != nil {
+		return nil, err
+	}
+
+	exclude := map[plumbing.Hash]bool{}
+	if base != "" {
+		var from *object.Commit
+		if from, err = g.revision(base); err != nil {
+			return nil, err
+		}
+		err = g.log(from, nil).ForEach(func(c *object.Commit) error {
+			exclude[c.Hash] = true
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		// The history since the base is only complete if the clone
+		// contains their merge base.
+		if len(g.missing) != 0 {
+			if _, err = g.mergeBase(base, head); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	depth, need := 2, "the changes of HEAD"
+	if base != "" {
+		depth, need = 0, "the commits since "+base
+	}
+	err = g.log(head, exclude).ForEach(func(c *object.Commit) error {
+		if exclude[c.Hash] {
+			return nil
+		}
+		if c.NumParents() <= 1 {
+			commit, err := g.commitChanges(c, depth, need)
+			if err != nil {
+				return err
+			}
+			commits = append(commits, commit)
+			progress.Commit()
+		}
+		if base == "" {
+			return storer.ErrStop
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return commits, nil
+}
+
+// Ancestor returns the SHA of the nth first parent of HEAD, so that the
+// changes of the n most recent commits are those made since the ancestor. If
+// HEAD has fewer ancestors, the SHA of the root commit is returned.
+func (g *Git) Ancestor(n int) (sha string, err error) {
+	c, err := g.head()
+	if err != nil {
+		return "", err
+	}
+
+	for i := 0; i < n && c.NumParents() > 0; i++ {
+		if c, err = g.parent(c, n+1, fmt.Sprintf("the %d most recent commits", n)); err != nil {
+			return "", err
+		}
+	}
+
+	return c.Hash.String(), nil
+}
+
+// RecentCommits returns up to n of the most recent commits of HEAD, newest
+// first, following the first parents as Ancestor does, so that they are the
+// commits whose changes are made since the nth ancestor. Merge commits are
+// skipped.
+func (g *Git) RecentCommits(n int) (commits []*Commit, err error) {
+	c, err := g.head()
+	if err != nil {
+		return nil, err
+	}
+
+	need := fmt.Sprintf("the %d most recent commits", n)
+	for i := 0; i < n; i++ {
+		if c.NumParents() <= 1 {
+			commit, err := g.commitChanges(c, i+2, need)
+			if err != nil {
+				return nil, err
+			}
+			commits = append(commits, commit)
+			progress.Commit()
+		}
+		if i == n-1 || c.NumParents() == 0 {
+			break
+		}
+		if c, err = g.parent(c, i+2, need); err != nil {
+			return nil, err
+		}
+	}
+
+	return commits, nil
+}
+
+// commitChanges returns the changes the commit introduced. The depth is that
+// needed to read its parent, should the clone be too shallow.
+func (g *Git) commitChanges(c *object.Commit, depth int, need string) (*Commit, error) {
+	var fromTree *object.Tree
+	if c.NumParents() > 0 {
+		parent, err := g.parent(c, depth, need)
+		if err != nil {
+			return nil, err
+		}
+		if fromTree, err = parent.Tree(); err != nil {
+			return nil, err
+		}
+	}
+	toTree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := object.DiffTree(fromTree, toTree)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := changes.Patch()
+	if err != nil {
+		return nil, err
+	}
+
+	commit := &Commit{SHA: c.Hash.String(), Message: c.Message, Signed: c.PGPSignature != ""}
+	for _, fp := range patch.FilePatches() {
+		commit.Diffs = append(commit.Diffs, fileDiff(fp))
+	}
+
+	return commit, nil
+}
+
+// revision returns the commit of the revision, which may also be the full SHA
+// of a commit.
+func (g *Git) revision(rev string) (*object.Commit, error) {
+	var c *object.Commit
+	var err error
+	if isHash(rev) {
+		c, err = g.repo.CommitObject(plumbing.NewHash(rev))
+	} else {
+		var hash *plumbing.Hash
+		if hash, err = g.repo.ResolveRevision(plumbing.Revision(rev)); err == nil {
+			c, err = g.repo.CommitObject(*hash)
+		}
+	}
+	if err == plumbing.ErrReferenceNotFound || err == plumbing.ErrObjectNotFound {
+		return nil, &MissingRevisionError{Rev: rev}
+	}
+
+	return c, err
+}
+
+// MergeBase returns the SHA of the merge base of HEAD and the branch. The
+// branch is looked up among the local branches, and then among the branches
+// of the origin remote, since CI services rarely check out the base branch.
+func (g *Git) MergeBase(branch string) (sha string, err error) {
+	head, err := g.head()
+	if err != nil {
+		return "", err
+	}
+
+	var c *object.Commit
+	for _, rev := range []string{branch, "origin/" + branch} {
+		if c, err = g.mergeBase(rev, head); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	if c == nil {
+		return "", errors.Errorf("HEAD and %s have no common history, the clone may be too shallow", branch)
+	}
+
+	return c.Hash.String(), nil
+}
+
+// mergeBase returns the best common ancestor of the base revision and HEAD,
+// as git merge-base does: the common ancestor that is not an ancestor of
+// another one. Of several best common ancestors, as left by criss-cross
+// merges, the most recently committed is returned.
+func (g *Git) mergeBase(base string, head *object.Commit) (*object.Commit, error) {
+	commit, err := g.revision(base)
+	if err != nil {
+		return nil, err
+	}
+
+	ancestors := map[plumbing.Hash]bool{}
+	err = g.log(commit, nil).ForEach(func(c *object.Commit) error {
+		ancestors[c.Hash] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The common ancestors nearest to HEAD on each of its paths. Any of them
+	// can still be an ancestor of another, reached on a different path.
+	var candidates []*object.Commit
+	seen := map[plumbing.Hash]bool{head.Hash: true}
+	for queue := []*object.Commit{head}; len(queue) != 0; queue = queue[1:] {
+		c := queue[0]
+		if ancestors[c.Hash] {
+			candidates = append(candidates, c)
+			continue
+		}
+		var parents []*object.Commit
+		if parents, err = g.parents(c, seen); err != nil {
+			return nil, err
+		}
+		queue = append(queue, parents...)
+	}
+
+	// The ancestors of the candidates are not the best common ancestors.
+	seen = map[plumbing.Hash]bool{}
+	for stack := append([]*object.Commit{}, candidates...); len(stack) != 0; {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		var parents []*object.Commit
+		if parents, err = g.parents(c, seen); err != nil {
+			return nil, err
+		}
+		stack = append(stack, parents...)
+	}
+	var mergeBase *object.Commit
+	for _, c := range candidates {
+		if seen[c.Hash] {
+			continue
+		}
+		if mergeBase == nil || c.Committer.When.After(mergeBase.Committer.When) {
+			mergeBase = c
+		}
+	}
+	if mergeBase == nil && len(g.missing) != 0 {
+		return nil, &ShallowError{Need: "the merge base of HEAD and " + base}
+	}
+
+	return mergeBase, nil
+}
+
+func fileDiff(fp fdiff.FilePatch) *FileDiff {
+	d := &FileDiff{Binary: fp.IsBinary()}
+
+	from, to := fp.Files()
+	if from != nil {
+		d.From = from.Path()
+	}
+	if to != nil {
+		d.To = to.Path()
+	}
+
+	number := 0
+	for _, chunk := range fp.Chunks() {
+		lines := splitLines(chunk.Content())
+		switch chunk.Type() {
+		case fdiff.Equal:
+			number += len(lines)
+		case fdiff.Add:
+			for _, line := range lines {
+				number++
+				d.Added = append(d.Added, Line{Number: number, Text: line})
+			}
+		case fdiff.Delete:
+			d.Removed += len(lines)
+		}
+	}
+
+	return d
+}
+
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+
+	return lines
+}
@@ -0,0 +1,150 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// mergedRepo creates a repository whose feature branch, checked out, merged
+// main after branching from it:
+//
+//	main:    A---M
+//	          \   \
+//	feature:   F---X---G
+//
+// with the branch old still at A, and returns its directory and the SHAs of the commits by message.
+func mergedRepo(t *testing.T) (string, map[string]string) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	identity := []string{"-c", "user.name=test", "-c", "user.email=test@example.com"}
+	for _, args := range [][]string{
+		{"init", "--quiet", dir},
+		{"-C", dir, "commit", "--quiet", "--allow-empty", "-m", "A"},
+		{"-C", dir, "branch", "-M", "main"},
+		{"-C", dir, "branch", "old"},
+		{"-C", dir, "checkout", "--quiet", "-b", "feature"},
+		{"-C", dir, "commit", "--quiet", "--allow-empty", "-m", "F"},
+		{"-C", dir, "checkout", "--quiet", "main"},
+		{"-C", dir, "commit", "--quiet", "--allow-empty", "-m", "M"},
+		{"-C", dir, "checkout", "--quiet", "feature"},
+		{"-C", dir, "merge", "--quiet", "--no-ff", "-m", "X", "main"},
+		{"-C", dir, "commit", "--quiet", "--allow-empty", "-m", "G"},
+	} {
+		cmd := exec.Command("git", append(identity, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	out, err := exec.Command("git", "-C", dir, "log", "--all", "--format=%s %H").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	shas := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Fields(line)
+		shas[fields[0]] = fields[1]
+	}
+
+	return dir, shas
+}
+
+func TestRecentCommits(t *testing.T) {
+	dir, shas := mergedRepo(t)
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.Chdir(wd)
+
+	g, err := NewGit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		n        int
+		ancestor string
+		expected []string
+	}{
+		{n: 1, ancestor: "X", expected: []string{"G"}},
+		// The merge is skipped, and the commit merged from main is not one
+		// of the most recent commits of the branch.
+		{n: 3, ancestor: "A", expected: []string{"G", "F"}},
+		{n: 10, ancestor: "A", expected: []string{"G", "F", "A"}},
+	}
+
+	for _, test := range tests {
+		commits, err := g.RecentCommits(test.n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var messages []string
+		for _, c := range commits {
+			messages = append(messages, strings.TrimSpace(c.Message))
+		}
+		if strings.Join(messages, " ") != strings.Join(test.expected, " ") {
+			t.Errorf("Expected the %d most recent commits to be %v, got %v", test.n, test.expected, messages)
+		}
+		if ancestor, err := g.Ancestor(test.n); err != nil || ancestor != shas[test.ancestor] {
+			t.Errorf("Expected ancestor %d to be %s, got %s, %v", test.n, test.ancestor, ancestor, err)
+		}
+	}
+}
+
+func TestMergeBase(t *testing.T) {
+	dir, shas := mergedRepo(t)
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.Chdir(wd)
+
+	g, err := NewGit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		branch   string
+		expected string
+	}{
+		// The branch merged main, so that its merge base is the commit of
+		// main merged rather than the one it branched from.
+		{branch: "main", expected: "M"},
+		{branch: "old", expected: "A"},
+		{branch: "feature", expected: "G"},
+	}
+
+	for _, test := range tests {
+		sha, err := g.MergeBase(test.branch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sha != shas[test.expected] {
+			t.Errorf("Expected the merge base of %s to be %s, got %s", test.branch, test.expected, sha)
+		}
+	}
+}
@@ -5,11 +5,16 @@
 package git
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/pkg/errors"
 	git "gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/config"
 	"gopkg.in/src-d/go-git.v4/plumbing"
@@ -20,6 +25,11 @@ import (
 // Git is a helper for git.
 type Git struct {
 	repo *git.Repository
+	// missing are the parents of the commits at the boundary of a shallow
+	// clone.
+	missing []plumbing.Hash
+	// ctx stops the walks of the history once it is done.
+	ctx context.Context
 }
 
 func findDotGit(name string) (string, error) {
@@ -30,19 +40,85 @@ func findDotGit(name string) (string, error) {
 	return filepath.Abs(name)
 }
 
+// IsBare reports whether the directory is a bare repository, such as those of
+// git servers, which has no working tree.
+func IsBare(dir string) bool {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		return false
+	}
+	for _, name := range []string{"HEAD", "objects", "refs"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
 // NewGit instantiates and returns a Git struct.
 func NewGit() (g *Git, err error) {
+	if IsBare(".") {
+		return nil, errors.Errorf("the repository is bare, the policies of pushed commits are enforced by conform pre-receive")
+	}
 	p, err := findDotGit(".git")
 	if err != nil {
 		return
 	}
-	repo, err := git.PlainOpen(path.Dir(p))
+	repo, err := openRepository(path.Dir(p))
 	if err != nil {
 		return
 	}
-	g = &Git{repo: repo}
+	g = &Git{repo: repo, ctx: context.Background()}
+	if err = g.loadShallow(); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// WithContext makes the walks of the history stop with the error of the
+// context once it is done, so that a policy that timed out does not keep
+// walking, and returns the Git.
+func (g *Git) WithContext(ctx context.Context) *Git {
+	g.ctx = ctx
+	return g
+}
+
+// Root returns the absolute path of the root of the working tree.
+func (g *Git) Root() (string, error) {
+	wt, err := g.repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Abs(wt.Filesystem.Root())
+}
+
+// Prefix returns the slash separated path of the working directory relative
+// to the root of the working tree, with a trailing slash, or an empty string
+// at the root, like git rev-parse --show-prefix.
+func (g *Git) Prefix() (string, error) {
+	root, err := g.Root()
+	if err != nil {
+		return "", err
+	}
+	wd, err := filepath.Abs(".")
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, wd)
+	if err != nil {
+		return "", err
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == "." {
+		return "", nil
+	}
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", errors.Errorf("the working directory is outside of the working tree %s", root)
+	}
 
-	return g, err
+	return rel + "/", nil
 }
 
 // Message returns the commit message. In the case that a commit has multiple
@@ -125,6 +201,56 @@ func (g *Git) CheckoutPullRequest(number int) (err error) {
 	return nil
 }
 
+// Branch returns the short name of the branch that HEAD points to. It is
+// empty if HEAD is detached.
+func (g *Git) Branch() (branch string, err error) {
+	ref, err := g.repo.Head()
+	if err != nil {
+		return "", err
+	}
+	if !ref.Name().IsBranch() {
+		return "", nil
+	}
+
+	return ref.Name().Short(), nil
+}
+
+// Signature identifies who authored or committed a commit, and when.
+type Signature struct {
+	Name  string
+	Email string
+	When  time.Time
+}
+
+// Author returns the author of the current commit.
+func (g *Git) Author() (signature Signature, err error) {
+	commit, err := g.head()
+	if err != nil {
+		return signature, err
+	}
+
+	return Signature{Name: commit.Author.Name, Email: commit.Author.Email, When: commit.Author.When}, nil
+}
+
+// Committer returns the committer of the current commit.
+func (g *Git) Committer() (signature Signature, err error) {
+	commit, err := g.head()
+	if err != nil {
+		return signature, err
+	}
+
+	return Signature{Name: commit.Committer.Name, Email: commit.Committer.Email, When: commit.Committer.When}, nil
+}
+
+func (g *Git) head() (*object.Commit, error) {
+	ref, err := g.repo.Head()
+	if err != nil {
+		return nil, err
+	}
+
+	return g.repo.CommitObject(ref.Hash())
+}
+
 // SHA returns the sha of the current commit.
 func (g *Git) SHA() (sha string, err error) {
 	ref, err := g.repo.Head()
@@ -155,7 +281,7 @@ func (g *Git) AheadBehind(ref string) (ahead int, behind int, err error) {
 	}
 
 	var count int
-	iter := object.NewCommitPreorderIter(commit2, nil, nil)
+	iter := g.log(commit2, nil)
 	err = iter.ForEach(func(comm *object.Commit) error {
 		if comm.Hash != ref1.Hash() {
 			count++
@@ -170,3 +296,103 @@ func (g *Git) AheadBehind(ref string) (ahead int, behind int, err error) {
 
 	return count, 0, nil
 }
+
+// Messages returns the messages of up to n commits reachable from HEAD,
+// newest first. Merge commits are skipped.
+func (g *Git) Messages(n int) (messages []string, err error) {
+	head, err := g.head()
+	if err != nil {
+		return nil, err
+	}
+
+	err = g.log(head, nil).ForEach(func(c *object.Commit) error {
+		if len(messages) >= n {
+			return storer.ErrStop
+		}
+		if c.NumParents() <= 1 {
+			messages = append(messages, c.Message)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// CommitMessage returns the message of the commit with the SHA.
+func (g *Git) CommitMessage(sha string) (message string, err error) {
+	c, err := g.revision(sha)
+	if err != nil {
+		return "", err
+	}
+
+	return c.Message, nil
+}
+
+// TrackedFiles returns the paths of all files tracked in the index.
+func (g *Git) TrackedFiles() (files []string, err error) {
+	idx, err := g.repo.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+
+	files = make([]string, 0, len(idx.Entries))
+	for _, entry := range idx.Entries {
+		files = append(files, entry.Name)
+	}
+
+	return files, nil
+}
+
+// TrackedFileModes returns the modes of all files tracked in the index, keyed
+// by path. Modes are those recorded by git rather than the working tree, so
+// they are consistent across platforms.
+func (g *Git) TrackedFileModes() (modes map[string]os.FileMode, err error) {
+	idx, err := g.repo.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+
+	modes = make(map[string]os.FileMode, len(idx.Entries))
+	for _, entry := range idx.Entries {
+		var mode os.FileMode
+		if mode, err = entry.Mode.ToOSFileMode(); err != nil {
+			return nil, err
+		}
+		modes[entry.Name] = mode
+	}
+
+	return modes, nil
+}
+
+// IndexContents returns the contents of a file, of the path relative to the
+// working directory, as staged in the index. Unlike the working tree, these
+// contents are not subject to smudge filters.
+func (g *Git) IndexContents(name string) (contents []byte, err error) {
+	prefix, err := g.Prefix()
+	if err != nil {
+		return nil, err
+	}
+	idx, err := g.repo.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+	entry, err := idx.Entry(path.Join(prefix, filepath.ToSlash(name)))
+	if err != nil {
+		return nil, err
+	}
+	blob, err := g.repo.BlobObject(entry.Hash)
+	if err != nil {
+		return nil, err
+	}
+	r, err := blob.Reader()
+	if err != nil {
+		return nil, err
+	}
+	// nolint: errcheck
+	defer r.Close()
+
+	return ioutil.ReadAll(r)
+}
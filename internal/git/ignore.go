@@ -0,0 +1,165 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package git
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/src-d/go-git.v4/plumbing/format/gitignore"
+)
+
+// IgnoreFile is the name of the file, at the root of the repository, that
+// declares the paths ignored by all policies that scan files.
+const IgnoreFile = ".conformignore"
+
+// Ignore is the set of patterns declared in a .conformignore file, using the
+// syntax of .gitignore files. A nil Ignore ignores nothing.
+type Ignore struct {
+	matcher gitignore.Matcher
+	// root is the absolute path of the directory the patterns are relative
+	// to.
+	root string
+}
+
+// ReadIgnore reads the .conformignore file at the root of the repository. A
+// missing file results in an empty set of patterns.
+func ReadIgnore(root string) (*Ignore, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filepath.Join(root, IgnoreFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Ignore{root: root}, nil
+		}
+		return nil, err
+	}
+	// nolint: errcheck
+	defer f.Close()
+
+	i, err := ParseIgnore(f)
+	if err != nil {
+		return nil, err
+	}
+	i.root = root
+
+	return i, nil
+}
+
+// ParseIgnore parses the contents of a .conformignore file.
+func ParseIgnore(r io.Reader) (*Ignore, error) {
+	var patterns []gitignore.Pattern
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, nil))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &Ignore{matcher: gitignore.NewMatcher(patterns)}, nil
+}
+
+// Match reports whether the slash separated path relative to the root of the
+// repository is ignored. As with .gitignore files, the contents of an ignored
+// directory are ignored.
+func (i *Ignore) Match(p string, isDir bool) bool {
+	if i == nil || i.matcher == nil {
+		return false
+	}
+
+	parts := strings.Split(p, "/")
+	for n := 1; n < len(parts); n++ {
+		if i.matcher.Match(parts[:n], true) {
+			return true
+		}
+	}
+
+	return i.matcher.Match(parts, isDir)
+}
+
+// MatchFile reports whether the path relative to the working directory is
+// ignored. Paths outside of the repository are never ignored.
+func (i *Ignore) MatchFile(name string, isDir bool) bool {
+	if i == nil || i.matcher == nil {
+		return false
+	}
+
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(i.root, abs)
+	if err != nil {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == "." || rel == ".." || strings.HasPrefix(rel, "../") {
+		return false
+	}
+
+	return i.Match(rel, isDir)
+}
+
+// Filter returns the slash separated paths relative to the working directory
+// that are not ignored.
+func (i *Ignore) Filter(files []string) []string {
+	if i == nil || i.matcher == nil {
+		return files
+	}
+
+	filtered := make([]string, 0, len(files))
+	for _, file := range files {
+		if !i.MatchFile(filepath.FromSlash(file), false) {
+			filtered = append(filtered, file)
+		}
+	}
+
+	return filtered
+}
+
+// FilterModes returns the modes of the files that are not ignored, keyed by
+// slash separated path relative to the working directory.
+func (i *Ignore) FilterModes(modes map[string]os.FileMode) map[string]os.FileMode {
+	if i == nil || i.matcher == nil {
+		return modes
+	}
+
+	filtered := make(map[string]os.FileMode, len(modes))
+	for file, mode := range modes {
+		if !i.MatchFile(filepath.FromSlash(file), false) {
+			filtered[file] = mode
+		}
+	}
+
+	return filtered
+}
+
+// FilterDiffs returns the diffs of the files that are not ignored.
+func (i *Ignore) FilterDiffs(diffs []*FileDiff) []*FileDiff {
+	if i == nil || i.matcher == nil {
+		return diffs
+	}
+
+	filtered := make([]*FileDiff, 0, len(diffs))
+	for _, d := range diffs {
+		if !i.Match(d.Path(), false) {
+			filtered = append(filtered, d)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,46 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIgnore(t *testing.T) {
+	i, err := ParseIgnore(strings.NewReader("# Generated code.\n\nvendor/\n*.pb.go\n/build\ndocs/**/*.svg\n!docs/logo.svg\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		Name     string
+		Path     string
+		Expected bool
+	}{
+		{"Directory", "vendor/github.com/pkg/errors/errors.go", true},
+		{"Nested directory", "tools/vendor/a.go", true},
+		{"Name", "api/v1/api.pb.go", true},
+		{"Anchored", "build/out.txt", true},
+		{"Anchored not at root", "cmd/build/main.go", false},
+		{"Glob", "docs/images/diagram.svg", true},
+		{"Negated", "docs/logo.svg", false},
+		{"Not ignored", "main.go", false},
+		{"Comment", "# Generated code.", false},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			if ignored := i.Match(test.Path, false); ignored != test.Expected {
+				tt.Errorf("Expected %s to be ignored: %v, got %v", test.Path, test.Expected, ignored)
+			}
+		})
+	}
+
+	var none *Ignore
+	if files := none.Filter([]string{"vendor/a.go"}); len(files) != 1 {
+		t.Errorf("Expected a nil ignore to ignore nothing, got %v", files)
+	}
+}
@@ -0,0 +1,240 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// ShallowError is returned when the history needed is missing from a shallow
+// clone, as made by CI services that fetch a limited number of commits.
+type ShallowError struct {
+	// Depth is the fetch depth needed, or 0 if the full history may be
+	// needed, e.g. to find the merge base of a base branch.
+	Depth int
+	// Need describes what the history is needed for.
+	Need string
+}
+
+func (e *ShallowError) Error() string {
+	if e.Depth > 0 {
+		return fmt.Sprintf("the clone is too shallow for %s: fetch a depth of at least %d (git fetch --depth=%d, or fetch-depth: %d with actions/checkout), or pass --fetch", e.Need, e.Depth, e.Depth, e.Depth)
+	}
+
+	return fmt.Sprintf("the clone is too shallow for %s: fetch the full history (git fetch --unshallow, or fetch-depth: 0 with actions/checkout), or pass --fetch", e.Need)
+}
+
+// MissingRevisionError is returned when a revision, such as the base branch,
+// is not in the clone, as in CI clones of a single branch.
+type MissingRevisionError struct {
+	Rev string
+}
+
+func (e *MissingRevisionError) Error() string {
+	return fmt.Sprintf("%s is not in the clone: fetch it (git fetch origin %s), or pass --fetch", e.Rev, e.remoteRev())
+}
+
+// remoteRev returns the revision as named in the origin remote.
+func (e *MissingRevisionError) remoteRev() string {
+	return strings.TrimPrefix(e.Rev, "origin/")
+}
+
+// Shallow reports whether the repository is a shallow clone.
+func (g *Git) Shallow() (bool, error) {
+	shallow, err := g.repo.Storer.Shallow()
+	if err != nil {
+		return false, err
+	}
+
+	return len(shallow) != 0, nil
+}
+
+// loadShallow reads the parents of the commits at the boundary of a shallow
+// clone. The parents are missing, and walks of the history stop at them
+// rather than failing to read them.
+func (g *Git) loadShallow() error {
+	shallow, err := g.repo.Storer.Shallow()
+	if err != nil {
+		return err
+	}
+
+	g.missing = nil
+	for _, hash := range shallow {
+		c, err := g.repo.CommitObject(hash)
+		if err != nil {
+			return err
+		}
+		g.missing = append(g.missing, c.ParentHashes...)
+	}
+
+	return nil
+}
+
+// log returns an iterator over the history of the commit, in pre-order,
+// stopping at the boundary of a shallow clone, and with the error of the
+// context of the Git once it is done.
+func (g *Git) log(c *object.Commit, seen map[plumbing.Hash]bool) object.CommitIter {
+	return &contextIter{ctx: g.ctx, CommitIter: object.NewCommitPreorderIter(c, seen, g.missing)}
+}
+
+// contextIter is a commit iterator that stops once its context is done.
+type contextIter struct {
+	object.CommitIter
+	ctx context.Context
+}
+
+// Next implements the object.CommitIter.Next function.
+func (it *contextIter) Next() (*object.Commit, error) {
+	if err := it.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return it.CommitIter.Next()
+}
+
+// ForEach implements the object.CommitIter.ForEach function.
+func (it *contextIter) ForEach(cb func(*object.Commit) error) error {
+	return it.CommitIter.ForEach(func(c *object.Commit) error {
+		if err := it.ctx.Err(); err != nil {
+			return err
+		}
+		return cb(c)
+	})
+}
+
+// isMissing reports whether the commit is beyond the boundary of a shallow
+// clone.
+func (g *Git) isMissing(hash plumbing.Hash) bool {
+	for _, h := range g.missing {
+		if h == hash {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parent returns the first parent of the commit, or a ShallowError if the
+// parent is beyond the boundary of a shallow clone. The depth is that needed
+// to read the parent.
+func (g *Git) parent(c *object.Commit, depth int, need string) (*object.Commit, error) {
+	if err := g.ctx.Err(); err != nil {
+		return nil, err
+	}
+	if g.isMissing(c.ParentHashes[0]) {
+		return nil, &ShallowError{Depth: depth, Need: need}
+	}
+
+	return c.Parent(0)
+}
+
+// parents returns the parents of the commit not yet seen, and marks them
+// seen. The parents beyond the boundary of a shallow clone are skipped.
+func (g *Git) parents(c *object.Commit, seen map[plumbing.Hash]bool) ([]*object.Commit, error) {
+	if err := g.ctx.Err(); err != nil {
+		return nil, err
+	}
+	var parents []*object.Commit
+	for _, hash := range c.ParentHashes {
+		if seen[hash] || g.isMissing(hash) {
+			continue
+		}
+		seen[hash] = true
+		parent, err := g.repo.CommitObject(hash)
+		if err != nil {
+			return nil, err
+		}
+		parents = append(parents, parent)
+	}
+
+	return parents, nil
+}
+
+// Fetch fetches the history missing from a shallow clone from the origin
+// remote, deepening it by the depth, or the full history if the depth is 0.
+// The clone is deepened from its boundary rather than from the branches of
+// the remote, since HEAD need not be one of them, e.g. in pull requests.
+func (g *Git) Fetch(depth int) error {
+	args := []string{"fetch", "--quiet", "--unshallow", "origin"}
+	if depth > 0 {
+		args = []string{"fetch", "--quiet", "--deepen=" + strconv.Itoa(depth), "origin"}
+	}
+
+	return g.fetch(args...)
+}
+
+// FetchRevision fetches the revision reported missing by the error from the
+// origin remote. Branches are fetched as branches of the remote, so that they
+// are found as base branches.
+func (g *Git) FetchRevision(e *MissingRevisionError) error {
+	rev := e.remoteRev()
+	if !isHash(rev) {
+		rev = "+refs/heads/" + rev + ":refs/remotes/origin/" + rev
+	}
+
+	return g.fetch("fetch", "--quiet", "origin", rev)
+}
+
+// fetch runs git fetch in the working tree, and reopens the repository so
+// that the objects fetched are read.
+func (g *Git) fetch(args ...string) error {
+	root, err := g.Root()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = root
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err = cmd.Run(); err != nil {
+		return errors.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	if g.repo, err = git.PlainOpen(root); err != nil {
+		return err
+	}
+
+	return g.loadShallow()
+}
+
+// History checks that the history since the merge base of HEAD and the base
+// revision is in the clone, returning a MissingRevisionError or ShallowError
+// if it is not. If base is empty, the parent of HEAD is checked.
+func (g *Git) History(base string) error {
+	head, err := g.head()
+	if err != nil {
+		return err
+	}
+	if base == "" {
+		if head.NumParents() > 0 {
+			_, err = g.parent(head, 2, "the changes of HEAD")
+		}
+		return err
+	}
+	if _, err = g.revision(base); err != nil {
+		return err
+	}
+	if len(g.missing) == 0 {
+		return nil
+	}
+	_, err = g.mergeBase(base, head)
+
+	return err
+}
+
+func isHash(rev string) bool {
+	return len(rev) == 40 && strings.Trim(rev, "0123456789abcdef") == ""
+}
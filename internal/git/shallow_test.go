@@ -0,0 +1,99 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestShallow(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+
+	upstream := filepath.Join(dir, "upstream")
+	clone := filepath.Join(dir, "clone")
+	identity := []string{"-c", "user.name=test", "-c", "user.email=test@example.com"}
+	for _, args := range [][]string{
+		{"init", "--quiet", upstream},
+		{"-C", upstream, "commit", "--quiet", "--allow-empty", "-m", "first"},
+		{"-C", upstream, "branch", "base"},
+		{"-C", upstream, "commit", "--quiet", "--allow-empty", "-m", "second"},
+		{"-C", upstream, "commit", "--quiet", "--allow-empty", "-m", "third"},
+		{"clone", "--quiet", "--depth", "1", "--single-branch", "file://" + filepath.ToSlash(upstream), clone},
+	} {
+		cmd := exec.Command("git", append(identity, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(clone); err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.Chdir(wd)
+
+	g, err := NewGit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shallow, err := g.Shallow(); err != nil || !shallow {
+		t.Fatalf("Expected a shallow clone, got %v, %v", shallow, err)
+	}
+
+	if messages, err := g.Messages(3); err != nil || len(messages) != 1 {
+		t.Errorf("Expected the history to stop at the boundary of the clone, got %v, %v", messages, err)
+	}
+	if _, err = g.Diff(""); shallowDepth(err) != 2 {
+		t.Errorf("Expected a depth of 2 to be needed for the changes of HEAD, got %v", err)
+	}
+	if _, err = g.Ancestor(2); shallowDepth(err) != 3 {
+		t.Errorf("Expected a depth of 3 to be needed for 2 commits, got %v", err)
+	}
+	err = g.History("base")
+	missing, ok := err.(*MissingRevisionError)
+	if !ok {
+		t.Fatalf("Expected the base branch to be missing, got %v", err)
+	}
+
+	if err = g.FetchRevision(missing); err != nil {
+		t.Fatal(err)
+	}
+	if err = g.History("origin/base"); shallowDepth(err) != 0 {
+		t.Errorf("Expected the full history to be needed for the merge base, got %v", err)
+	}
+
+	if err = g.Fetch(2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = g.Ancestor(2); err != nil {
+		t.Errorf("Expected the deepened clone to have 2 ancestors, got %v", err)
+	}
+	if err = g.History("origin/base"); err != nil {
+		t.Errorf("Expected the merge base to be in the deepened clone, got %v", err)
+	}
+}
+
+// shallowDepth returns the depth needed by a ShallowError, or -1 if the error
+// is not one.
+func shallowDepth(err error) int {
+	if e, ok := err.(*ShallowError); ok {
+		return e.Depth
+	}
+
+	return -1
+}
@@ -0,0 +1,99 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package git
+
+import (
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/format/index"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/plumbing/storer"
+	"gopkg.in/src-d/go-git.v4/storage/memory"
+)
+
+// Submodule describes a submodule declared in .gitmodules.
+type Submodule struct {
+	// Name is the name of the submodule.
+	Name string
+	// Path is the path of the submodule relative to the root of the
+	// repository.
+	Path string
+	// URL is the URL the submodule is cloned from.
+	URL string
+	// Hash is the commit the submodule is pinned to in the index. It is empty
+	// if the submodule is declared but not pinned.
+	Hash string
+}
+
+// Submodules returns the submodules declared in .gitmodules.
+func (g *Git) Submodules() (submodules []Submodule, err error) {
+	w, err := g.repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	l, err := w.Submodules()
+	if err != nil {
+		return nil, err
+	}
+	idx, err := g.repo.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range l {
+		c := s.Config()
+		submodule := Submodule{
+			Name: c.Name,
+			Path: c.Path,
+			URL:  c.URL,
+		}
+		var e *index.Entry
+		e, err = idx.Entry(c.Path)
+		if err != nil && err != index.ErrEntryNotFound {
+			return nil, err
+		}
+		if e != nil {
+			submodule.Hash = e.Hash.String()
+		}
+		submodules = append(submodules, submodule)
+	}
+
+	return submodules, nil
+}
+
+// ReachableFromRemoteHead reports whether the commit is reachable from the
+// default branch of the remote repository. The remote is cloned into memory.
+func ReachableFromRemoteHead(url, hash string) (ok bool, err error) {
+	repo, err := git.Clone(memory.NewStorage(), nil, &git.CloneOptions{
+		URL:          url,
+		SingleBranch: true,
+		Tags:         git.NoTags,
+	})
+	if err != nil {
+		return false, err
+	}
+	ref, err := repo.Head()
+	if err != nil {
+		return false, err
+	}
+	head, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return false, err
+	}
+
+	target := plumbing.NewHash(hash)
+	err = object.NewCommitPreorderIter(head, nil, nil).ForEach(func(c *object.Commit) error {
+		if c.Hash == target {
+			ok = true
+			return storer.ErrStop
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return ok, nil
+}
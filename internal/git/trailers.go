@@ -0,0 +1,50 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package git
+
+import (
+	"regexp"
+	"strings"
+)
+
+var trailerRegex = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*):\s*(.*)$`)
+
+// Trailers returns the trailers of a commit message (e.g. "Signed-off-by:
+// ..."), keyed by lower case token. Like git interpret-trailers, trailers are
+// read from the last paragraph of the message, which must only contain
+// trailers. Lines starting with "#" are ignored.
+func Trailers(message string) map[string][]string {
+	var lines []string
+	for _, line := range strings.Split(strings.Replace(message, "\r\n", "\n", -1), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+
+	// Find the last paragraph, excluding the subject.
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	start := end
+	for start > 1 && strings.TrimSpace(lines[start-1]) != "" {
+		start--
+	}
+	if start == 0 || start >= end || (start == 1 && strings.TrimSpace(lines[0]) != "") {
+		return map[string][]string{}
+	}
+
+	trailers := map[string][]string{}
+	for _, line := range lines[start:end] {
+		m := trailerRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			return map[string][]string{}
+		}
+		token := strings.ToLower(m[1])
+		trailers[token] = append(trailers[token], m[2])
+	}
+
+	return trailers
+}
@@ -0,0 +1,31 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTrailers(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Message  string
+		Expected map[string][]string
+	}{
+		{"Trailers", "feat: add a thing\n\nBody.\n\nSigned-off-by: A <a@example.com>\nFrozen-Override: api change\n", map[string][]string{"signed-off-by": {"A <a@example.com>"}, "frozen-override": {"api change"}}},
+		{"Subject only", "Signed-off-by: A <a@example.com>\n", map[string][]string{}},
+		{"Not trailers", "feat: add a thing\n\nSee: the docs\nfor details.\n", map[string][]string{}},
+		{"Comments", "feat: add a thing\n\nSkip-Changelog: true\n# Please enter the commit message.\n", map[string][]string{"skip-changelog": {"true"}}},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			if trailers := Trailers(test.Message); !reflect.DeepEqual(trailers, test.Expected) {
+				tt.Errorf("Expected %v, got %v", test.Expected, trailers)
+			}
+		})
+	}
+}
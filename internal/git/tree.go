@@ -0,0 +1,152 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package git
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// Tree is the tree of files of a commit. File based policies read the files of
+// a tree, rather than those of the working tree, to enforce a commit
+// regardless of uncommitted changes.
+type Tree struct {
+	// SHA is the SHA of the commit.
+	SHA string
+
+	root  string
+	files map[string]*object.File
+	names []string
+}
+
+// Tree returns the tree of files of the commit of the revision.
+func (g *Git) Tree(rev string) (*Tree, error) {
+	root, err := g.Root()
+	if err != nil {
+		return nil, err
+	}
+	c, err := g.revision(rev)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Tree{SHA: c.Hash.String(), root: root, files: map[string]*object.File{}}
+	err = tree.Files().ForEach(func(f *object.File) error {
+		t.files[f.Name] = f
+		t.names = append(t.names, f.Name)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(t.names)
+
+	return t, nil
+}
+
+// Files returns the slash separated paths of the files of the tree, relative
+// to the root of the repository, sorted.
+func (t *Tree) Files() []string {
+	return append([]string(nil), t.names...)
+}
+
+// FileModes returns the modes of the files of the tree, keyed by path.
+func (t *Tree) FileModes() (map[string]os.FileMode, error) {
+	modes := make(map[string]os.FileMode, len(t.files))
+	for name, f := range t.files {
+		mode, err := f.Mode.ToOSFileMode()
+		if err != nil {
+			return nil, err
+		}
+		modes[name] = mode
+	}
+
+	return modes, nil
+}
+
+// Rel returns the slash separated path of the file, relative to the working
+// directory, relative to the root of the repository. It is empty if the file
+// is outside of the repository.
+func (t *Tree) Rel(name string) string {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(t.root, abs)
+	if err != nil {
+		return ""
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return ""
+	}
+
+	return rel
+}
+
+// Open opens the file of the tree, of the path relative to the working
+// directory. Errors for files missing from the tree satisfy os.IsNotExist.
+func (t *Tree) Open(name string) (io.ReadCloser, error) {
+	f, ok := t.files[t.Rel(name)]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+
+	return f.Reader()
+}
+
+// ReadFile reads the file of the tree, of the path relative to the working
+// directory.
+func (t *Tree) ReadFile(name string) ([]byte, error) {
+	r, err := t.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	// nolint: errcheck
+	defer r.Close()
+
+	return ioutil.ReadAll(r)
+}
+
+// Stat returns the description of the file of the tree, of the path relative
+// to the working directory.
+func (t *Tree) Stat(name string) (os.FileInfo, error) {
+	f, ok := t.files[t.Rel(name)]
+	if !ok {
+		return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+	}
+	mode, err := f.Mode.ToOSFileMode()
+	if err != nil {
+		return nil, err
+	}
+
+	return fileInfo{name: path.Base(f.Name), size: f.Size, mode: mode}, nil
+}
+
+// fileInfo describes a file of a tree.
+type fileInfo struct {
+	name string
+	size int64
+	mode os.FileMode
+}
+
+func (i fileInfo) Name() string       { return i.name }
+func (i fileInfo) Size() int64        { return i.size }
+func (i fileInfo) Mode() os.FileMode  { return i.mode }
+func (i fileInfo) ModTime() time.Time { return time.Time{} }
+func (i fileInfo) IsDir() bool        { return false }
+func (i fileInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,95 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTree(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+	// The temporary directory may be a symlink, e.g. on macOS.
+	if dir, err = filepath.EvalSymlinks(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	identity := []string{"-c", "user.name=test", "-c", "user.email=test@example.com"}
+	run := func(args ...string) string {
+		cmd := exec.Command("git", append(identity, append([]string{"-C", dir}, args...)...)...)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	run("init", "--quiet")
+	if err = os.MkdirAll(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, contents := range map[string]string{"a.txt": "committed\n", "sub/b.sh": "#!/bin/sh\n"} {
+		if err = ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("add", ".")
+	run("update-index", "--chmod=+x", "sub/b.sh")
+	run("commit", "--quiet", "-m", "first")
+	sha := run("rev-parse", "HEAD")
+	// The working tree differs from the commit.
+	if err = ioutil.WriteFile(filepath.Join(dir, "a.txt"), []byte("dirty\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(dir, "c.txt"), []byte("untracked\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(filepath.Join(dir, "sub")); err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.Chdir(wd)
+
+	g, err := NewGit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, err := g.Tree("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.SHA != sha {
+		t.Errorf("Expected the SHA of HEAD %s, got %s", sha, tree.SHA)
+	}
+	if files := tree.Files(); !reflect.DeepEqual(files, []string{"a.txt", "sub/b.sh"}) {
+		t.Errorf("Expected the files of the commit, got %v", files)
+	}
+	if contents, err := tree.ReadFile("../a.txt"); err != nil || string(contents) != "committed\n" {
+		t.Errorf("Expected the contents of the commit, got %q, %v", contents, err)
+	}
+	if _, err := tree.ReadFile("../c.txt"); !os.IsNotExist(err) {
+		t.Errorf("Expected untracked files to be missing, got %v", err)
+	}
+	if info, err := tree.Stat("b.sh"); err != nil || info.Name() != "b.sh" || info.Mode()&0111 == 0 {
+		t.Errorf("Expected an executable file b.sh, got %v, %v", info, err)
+	}
+	if rel := tree.Rel("../.."); rel != "" {
+		t.Errorf("Expected files outside of the repository to have no path, got %q", rel)
+	}
+}
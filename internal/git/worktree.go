@@ -0,0 +1,169 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	billy "gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/osfs"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/storage/filesystem"
+)
+
+// GitDir returns the git directory of the working tree at the root. It is the
+// .git directory, or the directory a .git file points to, as in linked
+// worktrees and submodules.
+func GitDir(root string) (string, error) {
+	dotGit := filepath.Join(root, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return dotGit, nil
+	}
+
+	contents, err := ioutil.ReadFile(dotGit)
+	if err != nil {
+		return "", err
+	}
+	line := strings.TrimSpace(string(contents))
+	if !strings.HasPrefix(line, "gitdir: ") {
+		return "", errors.Errorf("%s is not a gitdir file", dotGit)
+	}
+	dir := strings.TrimPrefix(line, "gitdir: ")
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(root, dir)
+	}
+
+	return filepath.Clean(dir), nil
+}
+
+// CommonDir returns the directory of the objects, refs, configuration, and
+// hooks shared by the linked worktrees of a repository, which the commondir
+// file of their git directory points to. It is the git directory itself
+// outside of linked worktrees.
+func CommonDir(gitDir string) (string, error) {
+	contents, err := ioutil.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return gitDir, nil
+		}
+		return "", err
+	}
+	dir := strings.TrimSpace(string(contents))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(gitDir, dir)
+	}
+
+	return filepath.Clean(dir), nil
+}
+
+// HooksDir returns the directory of the hooks of the repository, which are
+// shared by its linked worktrees.
+func (g *Git) HooksDir() (string, error) {
+	root, err := g.Root()
+	if err != nil {
+		return "", err
+	}
+	gitDir, err := GitDir(root)
+	if err != nil {
+		return "", err
+	}
+	commonDir, err := CommonDir(gitDir)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(commonDir, "hooks"), nil
+}
+
+// openRepository opens the repository of the working tree at the root. go-git
+// reads the git directory of linked worktrees as if it were that of the
+// repository, in which the refs and objects of the common directory are
+// missing, so the files of both are combined.
+func openRepository(root string) (*git.Repository, error) {
+	gitDir, err := GitDir(root)
+	if err != nil {
+		return nil, err
+	}
+	commonDir, err := CommonDir(gitDir)
+	if err != nil {
+		return nil, err
+	}
+	if commonDir == gitDir {
+		return git.PlainOpen(root)
+	}
+
+	s, err := filesystem.NewStorage(&worktreeFS{
+		Filesystem: osfs.New(commonDir),
+		worktree:   osfs.New(gitDir),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return git.Open(s, osfs.New(root))
+}
+
+// worktreeFS is the git directory of a linked worktree. The files of the
+// worktree, such as HEAD and the index, are read from its own git directory,
+// and the others from the common directory.
+type worktreeFS struct {
+	billy.Filesystem
+	worktree billy.Filesystem
+}
+
+// isWorktreePath reports whether the path of the git directory is specific to
+// the worktree.
+func isWorktreePath(name string) bool {
+	name = filepath.ToSlash(filepath.Clean(name))
+	switch {
+	case name == "index" || name == "logs/HEAD":
+		return true
+	case strings.HasPrefix(name, "refs/bisect/") || strings.HasPrefix(name, "refs/worktree/"):
+		return true
+	default:
+		// HEAD, and the pseudo refs such as ORIG_HEAD and MERGE_HEAD.
+		return !strings.Contains(name, "/") && strings.HasSuffix(name, "HEAD")
+	}
+}
+
+func (fs *worktreeFS) fs(name string) billy.Filesystem {
+	if isWorktreePath(name) {
+		return fs.worktree
+	}
+
+	return fs.Filesystem
+}
+
+func (fs *worktreeFS) Create(filename string) (billy.File, error) {
+	return fs.fs(filename).Create(filename)
+}
+
+func (fs *worktreeFS) Open(filename string) (billy.File, error) {
+	return fs.fs(filename).Open(filename)
+}
+
+func (fs *worktreeFS) OpenFile(filename string, flag int, perm os.FileMode) (billy.File, error) {
+	return fs.fs(filename).OpenFile(filename, flag, perm)
+}
+
+func (fs *worktreeFS) Stat(filename string) (os.FileInfo, error) {
+	return fs.fs(filename).Stat(filename)
+}
+
+func (fs *worktreeFS) Lstat(filename string) (os.FileInfo, error) {
+	return fs.fs(filename).Lstat(filename)
+}
+
+func (fs *worktreeFS) Remove(filename string) error {
+	return fs.fs(filename).Remove(filename)
+}
@@ -0,0 +1,111 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWorktree(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+	// The temporary directory may be a symlink, e.g. on macOS.
+	if dir, err = filepath.EvalSymlinks(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := filepath.Join(dir, "repo")
+	worktree := filepath.Join(dir, "worktree")
+	identity := []string{"-c", "user.name=test", "-c", "user.email=test@example.com"}
+	run := func(args ...string) string {
+		cmd := exec.Command("git", append(identity, args...)...)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	run("-c", "init.defaultBranch=master", "init", "--quiet", repo)
+	run("-C", repo, "commit", "--quiet", "--allow-empty", "-m", "first")
+	run("-C", repo, "worktree", "add", "--quiet", "-b", "feature", worktree)
+	if err = ioutil.WriteFile(filepath.Join(worktree, "a.txt"), []byte("a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("-C", worktree, "add", "a.txt")
+	run("-C", worktree, "commit", "--quiet", "-m", "second")
+	sha := run("-C", worktree, "rev-parse", "HEAD")
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(worktree); err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.Chdir(wd)
+
+	g, err := NewGit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root, err := g.Root(); err != nil || root != worktree {
+		t.Errorf("Expected the root to be %s, got %q, %v", worktree, root, err)
+	}
+	if head, err := g.SHA(); err != nil || head != sha {
+		t.Errorf("Expected HEAD to be %s, got %q, %v", sha, head, err)
+	}
+	if branch, err := g.Branch(); err != nil || branch != "feature" {
+		t.Errorf("Expected the branch to be feature, got %q, %v", branch, err)
+	}
+	if message, err := g.Message(); err != nil || message != "second\n" {
+		t.Errorf("Expected the message of HEAD, got %q, %v", message, err)
+	}
+	if files, err := g.TrackedFiles(); err != nil || !reflect.DeepEqual(files, []string{"a.txt"}) {
+		t.Errorf("Expected the files of the index of the worktree, got %v, %v", files, err)
+	}
+	if diffs, err := g.Diff("master"); err != nil || len(diffs) != 1 || diffs[0].Path() != "a.txt" {
+		t.Errorf("Expected the changes since master, got %v, %v", diffs, err)
+	}
+	if hooks, err := g.HooksDir(); err != nil || hooks != filepath.Join(repo, ".git", "hooks") {
+		t.Errorf("Expected the hooks of the repository, got %q, %v", hooks, err)
+	}
+}
+
+func TestIsWorktreePath(t *testing.T) {
+	for _, test := range []struct {
+		Path     string
+		Expected bool
+	}{
+		{"HEAD", true},
+		{"ORIG_HEAD", true},
+		{"index", true},
+		{"logs/HEAD", true},
+		{"refs/bisect/bad", true},
+		{"refs/heads/HEAD", false},
+		{"refs/heads/master", false},
+		{"packed-refs", false},
+		{"objects/pack", false},
+		{"config", false},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Path, func(tt *testing.T) {
+			if ok := isWorktreePath(test.Path); ok != test.Expected {
+				tt.Errorf("Expected %v, got %v", test.Expected, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package initializer
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/autonomy/conform/internal/enforcer"
+	yaml "gopkg.in/yaml.v2"
+)
+
+type declaration struct {
+	Type string        `yaml:"type"`
+	Spec yaml.MapSlice `yaml:"spec"`
+}
+
+// Config returns a starter configuration that enforces the conventions
+// detected by the inspection.
+func (i *Inspection) Config() ([]byte, error) {
+	var policies []declaration
+
+	policies = append(policies, declaration{Type: "commit", Spec: i.commitSpec()})
+
+	var suffixes []string
+	for _, l := range i.Languages {
+		suffixes = append(suffixes, l.Suffixes...)
+	}
+
+	if i.Header != "" {
+		policies = append(policies, declaration{
+			Type: "license",
+			Spec: yaml.MapSlice{
+				{Key: "includeSuffixes", Value: i.Languages[0].Suffixes},
+				{Key: "header", Value: i.Header},
+			},
+		})
+	}
+
+	if len(suffixes) != 0 {
+		for _, t := range []string{"newline", "whitespace"} {
+			policies = append(policies, declaration{
+				Type: t,
+				Spec: yaml.MapSlice{{Key: "includeSuffixes", Value: suffixes}},
+			})
+		}
+	}
+
+	b, err := yaml.Marshal(struct {
+		APIVersion string        `yaml:"apiVersion"`
+		Kind       string        `yaml:"kind"`
+		Policies   []declaration `yaml:"policies"`
+	}{APIVersion: enforcer.APIVersion, Kind: enforcer.Kind, Policies: policies})
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	for _, line := range i.summary() {
+		fmt.Fprintf(buf, "# %s\n", line)
+	}
+	buf.Write(b)
+
+	return buf.Bytes(), nil
+}
+
+func (i *Inspection) commitSpec() yaml.MapSlice {
+	length := DefaultHeaderLength
+	if i.Style.HeaderLength > length {
+		length = i.Style.HeaderLength
+	}
+
+	spec := yaml.MapSlice{
+		{Key: "headerLength", Value: length},
+		{Key: "dco", Value: i.Style.DCO},
+		{Key: "gpg", Value: false},
+	}
+	if i.Style.Conventional {
+		conventional := yaml.MapSlice{}
+		if len(i.Style.Types) != 0 {
+			conventional = append(conventional, yaml.MapItem{Key: "types", Value: i.Style.Types})
+		}
+		if len(i.Style.Scopes) != 0 {
+			conventional = append(conventional, yaml.MapItem{Key: "scopes", Value: i.Style.Scopes})
+		}
+		spec = append(spec, yaml.MapItem{Key: "conventional", Value: conventional})
+	}
+
+	return spec
+}
+
+// summary describes what was detected, for the comment at the top of the
+// generated configuration.
+func (i *Inspection) summary() []string {
+	lines := []string{"Generated by conform init."}
+
+	if len(i.Languages) != 0 {
+		var names []string
+		for _, l := range i.Languages {
+			names = append(names, l.Name)
+		}
+		lines = append(lines, "Languages: "+strings.Join(names, ", "))
+	}
+
+	if len(i.Licenses) != 0 {
+		lines = append(lines, "Licenses: "+strings.Join(i.Licenses, ", "))
+	}
+
+	if i.Style.Commits != 0 {
+		var conventions []string
+		if i.Style.Conventional {
+			conventions = append(conventions, "conventional commits")
+		}
+		if i.Style.DCO {
+			conventions = append(conventions, "signed off")
+		}
+		if len(conventions) == 0 {
+			conventions = append(conventions, "no conventions")
+		}
+		lines = append(lines, fmt.Sprintf("Commits: %s (last %d)", strings.Join(conventions, ", "), i.Style.Commits))
+	}
+
+	return lines
+}
@@ -0,0 +1,64 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package initializer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/pkg/errors"
+)
+
+// Hooks are the git hooks installed by InstallHooks, keyed by name.
+var Hooks = map[string]string{
+	"commit-msg": `#!/bin/sh
+# Installed by conform init.
+# The commit does not exist yet, so that its author and branch are passed.
+ident=$(git var GIT_AUTHOR_IDENT)
+email=${ident#*<}
+exec conform enforce --commit-msg-file "$1" \
+	--author-name "${ident%% <*}" \
+	--author-email "${email%%>*}" \
+	--ref "$(git symbolic-ref -q HEAD)"
+`,
+}
+
+// InstallHooks installs the git hooks that enforce the policies into the
+// repository in the current directory. Existing hooks are only replaced if
+// force is true.
+func InstallHooks(force bool) ([]string, error) {
+	g, err := git.NewGit()
+	if err != nil {
+		return nil, errors.Errorf("failed to open git repo: %v", err)
+	}
+	// The hooks of linked worktrees are those of the repository.
+	dir, err := g.HooksDir()
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	var installed []string
+	for name, contents := range Hooks {
+		p := filepath.Join(dir, name)
+		if _, err = os.Stat(p); err == nil && !force {
+			return installed, errors.Errorf("Hook %s already exists, use --force to replace it", p)
+		}
+		if err = ioutil.WriteFile(p, []byte(contents), 0755); err != nil {
+			return installed, err
+		}
+		// WriteFile does not change the mode of an existing file.
+		if err = os.Chmod(p, 0755); err != nil {
+			return installed, err
+		}
+		installed = append(installed, p)
+	}
+
+	return installed, nil
+}
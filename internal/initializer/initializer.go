@@ -0,0 +1,242 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package initializer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy/commit"
+	"github.com/autonomy/conform/internal/policy/dependency"
+	"github.com/pkg/errors"
+)
+
+// HistoryDepth is the number of commits inspected to detect the commit style.
+const HistoryDepth = 50
+
+// DefaultHeaderLength is the maximum length of the commit subject used when
+// the history does not call for a longer one.
+const DefaultHeaderLength = 72
+
+// threshold is the fraction of commits that must follow a convention for it to
+// be enforced.
+const threshold = 0.8
+
+// Languages maps the file suffixes of common languages to their names.
+var Languages = map[string]string{
+	".c":     "C",
+	".cc":    "C++",
+	".cpp":   "C++",
+	".cs":    "C#",
+	".go":    "Go",
+	".h":     "C",
+	".java":  "Java",
+	".js":    "JavaScript",
+	".kt":    "Kotlin",
+	".php":   "PHP",
+	".py":    "Python",
+	".rb":    "Ruby",
+	".rs":    "Rust",
+	".scala": "Scala",
+	".sh":    "Shell",
+	".swift": "Swift",
+	".ts":    "TypeScript",
+}
+
+// Language is a language used in the repository.
+type Language struct {
+	Name     string
+	Suffixes []string
+	Files    []string
+}
+
+// CommitStyle describes the conventions followed by the commit history.
+type CommitStyle struct {
+	// Commits is the number of commits inspected.
+	Commits int
+	// HeaderLength is the length of the longest commit subject.
+	HeaderLength int
+	// Conventional is true if the commits are conventional commits.
+	Conventional bool
+	// Types are the conventional commit types used, excluding feat and fix.
+	Types []string
+	// Scopes are the conventional commit scopes used.
+	Scopes []string
+	// DCO is true if the commits are signed off.
+	DCO bool
+}
+
+// Inspection is the result of inspecting a repository.
+type Inspection struct {
+	// Languages are the languages used, ordered from most to least files.
+	Languages []*Language
+	// Licenses are the licenses of the repository.
+	Licenses []string
+	// Header is the header shared by all files of the primary language.
+	Header string
+	// Style is the commit style of the history.
+	Style CommitStyle
+}
+
+// Inspect inspects the repository in the current directory.
+func Inspect() (*Inspection, error) {
+	g, err := git.NewGit()
+	if err != nil {
+		return nil, errors.Errorf("failed to open git repo: %v", err)
+	}
+
+	i := &Inspection{}
+
+	files, err := g.TrackedFiles()
+	if err != nil {
+		return nil, err
+	}
+	i.Languages = languages(files)
+
+	if i.Licenses, err = dependency.DetectLicenses("."); err != nil {
+		return nil, err
+	}
+
+	if len(i.Languages) != 0 {
+		var contents [][]byte
+		for _, name := range i.Languages[0].Files {
+			b, err := ioutil.ReadFile(filepath.FromSlash(name))
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return nil, err
+			}
+			contents = append(contents, b)
+		}
+		i.Header = commonHeader(contents)
+	}
+
+	// A repository without commits has no style to detect.
+	if messages, err := g.Messages(HistoryDepth); err == nil {
+		i.Style = commitStyle(messages)
+	}
+
+	return i, nil
+}
+
+func languages(files []string) []*Language {
+	byName := map[string]*Language{}
+	for _, name := range files {
+		suffix := path.Ext(name)
+		lang, ok := Languages[suffix]
+		if !ok {
+			continue
+		}
+		l, ok := byName[lang]
+		if !ok {
+			l = &Language{Name: lang}
+			byName[lang] = l
+		}
+		if !contains(l.Suffixes, suffix) {
+			l.Suffixes = append(l.Suffixes, suffix)
+		}
+		l.Files = append(l.Files, name)
+	}
+
+	langs := make([]*Language, 0, len(byName))
+	for _, l := range byName {
+		sort.Strings(l.Suffixes)
+		langs = append(langs, l)
+	}
+	sort.Slice(langs, func(i, j int) bool {
+		if len(langs[i].Files) != len(langs[j].Files) {
+			return len(langs[i].Files) > len(langs[j].Files)
+		}
+		return langs[i].Name < langs[j].Name
+	})
+
+	return langs
+}
+
+// commonHeader returns the leading lines shared by all of the contents, up to
+// the first blank line. At least two files are required for a header to be
+// considered common.
+func commonHeader(contents [][]byte) string {
+	if len(contents) < 2 {
+		return ""
+	}
+
+	header := strings.SplitAfter(string(bytes.Replace(contents[0], []byte("\r\n"), []byte("\n"), -1)), "\n")
+	for _, b := range contents[1:] {
+		lines := strings.SplitAfter(string(bytes.Replace(b, []byte("\r\n"), []byte("\n"), -1)), "\n")
+		n := 0
+		for n < len(header) && n < len(lines) && header[n] == lines[n] {
+			n++
+		}
+		header = header[:n]
+	}
+
+	for n, line := range header {
+		if strings.TrimSpace(line) == "" || !strings.HasSuffix(line, "\n") {
+			header = header[:n]
+			break
+		}
+	}
+
+	return strings.Join(header, "")
+}
+
+func commitStyle(messages []string) CommitStyle {
+	style := CommitStyle{Commits: len(messages)}
+	if len(messages) == 0 {
+		return style
+	}
+
+	var conventional, signed int
+	var types, scopes []string
+	for _, msg := range messages {
+		header := strings.SplitN(strings.TrimSpace(msg), "\n", 2)[0]
+		if len(header) > style.HeaderLength {
+			style.HeaderLength = len(header)
+		}
+		if groups := commit.HeaderRegex.FindStringSubmatch(header); groups != nil && groups[1] != "" {
+			conventional++
+			if groups[1] != commit.TypeFeat && groups[1] != commit.TypeFix && !contains(types, groups[1]) {
+				types = append(types, groups[1])
+			}
+			if groups[3] != "" && !contains(scopes, groups[3]) {
+				scopes = append(scopes, groups[3])
+			}
+		}
+		for _, line := range strings.Split(msg, "\n") {
+			if commit.DCORegex.MatchString(strings.TrimSpace(line)) {
+				signed++
+				break
+			}
+		}
+	}
+
+	if float64(conventional) >= threshold*float64(len(messages)) {
+		style.Conventional = true
+		sort.Strings(types)
+		sort.Strings(scopes)
+		style.Types, style.Scopes = types, scopes
+	}
+	style.DCO = float64(signed) >= threshold*float64(len(messages))
+
+	return style
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
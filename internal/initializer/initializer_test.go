@@ -0,0 +1,140 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package initializer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCommitStyle(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []string
+		expected CommitStyle
+	}{
+		{
+			name:     "Empty",
+			messages: nil,
+			expected: CommitStyle{},
+		},
+		{
+			name: "Conventional",
+			messages: []string{
+				"feat(policy): add a policy\n",
+				"docs: update the readme\n",
+				"chore(ci): bump the image\n",
+				"fix(policy): fix a policy\n",
+				"refactor(policy): simplify\n",
+			},
+			expected: CommitStyle{
+				Commits:      5,
+				HeaderLength: 26,
+				Conventional: true,
+				Types:        []string{"chore", "docs", "refactor"},
+				Scopes:       []string{"ci", "policy"},
+			},
+		},
+		{
+			name: "Mixed",
+			messages: []string{
+				"feat: add a policy\n",
+				"Update the readme\n",
+			},
+			expected: CommitStyle{
+				Commits:      2,
+				HeaderLength: 18,
+			},
+		},
+		{
+			name: "DCO",
+			messages: []string{
+				"Add a policy\n\nSigned-off-by: Jane Doe <jane@example.com>\n",
+				"Update the readme\n\nSigned-off-by: Jane Doe <jane@example.com>\n",
+			},
+			expected: CommitStyle{
+				Commits:      2,
+				HeaderLength: 17,
+				DCO:          true,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(tt *testing.T) {
+			style := commitStyle(test.messages)
+			if !reflect.DeepEqual(style, test.expected) {
+				tt.Errorf("Expected %+v, got %+v", test.expected, style)
+			}
+		})
+	}
+}
+
+func TestCommonHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents []string
+		expected string
+	}{
+		{
+			name:     "Single",
+			contents: []string{"// Copyright\n\npackage a\n"},
+			expected: "",
+		},
+		{
+			name: "Shared",
+			contents: []string{
+				"// Copyright\n// License\n\npackage a\n",
+				"// Copyright\n// License\n\npackage b\n",
+			},
+			expected: "// Copyright\n// License\n",
+		},
+		{
+			name: "Partial",
+			contents: []string{
+				"// Copyright 2018\n// License\n\npackage a\n",
+				"// Copyright 2018\n// Other\n\npackage b\n",
+			},
+			expected: "// Copyright 2018\n",
+		},
+		{
+			name: "Unshared",
+			contents: []string{
+				"package a\n",
+				"package b\n",
+			},
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(tt *testing.T) {
+			contents := make([][]byte, 0, len(test.contents))
+			for _, c := range test.contents {
+				contents = append(contents, []byte(c))
+			}
+			if header := commonHeader(contents); header != test.expected {
+				tt.Errorf("Expected header %q, got %q", test.expected, header)
+			}
+		})
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	langs := languages([]string{"main.go", "cmd/root.go", "hack/build.sh", "README.md", "web/app.ts"})
+
+	var names []string
+	for _, l := range langs {
+		names = append(names, l.Name)
+	}
+	expected := []string{"Go", "Shell", "TypeScript"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected languages %v, got %v", expected, names)
+	}
+}
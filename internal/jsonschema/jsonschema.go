@@ -0,0 +1,452 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+// Package jsonschema implements validation of documents against a JSON Schema.
+// The supported subset of keywords is: type, enum, const, properties,
+// patternProperties, additionalProperties, required, items, minItems,
+// maxItems, uniqueItems, minLength, maxLength, pattern, minimum, maximum,
+// allOf, anyOf, oneOf, not, if, then, else, and local $ref references (e.g.
+// "#/definitions/foo").
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ValidationError describes a violation of a schema.
+type ValidationError struct {
+	// Pointer is the JSON pointer to the offending value (e.g. "/spec/0").
+	Pointer string
+	// Message describes the violation.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	pointer := e.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	return fmt.Sprintf("%s: %s", pointer, e.Message)
+}
+
+// Schema is a parsed JSON Schema.
+type Schema struct {
+	root    map[string]interface{}
+	regexes map[string]*regexp.Regexp
+	// refs are the references being validated, by pointer, so that a
+	// reference that leads back to itself without descending into the
+	// document is reported rather than followed forever.
+	refs map[string]bool
+}
+
+// loadTimeout is the timeout of fetching a schema from a URL.
+const loadTimeout = 30 * time.Second
+
+// Load reads a schema from a local path or an HTTP(S) URL.
+func Load(location string) (*Schema, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		client := &http.Client{Timeout: loadTimeout}
+		var resp *http.Response
+		if resp, err = client.Get(location); err != nil {
+			return nil, err
+		}
+		// nolint: errcheck
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("failed to fetch %s: %s", location, resp.Status)
+		}
+		if data, err = ioutil.ReadAll(resp.Body); err != nil {
+			return nil, err
+		}
+	} else if data, err = ioutil.ReadFile(location); err != nil {
+		return nil, err
+	}
+
+	return Parse(data)
+}
+
+// Parse parses a schema encoded as JSON or YAML.
+func Parse(data []byte) (*Schema, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	root, ok := Normalize(v).(map[string]interface{})
+	if !ok {
+		return nil, errors.New("schema must be an object")
+	}
+
+	return &Schema{root: root, regexes: map[string]*regexp.Regexp{}, refs: map[string]bool{}}, nil
+}
+
+// Normalize converts a document decoded by yaml.v2 into the types produced by
+// encoding/json, so that it can be validated.
+func Normalize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			m[fmt.Sprintf("%v", k)] = Normalize(val)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			m[k] = Normalize(val)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(t))
+		for i, val := range t {
+			l[i] = Normalize(val)
+		}
+		return l
+	case int:
+		return float64(t)
+	case int64:
+		return float64(t)
+	case uint64:
+		return float64(t)
+	case json.Number:
+		f, err := t.Float64()
+		if err != nil {
+			return t.String()
+		}
+		return f
+	default:
+		return v
+	}
+}
+
+// Validate validates a document against the schema. The document must
+// consist of the types produced by encoding/json, see Normalize.
+func (s *Schema) Validate(doc interface{}) []error {
+	return s.validate(s.root, doc, "")
+}
+
+// nolint: gocyclo
+func (s *Schema) validate(schema map[string]interface{}, v interface{}, pointer string) (errs []error) {
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, &ValidationError{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		key := pointer + " " + ref
+		if s.refs[key] {
+			fail("circular $ref %q", ref)
+			return errs
+		}
+		resolved, err := s.resolve(ref)
+		if err != nil {
+			fail("%v", err)
+			return errs
+		}
+		s.refs[key] = true
+		defer delete(s.refs, key)
+		return s.validate(resolved, v, pointer)
+	}
+
+	if t, ok := schema["type"]; ok && !matchesType(t, v) {
+		fail("expected %s, got %s", describeType(t), typeOf(v))
+		return errs
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			if reflect.DeepEqual(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("value %v is not one of %v", v, enum)
+		}
+	}
+
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, v) {
+		fail("value %v is not %v", v, c)
+	}
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		errs = append(errs, s.validateObject(schema, t, pointer)...)
+	case []interface{}:
+		errs = append(errs, s.validateArray(schema, t, pointer)...)
+	case string:
+		if n, ok := number(schema["minLength"]); ok && float64(len([]rune(t))) < n {
+			fail("length must be at least %v", n)
+		}
+		if n, ok := number(schema["maxLength"]); ok && float64(len([]rune(t))) > n {
+			fail("length must be at most %v", n)
+		}
+		if expr, ok := schema["pattern"].(string); ok {
+			re, err := s.regexp(expr)
+			if err != nil {
+				fail("invalid pattern %q: %v", expr, err)
+			} else if !re.MatchString(t) {
+				fail("value %q does not match pattern %q", t, expr)
+			}
+		}
+	case float64:
+		if n, ok := number(schema["minimum"]); ok && t < n {
+			fail("value %v must be at least %v", t, n)
+		}
+		if n, ok := number(schema["maximum"]); ok && t > n {
+			fail("value %v must be at most %v", t, n)
+		}
+	}
+
+	errs = append(errs, s.validateCombinators(schema, v, pointer)...)
+
+	return errs
+}
+
+func (s *Schema) validateObject(schema map[string]interface{}, obj map[string]interface{}, pointer string) (errs []error) {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, ok := obj[name]; !ok {
+				errs = append(errs, &ValidationError{Pointer: pointer, Message: fmt.Sprintf("missing required property %q", name)})
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	patterns, _ := schema["patternProperties"].(map[string]interface{})
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		child := pointer + "/" + escape(k)
+		matched := false
+		if sub, ok := properties[k].(map[string]interface{}); ok {
+			matched = true
+			errs = append(errs, s.validate(sub, obj[k], child)...)
+		}
+		for expr, sub := range patterns {
+			re, err := s.regexp(expr)
+			if err != nil || !re.MatchString(k) {
+				continue
+			}
+			matched = true
+			if m, ok := sub.(map[string]interface{}); ok {
+				errs = append(errs, s.validate(m, obj[k], child)...)
+			}
+		}
+		if matched {
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				errs = append(errs, &ValidationError{Pointer: child, Message: fmt.Sprintf("unknown property %q", k)})
+			}
+		case map[string]interface{}:
+			errs = append(errs, s.validate(additional, obj[k], child)...)
+		}
+	}
+
+	return errs
+}
+
+func (s *Schema) validateArray(schema map[string]interface{}, arr []interface{}, pointer string) (errs []error) {
+	if n, ok := number(schema["minItems"]); ok && float64(len(arr)) < n {
+		errs = append(errs, &ValidationError{Pointer: pointer, Message: fmt.Sprintf("must have at least %v items", n)})
+	}
+	if n, ok := number(schema["maxItems"]); ok && float64(len(arr)) > n {
+		errs = append(errs, &ValidationError{Pointer: pointer, Message: fmt.Sprintf("must have at most %v items", n)})
+	}
+	if unique, ok := schema["uniqueItems"].(bool); ok && unique {
+		for i := range arr {
+			for j := i + 1; j < len(arr); j++ {
+				if reflect.DeepEqual(arr[i], arr[j]) {
+					errs = append(errs, &ValidationError{Pointer: pointer, Message: fmt.Sprintf("items %d and %d are equal", i, j)})
+				}
+			}
+		}
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		for i, item := range arr {
+			errs = append(errs, s.validate(items, item, pointer+"/"+strconv.Itoa(i))...)
+		}
+	}
+
+	return errs
+}
+
+func (s *Schema) validateCombinators(schema map[string]interface{}, v interface{}, pointer string) (errs []error) {
+	if all, ok := schema["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			if m, ok := sub.(map[string]interface{}); ok {
+				errs = append(errs, s.validate(m, v, pointer)...)
+			}
+		}
+	}
+
+	count := func(subs []interface{}) int {
+		n := 0
+		for _, sub := range subs {
+			if m, ok := sub.(map[string]interface{}); ok && len(s.validate(m, v, pointer)) == 0 {
+				n++
+			}
+		}
+		return n
+	}
+
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok && count(anyOf) == 0 {
+		errs = append(errs, &ValidationError{Pointer: pointer, Message: "value does not match any of the allowed schemas"})
+	}
+	if oneOf, ok := schema["oneOf"].([]interface{}); ok {
+		if n := count(oneOf); n != 1 {
+			errs = append(errs, &ValidationError{Pointer: pointer, Message: fmt.Sprintf("value must match exactly one schema, matched %d", n)})
+		}
+	}
+	if not, ok := schema["not"].(map[string]interface{}); ok && len(s.validate(not, v, pointer)) == 0 {
+		errs = append(errs, &ValidationError{Pointer: pointer, Message: "value must not match the schema"})
+	}
+	if cond, ok := schema["if"].(map[string]interface{}); ok {
+		branch := "else"
+		if len(s.validate(cond, v, pointer)) == 0 {
+			branch = "then"
+		}
+		if m, ok := schema[branch].(map[string]interface{}); ok {
+			errs = append(errs, s.validate(m, v, pointer)...)
+		}
+	}
+
+	return errs
+}
+
+func (s *Schema) resolve(ref string) (map[string]interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, errors.Errorf("unsupported $ref %q: only local references are supported", ref)
+	}
+
+	var v interface{} = s.root
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("invalid $ref %q", ref)
+		}
+		if v, ok = m[token]; !ok {
+			return nil, errors.Errorf("invalid $ref %q", ref)
+		}
+	}
+
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("invalid $ref %q", ref)
+	}
+
+	return m, nil
+}
+
+func (s *Schema) regexp(expr string) (*regexp.Regexp, error) {
+	if re, ok := s.regexes[expr]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	s.regexes[expr] = re
+
+	return re, nil
+}
+
+func matchesType(t interface{}, v interface{}) bool {
+	switch types := t.(type) {
+	case string:
+		return isType(types, v)
+	case []interface{}:
+		for _, name := range types {
+			if s, ok := name.(string); ok && isType(s, v) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+func isType(name string, v interface{}) bool {
+	switch name {
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	default:
+		return typeOf(v) == name
+	}
+}
+
+func typeOf(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func describeType(t interface{}) string {
+	if l, ok := t.([]interface{}); ok {
+		names := make([]string, 0, len(l))
+		for _, name := range l {
+			names = append(names, fmt.Sprintf("%v", name))
+		}
+		return strings.Join(names, " or ")
+	}
+
+	return fmt.Sprintf("%v", t)
+}
+
+func number(v interface{}) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
+
+func escape(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
@@ -0,0 +1,142 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package jsonschema
+
+import (
+	"testing"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const testSchema = `
+type: object
+required: [name]
+additionalProperties: false
+properties:
+  name:
+    type: string
+    pattern: ^[a-z]+$
+  replicas:
+    type: integer
+    minimum: 1
+  labels:
+    $ref: "#/definitions/labels"
+if:
+  properties:
+    name:
+      const: bar
+then:
+  required: [replicas]
+definitions:
+  labels:
+    type: object
+    additionalProperties:
+      type: string
+`
+
+func TestValidate(t *testing.T) {
+	type testDesc struct {
+		Name        string
+		Document    string
+		ExpectValid bool
+	}
+
+	schema, err := Parse([]byte(testSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []testDesc{
+		{
+			Name:        "Valid",
+			Document:    "name: foo\nreplicas: 2\nlabels:\n  app: foo\n",
+			ExpectValid: true,
+		},
+		{
+			Name:        "Missing Required",
+			Document:    "replicas: 2\n",
+			ExpectValid: false,
+		},
+		{
+			Name:        "Unknown Property",
+			Document:    "name: foo\nextra: true\n",
+			ExpectValid: false,
+		},
+		{
+			Name:        "Invalid Pattern",
+			Document:    "name: Foo\n",
+			ExpectValid: false,
+		},
+		{
+			Name:        "Invalid Reference",
+			Document:    "name: foo\nlabels:\n  app: 1\n",
+			ExpectValid: false,
+		},
+		{
+			Name:        "Conditional",
+			Document:    "name: bar\n",
+			ExpectValid: false,
+		},
+		{
+			Name:        "Conditional Satisfied",
+			Document:    "name: bar\nreplicas: 1\n",
+			ExpectValid: true,
+		},
+		{
+			Name:        "Below Minimum",
+			Document:    "name: foo\nreplicas: 0\n",
+			ExpectValid: false,
+		},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			var doc interface{}
+			if err := yaml.Unmarshal([]byte(test.Document), &doc); err != nil {
+				tt.Fatal(err)
+			}
+			errs := schema.Validate(Normalize(doc))
+
+			if test.ExpectValid && len(errs) != 0 {
+				tt.Errorf("Document is invalid with valid input: %v", errs)
+			}
+			if !test.ExpectValid && len(errs) == 0 {
+				tt.Error("Document is valid with invalid input")
+			}
+		})
+	}
+}
+
+func TestValidateCircularReference(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		valid  bool
+	}{
+		{name: "Self", schema: "$ref: \"#\"\n"},
+		{name: "Definition", schema: "$ref: \"#/definitions/a\"\ndefinitions:\n  a:\n    $ref: \"#/definitions/b\"\n  b:\n    allOf:\n    - $ref: \"#/definitions/a\"\n"},
+		// A recursive schema is not circular, since each reference
+		// descends into the document.
+		{name: "Recursive", schema: "type: object\nproperties:\n  child:\n    $ref: \"#\"\n", valid: true},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(tt *testing.T) {
+			schema, err := Parse([]byte(test.schema))
+			if err != nil {
+				tt.Fatal(err)
+			}
+			var doc interface{}
+			if err = yaml.Unmarshal([]byte("child:\n  child: {}\n"), &doc); err != nil {
+				tt.Fatal(err)
+			}
+			if errs := schema.Validate(Normalize(doc)); (len(errs) == 0) != test.valid {
+				tt.Errorf("Expected the document to be valid: %v, got %v", test.valid, errs)
+			}
+		})
+	}
+}
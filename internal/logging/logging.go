@@ -0,0 +1,235 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+// Package logging implements leveled, structured logging on top of log/slog.
+// Records are diagnostics, written to standard error so that they are never
+// mixed with the results written to standard output, in the logfmt format
+// (e.g. level=debug msg="loaded configuration" file=.conform.yaml) or as JSON
+// objects.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Level is the severity of a log record.
+type Level int
+
+const (
+	// LevelDebug records explain the decisions made, such as the files walked
+	// and the patterns matched.
+	LevelDebug Level = iota
+	// LevelInfo records describe the progress of enforcement, such as the
+	// configuration loaded and the time taken by each policy.
+	LevelInfo
+	// LevelWarn records describe problems that do not stop enforcement.
+	LevelWarn
+	// LevelError records describe problems that stop enforcement, or that
+	// lose some of its results, such as a report that cannot be written.
+	LevelError
+)
+
+// Levels are the levels, in increasing order of severity.
+var Levels = []Level{LevelDebug, LevelInfo, LevelWarn, LevelError}
+
+// String returns the name of the level.
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	default:
+		return "error"
+	}
+}
+
+func (l Level) slog() slog.Level {
+	switch l {
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelInfo:
+		return slog.LevelInfo
+	case LevelWarn:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// ParseLevel returns the level of the specified name.
+func ParseLevel(name string) (Level, error) {
+	for _, l := range Levels {
+		if strings.EqualFold(name, l.String()) {
+			return l, nil
+		}
+	}
+
+	return LevelWarn, errors.Errorf("Unknown log level %q: must be one of debug, info, warn, or error", name)
+}
+
+// Format is the format of the records.
+type Format string
+
+const (
+	// FormatText writes records in the logfmt format.
+	FormatText Format = "text"
+	// FormatJSON writes records as JSON objects, one per line.
+	FormatJSON Format = "json"
+)
+
+// ParseFormat returns the format of the specified name.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case FormatText, FormatJSON:
+		return f, nil
+	default:
+		return FormatText, errors.Errorf("Unknown log format %q: must be text or json", name)
+	}
+}
+
+var (
+	mu     sync.Mutex
+	level            = new(slog.LevelVar)
+	output io.Writer = os.Stderr
+	format           = FormatText
+	logger           = newLogger()
+)
+
+func init() {
+	level.Set(LevelWarn.slog())
+}
+
+// newLogger returns a logger writing to the output in the format.
+func newLogger() *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: replaceAttr}
+	if format == FormatJSON {
+		return slog.New(slog.NewJSONHandler(output, opts))
+	}
+
+	return slog.New(slog.NewTextHandler(output, opts))
+}
+
+// replaceAttr names the levels of records in lower case, as they are named
+// by the flags, and writes times to the second.
+func replaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) != 0 {
+		return a
+	}
+	switch a.Key {
+	case slog.LevelKey:
+		if l, ok := a.Value.Any().(slog.Level); ok {
+			a.Value = slog.StringValue(strings.ToLower(l.String()))
+		}
+	case slog.TimeKey:
+		if t, ok := a.Value.Any().(time.Time); ok {
+			a.Value = slog.StringValue(t.Format(time.RFC3339))
+		}
+	}
+
+	return a
+}
+
+// SetLevel sets the minimum level of the records written.
+func SetLevel(l Level) {
+	level.Set(l.slog())
+}
+
+// SetOutput sets the destination of the records.
+func SetOutput(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	output = w
+	logger = newLogger()
+}
+
+// SetFormat sets the format of the records.
+func SetFormat(f Format) {
+	mu.Lock()
+	defer mu.Unlock()
+	format = f
+	logger = newLogger()
+}
+
+// Enabled reports whether records of the level are written. It can be used
+// to avoid computing expensive attributes.
+func Enabled(l Level) bool {
+	return l.slog() >= level.Level()
+}
+
+// Debug writes a debug record with the message and the alternating keys and
+// values of its attributes.
+func Debug(msg string, keyvals ...interface{}) {
+	write(LevelDebug, msg, keyvals)
+}
+
+// Info writes an info record with the message and the alternating keys and
+// values of its attributes.
+func Info(msg string, keyvals ...interface{}) {
+	write(LevelInfo, msg, keyvals)
+}
+
+// Warn writes a warn record with the message and the alternating keys and
+// values of its attributes.
+func Warn(msg string, keyvals ...interface{}) {
+	write(LevelWarn, msg, keyvals)
+}
+
+// Error writes an error record with the message and the alternating keys and
+// values of its attributes.
+func Error(msg string, keyvals ...interface{}) {
+	write(LevelError, msg, keyvals)
+}
+
+// Fatal writes an error record with the message and the alternating keys and
+// values of its attributes, and exits with status 1.
+func Fatal(msg string, keyvals ...interface{}) {
+	write(LevelError, msg, keyvals)
+	os.Exit(1)
+}
+
+func write(l Level, msg string, keyvals []interface{}) {
+	if !Enabled(l) {
+		return
+	}
+
+	attrs := make([]slog.Attr, 0, (len(keyvals)+1)/2)
+	for i := 0; i < len(keyvals); i += 2 {
+		var v interface{} = "<missing>"
+		if i+1 < len(keyvals) {
+			v = keyvals[i+1]
+		}
+		attrs = append(attrs, slog.Any(fmt.Sprint(keyvals[i]), value(v)))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	logger.LogAttrs(context.Background(), l.slog(), msg, attrs...)
+}
+
+// value returns the value of an attribute as written, so that both formats
+// write lists and errors in the same way.
+func value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case time.Duration:
+		return v.String()
+	case []string:
+		return strings.Join(v, ",")
+	case error:
+		return v.Error()
+	default:
+		return v
+	}
+}
@@ -0,0 +1,89 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	buf := &bytes.Buffer{}
+	SetOutput(buf)
+	defer SetOutput(os.Stderr)
+	SetLevel(LevelInfo)
+	defer SetLevel(LevelWarn)
+
+	Debug("hidden")
+	Info("enforced policy", "policy", "commit", "duration", 2*time.Millisecond, "files", []string{"a", "b"})
+	Warn("message with spaces", "value", "a=b", "dangling")
+
+	expected := regexp.MustCompile(`^time=\S+ level=info msg="enforced policy" policy=commit duration=2ms files=a,b
+time=\S+ level=warn msg="message with spaces" value="a=b" dangling=<missing>
+$`)
+	if !expected.MatchString(buf.String()) {
+		t.Errorf("Expected records to match %q, got %q", expected, buf.String())
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	SetLevel(LevelInfo)
+	defer SetLevel(LevelWarn)
+
+	if Enabled(LevelDebug) {
+		t.Errorf("Expected debug to be disabled")
+	}
+	if !Enabled(LevelInfo) || !Enabled(LevelWarn) {
+		t.Errorf("Expected info and warn to be enabled")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	SetOutput(buf)
+	defer SetOutput(os.Stderr)
+	SetFormat(FormatJSON)
+	defer SetFormat(FormatText)
+
+	Info("hidden")
+	Error("failed to write the report", "format", "sarif", "error", errors.New("disk full"))
+
+	expected := regexp.MustCompile(`^\{"time":"\S+","level":"error","msg":"failed to write the report","format":"sarif","error":"disk full"\}
+$`)
+	if !expected.MatchString(buf.String()) {
+		t.Errorf("Expected records to match %q, got %q", expected, buf.String())
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected Level
+		err      bool
+	}{
+		{name: "debug", expected: LevelDebug},
+		{name: "INFO", expected: LevelInfo},
+		{name: "error", expected: LevelError},
+		{name: "trace", expected: LevelWarn, err: true},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(tt *testing.T) {
+			l, err := ParseLevel(test.name)
+			if (err != nil) != test.err {
+				tt.Fatalf("Expected error %v, got %v", test.err, err)
+			}
+			if l != test.expected {
+				tt.Errorf("Expected level %v, got %v", test.expected, l)
+			}
+		})
+	}
+}
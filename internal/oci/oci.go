@@ -0,0 +1,219 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+// Package oci pulls artifacts from OCI registries.
+package oci
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Scheme is the prefix of OCI references.
+const Scheme = "oci://"
+
+// manifestMediaTypes are the manifest media types accepted from registries.
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// Reference identifies an artifact in a registry.
+type Reference struct {
+	// Registry is the host of the registry (e.g. ghcr.io).
+	Registry string
+	// Repository is the name of the repository (e.g. org/policy).
+	Repository string
+	// Reference is the tag or digest of the artifact.
+	Reference string
+}
+
+// ParseReference parses a reference of the form
+// oci://registry/repository[:tag|@digest]. The tag defaults to latest.
+func ParseReference(ref string) (*Reference, error) {
+	s := strings.TrimPrefix(ref, Scheme)
+	i := strings.Index(s, "/")
+	if i <= 0 || i == len(s)-1 {
+		return nil, errors.Errorf("invalid reference %q", ref)
+	}
+	r := &Reference{Registry: s[:i], Repository: s[i+1:], Reference: "latest"}
+	if r.Registry == "docker.io" {
+		r.Registry = "registry-1.docker.io"
+	}
+	if j := strings.Index(r.Repository, "@"); j >= 0 {
+		r.Repository, r.Reference = r.Repository[:j], r.Repository[j+1:]
+	} else if j := strings.LastIndex(r.Repository, ":"); j >= 0 {
+		r.Repository, r.Reference = r.Repository[:j], r.Repository[j+1:]
+	}
+	if r.Repository == "" || r.Reference == "" {
+		return nil, errors.Errorf("invalid reference %q", ref)
+	}
+
+	return r, nil
+}
+
+// String returns the reference in its canonical form.
+func (r *Reference) String() string {
+	sep := ":"
+	if strings.Contains(r.Reference, ":") {
+		sep = "@"
+	}
+	return Scheme + r.Registry + "/" + r.Repository + sep + r.Reference
+}
+
+// Puller pulls artifacts from registries.
+type Puller struct {
+	// Client is the HTTP client used to call registries.
+	Client *http.Client
+	token  string
+}
+
+// Pull pulls the single layer of an artifact, or the layer with a WASM media
+// type if there are several, using anonymous access.
+func Pull(ref string) ([]byte, error) {
+	return (&Puller{Client: http.DefaultClient}).Pull(ref)
+}
+
+// Pull pulls the single layer of an artifact, or the layer with a WASM media
+// type if there are several.
+func (p *Puller) Pull(ref string) ([]byte, error) {
+	r, err := ParseReference(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest struct {
+		Layers []struct {
+			MediaType string `json:"mediaType"`
+			Digest    string `json:"digest"`
+		} `json:"layers"`
+	}
+	data, err := p.get(r, "manifests/"+r.Reference, strings.Join(manifestMediaTypes, ", "))
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return nil, errors.Errorf("invalid manifest: %v", err)
+	}
+
+	var digest string
+	for _, layer := range manifest.Layers {
+		if strings.Contains(layer.MediaType, "wasm") || len(manifest.Layers) == 1 {
+			digest = layer.Digest
+			break
+		}
+	}
+	if digest == "" {
+		return nil, errors.Errorf("%s does not have a WASM layer", r)
+	}
+
+	blob, err := p.get(r, "blobs/"+digest, "")
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(blob)
+	if actual := "sha256:" + hex.EncodeToString(sum[:]); actual != digest {
+		return nil, errors.Errorf("digest mismatch: expected %s, got %s", digest, actual)
+	}
+
+	return blob, nil
+}
+
+func (p *Puller) get(r *Reference, path, accept string) ([]byte, error) {
+	u := fmt.Sprintf("https://%s/v2/%s/%s", r.Registry, r.Repository, path)
+	resp, err := p.do(u, accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && p.token == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		// nolint: errcheck
+		resp.Body.Close()
+		if p.token, err = p.authorize(challenge); err != nil {
+			return nil, err
+		}
+		if resp, err = p.do(u, accept); err != nil {
+			return nil, err
+		}
+	}
+	// nolint: errcheck
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("GET %s: %s", u, resp.Status)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+func (p *Puller) do(u, accept string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	return p.Client.Do(req)
+}
+
+// authorize requests an anonymous token as described by a Bearer challenge.
+func (p *Puller) authorize(challenge string) (string, error) {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return "", errors.Errorf("unsupported authentication challenge %q", challenge)
+	}
+	params := map[string]string{}
+	for _, param := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(kv) == 2 {
+			params[kv[0]] = strings.Trim(kv[1], `"`)
+		}
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", errors.Errorf("invalid authentication realm %q", params["realm"])
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	resp, err := p.Client.Get(realm.String())
+	if err != nil {
+		return "", err
+	}
+	// nolint: errcheck
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("GET %s: %s", realm, resp.Status)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+
+	return token.AccessToken, nil
+}
@@ -0,0 +1,81 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package oci
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseReference(t *testing.T) {
+	for _, test := range []struct {
+		Ref      string
+		Expected string
+		Error    bool
+	}{
+		{Ref: "oci://ghcr.io/org/policy:v1", Expected: "oci://ghcr.io/org/policy:v1"},
+		{Ref: "oci://ghcr.io/org/policy", Expected: "oci://ghcr.io/org/policy:latest"},
+		{Ref: "oci://localhost:5000/policy@sha256:abc", Expected: "oci://localhost:5000/policy@sha256:abc"},
+		{Ref: "oci://docker.io/org/policy:v1", Expected: "oci://registry-1.docker.io/org/policy:v1"},
+		{Ref: "oci://ghcr.io", Error: true},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Ref, func(tt *testing.T) {
+			r, err := ParseReference(test.Ref)
+			if test.Error {
+				if err == nil {
+					tt.Error("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				tt.Fatal(err)
+			}
+			if r.String() != test.Expected {
+				tt.Errorf("Expected %s, got %s", test.Expected, r)
+			}
+		})
+	}
+}
+
+func TestPull(t *testing.T) {
+	blob := []byte("\x00asm\x01\x00\x00\x00")
+	sum := sha256.Sum256(blob)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			fmt.Fprint(w, `{"token": "secret"}`)
+		case r.Header.Get("Authorization") != "Bearer secret":
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="repository:org/policy:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/v2/org/policy/manifests/v1":
+			fmt.Fprintf(w, `{"layers": [{"mediaType": "application/vnd.oci.image.config.v1+json", "digest": "sha256:0"}, {"mediaType": "application/vnd.wasm.content.layer.v1+wasm", "digest": "%s"}]}`, digest)
+		case r.URL.Path == "/v2/org/policy/blobs/"+digest:
+			// nolint: errcheck
+			w.Write(blob)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := &Puller{Client: server.Client()}
+	actual, err := p.Pull("oci://" + strings.TrimPrefix(server.URL, "https://") + "/org/policy:v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(actual) != string(blob) {
+		t.Errorf("Expected %q, got %q", blob, actual)
+	}
+}
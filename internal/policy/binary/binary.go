@@ -0,0 +1,95 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package binary
+
+import (
+	"fmt"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// Binary implements the policy.Policy interface and rejects binary files
+// added by the changes being enforced. Files tracked with Git LFS are
+// committed as text pointers, and are therefore always allowed.
+type Binary struct {
+	// AllowedPaths are gitignore style patterns of binary files that may be
+	// added (e.g. *.png, testdata/).
+	AllowedPaths []string `mapstructure:"allowedPaths"`
+
+	diffs []*git.FileDiff
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (b *Binary) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	var g *git.Git
+	if g, err = policy.NewGit(options); err != nil {
+		return report, errors.Errorf("failed to open git repo: %v", err)
+	}
+
+	var base string
+	if options.BaseBranch != nil {
+		base = *options.BaseBranch
+	}
+	if b.diffs, err = g.Diff(base); err != nil {
+		return report, errors.Errorf("failed to get diff: %v", err)
+	}
+	b.diffs = options.Ignore.FilterDiffs(b.diffs)
+
+	report.AddCheck(b.ValidateBinaryFiles())
+
+	return report, nil
+}
+
+// PathPatterns implements the policy.PathMatcher.PathPatterns function.
+func (b *Binary) PathPatterns() []string {
+	return b.AllowedPaths
+}
+
+// BinaryFileCheck ensures that no binary files are added.
+type BinaryFileCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (b BinaryFileCheck) Name() string {
+	return "Binary Files"
+}
+
+// Message returns to check message.
+func (b BinaryFileCheck) Message() string {
+	if len(b.errors) != 0 {
+		return fmt.Sprintf("Found %d added binary files", len(b.errors))
+	}
+	return "No binary files added"
+}
+
+// Errors returns any violations of the check.
+func (b BinaryFileCheck) Errors() []error {
+	return b.errors
+}
+
+// ValidateBinaryFiles checks each added file that is binary against the
+// allowed paths.
+func (b Binary) ValidateBinaryFiles() policy.Check {
+	check := &BinaryFileCheck{}
+
+	for _, d := range b.diffs {
+		if d.From != "" || d.To == "" || !d.Binary {
+			continue
+		}
+		if git.MatchAny(b.AllowedPaths, d.To) {
+			continue
+		}
+		check.errors = append(check.errors, policy.FileError(d.To, 0, errors.Errorf("Binary file %s must not be added", d.To)))
+	}
+
+	return check
+}
@@ -0,0 +1,38 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package binary
+
+import (
+	"testing"
+
+	"github.com/autonomy/conform/internal/git"
+)
+
+func TestValidateBinaryFiles(t *testing.T) {
+	for _, test := range []struct {
+		Name         string
+		AllowedPaths []string
+		Diff         git.FileDiff
+		Errors       int
+	}{
+		{Name: "Added text", Diff: git.FileDiff{To: "main.go"}},
+		{Name: "Added binary", Diff: git.FileDiff{To: "conform", Binary: true}, Errors: 1},
+		{Name: "Modified binary", Diff: git.FileDiff{From: "logo.png", To: "logo.png", Binary: true}},
+		{Name: "Renamed binary", Diff: git.FileDiff{From: "logo.png", To: "docs/logo.png", Binary: true}},
+		{Name: "Deleted binary", Diff: git.FileDiff{From: "conform", Binary: true}},
+		{Name: "Allowed binary", AllowedPaths: []string{"*.png"}, Diff: git.FileDiff{To: "docs/logo.png", Binary: true}},
+		{Name: "Allowed directory", AllowedPaths: []string{"testdata/"}, Diff: git.FileDiff{To: "testdata/a.bin", Binary: true}},
+		{Name: "Not allowed binary", AllowedPaths: []string{"*.png"}, Diff: git.FileDiff{To: "conform", Binary: true}, Errors: 1},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			b := Binary{AllowedPaths: test.AllowedPaths, diffs: []*git.FileDiff{&test.Diff}}
+			if errs := b.ValidateBinaryFiles().Errors(); len(errs) != test.Errors {
+				tt.Errorf("Expected %d errors, got %v", test.Errors, errs)
+			}
+		})
+	}
+}
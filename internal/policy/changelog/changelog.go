@@ -0,0 +1,201 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package changelog
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/autonomy/conform/internal/provider"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultDirectory is the default directory of changelog fragments.
+	DefaultDirectory = "changelog.d"
+	// DefaultSkipTrailer is the default trailer exempting a change from
+	// requiring a fragment.
+	DefaultSkipTrailer = "Skip-Changelog"
+	// DefaultSkipLabel is the default pull request label exempting a change
+	// from requiring a fragment.
+	DefaultSkipLabel = "skip-changelog"
+)
+
+// Changelog implements the policy.Policy interface and enforces that changes
+// add a changelog fragment, in the style of towncrier.
+type Changelog struct {
+	// Paths are gitignore style patterns of the source files that require a
+	// fragment when changed (e.g. cmd/, internal/).
+	Paths []string `mapstructure:"paths"`
+	// Directory is the directory of the fragments. Defaults to
+	// DefaultDirectory.
+	Directory string `mapstructure:"directory"`
+	// SkipTrailer is the commit message trailer exempting a change. Defaults
+	// to DefaultSkipTrailer.
+	SkipTrailer string `mapstructure:"skipTrailer"`
+	// SkipLabel is the pull request label exempting a change. Defaults to
+	// DefaultSkipLabel.
+	SkipLabel string `mapstructure:"skipLabel"`
+
+	diffs    []*git.FileDiff
+	messages []string
+	labels   []string
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (c *Changelog) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	var g *git.Git
+	if g, err = policy.NewGit(options); err != nil {
+		return report, errors.Errorf("failed to open git repo: %v", err)
+	}
+
+	var base string
+	if options.BaseBranch != nil {
+		base = *options.BaseBranch
+	}
+	if c.diffs, err = g.Diff(base); err != nil {
+		return report, errors.Errorf("failed to get diff: %v", err)
+	}
+
+	c.messages = nil
+	if options.CommitMsgFile != nil {
+		var contents []byte
+		if contents, err = ioutil.ReadFile(*options.CommitMsgFile); err != nil {
+			return report, errors.Errorf("failed to read commit message file: %v", err)
+		}
+		c.messages = append(c.messages, string(contents))
+	}
+	var commits []*git.Commit
+	if commits, err = g.Commits(base); err != nil {
+		return report, errors.Errorf("failed to get commits: %v", err)
+	}
+	for _, commit := range commits {
+		c.messages = append(c.messages, commit.Message)
+	}
+
+	if c.labels, err = labels(options.Context); err != nil {
+		return report, err
+	}
+
+	report.AddCheck(c.ValidateChangelog())
+
+	return report, nil
+}
+
+// PathPatterns implements the policy.PathMatcher.PathPatterns function.
+func (c *Changelog) PathPatterns() []string {
+	return c.Paths
+}
+
+func labels(ctx context.Context) ([]string, error) {
+	prov, err := provider.New(ctx)
+	if err == provider.ErrNotPullRequest {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Errorf("failed to detect provider: %v", err)
+	}
+	pr, err := prov.PullRequest()
+	if err != nil {
+		return nil, errors.Errorf("failed to get pull request: %v", err)
+	}
+
+	return pr.Labels, nil
+}
+
+func (c Changelog) directory() string {
+	if c.Directory == "" {
+		return DefaultDirectory
+	}
+	return strings.Trim(path.Clean(c.Directory), "/")
+}
+
+// ChangelogCheck ensures that changes add a changelog fragment.
+type ChangelogCheck struct {
+	skipped  string
+	fragment string
+	errors   []error
+}
+
+// Name returns the name of the check.
+func (c ChangelogCheck) Name() string {
+	return "Changelog Fragment"
+}
+
+// Message returns to check message.
+func (c ChangelogCheck) Message() string {
+	switch {
+	case len(c.errors) != 0:
+		return "Missing changelog fragment"
+	case c.skipped != "":
+		return fmt.Sprintf("Skipped by %s", c.skipped)
+	case c.fragment != "":
+		return fmt.Sprintf("Found %s", c.fragment)
+	}
+	return "No source changes"
+}
+
+// Errors returns any violations of the check.
+func (c ChangelogCheck) Errors() []error {
+	return c.errors
+}
+
+// ValidateChangelog checks that a fragment is added when source files change,
+// unless the change is exempted by a trailer or label.
+func (c Changelog) ValidateChangelog() policy.Check {
+	check := &ChangelogCheck{}
+
+	var source string
+	for _, diff := range c.diffs {
+		p := diff.Path()
+		if strings.HasPrefix(p, c.directory()+"/") {
+			if diff.To != "" && check.fragment == "" {
+				check.fragment = diff.To
+			}
+			continue
+		}
+		if source == "" && git.MatchAny(c.Paths, p) {
+			source = p
+		}
+	}
+	if source == "" || check.fragment != "" {
+		return check
+	}
+
+	trailer := c.SkipTrailer
+	if trailer == "" {
+		trailer = DefaultSkipTrailer
+	}
+	for _, msg := range c.messages {
+		if _, ok := git.Trailers(msg)[strings.ToLower(trailer)]; ok {
+			check.skipped = trailer + " trailer"
+			return check
+		}
+	}
+
+	label := c.SkipLabel
+	if label == "" {
+		label = DefaultSkipLabel
+	}
+	for _, l := range c.labels {
+		if l == label {
+			check.skipped = label + " label"
+			return check
+		}
+	}
+
+	check.errors = append(check.errors, errors.Errorf("%s changed without adding a fragment to %s/", source, c.directory()))
+
+	return check
+}
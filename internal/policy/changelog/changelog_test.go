@@ -0,0 +1,37 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package changelog
+
+import (
+	"testing"
+
+	"github.com/autonomy/conform/internal/git"
+)
+
+func TestValidateChangelog(t *testing.T) {
+	source := &git.FileDiff{From: "cmd/root.go", To: "cmd/root.go"}
+	for _, test := range []struct {
+		Name     string
+		Policy   Changelog
+		Expected int
+	}{
+		{"Fragment", Changelog{diffs: []*git.FileDiff{source, {To: "changelog.d/123.feature.md"}}}, 0},
+		{"Missing", Changelog{diffs: []*git.FileDiff{source}}, 1},
+		{"Fragment deleted", Changelog{diffs: []*git.FileDiff{source, {From: "changelog.d/122.bugfix.md"}}}, 1},
+		{"No source changes", Changelog{diffs: []*git.FileDiff{{From: "README.md", To: "README.md"}}}, 0},
+		{"Trailer", Changelog{diffs: []*git.FileDiff{source}, messages: []string{"chore: bump\n\nSkip-Changelog: true\n"}}, 0},
+		{"Label", Changelog{diffs: []*git.FileDiff{source}, labels: []string{"skip-changelog"}}, 0},
+		{"Directory", Changelog{Directory: "changes/", diffs: []*git.FileDiff{source, {To: "changes/123.md"}}}, 0},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			test.Policy.Paths = []string{"cmd/", "internal/"}
+			if errs := test.Policy.ValidateChangelog().Errors(); len(errs) != test.Expected {
+				tt.Errorf("Expected %d errors, got %v", test.Expected, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package codeowners
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// CodeOwners implements the policy.Policy interface and enforces that the
+// files being changed have owners in the CODEOWNERS file.
+type CodeOwners struct {
+	// ExcludePaths are gitignore style patterns of files that do not require
+	// an owner.
+	ExcludePaths []string `mapstructure:"excludePaths"`
+
+	codeOwners *git.CodeOwners
+	diffs      []*git.FileDiff
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (c *CodeOwners) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	var g *git.Git
+	if g, err = policy.NewGit(options); err != nil {
+		return report, errors.Errorf("failed to open git repo: %v", err)
+	}
+
+	if c.codeOwners, err = policy.ReadCodeOwners(options.Tree); err != nil {
+		return report, errors.Errorf("failed to read CODEOWNERS: %v", err)
+	}
+
+	var base string
+	if options.BaseBranch != nil {
+		base = *options.BaseBranch
+	}
+	if c.diffs, err = g.Diff(base); err != nil {
+		return report, errors.Errorf("failed to get diff: %v", err)
+	}
+
+	report.AddCheck(c.ValidateCoverage())
+
+	return report, nil
+}
+
+// PathPatterns implements the policy.PathMatcher.PathPatterns function.
+func (c *CodeOwners) PathPatterns() []string {
+	return c.ExcludePaths
+}
+
+// CoverageCheck ensures that changed files have owners.
+type CoverageCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (c CoverageCheck) Name() string {
+	return "Code Owners"
+}
+
+// Message returns to check message.
+func (c CoverageCheck) Message() string {
+	if len(c.errors) != 0 {
+		return fmt.Sprintf("Found %d files without owners", len(c.errors))
+	}
+	return "All changed files have owners"
+}
+
+// Errors returns any violations of the check.
+func (c CoverageCheck) Errors() []error {
+	return c.errors
+}
+
+// ValidateCoverage checks that every changed file, other than deleted files,
+// matches a CODEOWNERS rule with at least one owner.
+func (c CodeOwners) ValidateCoverage() policy.Check {
+	check := &CoverageCheck{}
+
+	if c.codeOwners.Path == "" {
+		check.errors = append(check.errors, errors.Errorf("No CODEOWNERS file found in %s", strings.Join(git.CodeOwnersPaths, ", ")))
+		return check
+	}
+
+	for _, diff := range c.diffs {
+		if diff.To == "" || git.MatchAny(c.ExcludePaths, diff.To) {
+			continue
+		}
+		if len(c.codeOwners.Owners(diff.To)) == 0 {
+			check.errors = append(check.errors, policy.FileError(diff.To, 0, errors.Errorf("%s is not covered by %s", diff.To, c.codeOwners.Path)))
+		}
+	}
+
+	return check
+}
@@ -0,0 +1,44 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package codeowners
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/autonomy/conform/internal/git"
+)
+
+func TestValidateCoverage(t *testing.T) {
+	codeOwners, err := git.ParseCodeOwners(strings.NewReader("# Owners\n/cmd/ @org/cli\n*.md @docs\n/internal/generated/\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	codeOwners.Path = ".github/CODEOWNERS"
+	for _, test := range []struct {
+		Name     string
+		Diffs    []*git.FileDiff
+		Expected int
+	}{
+		{"Covered", []*git.FileDiff{{To: "cmd/root.go"}, {To: "internal/README.md"}}, 0},
+		{"New directory", []*git.FileDiff{{To: "pkg/plugin/plugin.go"}, {To: "cmd/root.go"}}, 1},
+		{"Ownership removed", []*git.FileDiff{{To: "internal/generated/types.go"}}, 1},
+		{"Deleted", []*git.FileDiff{{From: "pkg/old.go"}}, 0},
+		{"Excluded", []*git.FileDiff{{To: "vendor/dep/dep.go"}}, 0},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			c := CodeOwners{ExcludePaths: []string{"vendor/"}, codeOwners: codeOwners, diffs: test.Diffs}
+			if errs := c.ValidateCoverage().Errors(); len(errs) != test.Expected {
+				tt.Errorf("Expected %d errors, got %v", test.Expected, errs)
+			}
+		})
+	}
+
+	if errs := (CodeOwners{codeOwners: &git.CodeOwners{}}).ValidateCoverage().Errors(); len(errs) != 1 {
+		t.Errorf("Expected a missing CODEOWNERS error, got %v", errs)
+	}
+}
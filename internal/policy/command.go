@@ -0,0 +1,31 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package policy
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// commandWaitDelay is how long a command that was killed is waited for, since
+// the processes it started may still hold its output open.
+const commandWaitDelay = 2 * time.Second
+
+// Command returns the command of a policy, like exec.CommandContext, except
+// that once the context is done, e.g. when the policy times out, the process
+// group of the command is killed, including the processes it started (e.g.
+// those of sh -c), and the command is waited for only briefly.
+func Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(Context(ctx), name, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = commandWaitDelay
+
+	return cmd
+}
@@ -67,15 +67,17 @@ func (c Commit) ValidateConventionalCommit() policy.Check {
 		return check
 	}
 
-	c.Conventional.Types = append(c.Conventional.Types, TypeFeat, TypeFix)
+	// The types are copied, so that validating several messages does not
+	// append the default types more than once.
+	types := append(append([]string{}, c.Conventional.Types...), TypeFeat, TypeFix)
 	typeIsValid := false
-	for _, t := range c.Conventional.Types {
+	for _, t := range types {
 		if t == groups[1] {
 			typeIsValid = true
 		}
 	}
 	if !typeIsValid {
-		check.errors = append(check.errors, errors.Errorf("Invalid type %q: allowed types are %v", groups[1], c.Conventional.Types))
+		check.errors = append(check.errors, errors.Errorf("Invalid type %q: allowed types are %v", groups[1], types))
 		return check
 	}
 
@@ -35,9 +35,12 @@ func (g GPGCheck) Errors() []error {
 
 // ValidateGPGSign checks the commit message for a GPG signature.
 func (c Commit) ValidateGPGSign(g *git.Git) policy.Check {
+	return validateGPGSignature(g.HasGPGSignature())
+}
+
+func validateGPGSignature(ok bool, err error) policy.Check {
 	check := &GPGCheck{}
 
-	ok, err := g.HasGPGSignature()
 	if err != nil {
 		check.errors = append(check.errors, err)
 		return check
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/logging"
 	"github.com/autonomy/conform/internal/policy"
 	"github.com/pkg/errors"
 )
@@ -51,7 +52,7 @@ func (c *Commit) Compliance(options *policy.Options) (*policy.Report, error) {
 	// Setup the policy for all checks.
 
 	var g *git.Git
-	if g, err = git.NewGit(); err != nil {
+	if g, err = policy.NewGit(options); err != nil {
 		return report, errors.Errorf("failed to open git repo: %v", err)
 	}
 
@@ -62,40 +63,126 @@ func (c *Commit) Compliance(options *policy.Options) (*policy.Report, error) {
 			return report, errors.Errorf("failed to read commit message file: %v", err)
 		}
 		msg = string(contents)
+		logging.Debug("read commit message", "file", *options.CommitMsgFile)
 	} else if msg, err = g.Message(); err != nil {
 		return report, errors.Errorf("failed to get commit message: %v", err)
+	} else {
+		logging.Debug("read commit message", "ref", "HEAD")
+	}
+	c.msg = msg
+
+	if options.CommitCount > 0 && options.CommitMsgFile == nil {
+		var commits []*git.Commit
+		if commits, err = g.RecentCommits(options.CommitCount); err != nil {
+			return report, errors.Errorf("failed to get commits: %v", err)
+		}
+		for _, commit := range commits {
+			c.msg = commit.Message
+			signed := commit.Signed
+			for _, check := range c.messageChecks(func() (bool, error) { return signed, nil }) {
+				report.AddCheck(&commitCheck{Check: check, sha: commit.SHA, prefixed: true})
+			}
+		}
+		// Suggestions are made for the message of HEAD.
+		c.msg = msg
+	} else {
+		// The message of the commit message file is not of a commit yet.
+		sha := ""
+		if options.CommitMsgFile == nil {
+			if sha, err = g.SHA(); err != nil {
+				return report, errors.Errorf("failed to get the SHA of HEAD: %v", err)
+			}
+		}
+		for _, check := range c.messageChecks(g.HasGPGSignature) {
+			if sha != "" {
+				check = &commitCheck{Check: check, sha: sha}
+			}
+			report.AddCheck(check)
+		}
 	}
+
+	if c.MaximumOfOneCommit {
+		report.AddCheck(c.ValidateNumberOfCommits(g, "refs/heads/master"))
+	}
+
+	return report, nil
+}
+
+// ValidateMessage returns the violations of the message of a commit that does
+// not exist yet, which is assumed to be signed when GPG signatures are
+// required.
+func (c *Commit) ValidateMessage(msg string) []error {
 	c.msg = msg
 
+	var errs []error
+	for _, check := range c.messageChecks(func() (bool, error) { return true, nil }) {
+		errs = append(errs, check.Errors()...)
+	}
+
+	return errs
+}
+
+// messageChecks returns the checks of the commit message, in which signed
+// reports whether the commit has a GPG signature.
+func (c *Commit) messageChecks(signed func() (bool, error)) []policy.Check {
+	var checks []policy.Check
+
 	if c.HeaderLength != 0 {
-		report.AddCheck(c.ValidateHeaderLength())
+		checks = append(checks, c.ValidateHeaderLength())
 	}
 
 	if c.DCO {
-		report.AddCheck(c.ValidateDCO())
+		checks = append(checks, c.ValidateDCO())
 	}
 
 	if c.GPG {
-		report.AddCheck(c.ValidateGPGSign(g))
+		checks = append(checks, validateGPGSignature(signed()))
 	}
 
 	if c.Imperative {
-		report.AddCheck(c.ValidateImperative())
+		checks = append(checks, c.ValidateImperative())
 	}
 
 	if c.Conventional != nil {
-		report.AddCheck(c.ValidateConventionalCommit())
+		checks = append(checks, c.ValidateConventionalCommit())
 	}
 
-	if c.MaximumOfOneCommit {
-		report.AddCheck(c.ValidateNumberOfCommits(g, "refs/heads/master"))
+	if c.RequireCommitBody {
+		checks = append(checks, c.ValidateBody())
 	}
 
-	if c.RequireCommitBody {
-		report.AddCheck(c.ValidateBody())
+	return checks
+}
+
+// commitCheck is a check of the message of a commit, whose violations are
+// located at the commit. The message and violations of a check of one of
+// several commits are prefixed by the abbreviated SHA of the commit.
+type commitCheck struct {
+	policy.Check
+	sha      string
+	prefixed bool
+}
+
+// Message returns to check message.
+func (c *commitCheck) Message() string {
+	if !c.prefixed {
+		return c.Check.Message()
 	}
 
-	return report, nil
+	return c.sha[:7] + ": " + c.Check.Message()
+}
+
+// Errors returns any violations of the check.
+func (c *commitCheck) Errors() []error {
+	var errs []error
+	for _, err := range c.Check.Errors() {
+		if c.prefixed {
+			err = errors.Errorf("%s: %v", c.sha[:7], err)
+		}
+		errs = append(errs, policy.CommitError(c.sha, err))
+	}
+
+	return errs
 }
 
 func (c Commit) firstWord() (string, error) {
@@ -211,6 +211,54 @@ func TestEmptyConventionalCommitPolicy(t *testing.T) {
 	}
 }
 
+func TestCommitCount(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer RemoveAll(dir)
+	err = os.Chdir(dir)
+	if err != nil {
+		t.Error(err)
+	}
+	err = initRepo()
+	if err != nil {
+		t.Error(err)
+	}
+	// The invalid commit is followed by a valid one.
+	if err = createInvalidCommit(); err != nil {
+		t.Error(err)
+	}
+	if _, err = exec.Command("git", "-c", "user.name='test'", "-c", "user.email='test@autonomy.io'", "commit", "--allow-empty", "-m", "type(scope): description").Output(); err != nil {
+		t.Error(err)
+	}
+
+	c := &Commit{
+		Conventional: &Conventional{
+			Types:  []string{"type"},
+			Scopes: []string{"scope"},
+		},
+	}
+	for count, valid := range map[int]bool{0: true, 1: true, 2: false, 5: false} {
+		report, err := c.Compliance(policy.NewDefaultOptions(policy.WithCommitCount(count)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Valid() != valid {
+			t.Errorf("Expected the report of %d commits to be valid: %t, got %t", count, valid, report.Valid())
+		}
+		expected := count
+		if count == 0 {
+			expected = 1
+		} else if count > 2 {
+			expected = 2
+		}
+		if len(report.Checks()) != expected {
+			t.Errorf("Expected %d checks for %d commits, got %d", expected, count, len(report.Checks()))
+		}
+	}
+}
+
 func runCompliance() (*policy.Report, error) {
 	c := &Commit{
 		Conventional: &Conventional{
@@ -0,0 +1,76 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package commit
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+// Fixes implements the policy.Fixer.Fixes function. Commit messages are not
+// rewritten, since that would rewrite history, so that the fixes only suggest
+// how to reword the message.
+func (c *Commit) Fixes(options *policy.Options) ([]policy.Fix, error) {
+	report, err := c.Compliance(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixes []policy.Fix
+	for _, check := range report.Checks() {
+		if len(check.Errors()) == 0 {
+			continue
+		}
+		if suggestion := c.suggest(check); suggestion != "" {
+			fixes = append(fixes, policy.Fix{Description: suggestion})
+		}
+	}
+
+	return fixes, nil
+}
+
+// suggest returns how to reword the commit message to pass the check.
+func (c *Commit) suggest(check policy.Check) string {
+	header := strings.Split(strings.TrimPrefix(c.msg, "\n"), "\n")[0]
+
+	switch check.Name() {
+	case "Header Length":
+		return fmt.Sprintf("Shorten the header to at most %d characters", MaxNumberOfCommitCharacters)
+	case "DCO":
+		return "Sign off the commit with git commit --amend --signoff"
+	case "GPG":
+		return "Sign the commit with git commit --amend --gpg-sign"
+	case "Imperative Mood":
+		return "Start the header with an imperative verb, e.g. \"add\" instead of \"added\" or \"adds\""
+	case "Conventional Commit":
+		if len(parseHeader(c.msg)) != 6 {
+			commitType := TypeFeat
+			if c.Conventional != nil && len(c.Conventional.Types) != 0 {
+				commitType = c.Conventional.Types[0]
+			}
+			return fmt.Sprintf("Reword the header as <type>[(<scope>)]: <description>, e.g. %q", commitType+": "+lowerFirst(header))
+		}
+		return "Reword the header to use an allowed type and scope, and a description of at most 72 characters"
+	case "Number of Commits":
+		return "Squash the commits into one"
+	case "Commit Body":
+		return "Add a body to the commit message, explaining what changed and why"
+	default:
+		return ""
+	}
+}
+
+func lowerFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+
+	return string(unicode.ToLower(r)) + s[size:]
+}
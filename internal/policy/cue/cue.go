@@ -0,0 +1,149 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package cue
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/autonomy/conform/internal/policy/input"
+	"github.com/pkg/errors"
+)
+
+// CUE implements the policy.Policy interface and validates the input.Input of
+// the changes being enforced against user supplied CUE schemas. The schemas
+// are evaluated with the cue binary, which must be in the PATH.
+type CUE struct {
+	// Name is the name of the check. Defaults to "CUE".
+	Name string `mapstructure:"name"`
+	// Schemas are the paths of the CUE files declaring the constraints.
+	Schemas []string `mapstructure:"schemas"`
+	// Definition is the optional definition that the input must conform to
+	// (e.g. #Input). When empty, the input is unified with the schemas.
+	Definition string `mapstructure:"definition"`
+
+	input *input.Input
+	ctx   context.Context
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (c *CUE) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	if c.input, err = input.New(options); err != nil {
+		return report, err
+	}
+	c.ctx = options.Context
+
+	report.AddCheck(c.ValidateCUE())
+
+	return report, nil
+}
+
+// CUECheck reports the constraints violated by the input.
+type CUECheck struct {
+	name   string
+	errors []error
+}
+
+// Name returns the name of the check.
+func (c CUECheck) Name() string {
+	return c.name
+}
+
+// Message returns to check message.
+func (c CUECheck) Message() string {
+	if len(c.errors) != 0 {
+		return fmt.Sprintf("Found %d violations", len(c.errors))
+	}
+	return "No violations"
+}
+
+// Errors returns any violations of the check.
+func (c CUECheck) Errors() []error {
+	return c.errors
+}
+
+// ValidateCUE vets the input against the schemas, and reports each violated
+// constraint.
+func (c CUE) ValidateCUE() policy.Check {
+	check := &CUECheck{name: c.Name}
+	if check.name == "" {
+		check.name = "CUE"
+	}
+
+	dir, err := ioutil.TempDir("", "conform-cue")
+	if err != nil {
+		check.errors = append(check.errors, errors.Errorf("Failed to create temporary directory: %v", err))
+		return check
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+
+	data, err := json.Marshal(c.input)
+	if err != nil {
+		check.errors = append(check.errors, errors.Errorf("Failed to encode input: %v", err))
+		return check
+	}
+	file := filepath.Join(dir, "input.json")
+	if err = ioutil.WriteFile(file, data, 0644); err != nil {
+		check.errors = append(check.errors, errors.Errorf("Failed to write input: %v", err))
+		return check
+	}
+
+	args := []string{"vet", "--concrete"}
+	if c.Definition != "" {
+		args = append(args, "--schema", c.Definition)
+	}
+	args = append(args, c.Schemas...)
+	args = append(args, file)
+
+	var out bytes.Buffer
+	cmd := policy.Command(c.ctx, "cue", args...)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err = cmd.Run(); err == nil {
+		return check
+	}
+	if _, ok := err.(*exec.ExitError); !ok {
+		check.errors = append(check.errors, errors.Errorf("Failed to run cue: %v", err))
+		return check
+	}
+
+	for _, msg := range Violations(out.Bytes()) {
+		check.errors = append(check.errors, errors.New(msg))
+	}
+	if len(check.errors) == 0 {
+		check.errors = append(check.errors, errors.Errorf("cue vet failed: %s", strings.TrimSpace(out.String())))
+	}
+
+	return check
+}
+
+// Violations parses the output of cue vet. Each error is reported on an
+// unindented line, followed by indented source positions.
+func Violations(out []byte) (violations []string) {
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		violations = append(violations, strings.TrimSuffix(line, ":"))
+	}
+
+	return violations
+}
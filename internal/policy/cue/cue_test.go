@@ -0,0 +1,26 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package cue
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestViolations(t *testing.T) {
+	out := `commit.header: invalid value "fix" (out of bound =~"^feat"):
+    ./schema.cue:4:10
+    ./input.json:1:30
+changes: incompatible list lengths (0 and 1):
+    ./schema.cue:5:11
+`
+	expected := []string{
+		`commit.header: invalid value "fix" (out of bound =~"^feat")`,
+		`changes: incompatible list lengths (0 and 1)`,
+	}
+	if actual := Violations([]byte(out)); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,103 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package dependency
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Unknown is the identifier of a license that could not be classified.
+const Unknown = "unknown"
+
+// LicenseFileRegex is the regular expression used to find license files in
+// the root of a module.
+var LicenseFileRegex = regexp.MustCompile(`(?i)^(un)?licen[cs]e(\.(md|txt|rst))?$|^copying(\.(md|txt))?$`)
+
+// signature identifies a license by phrases that must all be present.
+type signature struct {
+	id      string
+	phrases []string
+	absent  []string
+}
+
+// signatures are ordered from most to least specific. Since some licenses
+// mention others (e.g. the MPL mentions the GPL), the signature whose first
+// phrase appears earliest in the text wins, and ties are broken by order.
+var signatures = []signature{
+	{id: "AGPL-3.0", phrases: []string{"gnu affero general public license"}},
+	{id: "LGPL-3.0", phrases: []string{"gnu lesser general public license", "version 3"}},
+	{id: "LGPL-2.1", phrases: []string{"gnu lesser general public license", "version 2.1"}},
+	{id: "GPL-3.0", phrases: []string{"gnu general public license", "version 3"}},
+	{id: "GPL-2.0", phrases: []string{"gnu general public license", "version 2"}},
+	{id: "MPL-2.0", phrases: []string{"mozilla public license", "2.0"}},
+	{id: "Apache-2.0", phrases: []string{"apache license", "version 2.0"}},
+	{id: "BSD-3-Clause", phrases: []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{id: "BSD-2-Clause", phrases: []string{"redistribution and use in source and binary forms"}, absent: []string{"neither the name"}},
+	{id: "MIT", phrases: []string{"permission is hereby granted, free of charge"}},
+	{id: "ISC", phrases: []string{"permission to use, copy, modify, and/or distribute this software for any purpose"}},
+	{id: "Unlicense", phrases: []string{"this is free and unencumbered software released into the public domain"}},
+}
+
+// Classify returns the SPDX identifier of the license text, or Unknown.
+func Classify(text string) string {
+	text = strings.Join(strings.Fields(strings.ToLower(text)), " ")
+
+	id, position := Unknown, len(text)
+	for _, s := range signatures {
+		if !matches(text, s) {
+			continue
+		}
+		if i := strings.Index(text, s.phrases[0]); i < position {
+			id, position = s.id, i
+		}
+	}
+
+	return id
+}
+
+func matches(text string, s signature) bool {
+	for _, phrase := range s.phrases {
+		if !strings.Contains(text, phrase) {
+			return false
+		}
+	}
+	for _, phrase := range s.absent {
+		if strings.Contains(text, phrase) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// DetectLicenses classifies each license file in the root of the directory.
+// A module may have more than one license file (e.g. dual licensing).
+func DetectLicenses(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var licenses []string
+	for _, info := range infos {
+		if info.IsDir() || !LicenseFileRegex.MatchString(info.Name()) {
+			continue
+		}
+		contents, err := ioutil.ReadFile(filepath.Join(dir, info.Name()))
+		if err != nil {
+			if os.IsPermission(err) {
+				continue
+			}
+			return nil, err
+		}
+		licenses = append(licenses, Classify(string(contents)))
+	}
+
+	return licenses, nil
+}
@@ -0,0 +1,53 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package dependency
+
+import (
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	type testDesc struct {
+		Name     string
+		Text     string
+		Expected string
+	}
+
+	for _, test := range []testDesc{
+		{
+			Name:     "MIT",
+			Text:     "MIT License\n\nCopyright (c) 2019 Foo\n\nPermission is hereby granted, free of charge, to any person obtaining a copy",
+			Expected: "MIT",
+		},
+		{
+			Name:     "MPL Mentioning AGPL",
+			Text:     "Mozilla Public License Version 2.0\n\n1.12. \"Secondary License\" means either the GNU General Public License, Version 2.0, the GNU Lesser General Public License, Version 2.1, the GNU Affero General Public License, Version 3.0",
+			Expected: "MPL-2.0",
+		},
+		{
+			Name:     "AGPL",
+			Text:     "GNU AFFERO GENERAL PUBLIC LICENSE\nVersion 3, 19 November 2007\n\nthe GNU General Public License",
+			Expected: "AGPL-3.0",
+		},
+		{
+			Name:     "BSD-2-Clause",
+			Text:     "Redistribution and use in source and binary forms, with or without\nmodification, are permitted",
+			Expected: "BSD-2-Clause",
+		},
+		{
+			Name:     "Unknown",
+			Text:     "All rights reserved.",
+			Expected: Unknown,
+		},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			if actual := Classify(test.Text); actual != test.Expected {
+				tt.Errorf("Expected %s, got %s", test.Expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package dependency
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// Dependency implements the policy.Policy interface and enforces that the
+// licenses of Go module dependencies are allowed.
+type Dependency struct {
+	// Allowed are the SPDX identifiers of allowed licenses (e.g. MIT,
+	// Apache-2.0).
+	Allowed []string `mapstructure:"allowed"`
+	// Exceptions are module paths that are not checked.
+	Exceptions []string `mapstructure:"exceptions"`
+
+	modules []*Module
+}
+
+// Module is a module as described by `go list -json`.
+type Module struct {
+	Path    string
+	Version string
+	Main    bool
+	Dir     string
+	Replace *Module
+}
+
+// pkg is a package as described by `go list -json`.
+type pkg struct {
+	Standard bool
+	Module   *Module
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (d *Dependency) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	if d.modules, err = listModules(options.Context); err != nil {
+		return report, errors.Errorf("failed to list modules: %v", err)
+	}
+
+	report.AddCheck(d.ValidateLicenses())
+
+	return report, nil
+}
+
+// LicenseCheck ensures that dependencies have allowed licenses.
+type LicenseCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (l LicenseCheck) Name() string {
+	return "Dependency Licenses"
+}
+
+// Message returns to check message.
+func (l LicenseCheck) Message() string {
+	if len(l.errors) != 0 {
+		return fmt.Sprintf("Found %d dependencies without an allowed license", len(l.errors))
+	}
+	return "All dependencies have an allowed license"
+}
+
+// Errors returns any violations of the check.
+func (l LicenseCheck) Errors() []error {
+	return l.errors
+}
+
+// ValidateLicenses detects the licenses of each dependency in the module
+// cache, and checks them against the allowlist. A dependency is allowed if
+// any of its licenses are allowed.
+func (d Dependency) ValidateLicenses() policy.Check {
+	check := &LicenseCheck{}
+
+	for _, m := range d.modules {
+		if m.Main || d.excepted(m.Path) {
+			continue
+		}
+		dir := m.Dir
+		if m.Replace != nil && m.Replace.Dir != "" {
+			dir = m.Replace.Dir
+		}
+		if dir == "" {
+			check.errors = append(check.errors, errors.Errorf("Module %s@%s is not in the module cache", m.Path, m.Version))
+			continue
+		}
+		licenses, err := DetectLicenses(dir)
+		if err != nil {
+			check.errors = append(check.errors, errors.Errorf("Failed to detect license of %s@%s: %v", m.Path, m.Version, err))
+			continue
+		}
+		if len(licenses) == 0 {
+			check.errors = append(check.errors, errors.Errorf("Module %s@%s does not have a license", m.Path, m.Version))
+			continue
+		}
+		if !d.allowed(licenses) {
+			check.errors = append(check.errors, errors.Errorf("Module %s@%s has license %s which is not allowed", m.Path, m.Version, strings.Join(licenses, ", ")))
+		}
+	}
+
+	return check
+}
+
+func (d Dependency) allowed(licenses []string) bool {
+	for _, license := range licenses {
+		for _, allowed := range d.Allowed {
+			if strings.EqualFold(license, allowed) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (d Dependency) excepted(path string) bool {
+	for _, exception := range d.Exceptions {
+		if path == exception {
+			return true
+		}
+	}
+
+	return false
+}
+
+// listModules returns the modules that provide the packages imported by the
+// main module. Unlike `go list -m all`, this excludes modules that are part
+// of the module graph but are never compiled into the binary, and guarantees
+// that each module is in the module cache.
+func listModules(ctx context.Context) (modules []*Module, err error) {
+	cmd := policy.Command(ctx, "go", "list", "-deps", "-json", "./...")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	seen := map[string]bool{}
+	decoder := json.NewDecoder(bytes.NewReader(out))
+	for {
+		p := &pkg{}
+		if err = decoder.Decode(p); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		if p.Standard || p.Module == nil || seen[p.Module.Path] {
+			continue
+		}
+		seen[p.Module.Path] = true
+		modules = append(modules, p.Module)
+	}
+
+	return modules, nil
+}
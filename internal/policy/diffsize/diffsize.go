@@ -0,0 +1,119 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package diffsize
+
+import (
+	"fmt"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// DiffSize implements the policy.Policy interface and limits the size of the
+// changes being enforced.
+type DiffSize struct {
+	// MaximumAdded is the maximum number of lines added. Zero is unlimited.
+	MaximumAdded int `mapstructure:"maximumAdded"`
+	// MaximumRemoved is the maximum number of lines removed. Zero is
+	// unlimited.
+	MaximumRemoved int `mapstructure:"maximumRemoved"`
+	// MaximumLines is the maximum number of lines added and removed. Zero is
+	// unlimited.
+	MaximumLines int `mapstructure:"maximumLines"`
+	// MaximumFiles is the maximum number of files touched. Zero is unlimited.
+	MaximumFiles int `mapstructure:"maximumFiles"`
+	// ExcludePaths are gitignore style patterns of files that do not count
+	// towards the limits, such as generated and vendored code.
+	ExcludePaths []string `mapstructure:"excludePaths"`
+
+	diffs []*git.FileDiff
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (d *DiffSize) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	var g *git.Git
+	if g, err = policy.NewGit(options); err != nil {
+		return report, errors.Errorf("failed to open git repo: %v", err)
+	}
+
+	var base string
+	if options.BaseBranch != nil {
+		base = *options.BaseBranch
+	}
+	if d.diffs, err = g.Diff(base); err != nil {
+		return report, errors.Errorf("failed to get diff: %v", err)
+	}
+	d.diffs = options.Ignore.FilterDiffs(d.diffs)
+
+	report.AddCheck(d.ValidateDiffSize())
+
+	return report, nil
+}
+
+// PathPatterns implements the policy.PathMatcher.PathPatterns function.
+func (d *DiffSize) PathPatterns() []string {
+	return d.ExcludePaths
+}
+
+// DiffSizeCheck ensures that the changes are within the configured limits.
+type DiffSizeCheck struct {
+	added   int
+	removed int
+	files   int
+	errors  []error
+}
+
+// Name returns the name of the check.
+func (d DiffSizeCheck) Name() string {
+	return "Diff Size"
+}
+
+// Message returns to check message.
+func (d DiffSizeCheck) Message() string {
+	if len(d.errors) != 0 {
+		return fmt.Sprintf("Found %d diff size violations", len(d.errors))
+	}
+	return fmt.Sprintf("%d files changed, %d insertions, %d deletions", d.files, d.added, d.removed)
+}
+
+// Errors returns any violations of the check.
+func (d DiffSizeCheck) Errors() []error {
+	return d.errors
+}
+
+// ValidateDiffSize checks the number of lines and files changed against the
+// configured limits.
+func (d DiffSize) ValidateDiffSize() policy.Check {
+	check := &DiffSizeCheck{}
+
+	for _, diff := range d.diffs {
+		if git.MatchAny(d.ExcludePaths, diff.Path()) {
+			continue
+		}
+		check.files++
+		check.added += len(diff.Added)
+		check.removed += diff.Removed
+	}
+
+	if d.MaximumAdded != 0 && check.added > d.MaximumAdded {
+		check.errors = append(check.errors, errors.Errorf("%d lines added, maximum is %d", check.added, d.MaximumAdded))
+	}
+	if d.MaximumRemoved != 0 && check.removed > d.MaximumRemoved {
+		check.errors = append(check.errors, errors.Errorf("%d lines removed, maximum is %d", check.removed, d.MaximumRemoved))
+	}
+	if lines := check.added + check.removed; d.MaximumLines != 0 && lines > d.MaximumLines {
+		check.errors = append(check.errors, errors.Errorf("%d lines changed, maximum is %d", lines, d.MaximumLines))
+	}
+	if d.MaximumFiles != 0 && check.files > d.MaximumFiles {
+		check.errors = append(check.errors, errors.Errorf("%d files changed, maximum is %d", check.files, d.MaximumFiles))
+	}
+
+	return check
+}
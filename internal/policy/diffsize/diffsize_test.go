@@ -0,0 +1,38 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package diffsize
+
+import (
+	"testing"
+
+	"github.com/autonomy/conform/internal/git"
+)
+
+func TestValidateDiffSize(t *testing.T) {
+	diffs := []*git.FileDiff{
+		{To: "main.go", Added: make([]git.Line, 10), Removed: 5},
+		{From: "old.go", Removed: 20},
+		{To: "vendor/dep/dep.go", Added: make([]git.Line, 1000)},
+	}
+	for _, test := range []struct {
+		Name     string
+		Policy   DiffSize
+		Expected int
+	}{
+		{"Unlimited", DiffSize{}, 0},
+		{"Within", DiffSize{MaximumAdded: 10, MaximumRemoved: 25, MaximumLines: 35, MaximumFiles: 2, ExcludePaths: []string{"vendor/"}}, 0},
+		{"Not excluded", DiffSize{MaximumAdded: 10, MaximumFiles: 2}, 2},
+		{"Lines", DiffSize{MaximumLines: 34, ExcludePaths: []string{"vendor/"}}, 1},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			test.Policy.diffs = diffs
+			if errs := test.Policy.ValidateDiffSize().Errors(); len(errs) != test.Expected {
+				tt.Errorf("Expected %d errors, got %v", test.Expected, errs)
+			}
+		})
+	}
+}
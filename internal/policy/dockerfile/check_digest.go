@@ -0,0 +1,56 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package dockerfile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// DigestCheck ensures that base images are pinned by digest.
+type DigestCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (d DigestCheck) Name() string {
+	return "Base Image Digest"
+}
+
+// Message returns to check message.
+func (d DigestCheck) Message() string {
+	if len(d.errors) != 0 {
+		return fmt.Sprintf("Found %d base images not pinned by digest", len(d.errors))
+	}
+	return "All base images are pinned by digest"
+}
+
+// Errors returns any violations of the check.
+func (d DigestCheck) Errors() []error {
+	return d.errors
+}
+
+// ValidateDigest checks that each base image includes a digest. References to
+// previous stages, scratch, and images that depend on unresolved build
+// arguments are ignored.
+func (p Dockerfile) ValidateDigest() policy.Check {
+	check := &DigestCheck{}
+
+	for _, d := range p.dockerfiles {
+		for _, s := range d.Stages {
+			if s.Image == "scratch" || d.IsStage(s.Image) || strings.Contains(s.Image, "$") {
+				continue
+			}
+			if !strings.Contains(s.Image, "@sha256:") {
+				check.errors = append(check.errors, policy.FileError(d.Path, s.Line, errors.Errorf("%s:%d: base image %s is not pinned by digest", d.Path, s.Line, s.Image)))
+			}
+		}
+	}
+
+	return check
+}
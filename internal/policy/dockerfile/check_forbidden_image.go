@@ -0,0 +1,68 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package dockerfile
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// ForbiddenImageCheck ensures that forbidden base images are not used.
+type ForbiddenImageCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (f ForbiddenImageCheck) Name() string {
+	return "Forbidden Base Images"
+}
+
+// Message returns to check message.
+func (f ForbiddenImageCheck) Message() string {
+	if len(f.errors) != 0 {
+		return fmt.Sprintf("Found %d forbidden base images", len(f.errors))
+	}
+	return "No forbidden base images are used"
+}
+
+// Errors returns any violations of the check.
+func (f ForbiddenImageCheck) Errors() []error {
+	return f.errors
+}
+
+// ValidateForbiddenImages checks each base image against the forbidden
+// patterns.
+func (p Dockerfile) ValidateForbiddenImages() policy.Check {
+	check := &ForbiddenImageCheck{}
+
+	regexes := make([]*regexp.Regexp, 0, len(p.ForbiddenImages))
+	for _, expr := range p.ForbiddenImages {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			check.errors = append(check.errors, errors.Errorf("Invalid image pattern %q: %v", expr, err))
+			return check
+		}
+		regexes = append(regexes, re)
+	}
+
+	for _, d := range p.dockerfiles {
+		for _, s := range d.Stages {
+			if d.IsStage(s.Image) {
+				continue
+			}
+			for _, re := range regexes {
+				if re.MatchString(s.Image) {
+					check.errors = append(check.errors, policy.FileError(d.Path, s.Line, errors.Errorf("%s:%d: base image %s is forbidden", d.Path, s.Line, s.Image)))
+					break
+				}
+			}
+		}
+	}
+
+	return check
+}
@@ -0,0 +1,80 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package dockerfile
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// LabelsCheck ensures that images follow the label conventions.
+type LabelsCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (l LabelsCheck) Name() string {
+	return "Labels"
+}
+
+// Message returns to check message.
+func (l LabelsCheck) Message() string {
+	if len(l.errors) != 0 {
+		return fmt.Sprintf("Found %d label violations", len(l.errors))
+	}
+	return "All images follow the label conventions"
+}
+
+// Errors returns any violations of the check.
+func (l LabelsCheck) Errors() []error {
+	return l.errors
+}
+
+// ValidateLabels checks that the final stage of each Dockerfile declares the
+// required labels, and that all label keys match the label pattern.
+func (p Dockerfile) ValidateLabels() policy.Check {
+	check := &LabelsCheck{}
+
+	var re *regexp.Regexp
+	if p.LabelPattern != "" {
+		var err error
+		if re, err = regexp.Compile(p.LabelPattern); err != nil {
+			check.errors = append(check.errors, errors.Errorf("Invalid label pattern %q: %v", p.LabelPattern, err))
+			return check
+		}
+	}
+
+	for _, d := range p.dockerfiles {
+		final := d.Final()
+		if final == nil {
+			continue
+		}
+		labels := final.Labels()
+		for _, required := range p.RequiredLabels {
+			if _, ok := labels[required]; !ok {
+				check.errors = append(check.errors, policy.FileError(d.Path, 0, errors.Errorf("%s: final stage is missing label %s", d.Path, required)))
+			}
+		}
+		if re == nil {
+			continue
+		}
+		keys := make([]string, 0, len(labels))
+		for key := range labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if !re.MatchString(key) {
+				check.errors = append(check.errors, policy.FileError(d.Path, 0, errors.Errorf("%s: label %s does not match %s", d.Path, key, p.LabelPattern)))
+			}
+		}
+	}
+
+	return check
+}
@@ -0,0 +1,63 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package dockerfile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// UserCheck ensures that images do not run as root.
+type UserCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (u UserCheck) Name() string {
+	return "Non-Root User"
+}
+
+// Message returns to check message.
+func (u UserCheck) Message() string {
+	if len(u.errors) != 0 {
+		return fmt.Sprintf("Found %d images that run as root", len(u.errors))
+	}
+	return "All images run as a non-root user"
+}
+
+// Errors returns any violations of the check.
+func (u UserCheck) Errors() []error {
+	return u.errors
+}
+
+// ValidateUser checks that the last USER instruction of the final stage of
+// each Dockerfile is not root.
+func (p Dockerfile) ValidateUser() policy.Check {
+	check := &UserCheck{}
+
+	for _, d := range p.dockerfiles {
+		final := d.Final()
+		if final == nil {
+			continue
+		}
+		user := ""
+		for _, i := range final.Instructions {
+			if i.Command == "USER" && len(i.Args) > 0 {
+				user = strings.SplitN(i.Args[0], ":", 2)[0]
+			}
+		}
+		switch user {
+		case "":
+			check.errors = append(check.errors, policy.FileError(d.Path, 0, errors.Errorf("%s: final stage does not set a USER", d.Path)))
+		case "root", "0":
+			check.errors = append(check.errors, policy.FileError(d.Path, 0, errors.Errorf("%s: final stage runs as %s", d.Path, user)))
+		}
+	}
+
+	return check
+}
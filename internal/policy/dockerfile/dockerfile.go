@@ -0,0 +1,104 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package dockerfile
+
+import (
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// DefaultPaths are the default patterns used to find Dockerfiles.
+var DefaultPaths = []string{"Dockerfile", "*.Dockerfile", "Dockerfile.*"}
+
+// Dockerfile implements the policy.Policy interface and enforces best practices
+// in Dockerfiles.
+type Dockerfile struct {
+	// Paths are gitignore style patterns used to find Dockerfiles. Defaults to
+	// DefaultPaths.
+	Paths []string `mapstructure:"paths"`
+	// RequireDigest enforces that base images are pinned by digest.
+	RequireDigest bool `mapstructure:"requireDigest"`
+	// ForbiddenImages are regular expressions of base images that are not
+	// allowed (e.g. ^ubuntu).
+	ForbiddenImages []string `mapstructure:"forbiddenImages"`
+	// RequireNonRootUser enforces that the final stage sets a USER other than
+	// root.
+	RequireNonRootUser bool `mapstructure:"requireNonRootUser"`
+	// RequiredLabels are the labels that the final stage must declare.
+	RequiredLabels []string `mapstructure:"requiredLabels"`
+	// LabelPattern is a regular expression that all label keys must match
+	// (e.g. ^org\.opencontainers\.image\.).
+	LabelPattern string `mapstructure:"labelPattern"`
+
+	dockerfiles []*File
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (p *Dockerfile) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	var g *git.Git
+	if g, err = policy.NewGit(options); err != nil {
+		return report, errors.Errorf("failed to open git repo: %v", err)
+	}
+
+	var files []string
+	if files, err = policy.TrackedFiles(options.Tree, g); err != nil {
+		return report, errors.Errorf("failed to list tracked files: %v", err)
+	}
+	files = options.Ignore.Filter(files)
+
+	paths := p.Paths
+	if len(paths) == 0 {
+		paths = DefaultPaths
+	}
+	p.dockerfiles = nil
+	for _, file := range files {
+		if !git.MatchAny(paths, file) {
+			continue
+		}
+		var d *File
+		if d, err = Parse(options.Tree, file); err != nil {
+			return report, errors.Errorf("failed to parse %s: %v", file, err)
+		}
+		p.dockerfiles = append(p.dockerfiles, d)
+	}
+
+	if p.RequireDigest {
+		report.AddCheck(p.ValidateDigest())
+	}
+
+	if len(p.ForbiddenImages) != 0 {
+		report.AddCheck(p.ValidateForbiddenImages())
+	}
+
+	if p.RequireNonRootUser {
+		report.AddCheck(p.ValidateUser())
+	}
+
+	if len(p.RequiredLabels) != 0 || p.LabelPattern != "" {
+		report.AddCheck(p.ValidateLabels())
+	}
+
+	return report, nil
+}
+
+// Validate implements the policy.Validator.Validate function.
+func (p *Dockerfile) Validate() []error {
+	errs := policy.ValidateRegexps("image pattern", p.ForbiddenImages...)
+	if p.LabelPattern != "" {
+		errs = append(errs, policy.ValidateRegexps("label pattern", p.LabelPattern)...)
+	}
+
+	return errs
+}
+
+// PathPatterns implements the policy.PathMatcher.PathPatterns function.
+func (p *Dockerfile) PathPatterns() []string {
+	return p.Paths
+}
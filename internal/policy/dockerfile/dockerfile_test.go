@@ -0,0 +1,180 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package dockerfile
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestParse(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.Chdir(wd)
+
+	contents := `ARG VERSION=1.12
+# The build stage.
+FROM --platform=linux/amd64 golang:${VERSION} AS build
+RUN go build \
+    ./...
+
+FROM alpine:3.9
+LABEL org.opencontainers.image.title="conform" maintainer=autonomy
+LABEL description "Policy enforcement"
+COPY --from=build /conform /conform
+USER nobody
+`
+	if err = ioutil.WriteFile("Dockerfile", []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := Parse(nil, "Dockerfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Stages) != 2 {
+		t.Fatalf("Expected 2 stages, got %d", len(d.Stages))
+	}
+	build := d.Stages[0]
+	if build.Image != "golang:1.12" || build.Name != "build" || build.Line != 3 {
+		t.Errorf("Unexpected build stage %+v", build)
+	}
+	if len(build.Instructions) != 1 || build.Instructions[0].Line != 4 || len(build.Instructions[0].Args) != 3 {
+		t.Errorf("Expected the continued RUN instruction on line 4, got %+v", build.Instructions)
+	}
+	if !d.IsStage("BUILD") || d.IsStage("alpine") {
+		t.Errorf("Expected only build to be a stage")
+	}
+	final := d.Final()
+	if final.Image != "alpine:3.9" || final.Line != 7 {
+		t.Errorf("Unexpected final stage %+v", final)
+	}
+	labels := final.Labels()
+	expected := map[string]string{
+		"org.opencontainers.image.title": "conform",
+		"maintainer":                     "autonomy",
+		"description":                    "Policy enforcement",
+	}
+	if len(labels) != len(expected) {
+		t.Errorf("Expected the labels %v, got %v", expected, labels)
+	}
+	for k, v := range expected {
+		if labels[k] != v {
+			t.Errorf("Expected the label %s to be %q, got %q", k, v, labels[k])
+		}
+	}
+}
+
+func TestDockerfileChecks(t *testing.T) {
+	stage := func(image, name string, instructions ...Instruction) *Stage {
+		return &Stage{Image: image, Name: name, Line: 1, Instructions: instructions}
+	}
+	user := func(u string) Instruction {
+		return Instruction{Command: "USER", Args: []string{u}}
+	}
+	label := func(kv string) Instruction {
+		return Instruction{Command: "LABEL", Args: []string{kv}}
+	}
+
+	for _, test := range []struct {
+		Name       string
+		Dockerfile Dockerfile
+		Stages     []*Stage
+		Validate   func(Dockerfile) policy.Check
+		Errors     int
+	}{
+		{
+			Name:     "Digest",
+			Stages:   []*Stage{stage("alpine@sha256:0123", "")},
+			Validate: Dockerfile.ValidateDigest,
+		},
+		{
+			Name:     "No digest",
+			Stages:   []*Stage{stage("golang:1.12", "build"), stage("alpine:3.9", "")},
+			Validate: Dockerfile.ValidateDigest,
+			Errors:   2,
+		},
+		{
+			Name:     "Digest ignores stages and scratch",
+			Stages:   []*Stage{stage("golang@sha256:0123", "build"), stage("build", ""), stage("scratch", ""), stage("${IMAGE}", "")},
+			Validate: Dockerfile.ValidateDigest,
+		},
+		{
+			Name:       "Allowed image",
+			Dockerfile: Dockerfile{ForbiddenImages: []string{`^ubuntu`}},
+			Stages:     []*Stage{stage("alpine:3.9", "")},
+			Validate:   Dockerfile.ValidateForbiddenImages,
+		},
+		{
+			Name:       "Forbidden image",
+			Dockerfile: Dockerfile{ForbiddenImages: []string{`^ubuntu`}},
+			Stages:     []*Stage{stage("ubuntu:18.04", "")},
+			Validate:   Dockerfile.ValidateForbiddenImages,
+			Errors:     1,
+		},
+		{
+			Name:       "Forbidden stage name",
+			Dockerfile: Dockerfile{ForbiddenImages: []string{`^ubuntu`}},
+			Stages:     []*Stage{stage("alpine:3.9", "ubuntu"), stage("ubuntu", "")},
+			Validate:   Dockerfile.ValidateForbiddenImages,
+		},
+		{
+			Name:     "Non root user",
+			Stages:   []*Stage{stage("alpine:3.9", "", user("nobody:nogroup"))},
+			Validate: Dockerfile.ValidateUser,
+		},
+		{
+			Name:     "Root user",
+			Stages:   []*Stage{stage("alpine:3.9", "", user("nobody"), user("0:0"))},
+			Validate: Dockerfile.ValidateUser,
+			Errors:   1,
+		},
+		{
+			Name:     "No user",
+			Stages:   []*Stage{stage("golang:1.12", "build", user("nobody")), stage("alpine:3.9", "")},
+			Validate: Dockerfile.ValidateUser,
+			Errors:   1,
+		},
+		{
+			Name:       "Required labels",
+			Dockerfile: Dockerfile{RequiredLabels: []string{"maintainer", "version"}},
+			Stages:     []*Stage{stage("alpine:3.9", "", label("maintainer=autonomy"))},
+			Validate:   Dockerfile.ValidateLabels,
+			Errors:     1,
+		},
+		{
+			Name:       "Label pattern",
+			Dockerfile: Dockerfile{LabelPattern: `^org\.opencontainers\.`},
+			Stages:     []*Stage{stage("alpine:3.9", "", label("org.opencontainers.image.title=conform"), label("maintainer=autonomy"))},
+			Validate:   Dockerfile.ValidateLabels,
+			Errors:     1,
+		},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			p := test.Dockerfile
+			p.dockerfiles = []*File{{Path: "Dockerfile", Stages: test.Stages}}
+			if errs := test.Validate(p).Errors(); len(errs) != test.Errors {
+				tt.Errorf("Expected %d errors, got %v", test.Errors, errs)
+			}
+		})
+	}
+}
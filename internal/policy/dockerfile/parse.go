@@ -0,0 +1,177 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package dockerfile
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+)
+
+// Instruction is a single instruction of a Dockerfile.
+type Instruction struct {
+	// Command is the upper case name of the instruction (e.g. FROM).
+	Command string
+	// Args are the whitespace separated arguments of the instruction.
+	Args []string
+	// Line is the 1-indexed line number the instruction starts on.
+	Line int
+}
+
+// Stage is a build stage of a Dockerfile.
+type Stage struct {
+	// Image is the base image of the stage, with build arguments declared
+	// before the first FROM substituted.
+	Image string
+	// Name is the name of the stage given by "AS", if any.
+	Name string
+	// Line is the line number of the FROM instruction.
+	Line int
+	// Instructions are the instructions of the stage, excluding FROM.
+	Instructions []Instruction
+}
+
+// File is a parsed Dockerfile.
+type File struct {
+	Path   string
+	Stages []*Stage
+}
+
+// Parse reads and parses the Dockerfile at the specified path, from the tree
+// if it is not nil.
+func Parse(tree *git.Tree, name string) (*File, error) {
+	f, err := policy.Open(tree, name)
+	if err != nil {
+		return nil, err
+	}
+	// nolint: errcheck
+	defer f.Close()
+
+	instructions, err := instructions(f)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &File{Path: name}
+	args := map[string]string{}
+	var stage *Stage
+	for _, i := range instructions {
+		switch {
+		case i.Command == "ARG" && stage == nil:
+			for _, arg := range i.Args {
+				kv := strings.SplitN(arg, "=", 2)
+				if len(kv) == 2 {
+					args[kv[0]] = strings.Trim(kv[1], "\"'")
+				}
+			}
+		case i.Command == "FROM":
+			stage = &Stage{Line: i.Line}
+			var positional []string
+			for _, arg := range i.Args {
+				if !strings.HasPrefix(arg, "--") {
+					positional = append(positional, arg)
+				}
+			}
+			if len(positional) > 0 {
+				stage.Image = os.Expand(positional[0], func(key string) string {
+					if v, ok := args[key]; ok {
+						return v
+					}
+					return "${" + key + "}"
+				})
+			}
+			if len(positional) == 3 && strings.EqualFold(positional[1], "AS") {
+				stage.Name = positional[2]
+			}
+			d.Stages = append(d.Stages, stage)
+		case stage != nil:
+			stage.Instructions = append(stage.Instructions, i)
+		}
+	}
+
+	return d, nil
+}
+
+func instructions(r io.Reader) (instructions []Instruction, err error) {
+	var (
+		current string
+		start   int
+	)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if current == "" && (line == "" || strings.HasPrefix(line, "#")) {
+			continue
+		}
+		if current == "" {
+			start = n
+		}
+		if strings.HasSuffix(line, "\\") {
+			current += strings.TrimSuffix(line, "\\") + " "
+			continue
+		}
+		current += line
+		fields := strings.Fields(current)
+		current = ""
+		if len(fields) == 0 {
+			continue
+		}
+		instructions = append(instructions, Instruction{
+			Command: strings.ToUpper(fields[0]),
+			Args:    fields[1:],
+			Line:    start,
+		})
+	}
+
+	return instructions, scanner.Err()
+}
+
+// IsStage reports whether the image refers to a previous stage of the
+// Dockerfile rather than an image.
+func (d *File) IsStage(image string) bool {
+	for _, s := range d.Stages {
+		if s.Name != "" && strings.EqualFold(s.Name, image) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Final returns the last stage of the Dockerfile, which produces the image.
+func (d *File) Final() *Stage {
+	if len(d.Stages) == 0 {
+		return nil
+	}
+
+	return d.Stages[len(d.Stages)-1]
+}
+
+// Labels returns the labels declared by LABEL instructions of the stage.
+func (s *Stage) Labels() map[string]string {
+	labels := map[string]string{}
+	for _, i := range s.Instructions {
+		if i.Command != "LABEL" || len(i.Args) == 0 {
+			continue
+		}
+		if !strings.Contains(i.Args[0], "=") {
+			// Legacy form: LABEL <key> <value>
+			labels[strings.Trim(i.Args[0], "\"")] = strings.Trim(strings.Join(i.Args[1:], " "), "\"")
+			continue
+		}
+		for _, arg := range i.Args {
+			kv := strings.SplitN(arg, "=", 2)
+			if len(kv) == 2 {
+				labels[strings.Trim(kv[0], "\"")] = strings.Trim(kv[1], "\"")
+			}
+		}
+	}
+
+	return labels
+}
@@ -0,0 +1,145 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package eol
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+const (
+	// LF is a Unix style line ending.
+	LF = "lf"
+	// CRLF is a Windows style line ending.
+	CRLF = "crlf"
+)
+
+// EOL implements the policy.Policy interface and enforces the line endings of
+// tracked text files.
+type EOL struct {
+	// SkipPaths are the patterns of the paths of the files that shouldn't
+	// be checked, as the skipPaths of policy.Files.
+	SkipPaths []string `mapstructure:"skipPaths"`
+	// CRLF applies fnmatch-style patterns to the base name of files that must
+	// use CRLF line endings (e.g. *.bat). All other files must use LF.
+	CRLF []string `mapstructure:"crlf"`
+
+	files      []string
+	attributes *git.Attributes
+	tree       *git.Tree
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (e *EOL) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	var g *git.Git
+	if g, err = policy.NewGit(options); err != nil {
+		return report, errors.Errorf("failed to open git repo: %v", err)
+	}
+
+	if e.files, err = policy.TrackedFiles(options.Tree, g); err != nil {
+		return report, errors.Errorf("failed to list tracked files: %v", err)
+	}
+	e.files = options.Ignore.Filter(e.files)
+	e.tree = options.Tree
+
+	if e.attributes, err = policy.ReadAttributes(options.Tree, ".gitattributes"); err != nil {
+		return report, errors.Errorf("failed to read .gitattributes: %v", err)
+	}
+
+	report.AddCheck(e.ValidateLineEndings())
+
+	return report, nil
+}
+
+// PathPatterns implements the policy.PathMatcher.PathPatterns function.
+func (e *EOL) PathPatterns() []string {
+	return e.SkipPaths
+}
+
+// LineEndingCheck enforces the line endings of text files.
+type LineEndingCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (l LineEndingCheck) Name() string {
+	return "Line Endings"
+}
+
+// Message returns to check message.
+func (l LineEndingCheck) Message() string {
+	if len(l.errors) != 0 {
+		return fmt.Sprintf("Found %d files with invalid line endings", len(l.errors))
+	}
+	return "All files have valid line endings"
+}
+
+// Errors returns any violations of the check.
+func (l LineEndingCheck) Errors() []error {
+	return l.errors
+}
+
+// ValidateLineEndings checks that each tracked text file uses the expected
+// line endings, and that the expected line endings agree with any eol
+// attribute declared in .gitattributes.
+func (e EOL) ValidateLineEndings() policy.Check {
+	check := &LineEndingCheck{}
+
+	for _, file := range e.files {
+		if git.MatchAny(e.SkipPaths, file) {
+			continue
+		}
+
+		attrs := e.attributes.Get(file)
+		if attrs["text"] == git.AttributeUnset {
+			continue
+		}
+
+		expected := e.expected(file)
+		if eol, ok := attrs["eol"]; ok && eol != expected {
+			check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("File %s is declared eol=%s in .gitattributes, but policy requires %s", file, eol, expected)))
+			continue
+		}
+
+		contents, err := policy.ReadFile(e.tree, file)
+		if err != nil {
+			check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("Failed to open %s", file)))
+			continue
+		}
+		if git.IsBinary(contents) {
+			continue
+		}
+
+		crlf := bytes.Count(contents, []byte("\r\n"))
+		lf := bytes.Count(contents, []byte("\n")) - crlf
+		switch {
+		case expected == LF && crlf != 0:
+			check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("File %s has %d CRLF line endings", file, crlf)))
+		case expected == CRLF && lf != 0:
+			check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("File %s has %d LF line endings", file, lf)))
+		}
+	}
+
+	return check
+}
+
+func (e EOL) expected(file string) string {
+	for _, pattern := range e.CRLF {
+		if matches, err := path.Match(pattern, path.Base(file)); err == nil && matches {
+			return CRLF
+		}
+	}
+
+	return LF
+}
@@ -0,0 +1,74 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package eol
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/autonomy/conform/internal/git"
+)
+
+func TestValidateLineEndings(t *testing.T) {
+	for _, test := range []struct {
+		Name       string
+		File       string
+		Contents   string
+		Attributes string
+		Errors     int
+	}{
+		{Name: "LF", File: "a.txt", Contents: "a\nb\n"},
+		{Name: "CRLF", File: "a.txt", Contents: "a\r\nb\n", Errors: 1},
+		{Name: "CRLF pattern", File: "run.bat", Contents: "a\r\nb\r\n"},
+		{Name: "LF in CRLF pattern", File: "run.bat", Contents: "a\r\nb\n", Errors: 1},
+		{Name: "Binary", File: "a.bin", Contents: "a\r\n\x00"},
+		{Name: "Skipped", File: "vendor/a.txt", Contents: "a\r\n"},
+		{Name: "Not text", File: "a.txt", Contents: "a\r\n", Attributes: "*.txt -text\n"},
+		{Name: "Declared eol", File: "a.txt", Contents: "a\n", Attributes: "*.txt eol=crlf\n", Errors: 1},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			dir, err := ioutil.TempDir("", "conform")
+			if err != nil {
+				tt.Fatal(err)
+			}
+			// nolint: errcheck
+			defer os.RemoveAll(dir)
+			wd, err := os.Getwd()
+			if err != nil {
+				tt.Fatal(err)
+			}
+			if err = os.Chdir(dir); err != nil {
+				tt.Fatal(err)
+			}
+			// nolint: errcheck
+			defer os.Chdir(wd)
+
+			if err = os.MkdirAll(filepath.Dir(test.File), 0755); err != nil {
+				tt.Fatal(err)
+			}
+			if err = ioutil.WriteFile(test.File, []byte(test.Contents), 0644); err != nil {
+				tt.Fatal(err)
+			}
+			attributes, err := git.ParseAttributes(strings.NewReader(test.Attributes))
+			if err != nil {
+				tt.Fatal(err)
+			}
+			e := EOL{
+				SkipPaths:  []string{"vendor/"},
+				CRLF:       []string{"*.bat"},
+				files:      []string{test.File},
+				attributes: attributes,
+			}
+			if errs := e.ValidateLineEndings().Errors(); len(errs) != test.Errors {
+				tt.Errorf("Expected %d errors, got %v", test.Errors, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package exec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os/exec"
+	"strings"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/autonomy/conform/internal/policy/input"
+	"github.com/pkg/errors"
+)
+
+// Exec implements the policy.Policy interface and runs an external command.
+// The input.Input of the changes being enforced is written to the standard
+// input of the command as JSON, and the command writes a Result to its
+// standard output as JSON.
+type Exec struct {
+	// Name is the name of the check. Defaults to "Exec".
+	Name string `mapstructure:"name"`
+	// Command is the command to run.
+	Command string `mapstructure:"command"`
+	// Args are the arguments passed to the command.
+	Args []string `mapstructure:"args"`
+
+	input *input.Input
+	ctx   context.Context
+}
+
+// Result is the JSON result written by the command.
+type Result struct {
+	// Pass reports whether the check passed. A result with errors never
+	// passes.
+	Pass bool `json:"pass"`
+	// Message is the check message.
+	Message string `json:"message"`
+	// Errors are the violations found by the command.
+	Errors []string `json:"errors"`
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (e *Exec) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	if e.input, err = input.New(options); err != nil {
+		return report, err
+	}
+	e.ctx = options.Context
+
+	report.AddCheck(e.ValidateExec())
+
+	return report, nil
+}
+
+// ExecCheck reports the result of the command.
+type ExecCheck struct {
+	name    string
+	message string
+	errors  []error
+}
+
+// Name returns the name of the check.
+func (e ExecCheck) Name() string {
+	return e.name
+}
+
+// Message returns to check message.
+func (e ExecCheck) Message() string {
+	return e.message
+}
+
+// Errors returns any violations of the check.
+func (e ExecCheck) Errors() []error {
+	return e.errors
+}
+
+// ValidateExec runs the command and converts its result to a check.
+func (e Exec) ValidateExec() policy.Check {
+	check := &ExecCheck{name: e.Name}
+	if check.name == "" {
+		check.name = "Exec"
+	}
+
+	result, err := e.run()
+	if err != nil {
+		check.message = "Failed to run " + e.Command
+		check.errors = append(check.errors, errors.Errorf("Failed to run %s: %v", e.Command, err))
+		return check
+	}
+
+	check.message = result.Message
+	check.errors = result.Violations(e.Command + " failed")
+
+	return check
+}
+
+func (e Exec) run() (*Result, error) {
+	data, err := json.Marshal(e.input)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := policy.Command(e.ctx, e.Command, e.Args...)
+	cmd.Stdin = bytes.NewReader(data)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+	if _, ok := runErr.(*exec.ExitError); runErr != nil && !ok {
+		return nil, runErr
+	}
+
+	return ParseResult(stdout.Bytes(), stderr.Bytes(), runErr)
+}
+
+// ParseResult parses the result written to stdout by a command that exited
+// with exitErr. A command may exit with a non-zero status to fail the check,
+// as long as it writes a result.
+func ParseResult(stdout, stderr []byte, exitErr error) (*Result, error) {
+	result := &Result{}
+	if err := json.Unmarshal(stdout, result); err != nil {
+		if exitErr != nil {
+			return nil, errors.Errorf("%v: %s", exitErr, strings.TrimSpace(string(stderr)))
+		}
+		return nil, errors.Errorf("invalid result: %v", err)
+	}
+	if exitErr != nil {
+		result.Pass = false
+	}
+
+	return result, nil
+}
+
+// Violations returns the errors of the result. A failed result without errors
+// is reported with its message, or with msg if the message is empty.
+func (r *Result) Violations(msg string) []error {
+	var errs []error
+	for _, e := range r.Errors {
+		errs = append(errs, errors.New(e))
+	}
+	if !r.Pass && len(errs) == 0 {
+		if r.Message != "" {
+			msg = r.Message
+		}
+		errs = append(errs, errors.New(msg))
+	}
+
+	return errs
+}
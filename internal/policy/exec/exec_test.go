@@ -0,0 +1,39 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package exec
+
+import (
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy/input"
+)
+
+func TestValidateExec(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Script   string
+		Expected int
+	}{
+		{"Pass", `cat >/dev/null; echo '{"pass": true, "message": "ok"}'`, 0},
+		{"Errors", `cat >/dev/null; echo '{"pass": false, "errors": ["a", "b"]}'`, 2},
+		{"Fail", `cat >/dev/null; echo '{"pass": false, "message": "failed"}'`, 1},
+		{"Exit Status", `cat >/dev/null; echo '{"pass": true}'; exit 1`, 1},
+		{"Input", `grep -q '"header":"feat: a"' && echo '{"pass": true}'`, 0},
+		{"Invalid", `echo oops`, 1},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			e := Exec{
+				Command: "sh",
+				Args:    []string{"-c", test.Script},
+				input:   &input.Input{Commit: input.Commit{Header: "feat: a"}},
+			}
+			if errs := e.ValidateExec().Errors(); len(errs) != test.Expected {
+				tt.Errorf("Expected %d errors, got %v", test.Expected, errs)
+			}
+		})
+	}
+}
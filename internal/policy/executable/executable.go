@@ -0,0 +1,174 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package executable
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// DefaultNonExecutableSuffixes is the default set of suffixes of files that
+// must not have the executable bit set.
+var DefaultNonExecutableSuffixes = []string{".go", ".md", ".json", ".toml", ".yaml", ".yml"}
+
+// Executable implements the policy.Policy interface and enforces the
+// executable bit of tracked files.
+type Executable struct {
+	// SkipPaths are the patterns of the paths of the files that shouldn't
+	// be checked, as the skipPaths of policy.Files.
+	SkipPaths []string `mapstructure:"skipPaths"`
+	// Shebang enforces that files beginning with a shebang are executable.
+	Shebang bool `mapstructure:"shebang"`
+	// NonExecutableSuffixes are the suffixes of files that must not be
+	// executable. Defaults to DefaultNonExecutableSuffixes.
+	NonExecutableSuffixes []string `mapstructure:"nonExecutableSuffixes"`
+
+	modes map[string]os.FileMode
+	tree  *git.Tree
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (e *Executable) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	var g *git.Git
+	if g, err = policy.NewGit(options); err != nil {
+		return report, errors.Errorf("failed to open git repo: %v", err)
+	}
+
+	if e.modes, err = policy.TrackedFileModes(options.Tree, g); err != nil {
+		return report, errors.Errorf("failed to list tracked files: %v", err)
+	}
+	e.modes = options.Ignore.FilterModes(e.modes)
+	e.tree = options.Tree
+
+	report.AddCheck(e.ValidateExecutableBit())
+
+	return report, nil
+}
+
+// PathPatterns implements the policy.PathMatcher.PathPatterns function.
+func (e *Executable) PathPatterns() []string {
+	return e.SkipPaths
+}
+
+// ExecutableBitCheck enforces the executable bit of files.
+type ExecutableBitCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (e ExecutableBitCheck) Name() string {
+	return "Executable Bit"
+}
+
+// Message returns to check message.
+func (e ExecutableBitCheck) Message() string {
+	if len(e.errors) != 0 {
+		return fmt.Sprintf("Found %d files with an invalid mode", len(e.errors))
+	}
+	return "All files have a valid mode"
+}
+
+// Errors returns any violations of the check.
+func (e ExecutableBitCheck) Errors() []error {
+	return e.errors
+}
+
+// ValidateExecutableBit checks that scripts are executable, and that files
+// with non executable suffixes are not.
+func (e Executable) ValidateExecutableBit() policy.Check {
+	check := &ExecutableBitCheck{}
+
+	suffixes := e.NonExecutableSuffixes
+	if suffixes == nil {
+		suffixes = DefaultNonExecutableSuffixes
+	}
+
+	files := make([]string, 0, len(e.modes))
+	for file := range e.modes {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		mode := e.modes[file]
+		if !mode.IsRegular() || git.MatchAny(e.SkipPaths, file) {
+			continue
+		}
+		if IsExecutable(mode) {
+			for _, suffix := range suffixes {
+				if strings.HasSuffix(file, suffix) {
+					check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("File %s must not be executable", file)))
+					break
+				}
+			}
+			continue
+		}
+
+		if e.Shebang {
+			ok, err := HasShebang(e.tree, file)
+			if err != nil {
+				check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("Failed to open %s", file)))
+				continue
+			}
+			if err = ModeViolation(file, mode, ok, false); err != nil {
+				check.errors = append(check.errors, err)
+			}
+		}
+	}
+
+	return check
+}
+
+// IsExecutable reports whether the executable bit of the mode is set.
+func IsExecutable(mode os.FileMode) bool {
+	return mode&0111 != 0
+}
+
+// ModeViolation returns the violation of the file if its executable bit
+// disagrees with its shebang: a file with a shebang must be executable, and,
+// if requireShebang, an executable file must have a shebang.
+func ModeViolation(file string, mode os.FileMode, shebang, requireShebang bool) error {
+	switch {
+	case shebang && !IsExecutable(mode):
+		return policy.FileError(file, 0, errors.Errorf("File %s has a shebang but is not executable", file))
+	case !shebang && IsExecutable(mode) && requireShebang:
+		return policy.FileError(file, 0, errors.Errorf("File %s is executable but does not have a shebang", file))
+	}
+
+	return nil
+}
+
+// HasShebang reports whether the file, read from the tree if it is not nil,
+// begins with "#!".
+func HasShebang(t *git.Tree, name string) (bool, error) {
+	f, err := policy.Open(t, name)
+	if err != nil {
+		return false, err
+	}
+	// nolint: errcheck
+	defer f.Close()
+
+	buf := make([]byte, 2)
+	if _, err = io.ReadFull(f, buf); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return bytes.Equal(buf, []byte("#!")), nil
+}
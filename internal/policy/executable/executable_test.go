@@ -0,0 +1,93 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package executable
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateExecutableBit(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		File     string
+		Contents string
+		Mode     os.FileMode
+		Shebang  bool
+		Errors   int
+	}{
+		{Name: "Script", File: "run.sh", Contents: "#!/bin/sh\n", Mode: 0755, Shebang: true},
+		{Name: "Executable source", File: "main.go", Contents: "package main\n", Mode: 0755, Errors: 1},
+		{Name: "Source", File: "main.go", Contents: "package main\n", Mode: 0644},
+		{Name: "Skipped", File: "vendor/main.go", Contents: "package main\n", Mode: 0755},
+		{Name: "Script not executable", File: "run.sh", Contents: "#!/bin/sh\n", Mode: 0644, Shebang: true, Errors: 1},
+		{Name: "Shebang not checked", File: "run.sh", Contents: "#!/bin/sh\n", Mode: 0644},
+		{Name: "Empty", File: "run", Contents: "", Mode: 0644, Shebang: true},
+		{Name: "Symlink", File: "link", Mode: os.ModeSymlink | 0777, Shebang: true},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			dir, err := ioutil.TempDir("", "conform")
+			if err != nil {
+				tt.Fatal(err)
+			}
+			// nolint: errcheck
+			defer os.RemoveAll(dir)
+			wd, err := os.Getwd()
+			if err != nil {
+				tt.Fatal(err)
+			}
+			if err = os.Chdir(dir); err != nil {
+				tt.Fatal(err)
+			}
+			// nolint: errcheck
+			defer os.Chdir(wd)
+
+			if test.Mode.IsRegular() {
+				if err = os.MkdirAll(filepath.Dir(test.File), 0755); err != nil {
+					tt.Fatal(err)
+				}
+				if err = ioutil.WriteFile(test.File, []byte(test.Contents), 0644); err != nil {
+					tt.Fatal(err)
+				}
+			}
+			e := Executable{
+				SkipPaths: []string{"vendor/"},
+				Shebang:   test.Shebang,
+				modes:     map[string]os.FileMode{test.File: test.Mode},
+			}
+			if errs := e.ValidateExecutableBit().Errors(); len(errs) != test.Errors {
+				tt.Errorf("Expected %d errors, got %v", test.Errors, errs)
+			}
+		})
+	}
+}
+
+func TestModeViolation(t *testing.T) {
+	for _, test := range []struct {
+		Name           string
+		Mode           os.FileMode
+		Shebang        bool
+		RequireShebang bool
+		Violation      bool
+	}{
+		{"Script", 0755, true, true, false},
+		{"Script not executable", 0644, true, false, true},
+		{"Executable without shebang", 0755, false, true, true},
+		{"Executable without shebang allowed", 0755, false, false, false},
+		{"Regular file", 0644, false, true, false},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			if err := ModeViolation("a", test.Mode, test.Shebang, test.RequireShebang); (err != nil) != test.Violation {
+				tt.Errorf("Expected a violation %v, got %v", test.Violation, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package filename
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// CaseConflictCheck ensures that no two tracked paths differ only by case.
+// Such paths cannot be checked out on case-insensitive filesystems like the
+// defaults on macOS and Windows.
+type CaseConflictCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (c CaseConflictCheck) Name() string {
+	return "Case Conflict"
+}
+
+// Message returns to check message.
+func (c CaseConflictCheck) Message() string {
+	if len(c.errors) != 0 {
+		return fmt.Sprintf("Found %d paths that conflict by case", len(c.errors))
+	}
+	return "No paths conflict by case"
+}
+
+// Errors returns any violations of the check.
+func (c CaseConflictCheck) Errors() []error {
+	return c.errors
+}
+
+// ValidateCaseConflicts checks the tracked paths for any that differ only by
+// case. Directories are considered as well, since "Foo/a" and "foo/b" would
+// be merged into a single directory on checkout.
+func (f Filename) ValidateCaseConflicts() policy.Check {
+	check := &CaseConflictCheck{}
+
+	seen := map[string]string{}
+	conflicts := map[string]string{}
+	for _, file := range f.files {
+		for p := file; p != "." && p != "/" && p != ""; p = path.Dir(p) {
+			key := strings.ToLower(p)
+			original, ok := seen[key]
+			if !ok {
+				seen[key] = p
+				continue
+			}
+			if original != p {
+				conflicts[p] = original
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(conflicts))
+	for p := range conflicts {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		check.errors = append(check.errors, policy.FileError(p, 0, errors.Errorf("Path %s conflicts with %s", p, conflicts[p])))
+	}
+
+	return check
+}
@@ -0,0 +1,50 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package filename
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// PathDepthCheck ensures that tracked files are not nested too deeply.
+type PathDepthCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (p PathDepthCheck) Name() string {
+	return "Path Depth"
+}
+
+// Message returns to check message.
+func (p PathDepthCheck) Message() string {
+	if len(p.errors) != 0 {
+		return fmt.Sprintf("Found %d paths that are nested too deeply", len(p.errors))
+	}
+	return "All paths are within the maximum depth"
+}
+
+// Errors returns any violations of the check.
+func (p PathDepthCheck) Errors() []error {
+	return p.errors
+}
+
+// ValidatePathDepth checks the number of directories each tracked file is
+// nested in. Files at the root of the repository have a depth of zero.
+func (f Filename) ValidatePathDepth() policy.Check {
+	check := &PathDepthCheck{}
+
+	for _, file := range f.files {
+		if depth := strings.Count(file, "/"); depth > f.MaximumDepth {
+			check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("Path %s has a depth of %d, maximum is %d", file, depth, f.MaximumDepth)))
+		}
+	}
+
+	return check
+}
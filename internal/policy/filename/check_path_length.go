@@ -0,0 +1,51 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package filename
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// PathLengthCheck ensures that tracked paths are not too long. Windows limits
+// paths to 260 characters by default, including the path of the checkout, so
+// the maximum should leave room for it.
+type PathLengthCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (p PathLengthCheck) Name() string {
+	return "Path Length"
+}
+
+// Message returns to check message.
+func (p PathLengthCheck) Message() string {
+	if len(p.errors) != 0 {
+		return fmt.Sprintf("Found %d paths that are too long", len(p.errors))
+	}
+	return "All paths are within the maximum length"
+}
+
+// Errors returns any violations of the check.
+func (p PathLengthCheck) Errors() []error {
+	return p.errors
+}
+
+// ValidatePathLength checks the number of characters of each tracked path.
+func (f Filename) ValidatePathLength() policy.Check {
+	check := &PathLengthCheck{}
+
+	for _, file := range f.files {
+		if length := utf8.RuneCountInString(file); length > f.MaximumPathLength {
+			check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("Path %s has %d characters, maximum is %d", file, length, f.MaximumPathLength)))
+		}
+	}
+
+	return check
+}
@@ -0,0 +1,58 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package filename
+
+import (
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// Filename implements the policy.Policy interface and enforces rules on the
+// paths of tracked files.
+type Filename struct {
+	// CaseConflicts enables the check that no two tracked paths differ only
+	// by case.
+	CaseConflicts bool `mapstructure:"caseConflicts"`
+	// MaximumPathLength is the maximum number of characters in a tracked
+	// path. Zero is unlimited.
+	MaximumPathLength int `mapstructure:"maximumPathLength"`
+	// MaximumDepth is the maximum number of directories a tracked file may be
+	// nested in. Zero is unlimited.
+	MaximumDepth int `mapstructure:"maximumDepth"`
+
+	files []string
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (f *Filename) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	var g *git.Git
+	if g, err = policy.NewGit(options); err != nil {
+		return report, errors.Errorf("failed to open git repo: %v", err)
+	}
+
+	if f.files, err = policy.TrackedFiles(options.Tree, g); err != nil {
+		return report, errors.Errorf("failed to list tracked files: %v", err)
+	}
+	f.files = options.Ignore.Filter(f.files)
+
+	if f.CaseConflicts {
+		report.AddCheck(f.ValidateCaseConflicts())
+	}
+
+	if f.MaximumPathLength != 0 {
+		report.AddCheck(f.ValidatePathLength())
+	}
+
+	if f.MaximumDepth != 0 {
+		report.AddCheck(f.ValidatePathDepth())
+	}
+
+	return report, nil
+}
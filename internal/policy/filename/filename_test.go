@@ -0,0 +1,101 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package filename
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestValidateCaseConflicts(t *testing.T) {
+	type testDesc struct {
+		Name        string
+		Files       []string
+		ExpectValid bool
+	}
+
+	for _, test := range []testDesc{
+		{
+			Name:        "No Conflicts",
+			Files:       []string{"README.md", "cmd/root.go", "cmd/version.go"},
+			ExpectValid: true,
+		},
+		{
+			Name:        "File Conflict",
+			Files:       []string{"README.md", "readme.md"},
+			ExpectValid: false,
+		},
+		{
+			Name:        "Directory Conflict",
+			Files:       []string{"Docs/a.md", "docs/b.md"},
+			ExpectValid: false,
+		},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			var report policy.Report
+			f := Filename{files: test.Files}
+			report.AddCheck(f.ValidateCaseConflicts())
+
+			if test.ExpectValid {
+				if !report.Valid() {
+					tt.Error("Report is invalid with no conflicting paths")
+				}
+			} else {
+				if report.Valid() {
+					tt.Error("Report is valid with conflicting paths")
+				}
+			}
+		})
+	}
+}
+
+func TestValidatePathLimits(t *testing.T) {
+	type testDesc struct {
+		Name        string
+		Files       []string
+		ExpectValid bool
+	}
+
+	for _, test := range []testDesc{
+		{
+			Name:        "Within Limits",
+			Files:       []string{"README.md", "a/b/c.go"},
+			ExpectValid: true,
+		},
+		{
+			Name:        "Too Long",
+			Files:       []string{"a/" + strings.Repeat("x", 20)},
+			ExpectValid: false,
+		},
+		{
+			Name:        "Too Deep",
+			Files:       []string{"a/b/c/d.go"},
+			ExpectValid: false,
+		},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			var report policy.Report
+			f := Filename{files: test.Files, MaximumPathLength: 20, MaximumDepth: 2}
+			report.AddCheck(f.ValidatePathLength())
+			report.AddCheck(f.ValidatePathDepth())
+
+			if test.ExpectValid {
+				if !report.Valid() {
+					tt.Error("Report is invalid with paths within the limits")
+				}
+			} else {
+				if report.Valid() {
+					tt.Error("Report is valid with paths exceeding the limits")
+				}
+			}
+		})
+	}
+}
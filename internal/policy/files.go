@@ -0,0 +1,166 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package policy
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/logging"
+	"github.com/autonomy/conform/internal/progress"
+)
+
+// Files defines the set of files a file based policy applies to. It is meant
+// to be embedded in a policy using the mapstructure squash tag.
+type Files struct {
+	// SkipPaths are gitignore style patterns of the paths to skip completely,
+	// the parts of the tree which shouldn't be scanned (e.g. vendor/). See
+	// git.MatchPattern for their syntax, which is that of the skipPaths of
+	// every policy.
+	SkipPaths []string `mapstructure:"skipPaths"`
+	// IncludeSuffixes is the regex used to find files that the policy should
+	// be applied to.
+	IncludeSuffixes []string `mapstructure:"includeSuffixes"`
+	// ExcludeSuffixes is the Suffixes used to find files that the policy
+	// should not be applied to.
+	ExcludeSuffixes []string `mapstructure:"excludeSuffixes"`
+}
+
+// WalkFunc is the type of the function called for each file selected by
+// Files.Walk.
+type WalkFunc func(path string, info os.FileInfo) error
+
+// Walk walks the current directory, except the git directory, and calls fn
+// for each regular file that is neither skipped nor ignored by the
+// .conformignore file, does not match an excluded suffix, and matches an
+// included suffix.
+func (f Files) Walk(options *Options, fn WalkFunc) error {
+	if options.Tree != nil {
+		return f.walkTree(options, fn)
+	}
+
+	return filepath.Walk(".", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err = options.Context.Err(); err != nil {
+			return err
+		}
+
+		// The git directory, or the .git file pointing to it in linked
+		// worktrees and submodules, is not part of the tree.
+		if info.Name() == ".git" {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if path != "." && git.MatchAny(f.SkipPaths, filepath.ToSlash(path)) {
+			logging.Debug("skipped path", "path", path)
+			if info.IsDir() {
+				// skip whole directory tree
+				return filepath.SkipDir
+			}
+			// skip single file
+			return nil
+		}
+
+		if path != "." && options.Ignore.MatchFile(path, info.IsDir()) {
+			logging.Debug("ignored path", "path", path)
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		if !f.Match(info.Name()) {
+			return nil
+		}
+		logging.Debug("selected file", "path", path)
+		progress.File()
+
+		return fn(path, info)
+	})
+}
+
+// walkTree calls fn for each file of the tree of the options under the
+// current directory that Walk would select in the working tree.
+func (f Files) walkTree(options *Options, fn WalkFunc) error {
+	dir := options.Tree.Rel(".")
+	for _, name := range options.Tree.Files() {
+		if err := options.Context.Err(); err != nil {
+			return err
+		}
+		p := name
+		if dir != "." {
+			if !strings.HasPrefix(name, dir+"/") {
+				continue
+			}
+			p = strings.TrimPrefix(name, dir+"/")
+		}
+		if !f.Selected(p) || options.Ignore.MatchFile(p, false) {
+			continue
+		}
+		info, err := options.Tree.Stat(p)
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		logging.Debug("selected file", "path", p, "tree", options.Tree.SHA)
+		progress.File()
+
+		if err = fn(filepath.FromSlash(p), info); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Match reports whether the file name matches an included suffix and none of
+// the excluded suffixes.
+func (f Files) Match(name string) bool {
+	// Skip excluded suffixes.
+	for _, suffix := range f.ExcludeSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return false
+		}
+	}
+	// Check files matching the included suffixes.
+	for _, suffix := range f.IncludeSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Suffixes implements the SuffixMatcher.Suffixes function.
+func (f Files) Suffixes() []string {
+	return f.IncludeSuffixes
+}
+
+// Selected reports whether the slash separated path relative to the root of
+// the repository would be selected by Walk. It is intended for policies that
+// operate on a list of paths, rather than on the working tree.
+func (f Files) Selected(p string) bool {
+	// A pattern matching a directory matches everything inside of it.
+	if git.MatchAny(f.SkipPaths, p) {
+		return false
+	}
+
+	return f.Match(path.Base(p))
+}
@@ -0,0 +1,37 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package policy
+
+import "testing"
+
+func TestSelected(t *testing.T) {
+	f := Files{
+		SkipPaths:       []string{"vendor/", "*.pb.go", "/docs/**", ".build*/"},
+		IncludeSuffixes: []string{".go", ".md"},
+		ExcludeSuffixes: []string{"_test.go"},
+	}
+
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{path: "main.go", expected: true},
+		{path: "cmd/root.go", expected: true},
+		{path: "main_test.go", expected: false},
+		{path: "main.c", expected: false},
+		{path: "vendor/a/a.go", expected: false},
+		{path: "third_party/vendor/a.go", expected: false},
+		{path: "api/v1/api.pb.go", expected: false},
+		{path: "docs/README.md", expected: false},
+		{path: "api/docs/README.md", expected: true},
+		{path: ".build-linux/main.go", expected: false},
+	}
+
+	for _, test := range tests {
+		if selected := f.Selected(test.path); selected != test.expected {
+			t.Errorf("Expected %s to be selected: %v, got %v", test.path, test.expected, selected)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package frontmatter
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/jsonschema"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Frontmatter implements the policy.Policy interface and validates the YAML
+// frontmatter of Markdown files.
+type Frontmatter struct {
+	// Paths are gitignore style patterns of the Markdown files to validate
+	// (e.g. content/, docs/**/*.md).
+	Paths []string `mapstructure:"paths"`
+	// Required are the keys that the frontmatter must declare.
+	Required []string `mapstructure:"required"`
+	// Formats map keys to regular expressions that their values must match.
+	Formats map[string]string `mapstructure:"formats"`
+	// Schema is the optional local path or HTTP(S) URL of a JSON Schema that
+	// the frontmatter must conform to.
+	Schema string `mapstructure:"schema"`
+
+	files []string
+	tree  *git.Tree
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (f *Frontmatter) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	var g *git.Git
+	if g, err = policy.NewGit(options); err != nil {
+		return report, errors.Errorf("failed to open git repo: %v", err)
+	}
+
+	var files []string
+	if files, err = policy.TrackedFiles(options.Tree, g); err != nil {
+		return report, errors.Errorf("failed to list tracked files: %v", err)
+	}
+	files = options.Ignore.Filter(files)
+
+	f.files = nil
+	f.tree = options.Tree
+	for _, file := range files {
+		ext := strings.ToLower(path.Ext(file))
+		if (ext == ".md" || ext == ".markdown") && git.MatchAny(f.Paths, file) {
+			f.files = append(f.files, file)
+		}
+	}
+
+	report.AddCheck(f.ValidateFrontmatter())
+
+	return report, nil
+}
+
+// Validate implements the policy.Validator.Validate function.
+func (f *Frontmatter) Validate() []error {
+	keys := make([]string, 0, len(f.Formats))
+	for key := range f.Formats {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		if _, err := regexp.Compile(f.Formats[key]); err != nil {
+			errs = append(errs, errors.Errorf("Invalid format for %s: %v", key, err))
+		}
+	}
+
+	return errs
+}
+
+// PathPatterns implements the policy.PathMatcher.PathPatterns function.
+func (f *Frontmatter) PathPatterns() []string {
+	return f.Paths
+}
+
+// FrontmatterCheck ensures that Markdown files declare valid frontmatter.
+type FrontmatterCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (f FrontmatterCheck) Name() string {
+	return "Frontmatter"
+}
+
+// Message returns to check message.
+func (f FrontmatterCheck) Message() string {
+	if len(f.errors) != 0 {
+		return fmt.Sprintf("Found %d frontmatter violations", len(f.errors))
+	}
+	return "All frontmatter is valid"
+}
+
+// Errors returns any violations of the check.
+func (f FrontmatterCheck) Errors() []error {
+	return f.errors
+}
+
+// ValidateFrontmatter checks the frontmatter of each file for the required
+// keys, value formats, and schema.
+// nolint: gocyclo
+func (f Frontmatter) ValidateFrontmatter() policy.Check {
+	check := &FrontmatterCheck{}
+
+	formats := map[string]*regexp.Regexp{}
+	for key, expr := range f.Formats {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			check.errors = append(check.errors, errors.Errorf("Invalid format for %s: %v", key, err))
+			return check
+		}
+		formats[key] = re
+	}
+
+	var schema *jsonschema.Schema
+	if f.Schema != "" {
+		var err error
+		if schema, err = jsonschema.Load(f.Schema); err != nil {
+			check.errors = append(check.errors, errors.Errorf("Failed to load schema %s: %v", f.Schema, err))
+			return check
+		}
+	}
+
+	keys := make([]string, 0, len(formats))
+	for key := range formats {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, file := range f.files {
+		contents, err := policy.ReadFile(f.tree, file)
+		if err != nil {
+			check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("Failed to read %s: %v", file, err)))
+			continue
+		}
+		data, ok := Extract(contents)
+		if !ok {
+			check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("File %s does not have frontmatter", file)))
+			continue
+		}
+		var doc map[string]interface{}
+		if err = yaml.Unmarshal(data, &doc); err != nil {
+			check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("File %s has invalid frontmatter: %v", file, err)))
+			continue
+		}
+
+		for _, key := range f.Required {
+			if _, ok := doc[key]; !ok {
+				check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("File %s is missing frontmatter key %s", file, key)))
+			}
+		}
+		for _, key := range keys {
+			value, ok := doc[key]
+			if !ok {
+				continue
+			}
+			if s := fmt.Sprint(value); !formats[key].MatchString(s) {
+				check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("File %s has invalid %s %q: must match %s", file, key, s, formats[key])))
+			}
+		}
+		if schema != nil {
+			for _, verr := range schema.Validate(jsonschema.Normalize(doc)) {
+				check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("File %s frontmatter does not conform to %s: %v", file, f.Schema, verr)))
+			}
+		}
+	}
+
+	return check
+}
+
+// Extract returns the YAML frontmatter delimited by "---" lines at the start
+// of the contents.
+func Extract(contents []byte) ([]byte, bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != "---" {
+		return nil, false
+	}
+
+	var data bytes.Buffer
+	for scanner.Scan() {
+		line := scanner.Text()
+		if trimmed := strings.TrimSpace(line); trimmed == "---" || trimmed == "..." {
+			return data.Bytes(), true
+		}
+		data.WriteString(line)
+		data.WriteString("\n")
+	}
+
+	return nil, false
+}
@@ -0,0 +1,31 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package frontmatter
+
+import "testing"
+
+func TestExtract(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Contents string
+		Expected string
+		OK       bool
+	}{
+		{"Valid", "---\ntitle: a\n---\n# A\n", "title: a\n", true},
+		{"Dots", "---\ntitle: a\n...\n", "title: a\n", true},
+		{"Empty", "---\n---\n", "", true},
+		{"Missing", "# A\n", "", false},
+		{"Unterminated", "---\ntitle: a\n", "", false},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			actual, ok := Extract([]byte(test.Contents))
+			if ok != test.OK || string(actual) != test.Expected {
+				tt.Errorf("Expected %q (%v), got %q (%v)", test.Expected, test.OK, actual, ok)
+			}
+		})
+	}
+}
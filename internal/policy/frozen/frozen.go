@@ -0,0 +1,129 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package frozen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// DefaultOverrideTrailer is the default trailer allowing a commit to modify
+// frozen paths.
+const DefaultOverrideTrailer = "Frozen-Override"
+
+// Frozen implements the policy.Policy interface and protects paths that must
+// not change once released, such as published API versions and applied
+// database migrations.
+type Frozen struct {
+	// Paths are gitignore style patterns of the frozen files (e.g. api/v1/**,
+	// migrations/0001_*.sql). Adding new files is allowed.
+	Paths []string `mapstructure:"paths"`
+	// OverrideTrailer is the commit message trailer that allows a commit to
+	// modify or delete frozen files, along with a reason (e.g.
+	// "Frozen-Override: fix typo in comment"). Defaults to
+	// DefaultOverrideTrailer.
+	OverrideTrailer string `mapstructure:"overrideTrailer"`
+
+	commits []*git.Commit
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (f *Frozen) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	var g *git.Git
+	if g, err = policy.NewGit(options); err != nil {
+		return report, errors.Errorf("failed to open git repo: %v", err)
+	}
+
+	var base string
+	if options.BaseBranch != nil {
+		base = *options.BaseBranch
+	}
+	if f.commits, err = g.Commits(base); err != nil {
+		return report, errors.Errorf("failed to get commits: %v", err)
+	}
+
+	report.AddCheck(f.ValidateFrozen())
+
+	return report, nil
+}
+
+// PathPatterns implements the policy.PathMatcher.PathPatterns function.
+func (f *Frozen) PathPatterns() []string {
+	return f.Paths
+}
+
+func (f Frozen) overrideTrailer() string {
+	if f.OverrideTrailer == "" {
+		return DefaultOverrideTrailer
+	}
+	return f.OverrideTrailer
+}
+
+// FrozenCheck ensures that frozen files are not modified.
+type FrozenCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (f FrozenCheck) Name() string {
+	return "Frozen Paths"
+}
+
+// Message returns to check message.
+func (f FrozenCheck) Message() string {
+	if len(f.errors) != 0 {
+		return fmt.Sprintf("Found %d changes to frozen paths", len(f.errors))
+	}
+	return "No frozen paths changed"
+}
+
+// Errors returns any violations of the check.
+func (f FrozenCheck) Errors() []error {
+	return f.errors
+}
+
+// ValidateFrozen checks that no commit modifies, renames, or deletes a frozen
+// file, unless its message has a non-empty override trailer.
+func (f Frozen) ValidateFrozen() policy.Check {
+	check := &FrozenCheck{}
+
+	token := strings.ToLower(f.overrideTrailer())
+	for _, commit := range f.commits {
+		overridden := false
+		for _, reason := range git.Trailers(commit.Message)[token] {
+			if strings.TrimSpace(reason) != "" {
+				overridden = true
+			}
+		}
+		if overridden {
+			continue
+		}
+
+		for _, diff := range commit.Diffs {
+			if diff.From == "" || !git.MatchAny(f.Paths, diff.From) {
+				continue
+			}
+			action := "modifies"
+			switch diff.To {
+			case "":
+				action = "deletes"
+			case diff.From:
+			default:
+				action = "renames"
+			}
+			check.errors = append(check.errors, policy.CommitError(commit.SHA, errors.Errorf("%s %s frozen path %s without a %s trailer", commit.SHA[:7], action, diff.From, f.overrideTrailer())))
+		}
+	}
+
+	return check
+}
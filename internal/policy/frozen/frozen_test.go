@@ -0,0 +1,42 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package frozen
+
+import (
+	"testing"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestValidateFrozen(t *testing.T) {
+	sha := "0123456789abcdef"
+	for _, test := range []struct {
+		Name     string
+		Commit   *git.Commit
+		Expected int
+	}{
+		{"Added", &git.Commit{SHA: sha, Message: "feat: add v2", Diffs: []*git.FileDiff{{To: "api/v1/new.proto"}, {To: "api/v2/api.proto"}}}, 0},
+		{"Modified", &git.Commit{SHA: sha, Message: "fix: typo", Diffs: []*git.FileDiff{{From: "api/v1/api.proto", To: "api/v1/api.proto"}}}, 1},
+		{"Deleted and renamed", &git.Commit{SHA: sha, Message: "chore: cleanup", Diffs: []*git.FileDiff{{From: "api/v1/api.proto"}, {From: "migrations/0001_init.sql", To: "migrations/init.sql"}}}, 2},
+		{"Overridden", &git.Commit{SHA: sha, Message: "fix: typo\n\nFrozen-Override: comment only\n", Diffs: []*git.FileDiff{{From: "api/v1/api.proto", To: "api/v1/api.proto"}}}, 0},
+		{"Empty override", &git.Commit{SHA: sha, Message: "fix: typo\n\nFrozen-Override:\n", Diffs: []*git.FileDiff{{From: "api/v1/api.proto", To: "api/v1/api.proto"}}}, 1},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			f := Frozen{Paths: []string{"api/v1/**", "migrations/0001_*.sql"}, commits: []*git.Commit{test.Commit}}
+			errs := f.ValidateFrozen().Errors()
+			if len(errs) != test.Expected {
+				tt.Errorf("Expected %d errors, got %v", test.Expected, errs)
+			}
+			for _, err := range errs {
+				if l, ok := policy.LocationOf(err); !ok || l.Commit != sha {
+					tt.Errorf("Expected the violation to be in commit %s, got %v", sha, l)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package generate
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// Generate implements the policy.Policy interface and enforces that generated
+// files are up to date.
+type Generate struct {
+	// Commands are the generator commands to run (e.g. go generate ./...).
+	// Each command is run with sh in a temporary worktree of HEAD.
+	Commands []string `mapstructure:"commands"`
+	// Paths are gitignore style patterns of the generated files. When empty,
+	// a change to any file is reported.
+	Paths []string `mapstructure:"paths"`
+
+	ctx context.Context
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (g *Generate) Compliance(options *policy.Options) (*policy.Report, error) {
+	report := &policy.Report{}
+
+	g.ctx = options.Context
+	report.AddCheck(g.ValidateGenerated())
+
+	return report, nil
+}
+
+// PathPatterns implements the policy.PathMatcher.PathPatterns function.
+func (g *Generate) PathPatterns() []string {
+	return g.Paths
+}
+
+// GeneratedCheck ensures that generated files are up to date.
+type GeneratedCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (g GeneratedCheck) Name() string {
+	return "Generated Code"
+}
+
+// Message returns to check message.
+func (g GeneratedCheck) Message() string {
+	if len(g.errors) != 0 {
+		return fmt.Sprintf("Found %d stale generated files", len(g.errors))
+	}
+	return "All generated files are up to date"
+}
+
+// Errors returns any violations of the check.
+func (g GeneratedCheck) Errors() []error {
+	return g.errors
+}
+
+// ValidateGenerated runs the generator commands in a temporary worktree of
+// HEAD, and reports each file that differs from what is committed.
+func (g Generate) ValidateGenerated() policy.Check {
+	check := &GeneratedCheck{}
+
+	dir, err := ioutil.TempDir("", "conform-generate")
+	if err != nil {
+		check.errors = append(check.errors, errors.Errorf("Failed to create temporary directory: %v", err))
+		return check
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+
+	worktree := dir + "/worktree"
+	if _, err = run(g.ctx, "", "git", "worktree", "add", "--detach", worktree, "HEAD"); err != nil {
+		check.errors = append(check.errors, errors.Errorf("Failed to create worktree: %v", err))
+		return check
+	}
+	// The worktree is removed even if the policy timed out.
+	// nolint: errcheck
+	defer run(context.Background(), "", "git", "worktree", "remove", "--force", worktree)
+
+	for _, command := range g.Commands {
+		if _, err = run(g.ctx, worktree, "sh", "-c", command); err != nil {
+			check.errors = append(check.errors, errors.Errorf("Command %q failed: %v", command, err))
+			return check
+		}
+	}
+
+	out, err := run(g.ctx, worktree, "git", "status", "--porcelain", "--untracked-files=all")
+	if err != nil {
+		check.errors = append(check.errors, errors.Errorf("Failed to get worktree status: %v", err))
+		return check
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) < 4 {
+			continue
+		}
+		status, file := strings.TrimSpace(line[:2]), line[3:]
+		if i := strings.Index(file, " -> "); i >= 0 {
+			file = file[i+len(" -> "):]
+		}
+		if len(g.Paths) != 0 && !git.MatchAny(g.Paths, file) {
+			continue
+		}
+		switch status {
+		case "??":
+			check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("File %s is generated but not committed", file)))
+		case "D":
+			check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("File %s is committed but no longer generated", file)))
+		default:
+			check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("File %s is out of date", file)))
+		}
+	}
+
+	return check
+}
+
+func run(ctx context.Context, dir, name string, args ...string) ([]byte, error) {
+	cmd := policy.Command(ctx, name, args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
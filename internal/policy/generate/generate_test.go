@@ -0,0 +1,69 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package generate
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestValidateGenerated(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.Chdir(wd)
+
+	for name, contents := range map[string]string{"gen.txt": "a\n", "old.txt": "old\n"} {
+		if err = ioutil.WriteFile(name, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "initial commit"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	for _, test := range []struct {
+		Name     string
+		Commands []string
+		Paths    []string
+		Errors   int
+	}{
+		{Name: "Up to date", Commands: []string{"echo a > gen.txt"}},
+		{Name: "Out of date", Commands: []string{"echo b > gen.txt"}, Errors: 1},
+		{Name: "Not committed", Commands: []string{"echo c > new.txt"}, Errors: 1},
+		{Name: "No longer generated", Commands: []string{"rm old.txt"}, Errors: 1},
+		{Name: "Commands", Commands: []string{"echo b > gen.txt", "echo c > new.txt"}, Errors: 2},
+		{Name: "Other paths", Commands: []string{"echo b > gen.txt"}, Paths: []string{"*.go"}},
+		{Name: "Failed command", Commands: []string{"exit 1", "echo b > gen.txt"}, Errors: 1},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			g := Generate{Commands: test.Commands, Paths: test.Paths}
+			if errs := g.ValidateGenerated().Errors(); len(errs) != test.Errors {
+				tt.Errorf("Expected %d errors, got %v", test.Errors, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package policy
+
+import "github.com/autonomy/conform/internal/git"
+
+// NewGit opens the repository of the working directory for a policy. Its
+// walks of the history stop once the context of the options is done, e.g.
+// when the policy times out.
+func NewGit(options *Options) (*git.Git, error) {
+	g, err := git.NewGit()
+	if err != nil {
+		return nil, err
+	}
+
+	return g.WithContext(options.Context), nil
+}
@@ -0,0 +1,72 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package gitattributes
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// LFSPointerPrefix is the prefix of a Git LFS pointer file.
+var LFSPointerPrefix = []byte("version https://git-lfs.github.com/spec/v1")
+
+// ConsistencyCheck ensures that tracked files agree with their declared
+// attributes.
+type ConsistencyCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (c ConsistencyCheck) Name() string {
+	return "Attribute Consistency"
+}
+
+// Message returns to check message.
+func (c ConsistencyCheck) Message() string {
+	if len(c.errors) != 0 {
+		return fmt.Sprintf("Found %d files that contradict their attributes", len(c.errors))
+	}
+	return "All files agree with their attributes"
+}
+
+// Errors returns any violations of the check.
+func (c ConsistencyCheck) Errors() []error {
+	return c.errors
+}
+
+// ValidateConsistency checks that files with filter=lfs are stored as LFS
+// pointers, and that files declared as text are not binary. The contents are
+// read from the index, so that LFS smudging does not affect the result.
+func (a GitAttributes) ValidateConsistency() policy.Check {
+	check := &ConsistencyCheck{}
+
+	for _, file := range a.files {
+		attrs := a.attributes.Get(file)
+		lfs := attrs["filter"] == "lfs"
+		text := attrs["text"] == git.AttributeSet
+		if !lfs && !text {
+			continue
+		}
+
+		contents, err := a.git.IndexContents(file)
+		if err != nil {
+			check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("Failed to read %s from the index: %v", file, err)))
+			continue
+		}
+
+		switch {
+		case lfs && !bytes.HasPrefix(contents, LFSPointerPrefix):
+			check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("File %s is declared filter=lfs but is not an LFS pointer", file)))
+		case !lfs && text && git.IsBinary(contents):
+			check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("File %s is declared text but is binary", file)))
+		}
+	}
+
+	return check
+}
@@ -0,0 +1,77 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package gitattributes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// RequiredCheck ensures that required rules are present in .gitattributes.
+type RequiredCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (r RequiredCheck) Name() string {
+	return "Required Attributes"
+}
+
+// Message returns to check message.
+func (r RequiredCheck) Message() string {
+	if len(r.errors) != 0 {
+		return fmt.Sprintf("Found %d missing rules", len(r.errors))
+	}
+	return "All required rules are present"
+}
+
+// Errors returns any violations of the check.
+func (r RequiredCheck) Errors() []error {
+	return r.errors
+}
+
+// ValidateRequired checks that each required line is satisfied by a rule in
+// .gitattributes.
+func (a GitAttributes) ValidateRequired() policy.Check {
+	check := &RequiredCheck{}
+
+	required, err := git.ParseAttributes(strings.NewReader(strings.Join(a.Required, "\n")))
+	if err != nil {
+		check.errors = append(check.errors, errors.Errorf("Invalid required rules: %v", err))
+		return check
+	}
+
+	for _, want := range required.Rules {
+		if !a.satisfied(want) {
+			check.errors = append(check.errors, errors.Errorf("Rule %q is missing from .gitattributes", a.Required[want.Line-1]))
+		}
+	}
+
+	return check
+}
+
+func (a GitAttributes) satisfied(want git.AttributeRule) bool {
+	for _, rule := range a.attributes.Rules {
+		if rule.Pattern != want.Pattern {
+			continue
+		}
+		ok := true
+		for k, v := range want.Attributes {
+			if rule.Attributes[k] != v {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+
+	return false
+}
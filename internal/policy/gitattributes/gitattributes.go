@@ -0,0 +1,56 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package gitattributes
+
+import (
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// GitAttributes implements the policy.Policy interface and validates the
+// .gitattributes file.
+type GitAttributes struct {
+	// Required are .gitattributes lines that must be present (e.g.
+	// "*.png binary"). A rule satisfies a required line if it has the same
+	// pattern and assigns at least the same attributes.
+	Required []string `mapstructure:"required"`
+	// Consistency enforces that tracked files do not contradict their
+	// declared attributes.
+	Consistency bool `mapstructure:"consistency"`
+
+	git        *git.Git
+	files      []string
+	attributes *git.Attributes
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (a *GitAttributes) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	if a.git, err = policy.NewGit(options); err != nil {
+		return report, errors.Errorf("failed to open git repo: %v", err)
+	}
+
+	if a.files, err = policy.TrackedFiles(options.Tree, a.git); err != nil {
+		return report, errors.Errorf("failed to list tracked files: %v", err)
+	}
+
+	if a.attributes, err = policy.ReadAttributes(options.Tree, ".gitattributes"); err != nil {
+		return report, errors.Errorf("failed to read .gitattributes: %v", err)
+	}
+
+	if len(a.Required) != 0 {
+		report.AddCheck(a.ValidateRequired())
+	}
+
+	if a.Consistency {
+		report.AddCheck(a.ValidateConsistency())
+	}
+
+	return report, nil
+}
@@ -0,0 +1,114 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package gitattributes
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/autonomy/conform/internal/git"
+)
+
+func TestValidateRequired(t *testing.T) {
+	for _, test := range []struct {
+		Name       string
+		Attributes string
+		Required   []string
+		Errors     int
+	}{
+		{Name: "Present", Attributes: "*.png binary\n*.go text eol=lf\n", Required: []string{"*.go text"}},
+		{Name: "Same attributes", Attributes: "*.go text eol=lf\n", Required: []string{"*.go text eol=lf"}},
+		{Name: "Missing pattern", Attributes: "*.go text\n", Required: []string{"*.png binary"}, Errors: 1},
+		{Name: "Missing attribute", Attributes: "*.go text\n", Required: []string{"*.go text eol=lf"}, Errors: 1},
+		{Name: "Different value", Attributes: "*.go text eol=crlf\n", Required: []string{"*.go eol=lf"}, Errors: 1},
+		{Name: "Empty", Attributes: "", Required: []string{"*.png binary", "*.go text"}, Errors: 2},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			attributes, err := git.ParseAttributes(strings.NewReader(test.Attributes))
+			if err != nil {
+				tt.Fatal(err)
+			}
+			a := GitAttributes{Required: test.Required, attributes: attributes}
+			if errs := a.ValidateRequired().Errors(); len(errs) != test.Errors {
+				tt.Errorf("Expected %d errors, got %v", test.Errors, errs)
+			}
+		})
+	}
+}
+
+func TestValidateConsistency(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.Chdir(wd)
+
+	files := map[string]string{
+		"pointer.bin": "version https://git-lfs.github.com/spec/v1\noid sha256:0123\nsize 4\n",
+		"blob.bin":    "\x00\x01\x02\x03",
+		"text.txt":    "text\n",
+		"binary.txt":  "\x00\x01\x02\x03",
+	}
+	for name, contents := range files {
+		if err = ioutil.WriteFile(name, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	g, err := git.NewGit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		Name       string
+		Attributes string
+		File       string
+		Errors     int
+	}{
+		{Name: "LFS pointer", Attributes: "*.bin filter=lfs\n", File: "pointer.bin"},
+		{Name: "Not an LFS pointer", Attributes: "*.bin filter=lfs\n", File: "blob.bin", Errors: 1},
+		{Name: "Text", Attributes: "*.txt text\n", File: "text.txt"},
+		{Name: "Binary text", Attributes: "*.txt text\n", File: "binary.txt", Errors: 1},
+		{Name: "No attributes", Attributes: "", File: "binary.txt"},
+		{Name: "Not in the index", Attributes: "*.md text\n", File: "README.md", Errors: 1},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			attributes, err := git.ParseAttributes(strings.NewReader(test.Attributes))
+			if err != nil {
+				tt.Fatal(err)
+			}
+			a := GitAttributes{Consistency: true, git: g, files: []string{test.File}, attributes: attributes}
+			if errs := a.ValidateConsistency().Errors(); len(errs) != test.Errors {
+				tt.Errorf("Expected %d errors, got %v", test.Errors, errs)
+			}
+		})
+	}
+}
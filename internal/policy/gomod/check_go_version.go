@@ -0,0 +1,88 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package gomod
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// GoVersionCheck ensures that the go directive meets a minimum version.
+type GoVersionCheck struct {
+	version string
+	errors  []error
+}
+
+// Name returns the name of the check.
+func (g GoVersionCheck) Name() string {
+	return "Go Version"
+}
+
+// Message returns to check message.
+func (g GoVersionCheck) Message() string {
+	if len(g.errors) != 0 {
+		return g.errors[0].Error()
+	}
+	return "Go version is " + g.version
+}
+
+// Errors returns any violations of the check.
+func (g GoVersionCheck) Errors() []error {
+	return g.errors
+}
+
+// ValidateGoVersion checks the go directive against the minimum version.
+func (m GoMod) ValidateGoVersion() policy.Check {
+	check := &GoVersionCheck{version: m.file.Go}
+
+	if m.file.Go == "" {
+		check.errors = append(check.errors, policy.FileError(m.path(), 0, errors.Errorf("go.mod does not have a go directive")))
+		return check
+	}
+
+	if compareVersions(m.file.Go, m.MinimumGoVersion) < 0 {
+		check.errors = append(check.errors, policy.FileError(m.path(), m.file.GoLine, errors.Errorf("Line %d: go version %s is less than %s", m.file.GoLine, m.file.Go, m.MinimumGoVersion)))
+	}
+
+	return check
+}
+
+// compareVersions compares two dot separated Go versions, and returns -1, 0,
+// or 1. Non numeric suffixes (e.g. rc1) are ignored.
+func compareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "go"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "go"), ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x = leadingInt(as[i])
+		}
+		if i < len(bs) {
+			y = leadingInt(bs[i])
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func leadingInt(s string) int {
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	// nolint: errcheck
+	n, _ := strconv.Atoi(s[:end])
+
+	return n
+}
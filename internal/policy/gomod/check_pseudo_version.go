@@ -0,0 +1,71 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package gomod
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// PseudoVersionRegex is the regular expression used to detect pseudo-versions
+// (e.g. v0.0.0-20190508220229-2d0786266e9c).
+var PseudoVersionRegex = regexp.MustCompile(`\d{14}-[0-9a-f]{12}(\+incompatible)?$`)
+
+// PseudoVersionCheck ensures that release branches do not depend on
+// pseudo-versions.
+type PseudoVersionCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (p PseudoVersionCheck) Name() string {
+	return "Pseudo Versions"
+}
+
+// Message returns to check message.
+func (p PseudoVersionCheck) Message() string {
+	if len(p.errors) != 0 {
+		return fmt.Sprintf("Found %d pseudo-version dependencies", len(p.errors))
+	}
+	return "No pseudo-version dependencies on release branch"
+}
+
+// Errors returns any violations of the check.
+func (p PseudoVersionCheck) Errors() []error {
+	return p.errors
+}
+
+// ValidatePseudoVersions checks that no requirement uses a pseudo-version when
+// the current branch is a release branch.
+func (m GoMod) ValidatePseudoVersions() policy.Check {
+	check := &PseudoVersionCheck{}
+
+	release := false
+	for _, expr := range m.ReleaseBranches {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			check.errors = append(check.errors, errors.Errorf("Invalid release branch pattern %q: %v", expr, err))
+			return check
+		}
+		if re.MatchString(m.branch) {
+			release = true
+			break
+		}
+	}
+	if !release {
+		return check
+	}
+
+	for _, r := range m.file.Require {
+		if PseudoVersionRegex.MatchString(r.Version) {
+			check.errors = append(check.errors, policy.FileError(m.path(), r.Line, errors.Errorf("Line %d: %s uses pseudo-version %s on release branch %s", r.Line, r.Path, r.Version, m.branch)))
+		}
+	}
+
+	return check
+}
@@ -0,0 +1,57 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package gomod
+
+import (
+	"fmt"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// ReplaceCheck ensures that go.mod has no replace directives other than those
+// allowed.
+type ReplaceCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (r ReplaceCheck) Name() string {
+	return "Replace Directives"
+}
+
+// Message returns to check message.
+func (r ReplaceCheck) Message() string {
+	if len(r.errors) != 0 {
+		return fmt.Sprintf("Found %d forbidden replace directives", len(r.errors))
+	}
+	return "No forbidden replace directives"
+}
+
+// Errors returns any violations of the check.
+func (r ReplaceCheck) Errors() []error {
+	return r.errors
+}
+
+// ValidateReplace checks that each replace directive replaces an allowed
+// module.
+func (m GoMod) ValidateReplace() policy.Check {
+	check := &ReplaceCheck{}
+
+	for _, r := range m.file.Replace {
+		allowed := false
+		for _, path := range m.AllowedReplaces {
+			if r.Old.Path == path {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			check.errors = append(check.errors, policy.FileError(m.path(), r.Line, errors.Errorf("Line %d: replace of %s is not allowed", r.Line, r.Old.Path)))
+		}
+	}
+
+	return check
+}
@@ -0,0 +1,90 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package gomod
+
+import (
+	"io"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// GoMod implements the policy.Policy interface and enforces the hygiene of a
+// go.mod file.
+type GoMod struct {
+	// Path is the path to the go.mod file. Defaults to go.mod.
+	Path string `mapstructure:"path"`
+	// ForbidReplace disallows replace directives.
+	ForbidReplace bool `mapstructure:"forbidReplace"`
+	// AllowedReplaces are the module paths that may be replaced when
+	// ForbidReplace is set.
+	AllowedReplaces []string `mapstructure:"allowedReplaces"`
+	// MinimumGoVersion is the minimum version allowed in the go directive
+	// (e.g. 1.12).
+	MinimumGoVersion string `mapstructure:"minimumGoVersion"`
+	// ReleaseBranches are regular expressions of branch names on which
+	// pseudo-version dependencies are not allowed.
+	ReleaseBranches []string `mapstructure:"releaseBranches"`
+
+	file   *ModFile
+	branch string
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (m *GoMod) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	name := m.path()
+	var f io.ReadCloser
+	if f, err = policy.Open(options.Tree, name); err != nil {
+		return report, errors.Errorf("failed to open %s: %v", name, err)
+	}
+	// nolint: errcheck
+	defer f.Close()
+	if m.file, err = ParseModFile(f); err != nil {
+		return report, errors.Errorf("failed to parse %s: %v", name, err)
+	}
+
+	if m.ForbidReplace {
+		report.AddCheck(m.ValidateReplace())
+	}
+
+	if m.MinimumGoVersion != "" {
+		report.AddCheck(m.ValidateGoVersion())
+	}
+
+	if len(m.ReleaseBranches) != 0 {
+		if options.Ref != nil {
+			m.branch = *options.Ref
+		} else {
+			var g *git.Git
+			if g, err = policy.NewGit(options); err != nil {
+				return report, errors.Errorf("failed to open git repo: %v", err)
+			}
+			if m.branch, err = g.Branch(); err != nil {
+				return report, errors.Errorf("failed to get branch: %v", err)
+			}
+		}
+		report.AddCheck(m.ValidatePseudoVersions())
+	}
+
+	return report, nil
+}
+
+// path returns the path to the go.mod file.
+func (m GoMod) path() string {
+	if m.Path == "" {
+		return "go.mod"
+	}
+	return m.Path
+}
+
+// Validate implements the policy.Validator.Validate function.
+func (m *GoMod) Validate() []error {
+	return policy.ValidateRegexps("release branch pattern", m.ReleaseBranches...)
+}
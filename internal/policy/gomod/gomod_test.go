@@ -0,0 +1,129 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package gomod
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestGoModChecks(t *testing.T) {
+	contents := `module github.com/autonomy/conform
+
+go 1.12
+
+require (
+	github.com/pkg/errors v0.8.1
+	golang.org/x/sys v0.0.0-20190215142949-d0b11bdaac8a
+)
+
+replace github.com/pkg/errors => ../errors
+`
+
+	tests := []struct {
+		name     string
+		gomod    GoMod
+		contents string
+		validate func(GoMod) policy.Check
+		errors   int
+	}{
+		{
+			name:     "Replace allowed",
+			gomod:    GoMod{ForbidReplace: true, AllowedReplaces: []string{"github.com/pkg/errors"}},
+			validate: GoMod.ValidateReplace,
+		},
+		{
+			name:     "Replace forbidden",
+			gomod:    GoMod{ForbidReplace: true},
+			validate: GoMod.ValidateReplace,
+			errors:   1,
+		},
+		{
+			name:     "Go version equal",
+			gomod:    GoMod{MinimumGoVersion: "1.12"},
+			validate: GoMod.ValidateGoVersion,
+		},
+		{
+			name:     "Go version older",
+			gomod:    GoMod{MinimumGoVersion: "1.13"},
+			validate: GoMod.ValidateGoVersion,
+			errors:   1,
+		},
+		{
+			name:     "Go version newer",
+			gomod:    GoMod{MinimumGoVersion: "1.9"},
+			validate: GoMod.ValidateGoVersion,
+		},
+		{
+			name:     "No go directive",
+			gomod:    GoMod{MinimumGoVersion: "1.12"},
+			contents: "module github.com/autonomy/conform\n",
+			validate: GoMod.ValidateGoVersion,
+			errors:   1,
+		},
+		{
+			name:     "Pseudo-version on release branch",
+			gomod:    GoMod{ReleaseBranches: []string{`^release-`}, branch: "release-1.0"},
+			validate: GoMod.ValidatePseudoVersions,
+			errors:   1,
+		},
+		{
+			name:     "Pseudo-version on other branch",
+			gomod:    GoMod{ReleaseBranches: []string{`^release-`}, branch: "master"},
+			validate: GoMod.ValidatePseudoVersions,
+		},
+		{
+			name:     "Invalid release branch pattern",
+			gomod:    GoMod{ReleaseBranches: []string{`(`}, branch: "master"},
+			validate: GoMod.ValidatePseudoVersions,
+			errors:   1,
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(tt *testing.T) {
+			c := test.contents
+			if c == "" {
+				c = contents
+			}
+			m := test.gomod
+			var err error
+			if m.file, err = ParseModFile(strings.NewReader(c)); err != nil {
+				tt.Fatal(err)
+			}
+			errs := test.validate(m).Errors()
+			if len(errs) != test.errors {
+				tt.Fatalf("Expected %d errors, got %v", test.errors, errs)
+			}
+			for _, err := range errs {
+				if l, ok := policy.LocationOf(err); ok && l.File != "go.mod" {
+					tt.Errorf("Expected the violation to be in go.mod, got %s", l.File)
+				}
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	for _, test := range []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.12", "1.12", 0},
+		{"1.12", "1.9", 1},
+		{"1.9", "1.12", -1},
+		{"1.21.0", "1.21", 0},
+		{"go1.13", "1.12", 1},
+		{"1.21rc1", "1.21", 0},
+	} {
+		if actual := compareVersions(test.a, test.b); actual != test.expected {
+			t.Errorf("Expected compareVersions(%q, %q) to be %d, got %d", test.a, test.b, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package gomod
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Module is a module version referenced by a go.mod file.
+type Module struct {
+	Path    string
+	Version string
+	Line    int
+}
+
+// Replace is a replace directive of a go.mod file.
+type Replace struct {
+	Old  Module
+	New  Module
+	Line int
+}
+
+// ModFile is a parsed go.mod file. Only the directives relevant to the policy
+// are retained: exclude directives are validated, and retract directives
+// ignored.
+type ModFile struct {
+	Module  string
+	Go      string
+	GoLine  int
+	Require []Module
+	Replace []Replace
+}
+
+// ParseModFile parses the contents of a go.mod file.
+// nolint: gocyclo
+func ParseModFile(r io.Reader) (*ModFile, error) {
+	f := &ModFile{}
+
+	var block string
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if block != "" {
+			if fields[0] == ")" {
+				block = ""
+				continue
+			}
+			if err := f.add(block, fields, n); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if len(fields) == 2 && fields[1] == "(" {
+			block = fields[0]
+			continue
+		}
+		if err := f.add(fields[0], fields[1:], n); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if block != "" {
+		return nil, errors.Errorf("unterminated %s block", block)
+	}
+
+	return f, nil
+}
+
+func (f *ModFile) add(verb string, args []string, n int) error {
+	for i, arg := range args {
+		args[i] = strings.Trim(arg, "\"`")
+	}
+
+	switch verb {
+	case "module":
+		if len(args) != 1 {
+			return errors.Errorf("line %d: invalid module directive", n)
+		}
+		f.Module = args[0]
+	case "go":
+		if len(args) != 1 {
+			return errors.Errorf("line %d: invalid go directive", n)
+		}
+		f.Go = args[0]
+		f.GoLine = n
+	case "require", "exclude":
+		if len(args) != 2 {
+			return errors.Errorf("line %d: invalid %s directive", n, verb)
+		}
+		if verb == "require" {
+			f.Require = append(f.Require, Module{Path: args[0], Version: args[1], Line: n})
+		}
+	case "replace":
+		arrow := -1
+		for i, arg := range args {
+			if arg == "=>" {
+				arrow = i
+			}
+		}
+		if arrow < 1 || arrow == len(args)-1 {
+			return errors.Errorf("line %d: invalid replace directive", n)
+		}
+		r := Replace{Line: n}
+		r.Old.Path = args[0]
+		if arrow == 2 {
+			r.Old.Version = args[1]
+		}
+		r.New.Path = args[arrow+1]
+		if len(args) > arrow+2 {
+			r.New.Version = args[arrow+2]
+		}
+		f.Replace = append(f.Replace, r)
+	}
+
+	return nil
+}
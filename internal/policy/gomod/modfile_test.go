@@ -0,0 +1,108 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package gomod
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseModFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		expected *ModFile
+		err      bool
+	}{
+		{
+			name: "Directives",
+			contents: `module github.com/autonomy/conform // the module
+
+go 1.21
+
+require github.com/pkg/errors v0.8.1
+replace github.com/pkg/errors => ../errors
+exclude github.com/pkg/errors v0.8.0
+retract v1.0.0
+`,
+			expected: &ModFile{
+				Module:  "github.com/autonomy/conform",
+				Go:      "1.21",
+				GoLine:  3,
+				Require: []Module{{Path: "github.com/pkg/errors", Version: "v0.8.1", Line: 5}},
+				Replace: []Replace{{Old: Module{Path: "github.com/pkg/errors"}, New: Module{Path: "../errors"}, Line: 6}},
+			},
+		},
+		{
+			name: "Blocks",
+			contents: `module example.com/m
+
+require (
+	example.com/a v1.0.0
+	example.com/b v0.0.0-20190101000000-abcdefabcdef // indirect
+)
+
+replace (
+	example.com/a v1.0.0 => example.com/fork/a v1.0.1
+	example.com/b => ./b
+)
+
+exclude (
+	example.com/a v0.9.0
+	example.com/b v0.1.0
+)
+
+retract (
+	v1.0.0
+	[v1.1.0, v1.2.0]
+)
+`,
+			expected: &ModFile{
+				Module: "example.com/m",
+				Require: []Module{
+					{Path: "example.com/a", Version: "v1.0.0", Line: 4},
+					{Path: "example.com/b", Version: "v0.0.0-20190101000000-abcdefabcdef", Line: 5},
+				},
+				Replace: []Replace{
+					{Old: Module{Path: "example.com/a", Version: "v1.0.0"}, New: Module{Path: "example.com/fork/a", Version: "v1.0.1"}, Line: 9},
+					{Old: Module{Path: "example.com/b"}, New: Module{Path: "./b"}, Line: 10},
+				},
+			},
+		},
+		{
+			name:     "InvalidExclude",
+			contents: "module example.com/m\n\nexclude (\n\texample.com/a\n)\n",
+			err:      true,
+		},
+		{
+			name:     "InvalidReplace",
+			contents: "module example.com/m\n\nreplace example.com/a =>\n",
+			err:      true,
+		},
+		{
+			name:     "UnterminatedBlock",
+			contents: "module example.com/m\n\nreplace (\n\texample.com/a => ./a\n",
+			err:      true,
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			f, err := ParseModFile(strings.NewReader(test.contents))
+			if test.err != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", test.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(f, test.expected) {
+				t.Errorf("Expected %+v, got %+v", test.expected, f)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package input
+
+import (
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// Input is the structured description of the changes being enforced that is
+// passed to custom policy engines.
+type Input struct {
+	// Commit is the commit being enforced.
+	Commit Commit `json:"commit"`
+	// Ref describes the refs of the repository.
+	Ref Ref `json:"ref"`
+	// Files are the tracked files.
+	Files []string `json:"files"`
+	// Changes are the files changed relative to the base branch, or by the
+	// commit if no base branch is set.
+	Changes []Change `json:"changes"`
+}
+
+// Commit describes a commit.
+type Commit struct {
+	SHA       string    `json:"sha"`
+	Message   string    `json:"message"`
+	Header    string    `json:"header"`
+	Body      string    `json:"body"`
+	Author    Signature `json:"author"`
+	Committer Signature `json:"committer"`
+	Signed    bool      `json:"signed"`
+}
+
+// Signature identifies a person and a time.
+type Signature struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	When  time.Time `json:"when"`
+}
+
+// Ref describes the refs of the repository.
+type Ref struct {
+	// Branch is the branch HEAD points to. It is empty if HEAD is detached.
+	Branch string `json:"branch"`
+	// Base is the base branch the changes are compared against.
+	Base string `json:"base"`
+}
+
+// Change describes the changes made to a file.
+type Change struct {
+	Path    string `json:"path"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Binary  bool   `json:"binary"`
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+}
+
+// New builds the input from the repository in the current working directory.
+// nolint: gocyclo
+func New(options *policy.Options) (*Input, error) {
+	var err error
+
+	var g *git.Git
+	if g, err = policy.NewGit(options); err != nil {
+		return nil, errors.Errorf("failed to open git repo: %v", err)
+	}
+
+	in := &Input{}
+
+	if options.CommitMsgFile != nil {
+		var contents []byte
+		if contents, err = ioutil.ReadFile(*options.CommitMsgFile); err != nil {
+			return nil, errors.Errorf("failed to read commit message file: %v", err)
+		}
+		in.Commit.Message = string(contents)
+	} else if in.Commit.Message, err = g.Message(); err != nil {
+		return nil, errors.Errorf("failed to get commit message: %v", err)
+	}
+	parts := strings.SplitN(strings.TrimPrefix(in.Commit.Message, "\n"), "\n", 2)
+	in.Commit.Header = parts[0]
+	if len(parts) > 1 {
+		in.Commit.Body = strings.TrimSpace(parts[1])
+	}
+
+	if in.Commit.SHA, err = g.SHA(); err != nil {
+		return nil, errors.Errorf("failed to get commit sha: %v", err)
+	}
+	if in.Commit.Signed, err = g.HasGPGSignature(); err != nil {
+		return nil, errors.Errorf("failed to get commit signature: %v", err)
+	}
+	var author, committer git.Signature
+	if author, err = g.Author(); err != nil {
+		return nil, errors.Errorf("failed to get commit author: %v", err)
+	}
+	if committer, err = g.Committer(); err != nil {
+		return nil, errors.Errorf("failed to get commit committer: %v", err)
+	}
+	in.Commit.Author = Signature(author)
+	in.Commit.Committer = Signature(committer)
+	if options.AuthorName != nil {
+		in.Commit.Author.Name = *options.AuthorName
+	}
+	if options.AuthorEmail != nil {
+		in.Commit.Author.Email = *options.AuthorEmail
+	}
+
+	if options.Ref != nil {
+		in.Ref.Branch = *options.Ref
+	} else if in.Ref.Branch, err = g.Branch(); err != nil {
+		return nil, errors.Errorf("failed to get branch: %v", err)
+	}
+	if options.BaseBranch != nil {
+		in.Ref.Base = *options.BaseBranch
+	}
+
+	if in.Files, err = policy.TrackedFiles(options.Tree, g); err != nil {
+		return nil, errors.Errorf("failed to list tracked files: %v", err)
+	}
+
+	var diffs []*git.FileDiff
+	if diffs, err = g.Diff(in.Ref.Base); err != nil {
+		return nil, errors.Errorf("failed to get diff: %v", err)
+	}
+	in.Changes = make([]Change, 0, len(diffs))
+	for _, d := range diffs {
+		in.Changes = append(in.Changes, Change{
+			Path:    d.Path(),
+			From:    d.From,
+			To:      d.To,
+			Binary:  d.Binary,
+			Added:   len(d.Added),
+			Removed: d.Removed,
+		})
+	}
+
+	return in, nil
+}
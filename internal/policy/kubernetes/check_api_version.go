@@ -0,0 +1,55 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package kubernetes
+
+import (
+	"fmt"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// APIVersionCheck ensures that resources do not use removed API versions.
+type APIVersionCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (a APIVersionCheck) Name() string {
+	return "API Deprecations"
+}
+
+// Message returns to check message.
+func (a APIVersionCheck) Message() string {
+	if len(a.errors) != 0 {
+		return fmt.Sprintf("Found %d resources using removed APIs", len(a.errors))
+	}
+	return "No resources use removed APIs"
+}
+
+// Errors returns any violations of the check.
+func (a APIVersionCheck) Errors() []error {
+	return a.errors
+}
+
+// ValidateAPIVersions checks each resource, including Helm templates, for API
+// versions removed by the configured Kubernetes version.
+func (k Kubernetes) ValidateAPIVersions() policy.Check {
+	check := &APIVersionCheck{}
+
+	for _, m := range k.manifests {
+		d, ok := Find(m.APIVersion, m.Kind)
+		if !ok || !d.removedBy(k.Version) {
+			continue
+		}
+		if d.Replacement != "" {
+			check.errors = append(check.errors, policy.FileError(m.File, 0, errors.Errorf("%s uses %s which is removed in %s: use %s", m.location(), m.APIVersion, d.Removed, d.Replacement)))
+		} else {
+			check.errors = append(check.errors, policy.FileError(m.File, 0, errors.Errorf("%s uses %s which is removed in %s", m.location(), m.APIVersion, d.Removed)))
+		}
+	}
+
+	return check
+}
@@ -0,0 +1,55 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package kubernetes
+
+import (
+	"fmt"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// ChartCheck ensures that Helm charts are well formed.
+type ChartCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (c ChartCheck) Name() string {
+	return "Helm Chart"
+}
+
+// Message returns to check message.
+func (c ChartCheck) Message() string {
+	if len(c.errors) != 0 {
+		return fmt.Sprintf("Found %d invalid charts", len(c.errors))
+	}
+	return "All charts are valid"
+}
+
+// Errors returns any violations of the check.
+func (c ChartCheck) Errors() []error {
+	return c.errors
+}
+
+// ValidateCharts checks that each Chart.yaml declares a supported apiVersion,
+// a name, and a version.
+func (k Kubernetes) ValidateCharts() policy.Check {
+	check := &ChartCheck{}
+
+	for _, c := range k.charts {
+		if c.APIVersion != "v1" && c.APIVersion != "v2" {
+			check.errors = append(check.errors, policy.FileError(c.File, 0, errors.Errorf("%s: invalid apiVersion %q", c.File, c.APIVersion)))
+		}
+		if c.Name == "" {
+			check.errors = append(check.errors, policy.FileError(c.File, 0, errors.Errorf("%s: name is required", c.File)))
+		}
+		if c.Version == "" {
+			check.errors = append(check.errors, policy.FileError(c.File, 0, errors.Errorf("%s: version is required", c.File)))
+		}
+	}
+
+	return check
+}
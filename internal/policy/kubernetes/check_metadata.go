@@ -0,0 +1,61 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package kubernetes
+
+import (
+	"fmt"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// MetadataCheck ensures that resources declare the required labels and
+// annotations.
+type MetadataCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (m MetadataCheck) Name() string {
+	return "Required Metadata"
+}
+
+// Message returns to check message.
+func (m MetadataCheck) Message() string {
+	if len(m.errors) != 0 {
+		return fmt.Sprintf("Found %d missing labels and annotations", len(m.errors))
+	}
+	return "All resources have the required metadata"
+}
+
+// Errors returns any violations of the check.
+func (m MetadataCheck) Errors() []error {
+	return m.errors
+}
+
+// ValidateMetadata checks each resource for the required labels and
+// annotations. Helm templates are skipped, since their metadata is not known
+// until rendered.
+func (k Kubernetes) ValidateMetadata() policy.Check {
+	check := &MetadataCheck{}
+
+	for _, m := range k.manifests {
+		if m.Template {
+			continue
+		}
+		for _, label := range k.RequiredLabels {
+			if _, ok := m.Metadata.Labels[label]; !ok {
+				check.errors = append(check.errors, policy.FileError(m.File, 0, errors.Errorf("%s is missing label %s", m.location(), label)))
+			}
+		}
+		for _, annotation := range k.RequiredAnnotations {
+			if _, ok := m.Metadata.Annotations[annotation]; !ok {
+				check.errors = append(check.errors, policy.FileError(m.File, 0, errors.Errorf("%s is missing annotation %s", m.location(), annotation)))
+			}
+		}
+	}
+
+	return check
+}
@@ -0,0 +1,100 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package kubernetes
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Deprecation describes an API version that is removed in a Kubernetes
+// release.
+type Deprecation struct {
+	// APIVersion is the removed API version.
+	APIVersion string
+	// Kinds are the affected kinds. An empty list affects all kinds.
+	Kinds []string
+	// Replacement is the API version to migrate to, if any.
+	Replacement string
+	// Removed is the Kubernetes version that no longer serves the API.
+	Removed string
+}
+
+// Deprecations is the set of known API removals.
+var Deprecations = []Deprecation{
+	{APIVersion: "extensions/v1beta1", Kinds: []string{"Deployment", "DaemonSet", "ReplicaSet"}, Replacement: "apps/v1", Removed: "1.16"},
+	{APIVersion: "extensions/v1beta1", Kinds: []string{"NetworkPolicy"}, Replacement: "networking.k8s.io/v1", Removed: "1.16"},
+	{APIVersion: "extensions/v1beta1", Kinds: []string{"PodSecurityPolicy"}, Replacement: "policy/v1beta1", Removed: "1.16"},
+	{APIVersion: "extensions/v1beta1", Kinds: []string{"Ingress"}, Replacement: "networking.k8s.io/v1", Removed: "1.22"},
+	{APIVersion: "apps/v1beta1", Replacement: "apps/v1", Removed: "1.16"},
+	{APIVersion: "apps/v1beta2", Replacement: "apps/v1", Removed: "1.16"},
+	{APIVersion: "networking.k8s.io/v1beta1", Replacement: "networking.k8s.io/v1", Removed: "1.22"},
+	{APIVersion: "rbac.authorization.k8s.io/v1beta1", Replacement: "rbac.authorization.k8s.io/v1", Removed: "1.22"},
+	{APIVersion: "apiextensions.k8s.io/v1beta1", Replacement: "apiextensions.k8s.io/v1", Removed: "1.22"},
+	{APIVersion: "admissionregistration.k8s.io/v1beta1", Replacement: "admissionregistration.k8s.io/v1", Removed: "1.22"},
+	{APIVersion: "apiregistration.k8s.io/v1beta1", Replacement: "apiregistration.k8s.io/v1", Removed: "1.22"},
+	{APIVersion: "certificates.k8s.io/v1beta1", Replacement: "certificates.k8s.io/v1", Removed: "1.22"},
+	{APIVersion: "coordination.k8s.io/v1beta1", Replacement: "coordination.k8s.io/v1", Removed: "1.22"},
+	{APIVersion: "scheduling.k8s.io/v1beta1", Replacement: "scheduling.k8s.io/v1", Removed: "1.22"},
+	{APIVersion: "storage.k8s.io/v1beta1", Kinds: []string{"CSIDriver", "CSINode", "StorageClass", "VolumeAttachment"}, Replacement: "storage.k8s.io/v1", Removed: "1.22"},
+	{APIVersion: "batch/v1beta1", Kinds: []string{"CronJob"}, Replacement: "batch/v1", Removed: "1.25"},
+	{APIVersion: "discovery.k8s.io/v1beta1", Replacement: "discovery.k8s.io/v1", Removed: "1.25"},
+	{APIVersion: "events.k8s.io/v1beta1", Replacement: "events.k8s.io/v1", Removed: "1.25"},
+	{APIVersion: "autoscaling/v2beta1", Replacement: "autoscaling/v2", Removed: "1.25"},
+	{APIVersion: "policy/v1beta1", Kinds: []string{"PodDisruptionBudget"}, Replacement: "policy/v1", Removed: "1.25"},
+	{APIVersion: "policy/v1beta1", Kinds: []string{"PodSecurityPolicy"}, Removed: "1.25"},
+	{APIVersion: "autoscaling/v2beta2", Replacement: "autoscaling/v2", Removed: "1.26"},
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta1", Replacement: "flowcontrol.apiserver.k8s.io/v1", Removed: "1.26"},
+	{APIVersion: "storage.k8s.io/v1beta1", Kinds: []string{"CSIStorageCapacity"}, Replacement: "storage.k8s.io/v1", Removed: "1.27"},
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta2", Replacement: "flowcontrol.apiserver.k8s.io/v1", Removed: "1.29"},
+	{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta3", Replacement: "flowcontrol.apiserver.k8s.io/v1", Removed: "1.32"},
+}
+
+// Find returns the deprecation that applies to the API version and kind, if
+// any.
+func Find(apiVersion, kind string) (Deprecation, bool) {
+	for _, d := range Deprecations {
+		if d.APIVersion != apiVersion {
+			continue
+		}
+		if len(d.Kinds) == 0 {
+			return d, true
+		}
+		for _, k := range d.Kinds {
+			if k == kind {
+				return d, true
+			}
+		}
+	}
+
+	return Deprecation{}, false
+}
+
+// removedBy reports whether the API is removed in or before the Kubernetes
+// version. All deprecations apply when version is empty.
+func (d Deprecation) removedBy(version string) bool {
+	if version == "" {
+		return true
+	}
+	removedMajor, removedMinor := parseVersion(d.Removed)
+	major, minor := parseVersion(version)
+	if major != removedMajor {
+		return major > removedMajor
+	}
+
+	return minor >= removedMinor
+}
+
+func parseVersion(version string) (major, minor int) {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	// nolint: errcheck
+	major, _ = strconv.Atoi(parts[0])
+	if len(parts) > 1 {
+		// nolint: errcheck
+		minor, _ = strconv.Atoi(parts[1])
+	}
+
+	return major, minor
+}
@@ -0,0 +1,35 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package kubernetes
+
+import "testing"
+
+func TestFindRemoved(t *testing.T) {
+	tests := []struct {
+		Name       string
+		APIVersion string
+		Kind       string
+		Version    string
+		Removed    bool
+	}{
+		{"deprecated kind", "extensions/v1beta1", "Deployment", "1.16", true},
+		{"before removal", "extensions/v1beta1", "Ingress", "1.21", false},
+		{"after removal", "extensions/v1beta1", "Ingress", "1.23", true},
+		{"any version", "batch/v1beta1", "CronJob", "", true},
+		{"group wide", "rbac.authorization.k8s.io/v1beta1", "Role", "v1.22.3", true},
+		{"unaffected kind", "storage.k8s.io/v1beta1", "CSIStorageCapacity", "1.26", false},
+		{"current", "apps/v1", "Deployment", "", false},
+	}
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			d, ok := Find(test.APIVersion, test.Kind)
+			if removed := ok && d.removedBy(test.Version); removed != test.Removed {
+				tt.Errorf("Expected removed to be %v, got %v", test.Removed, removed)
+			}
+		})
+	}
+}
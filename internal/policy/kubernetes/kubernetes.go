@@ -0,0 +1,188 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package kubernetes
+
+import (
+	"bytes"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Kubernetes implements the policy.Policy interface and validates
+// Kubernetes manifests and Helm charts.
+type Kubernetes struct {
+	// Paths are gitignore style patterns of the manifests and charts to
+	// validate (e.g. deploy/, charts/).
+	Paths []string `mapstructure:"paths"`
+	// Version is the Kubernetes version that manifests must be compatible
+	// with (e.g. 1.22). When empty, all known API removals are reported.
+	Version string `mapstructure:"version"`
+	// RequiredLabels are the labels that each resource must declare.
+	RequiredLabels []string `mapstructure:"requiredLabels"`
+	// RequiredAnnotations are the annotations that each resource must
+	// declare.
+	RequiredAnnotations []string `mapstructure:"requiredAnnotations"`
+
+	manifests []*Manifest
+	charts    []*Chart
+	tree      *git.Tree
+}
+
+// Manifest is a single resource of a manifest file.
+type Manifest struct {
+	// File is the path of the file the resource was read from.
+	File string
+	// Index is the index of the document within the file.
+	Index int
+	// Template is true if the resource is a Helm template. Only the
+	// apiVersion and kind of templates are known.
+	Template   bool
+	APIVersion string
+	Kind       string
+	Metadata   struct {
+		Name        string            `yaml:"name"`
+		Labels      map[string]string `yaml:"labels"`
+		Annotations map[string]string `yaml:"annotations"`
+	}
+}
+
+// Chart is a Helm Chart.yaml file.
+type Chart struct {
+	File       string
+	APIVersion string `yaml:"apiVersion"`
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+}
+
+var (
+	apiVersionRegex = regexp.MustCompile(`(?m)^apiVersion:\s*["']?([^\s"']+)`)
+	kindRegex       = regexp.MustCompile(`(?m)^kind:\s*["']?([^\s"']+)`)
+	separatorRegex  = regexp.MustCompile(`(?m)^---`)
+)
+
+// Compliance implements the policy.Policy.Compliance function.
+func (k *Kubernetes) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	var g *git.Git
+	if g, err = policy.NewGit(options); err != nil {
+		return report, errors.Errorf("failed to open git repo: %v", err)
+	}
+
+	var files []string
+	if files, err = policy.TrackedFiles(options.Tree, g); err != nil {
+		return report, errors.Errorf("failed to list tracked files: %v", err)
+	}
+	files = options.Ignore.Filter(files)
+
+	k.manifests, k.charts = nil, nil
+	k.tree = options.Tree
+	for _, file := range files {
+		ext := path.Ext(file)
+		if (ext != ".yaml" && ext != ".yml") || !git.MatchAny(k.Paths, file) {
+			continue
+		}
+		if err = k.load(file); err != nil {
+			return report, errors.Errorf("failed to load %s: %v", file, err)
+		}
+	}
+
+	report.AddCheck(k.ValidateAPIVersions())
+
+	if len(k.RequiredLabels) != 0 || len(k.RequiredAnnotations) != 0 {
+		report.AddCheck(k.ValidateMetadata())
+	}
+
+	if len(k.charts) != 0 {
+		report.AddCheck(k.ValidateCharts())
+	}
+
+	return report, nil
+}
+
+// PathPatterns implements the policy.PathMatcher.PathPatterns function.
+func (k *Kubernetes) PathPatterns() []string {
+	return k.Paths
+}
+
+func (k *Kubernetes) load(file string) error {
+	contents, err := policy.ReadFile(k.tree, file)
+	if err != nil {
+		return err
+	}
+
+	if path.Base(file) == "Chart.yaml" {
+		chart := &Chart{File: file}
+		if err = yaml.Unmarshal(contents, chart); err != nil {
+			return err
+		}
+		k.charts = append(k.charts, chart)
+		return nil
+	}
+
+	// Helm templates are not valid YAML until rendered, so the apiVersion and
+	// kind are extracted textually.
+	if bytes.Contains(contents, []byte("{{")) {
+		for i, doc := range separatorRegex.Split(string(contents), -1) {
+			m := &Manifest{File: file, Index: i, Template: true}
+			if groups := apiVersionRegex.FindStringSubmatch(doc); groups != nil {
+				m.APIVersion = groups[1]
+			}
+			if groups := kindRegex.FindStringSubmatch(doc); groups != nil {
+				m.Kind = groups[1]
+			}
+			if m.APIVersion != "" && m.Kind != "" {
+				k.manifests = append(k.manifests, m)
+			}
+		}
+		return nil
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(contents))
+	for i := 0; ; i++ {
+		var raw struct {
+			APIVersion string `yaml:"apiVersion"`
+			Kind       string `yaml:"kind"`
+			Metadata   struct {
+				Name        string            `yaml:"name"`
+				Labels      map[string]string `yaml:"labels"`
+				Annotations map[string]string `yaml:"annotations"`
+			} `yaml:"metadata"`
+		}
+		if err = decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+		if raw.APIVersion == "" || raw.Kind == "" {
+			continue
+		}
+		m := &Manifest{File: file, Index: i, APIVersion: raw.APIVersion, Kind: raw.Kind}
+		m.Metadata = raw.Metadata
+		k.manifests = append(k.manifests, m)
+	}
+
+	return nil
+}
+
+// location returns a human readable location of the resource.
+func (m *Manifest) location() string {
+	name := m.Kind
+	if m.Metadata.Name != "" {
+		name += "/" + m.Metadata.Name
+	}
+
+	return strings.Join([]string{m.File, name}, ": ")
+}
@@ -9,8 +9,6 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
-	"path/filepath"
-	"strings"
 
 	"github.com/autonomy/conform/internal/policy"
 	"github.com/pkg/errors"
@@ -19,15 +17,8 @@ import (
 // License implements the policy.Policy interface and enforces source code
 // license headers.
 type License struct {
-	// SkipPaths applies fnmatch-style patterns to file paths to skip completely
-	// parts of the tree which shouldn't be scanned (e.g. .git/)
-	SkipPaths []string `mapstructure:"skipPaths"`
-	// IncludeSuffixes is the regex used to find files that the license policy
-	// should be applied to.
-	IncludeSuffixes []string `mapstructure:"includeSuffixes"`
-	// ExcludeSuffixes is the Suffixes used to find files that the license policy
-	// should not be applied to.
-	ExcludeSuffixes []string `mapstructure:"excludeSuffixes"`
+	// Files is the set of files that the license policy should be applied to.
+	policy.Files `mapstructure:",squash"`
 	// Header is the contents of the license header.
 	Header string `mapstructure:"header"`
 }
@@ -36,7 +27,7 @@ type License struct {
 func (l *License) Compliance(options *policy.Options) (*policy.Report, error) {
 	report := &policy.Report{}
 
-	report.AddCheck(l.ValidateLicenseHeader())
+	report.AddCheck(l.ValidateLicenseHeader(options))
 
 	return report, nil
 }
@@ -66,62 +57,21 @@ func (l HeaderCheck) Errors() []error {
 
 // ValidateLicenseHeader checks the header of a file and ensures it contains the
 // provided value.
-// nolint: gocyclo
-func (l License) ValidateLicenseHeader() policy.Check {
+func (l License) ValidateLicenseHeader(options *policy.Options) policy.Check {
 	check := HeaderCheck{}
 	if l.Header == "" {
 		check.errors = append(check.errors, errors.New("Header is not defined"))
 		return check
 	}
 	value := []byte(l.Header)
-	err := filepath.Walk(".", func(path string, info os.FileInfo, err error) error {
+	err := l.Walk(options, func(path string, info os.FileInfo) error {
+		contents, err := policy.ReadFile(options.Tree, path)
 		if err != nil {
-			return err
+			check.errors = append(check.errors, policy.FileError(path, 0, errors.Errorf("Failed to open %s", path)))
+			return nil
 		}
-
-		matchPath := path
-		if info.IsDir() {
-			// for directories, match against "dir/
-			matchPath += "/"
-		}
-		for _, pattern := range l.SkipPaths {
-			var matches bool
-			matches, err = filepath.Match(pattern, matchPath)
-			if err != nil {
-				return err
-			}
-			if matches {
-				if info.IsDir() {
-					// skip whole directory tree
-					return filepath.SkipDir
-				}
-				// skip single file
-				return nil
-			}
-		}
-
-		if info.Mode().IsRegular() {
-			// Skip excluded suffixes.
-			for _, suffix := range l.ExcludeSuffixes {
-				if strings.HasSuffix(info.Name(), suffix) {
-					return nil
-				}
-			}
-			// Check files matching the included suffixes.
-			for _, suffix := range l.IncludeSuffixes {
-				if strings.HasSuffix(info.Name(), suffix) {
-					var contents []byte
-					if contents, err = ioutil.ReadFile(path); err != nil {
-						check.errors = append(check.errors, errors.Errorf("Failed to open %s", path))
-						return nil
-					}
-					// ValidateLicenseHeader(&report, info.Name(), contents, []byte(l.Header))
-					if bytes.HasPrefix(contents, value) {
-						continue
-					}
-					check.errors = append(check.errors, errors.Errorf("File %s does not contain a license header", info.Name()))
-				}
-			}
+		if !bytes.HasPrefix(contents, value) {
+			check.errors = append(check.errors, policy.FileError(path, 1, errors.Errorf("File %s does not contain a license header", path)))
 		}
 		return nil
 	})
@@ -131,3 +81,40 @@ func (l License) ValidateLicenseHeader() policy.Check {
 
 	return check
 }
+
+// Fixes implements the policy.Fixer.Fixes function, prepending the license
+// header to the files without it.
+func (l *License) Fixes(options *policy.Options) ([]policy.Fix, error) {
+	if l.Header == "" {
+		return nil, nil
+	}
+	value := []byte(l.Header)
+	repair := func(contents []byte) []byte {
+		if bytes.HasPrefix(contents, value) {
+			return contents
+		}
+		return append(append([]byte{}, value...), contents...)
+	}
+	var fixes []policy.Fix
+	err := l.Walk(options, func(path string, info os.FileInfo) error {
+		contents, err := policy.ReadFile(options.Tree, path)
+		if err != nil {
+			return errors.Errorf("Failed to open %s", path)
+		}
+		if bytes.HasPrefix(contents, value) {
+			return nil
+		}
+		mode := info.Mode()
+		fixes = append(fixes, policy.Fix{
+			File:        path,
+			Description: "Add the license header",
+			Apply: func() error {
+				return ioutil.WriteFile(path, repair(contents), mode)
+			},
+			Repair: repair,
+		})
+		return nil
+	})
+
+	return fixes, err
+}
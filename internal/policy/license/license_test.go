@@ -0,0 +1,109 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package license
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestValidateLicenseHeader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.Chdir(wd)
+
+	header := "// Copyright Acme\n"
+	files := map[string]string{
+		"main.go":                 header + "package main\n",
+		"a.pb.go":                 "package main\n",
+		"api/api.pb.go":           "package api\n",
+		"api/api.go":              "package api\n",
+		"vendor/a/a.go":           "package a\n",
+		"third_party/vendor/b.go": "package b\n",
+		"docs/doc.go":             "package docs\n",
+	}
+	for name, contents := range files {
+		if err = os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(name, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, test := range []struct {
+		Name      string
+		SkipPaths []string
+		Expected  []string
+	}{
+		{
+			Name:     "None",
+			Expected: []string{"a.pb.go", "api/api.go", "api/api.pb.go", "docs/doc.go", "third_party/vendor/b.go", "vendor/a/a.go"},
+		},
+		{
+			// Patterns with a slash before their end keep the meaning of the
+			// fnmatch patterns.
+			Name:      "Slash",
+			SkipPaths: []string{"docs/*", "api/*.pb.go"},
+			Expected:  []string{"a.pb.go", "api/api.go", "third_party/vendor/b.go", "vendor/a/a.go"},
+		},
+		{
+			// Patterns without a slash, or with a trailing one only, match
+			// at any depth.
+			Name:      "Name",
+			SkipPaths: []string{"*.pb.go", "vendor/"},
+			Expected:  []string{"api/api.go", "docs/doc.go"},
+		},
+		{
+			Name:      "Anchored",
+			SkipPaths: []string{"/*.pb.go", "/vendor/"},
+			Expected:  []string{"api/api.go", "api/api.pb.go", "docs/doc.go", "third_party/vendor/b.go"},
+		},
+		{
+			Name:      "Double star",
+			SkipPaths: []string{"**/vendor/**", "api/**"},
+			Expected:  []string{"a.pb.go", "docs/doc.go"},
+		},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			l := License{
+				Files:  policy.Files{SkipPaths: test.SkipPaths, IncludeSuffixes: []string{".go"}},
+				Header: header,
+			}
+			var actual []string
+			for _, err := range l.ValidateLicenseHeader(policy.NewDefaultOptions()).Errors() {
+				location, ok := policy.LocationOf(err)
+				if !ok {
+					tt.Fatalf("Expected a violation of a file, got %v", err)
+				}
+				actual = append(actual, location.File)
+			}
+			sort.Strings(actual)
+			if !reflect.DeepEqual(actual, test.Expected) {
+				tt.Errorf("Expected the violations of %v, got %v", test.Expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package policy
+
+import (
+	"path/filepath"
+)
+
+// Location locates a violation in a file, or in a commit, for the reports
+// that link violations to their source, such as SARIF.
+type Location struct {
+	// File is the slash separated path of the file, relative to the working
+	// directory.
+	File string
+	// Line is the 1-indexed line of the file, or 0 if the violation is of
+	// the whole file.
+	Line int
+	// Commit is the SHA of the commit.
+	Commit string
+}
+
+// locatedError is the error of a violation with a location. Its message is
+// that of the violation, so that it is suppressed and baselined in the same
+// way as errors without a location.
+type locatedError struct {
+	err      error
+	location Location
+}
+
+func (e *locatedError) Error() string {
+	return e.err.Error()
+}
+
+// FileError returns the error of a violation at the line of the file, or of
+// the whole file if line is 0.
+func FileError(name string, line int, err error) error {
+	return &locatedError{err: err, location: Location{File: filepath.ToSlash(name), Line: line}}
+}
+
+// CommitError returns the error of a violation of the commit of the SHA.
+func CommitError(sha string, err error) error {
+	return &locatedError{err: err, location: Location{Commit: sha}}
+}
+
+// LocationOf returns the location of the error of a violation, if it has
+// one.
+func LocationOf(err error) (Location, bool) {
+	if e, ok := err.(*locatedError); ok {
+		return e.location, true
+	}
+
+	return Location{}, false
+}
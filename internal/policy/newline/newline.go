@@ -0,0 +1,149 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package newline
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// Newline implements the policy.Policy interface and enforces that text
+// files end with exactly one newline.
+type Newline struct {
+	// Files is the set of files that the newline policy should be applied to.
+	policy.Files `mapstructure:",squash"`
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (n *Newline) Compliance(options *policy.Options) (*policy.Report, error) {
+	report := &policy.Report{}
+
+	report.AddCheck(n.ValidateEOFNewline(options))
+
+	return report, nil
+}
+
+// EOFCheck enforces that files end with exactly one newline.
+type EOFCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (e EOFCheck) Name() string {
+	return "EOF Newline"
+}
+
+// Message returns to check message.
+func (e EOFCheck) Message() string {
+	if len(e.errors) != 0 {
+		return fmt.Sprintf("Found %d files without exactly one newline at EOF", len(e.errors))
+	}
+	return "All files end with exactly one newline"
+}
+
+// Errors returns any violations of the check.
+func (e EOFCheck) Errors() []error {
+	return e.errors
+}
+
+// ValidateEOFNewline checks that each file ends with exactly one newline.
+func (n Newline) ValidateEOFNewline(options *policy.Options) policy.Check {
+	check := EOFCheck{}
+	err := n.Walk(options, func(path string, info os.FileInfo) error {
+		contents, err := policy.ReadFile(options.Tree, path)
+		if err != nil {
+			check.errors = append(check.errors, policy.FileError(path, 0, errors.Errorf("Failed to open %s", path)))
+			return nil
+		}
+		if len(contents) == 0 {
+			return nil
+		}
+		_, count := trimNewlines(contents)
+		if count == 1 {
+			return nil
+		}
+		if count == 0 {
+			check.errors = append(check.errors, policy.FileError(path, 0, errors.Errorf("File %s does not end with a newline", path)))
+		} else {
+			check.errors = append(check.errors, policy.FileError(path, 0, errors.Errorf("File %s ends with %d newlines", path, count)))
+		}
+		return nil
+	})
+	if err != nil {
+		check.errors = append(check.errors, errors.Errorf("Failed to walk directory: %v", err))
+	}
+
+	return check
+}
+
+// trimNewlines removes all trailing LF and CRLF line endings from contents,
+// and returns the result along with the number of line endings removed.
+func trimNewlines(contents []byte) ([]byte, int) {
+	count := 0
+	for bytes.HasSuffix(contents, []byte("\n")) {
+		contents = bytes.TrimSuffix(contents, []byte("\n"))
+		contents = bytes.TrimSuffix(contents, []byte("\r"))
+		count++
+	}
+
+	return contents, count
+}
+
+// lineEnding returns the line ending of the last line of the contents, so
+// that fixes keep the line endings of the file: CRLF if it ends with CRLF, and
+// LF otherwise.
+func lineEnding(contents []byte) []byte {
+	if bytes.HasSuffix(contents, []byte("\r\n")) {
+		return []byte("\r\n")
+	}
+
+	return []byte("\n")
+}
+
+// Fixes implements the policy.Fixer.Fixes function, ending the files that do
+// not end with exactly one newline with exactly one.
+func (n *Newline) Fixes(options *policy.Options) ([]policy.Fix, error) {
+	var fixes []policy.Fix
+	err := n.Walk(options, func(path string, info os.FileInfo) error {
+		contents, err := policy.ReadFile(options.Tree, path)
+		if err != nil {
+			return errors.Errorf("Failed to open %s", path)
+		}
+		if len(contents) == 0 {
+			return nil
+		}
+		if _, count := trimNewlines(contents); count == 1 {
+			return nil
+		}
+		mode := info.Mode()
+		fixes = append(fixes, policy.Fix{
+			File:        path,
+			Description: "End the file with exactly one newline",
+			Apply: func() error {
+				return ioutil.WriteFile(path, repairNewlines(contents), mode)
+			},
+			Repair: repairNewlines,
+		})
+		return nil
+	})
+
+	return fixes, err
+}
+
+// repairNewlines returns the contents ending with exactly one newline, unless
+// they are empty.
+func repairNewlines(contents []byte) []byte {
+	if len(contents) == 0 {
+		return contents
+	}
+	body, _ := trimNewlines(contents)
+
+	return append(append([]byte{}, body...), lineEnding(contents)...)
+}
@@ -0,0 +1,86 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package newline
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestValidateEOFNewline(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Contents string
+		Errors   int
+	}{
+		{Name: "Newline", Contents: "a\n"},
+		{Name: "Empty", Contents: ""},
+		{Name: "Missing", Contents: "a", Errors: 1},
+		{Name: "Several", Contents: "a\n\n\n", Errors: 1},
+		{Name: "CRLF", Contents: "a\r\n"},
+		{Name: "Several CRLF", Contents: "a\r\nb\r\n\r\n", Errors: 1},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			dir, err := ioutil.TempDir("", "conform")
+			if err != nil {
+				tt.Fatal(err)
+			}
+			// nolint: errcheck
+			defer os.RemoveAll(dir)
+			wd, err := os.Getwd()
+			if err != nil {
+				tt.Fatal(err)
+			}
+			if err = os.Chdir(dir); err != nil {
+				tt.Fatal(err)
+			}
+			// nolint: errcheck
+			defer os.Chdir(wd)
+
+			if err = ioutil.WriteFile("a.txt", []byte(test.Contents), 0644); err != nil {
+				tt.Fatal(err)
+			}
+			n := Newline{Files: policy.Files{IncludeSuffixes: []string{".txt"}}}
+			if errs := n.ValidateEOFNewline(policy.NewDefaultOptions()).Errors(); len(errs) != test.Errors {
+				tt.Errorf("Expected %d errors, got %v", test.Errors, errs)
+			}
+			contents, err := ioutil.ReadFile("a.txt")
+			if err != nil {
+				tt.Fatal(err)
+			}
+			// The check never fixes the files.
+			if string(contents) != test.Contents {
+				tt.Errorf("Expected the contents %q, got %q", test.Contents, contents)
+			}
+		})
+	}
+}
+
+func TestRepairNewlines(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Contents string
+		Expected string
+	}{
+		{"Empty", "", ""},
+		{"Missing", "a", "a\n"},
+		{"Several", "a\n\n", "a\n"},
+		{"CRLF", "a\r\n\r\n", "a\r\n"},
+		{"Mixed", "a\r\n\n", "a\n"},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			if actual := string(repairNewlines([]byte(test.Contents))); actual != test.Expected {
+				tt.Errorf("Expected %q, got %q", test.Expected, actual)
+			}
+		})
+	}
+}
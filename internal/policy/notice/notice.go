@@ -0,0 +1,110 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package notice
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// DefaultPath is the default path of the NOTICE file.
+const DefaultPath = "NOTICE"
+
+// Notice implements the policy.Policy interface and enforces the presence and
+// contents of a NOTICE file, as commonly required by Apache-2.0 projects that
+// bundle third-party code.
+type Notice struct {
+	// Path is the path of the NOTICE file. Defaults to DefaultPath.
+	Path string `mapstructure:"path"`
+	// Required are regular expressions that must each match the contents of
+	// the file (e.g. "^This product includes software developed by"). The
+	// patterns are matched in multi-line mode, so that ^ and $ match at line
+	// boundaries.
+	Required []string `mapstructure:"required"`
+
+	contents []byte
+	missing  bool
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (n *Notice) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	n.contents, err = policy.ReadFile(options.Tree, n.path())
+	n.missing = os.IsNotExist(err)
+	if err != nil && !n.missing {
+		return report, errors.Errorf("failed to read %s: %v", n.path(), err)
+	}
+
+	report.AddCheck(n.ValidateNotice())
+
+	return report, nil
+}
+
+// Validate implements the policy.Validator.Validate function.
+func (n *Notice) Validate() []error {
+	return policy.ValidateRegexps("pattern", n.Required...)
+}
+
+func (n Notice) path() string {
+	if n.Path == "" {
+		return DefaultPath
+	}
+	return n.Path
+}
+
+// NoticeCheck ensures that the NOTICE file exists and contains the required
+// attributions.
+type NoticeCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (n NoticeCheck) Name() string {
+	return "NOTICE File"
+}
+
+// Message returns to check message.
+func (n NoticeCheck) Message() string {
+	if len(n.errors) != 0 {
+		return fmt.Sprintf("Found %d NOTICE violations", len(n.errors))
+	}
+	return "NOTICE file is valid"
+}
+
+// Errors returns any violations of the check.
+func (n NoticeCheck) Errors() []error {
+	return n.errors
+}
+
+// ValidateNotice checks that the NOTICE file exists and that each required
+// pattern matches its contents.
+func (n Notice) ValidateNotice() policy.Check {
+	check := &NoticeCheck{}
+
+	if n.missing {
+		check.errors = append(check.errors, policy.FileError(n.path(), 0, errors.Errorf("%s does not exist", n.path())))
+		return check
+	}
+
+	for _, pattern := range n.Required {
+		re, err := regexp.Compile("(?m)" + pattern)
+		if err != nil {
+			check.errors = append(check.errors, errors.Errorf("Invalid pattern %q: %v", pattern, err))
+			continue
+		}
+		if !re.Match(n.contents) {
+			check.errors = append(check.errors, policy.FileError(n.path(), 0, errors.Errorf("%s has no attribution matching %q", n.path(), pattern)))
+		}
+	}
+
+	return check
+}
@@ -0,0 +1,31 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package notice
+
+import (
+	"testing"
+)
+
+func TestValidateNotice(t *testing.T) {
+	contents := []byte("Conform\nCopyright 2019 Autonomy\n\nThis product includes software developed by\nThe Apache Software Foundation (http://www.apache.org/).\n")
+	for _, test := range []struct {
+		Name     string
+		Policy   Notice
+		Expected int
+	}{
+		{"Present", Notice{Required: []string{`^Copyright \d{4} Autonomy$`, `Apache Software Foundation`}, contents: contents}, 0},
+		{"Missing attribution", Notice{Required: []string{`^Copyright`, `^Portions of this software`}, contents: contents}, 1},
+		{"Invalid pattern", Notice{Required: []string{`(`}, contents: contents}, 1},
+		{"Missing file", Notice{Required: []string{`^Copyright`}, missing: true}, 1},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			if errs := test.Policy.ValidateNotice().Errors(); len(errs) != test.Expected {
+				tt.Errorf("Expected %d errors, got %v", test.Expected, errs)
+			}
+		})
+	}
+}
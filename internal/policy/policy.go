@@ -4,9 +4,13 @@
 
 package policy
 
+import "time"
+
 // Report summarizes the compliance of a policy.
 type Report struct {
 	checks []Check
+	// added are the times the checks were added at.
+	added []time.Time
 }
 
 // Check defines a policy check.
@@ -21,6 +25,46 @@ type Policy interface {
 	Compliance(*Options) (*Report, error)
 }
 
+// Validator is implemented by policies that can validate their spec, such as
+// by compiling its regular expressions, without running any checks.
+type Validator interface {
+	Validate() []error
+}
+
+// PathMatcher is implemented by policies whose spec declares gitignore style
+// path patterns.
+type PathMatcher interface {
+	PathPatterns() []string
+}
+
+// SuffixMatcher is implemented by policies whose spec declares the suffixes of
+// the files they apply to.
+type SuffixMatcher interface {
+	Suffixes() []string
+}
+
+// Fix is a repair of a violation.
+type Fix struct {
+	// File is the path of the file repaired, empty if the fix is not to a
+	// file.
+	File string
+	// Description describes the repair.
+	Description string
+	// Apply applies the repair. It is nil for fixes that can only be
+	// suggested, such as rewording a commit message.
+	Apply func() error
+	// Repair returns the contents of the file repaired, for the fixes that
+	// know the correct contents of their file, so that the repairs of a file
+	// can be combined into a patch. It is nil for other fixes.
+	Repair func(contents []byte) []byte
+}
+
+// Fixer is implemented by policies that can repair the violations of their
+// checks. Fixes returns the repairs without applying them.
+type Fixer interface {
+	Fixes(*Options) ([]Fix, error)
+}
+
 // Valid checks if a report is valid.
 func (r *Report) Valid() bool {
 	for _, check := range r.checks {
@@ -39,4 +83,22 @@ func (r *Report) Checks() []Check {
 // AddCheck adds a check to the policy report.
 func (r *Report) AddCheck(c Check) {
 	r.checks = append(r.checks, c)
+	r.added = append(r.added, time.Now())
+}
+
+// Durations returns the approximate time taken by each check, for a policy
+// whose enforcement started at start. Policies add their checks as they run
+// them, so each check is timed from the addition of the previous one.
+func (r *Report) Durations(start time.Time) []time.Duration {
+	durations := make([]time.Duration, len(r.added))
+	for i, added := range r.added {
+		if i > 0 {
+			start = r.added[i-1]
+		}
+		if d := added.Sub(start); d > 0 {
+			durations[i] = d
+		}
+	}
+
+	return durations
 }
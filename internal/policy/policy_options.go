@@ -4,12 +4,26 @@
 
 package policy
 
+import (
+	"context"
+
+	"github.com/autonomy/conform/internal/git"
+)
+
 // Option is a functional option used to pass in arguments to a Policy.
 type Option func(*Options)
 
 // Options defines the set of options available to a Policy.
 type Options struct {
 	CommitMsgFile *string
+	BaseBranch    *string
+	CommitCount   int
+	AuthorName    *string
+	AuthorEmail   *string
+	Ref           *string
+	Ignore        *git.Ignore
+	Context       context.Context
+	Tree          *git.Tree
 }
 
 // WithCommitMsgFile sets the path to the commit message file.
@@ -19,10 +33,97 @@ func WithCommitMsgFile(o *string) Option {
 	}
 }
 
+// WithBaseBranch sets the base branch that HEAD is compared against when a
+// policy operates on the changes being enforced.
+func WithBaseBranch(o *string) Option {
+	return func(args *Options) {
+		args.BaseBranch = o
+	}
+}
+
+// WithCommitCount enforces the messages of the specified number of the most
+// recent commits, rather than only the message of HEAD. The base branch is
+// expected to be set to the parent of the oldest of the commits, so that
+// policies of changes enforce the changes of the commits.
+func WithCommitCount(o int) Option {
+	return func(args *Options) {
+		args.CommitCount = o
+	}
+}
+
+// WithAuthorName sets the name of the author of the commit being enforced,
+// overriding the author of HEAD, e.g. for a commit message file of a commit
+// that does not exist yet.
+func WithAuthorName(o *string) Option {
+	return func(args *Options) {
+		args.AuthorName = o
+	}
+}
+
+// WithAuthorEmail sets the email of the author of the commit being enforced,
+// overriding the author of HEAD.
+func WithAuthorEmail(o *string) Option {
+	return func(args *Options) {
+		args.AuthorEmail = o
+	}
+}
+
+// WithRef sets the branch the commit being enforced is made on, overriding
+// the branch HEAD points to.
+func WithRef(o *string) Option {
+	return func(args *Options) {
+		args.Ref = o
+	}
+}
+
+// WithIgnore sets the paths ignored by the policies that scan files, as
+// declared in the .conformignore file of the repository.
+func WithIgnore(o *git.Ignore) Option {
+	return func(args *Options) {
+		args.Ignore = o
+	}
+}
+
+// WithContext sets the context of the enforcement of a policy, canceled when
+// the policy times out. Policies that walk files, run commands, or make
+// network calls stop once it is done.
+func WithContext(o context.Context) Option {
+	return func(args *Options) {
+		args.Context = o
+	}
+}
+
+// WithTree sets the tree of the commit that file based policies read files
+// from, instead of the working tree.
+func WithTree(o *git.Tree) Option {
+	return func(args *Options) {
+		args.Tree = o
+	}
+}
+
+// Context returns the context a policy saved from its options, or the
+// background context if its checks are run without enforcing it, e.g. in
+// tests.
+func Context(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+
+	return ctx
+}
+
 // NewDefaultOptions initializes a Options struct with default values.
 func NewDefaultOptions(setters ...Option) *Options {
 	opts := &Options{
 		CommitMsgFile: nil,
+		BaseBranch:    nil,
+		CommitCount:   0,
+		AuthorName:    nil,
+		AuthorEmail:   nil,
+		Ref:           nil,
+		Ignore:        nil,
+		Context:       context.Background(),
+		Tree:          nil,
 	}
 
 	for _, setter := range setters {
@@ -0,0 +1,114 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package pullrequest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// ApprovalsCheck enforces the approvals of the pull request.
+type ApprovalsCheck struct {
+	approvals int
+	errors    []error
+}
+
+// Name returns the name of the check.
+func (a ApprovalsCheck) Name() string {
+	return "Approvals"
+}
+
+// Message returns to check message.
+func (a ApprovalsCheck) Message() string {
+	if len(a.errors) != 0 {
+		return fmt.Sprintf("Found %d approval violations", len(a.errors))
+	}
+	return fmt.Sprintf("Approved by %d reviewers", a.approvals)
+}
+
+// Errors returns any violations of the check.
+func (a ApprovalsCheck) Errors() []error {
+	return a.errors
+}
+
+// ValidateApprovals checks the number of approvals, and that the owners of the
+// changed files approved. The author of the pull request never counts as an
+// approver.
+func (p PullRequest) ValidateApprovals() policy.Check {
+	check := &ApprovalsCheck{}
+
+	approvers := map[string]bool{}
+	for _, user := range p.approvals {
+		if !strings.EqualFold(user, p.pr.Author) {
+			approvers[strings.ToLower(user)] = true
+		}
+	}
+	check.approvals = len(approvers)
+
+	if check.approvals < p.MinimumApprovals {
+		check.errors = append(check.errors, errors.Errorf("%d approvals, minimum is %d", check.approvals, p.MinimumApprovals))
+	}
+
+	if !p.RequireCodeOwnerApproval {
+		return check
+	}
+
+	// Cache whether each set of owners approved, since many files usually
+	// share the same owners.
+	approved := map[string]bool{}
+	for _, diff := range p.diffs {
+		owners := p.codeOwners.Owners(diff.Path())
+		if len(owners) == 0 {
+			continue
+		}
+		key := strings.Join(owners, " ")
+		ok, seen := approved[key]
+		if !seen {
+			var err error
+			if ok, err = p.ownerApproved(owners, approvers); err != nil {
+				check.errors = append(check.errors, err)
+				return check
+			}
+			approved[key] = ok
+		}
+		if !ok {
+			check.errors = append(check.errors, errors.Errorf("%s requires approval by %s", diff.Path(), strings.Join(owners, ", ")))
+		}
+	}
+
+	return check
+}
+
+// ownerApproved reports whether any of the owners approved. Owners are users
+// (@user) or teams (@org/team). Email owners cannot be matched to reviewers
+// and are ignored.
+func (p PullRequest) ownerApproved(owners []string, approvers map[string]bool) (bool, error) {
+	for _, owner := range owners {
+		if !strings.HasPrefix(owner, "@") {
+			continue
+		}
+		owner = owner[1:]
+		if !strings.Contains(owner, "/") {
+			if approvers[strings.ToLower(owner)] {
+				return true, nil
+			}
+			continue
+		}
+		members, err := p.provider.TeamMembers(owner)
+		if err != nil {
+			return false, errors.Errorf("Failed to get members of %s: %v", owner, err)
+		}
+		for _, member := range members {
+			if approvers[strings.ToLower(member)] {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,97 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package pullrequest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+var (
+	headingRegex     = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*\s*$`)
+	taskRegex        = regexp.MustCompile(`^\s*[-*+]\s+\[( |x|X)\]\s+(.*)$`)
+	htmlCommentRegex = regexp.MustCompile(`(?s)<!--.*?-->`)
+)
+
+// DescriptionCheck enforces the description of the pull request.
+type DescriptionCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (d DescriptionCheck) Name() string {
+	return "Description"
+}
+
+// Message returns to check message.
+func (d DescriptionCheck) Message() string {
+	if len(d.errors) != 0 {
+		return fmt.Sprintf("Found %d description violations", len(d.errors))
+	}
+	return "Description is complete"
+}
+
+// Errors returns any violations of the check.
+func (d DescriptionCheck) Errors() []error {
+	return d.errors
+}
+
+// ValidateDescription checks that the description of the pull request has the
+// required sections, and that its tasks are completed. HTML comments, often
+// used for instructions in templates, are ignored.
+func (p PullRequest) ValidateDescription() policy.Check {
+	check := &DescriptionCheck{}
+
+	sections := Sections(htmlCommentRegex.ReplaceAllString(p.pr.Body, ""))
+
+	for _, required := range p.RequiredSections {
+		content, ok := sections[strings.ToLower(required)]
+		switch {
+		case !ok:
+			check.errors = append(check.errors, errors.Errorf("Missing section %q", required))
+		case strings.TrimSpace(content) == "":
+			check.errors = append(check.errors, errors.Errorf("Section %q is empty", required))
+		}
+	}
+
+	if p.RequireCompletedTasks {
+		for _, line := range strings.Split(p.pr.Body, "\n") {
+			if m := taskRegex.FindStringSubmatch(line); m != nil && m[1] == " " {
+				check.errors = append(check.errors, errors.Errorf("Task not completed: %s", strings.TrimSpace(m[2])))
+			}
+		}
+	}
+
+	return check
+}
+
+// Sections splits Markdown into the content following each heading, keyed by
+// the lower case heading text.
+func Sections(markdown string) map[string]string {
+	sections := map[string]string{}
+
+	var heading string
+	var content []string
+	inHeading := false
+	for _, line := range strings.Split(strings.Replace(markdown, "\r\n", "\n", -1), "\n") {
+		if m := headingRegex.FindStringSubmatch(line); m != nil {
+			if inHeading {
+				sections[heading] = strings.Join(content, "\n")
+			}
+			heading, content, inHeading = strings.ToLower(m[1]), nil, true
+			continue
+		}
+		content = append(content, line)
+	}
+	if inHeading {
+		sections[heading] = strings.Join(content, "\n")
+	}
+
+	return sections
+}
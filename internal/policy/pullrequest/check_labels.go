@@ -0,0 +1,62 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package pullrequest
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// LabelsCheck enforces the labels of the pull request.
+type LabelsCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (l LabelsCheck) Name() string {
+	return "Labels"
+}
+
+// Message returns to check message.
+func (l LabelsCheck) Message() string {
+	if len(l.errors) != 0 {
+		return fmt.Sprintf("Missing %d required labels", len(l.errors))
+	}
+	return "Required labels are present"
+}
+
+// Errors returns any violations of the check.
+func (l LabelsCheck) Errors() []error {
+	return l.errors
+}
+
+// ValidateLabels checks that each required label pattern matches a label of
+// the pull request.
+func (p PullRequest) ValidateLabels() policy.Check {
+	check := &LabelsCheck{}
+
+	for _, pattern := range p.RequiredLabels {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			check.errors = append(check.errors, errors.Errorf("Invalid label pattern %q: %v", pattern, err))
+			continue
+		}
+		found := false
+		for _, label := range p.pr.Labels {
+			if re.MatchString(label) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			check.errors = append(check.errors, errors.Errorf("No label matching %q", pattern))
+		}
+	}
+
+	return check
+}
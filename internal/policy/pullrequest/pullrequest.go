@@ -0,0 +1,124 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package pullrequest
+
+import (
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/autonomy/conform/internal/provider"
+	"github.com/pkg/errors"
+)
+
+// PullRequest implements the policy.Policy interface and enforces rules on
+// the pull request being built, as reported by GitHub or GitLab. Outside of a
+// pull request the checks pass.
+type PullRequest struct {
+	// RequiredLabels are regular expressions that must each match at least
+	// one label of the pull request (e.g. "^semver:" or "^area/").
+	RequiredLabels []string `mapstructure:"requiredLabels"`
+	// RequiredSections are the Markdown headings that the description must
+	// contain, each followed by some content.
+	RequiredSections []string `mapstructure:"requiredSections"`
+	// RequireCompletedTasks requires that every task list item of the
+	// description is checked.
+	RequireCompletedTasks bool `mapstructure:"requireCompletedTasks"`
+	// MinimumApprovals is the minimum number of approving reviews.
+	MinimumApprovals int `mapstructure:"minimumApprovals"`
+	// RequireCodeOwnerApproval requires that each changed file with an owner
+	// in the CODEOWNERS file is approved by one of its owners.
+	RequireCodeOwnerApproval bool `mapstructure:"requireCodeOwnerApproval"`
+
+	provider   provider.Provider
+	pr         *provider.PullRequest
+	approvals  []string
+	codeOwners *git.CodeOwners
+	diffs      []*git.FileDiff
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (p *PullRequest) Compliance(options *policy.Options) (*policy.Report, error) {
+	report := &policy.Report{}
+
+	var err error
+	p.provider, err = provider.New(options.Context)
+	if err == provider.ErrNotPullRequest {
+		report.AddCheck(NotPullRequestCheck{})
+		return report, nil
+	}
+	if err != nil {
+		return report, errors.Errorf("failed to detect provider: %v", err)
+	}
+	if p.pr, err = p.provider.PullRequest(); err != nil {
+		return report, errors.Errorf("failed to get pull request: %v", err)
+	}
+
+	if len(p.RequiredLabels) != 0 {
+		report.AddCheck(p.ValidateLabels())
+	}
+	if len(p.RequiredSections) != 0 || p.RequireCompletedTasks {
+		report.AddCheck(p.ValidateDescription())
+	}
+	if p.MinimumApprovals != 0 || p.RequireCodeOwnerApproval {
+		if err = p.approvalsInput(options); err != nil {
+			return report, err
+		}
+		report.AddCheck(p.ValidateApprovals())
+	}
+
+	return report, nil
+}
+
+// Validate implements the policy.Validator.Validate function.
+func (p *PullRequest) Validate() []error {
+	return policy.ValidateRegexps("label pattern", p.RequiredLabels...)
+}
+
+func (p *PullRequest) approvalsInput(options *policy.Options) (err error) {
+	if p.approvals, err = p.provider.Approvals(); err != nil {
+		return errors.Errorf("failed to get approvals: %v", err)
+	}
+	if !p.RequireCodeOwnerApproval {
+		return nil
+	}
+
+	if p.codeOwners, err = policy.ReadCodeOwners(options.Tree); err != nil {
+		return errors.Errorf("failed to read CODEOWNERS: %v", err)
+	}
+
+	var g *git.Git
+	if g, err = policy.NewGit(options); err != nil {
+		return errors.Errorf("failed to open git repo: %v", err)
+	}
+	// The files changed by the pull request are those changed since its
+	// base, unless another base branch is given.
+	base := p.pr.BaseSHA
+	if options.BaseBranch != nil {
+		base = *options.BaseBranch
+	}
+	if p.diffs, err = g.Diff(base); err != nil {
+		return errors.Errorf("failed to get diff: %v", err)
+	}
+
+	return nil
+}
+
+// NotPullRequestCheck is reported in place of the pull request checks when
+// not running for a pull request.
+type NotPullRequestCheck struct{}
+
+// Name returns the name of the check.
+func (n NotPullRequestCheck) Name() string {
+	return "Pull Request"
+}
+
+// Message returns to check message.
+func (n NotPullRequestCheck) Message() string {
+	return "Not a pull request, skipping"
+}
+
+// Errors returns any violations of the check.
+func (n NotPullRequestCheck) Errors() []error {
+	return nil
+}
@@ -0,0 +1,107 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package pullrequest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/provider"
+)
+
+func TestValidateLabels(t *testing.T) {
+	pr := &provider.PullRequest{Labels: []string{"semver:minor", "area/cli"}}
+	for _, test := range []struct {
+		Name     string
+		Labels   []string
+		Expected int
+	}{
+		{"Present", []string{"^semver:(major|minor|patch)$", "^area/"}, 0},
+		{"Missing", []string{"^semver:", "^kind/"}, 1},
+		{"Invalid", []string{"("}, 1},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			p := PullRequest{RequiredLabels: test.Labels, pr: pr}
+			if errs := p.ValidateLabels().Errors(); len(errs) != test.Expected {
+				tt.Errorf("Expected %d errors, got %v", test.Expected, errs)
+			}
+		})
+	}
+}
+
+func TestValidateDescription(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Body     string
+		Expected int
+	}{
+		{"Complete", "## Summary\nAdds a thing.\n\n## Testing\nUnit tests.\n\n- [x] Docs\n- [X] Tests\n", 0},
+		{"Missing", "## Summary\nAdds a thing.\n", 1},
+		{"Empty", "## Summary\n<!-- Describe the change. -->\n\n## Testing\nUnit tests.\n", 1},
+		{"Unchecked", "## Summary\nAdds a thing.\n## Testing\nNone.\n- [ ] Docs\n- [x] Tests\n", 1},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			p := PullRequest{
+				RequiredSections:      []string{"Summary", "testing"},
+				RequireCompletedTasks: true,
+				pr:                    &provider.PullRequest{Body: test.Body},
+			}
+			if errs := p.ValidateDescription().Errors(); len(errs) != test.Expected {
+				tt.Errorf("Expected %d errors, got %v", test.Expected, errs)
+			}
+		})
+	}
+}
+
+type fakeProvider struct {
+	provider.Provider
+	teams map[string][]string
+}
+
+func (f fakeProvider) TeamMembers(team string) ([]string, error) {
+	return f.teams[team], nil
+}
+
+func TestValidateApprovals(t *testing.T) {
+	codeOwners, err := git.ParseCodeOwners(strings.NewReader("* @lead\n/docs/ @org/docs # Docs team\n/vendor/\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	diffs := []*git.FileDiff{{To: "docs/README.md"}, {To: "docs/guide.md"}, {To: "vendor/dep.go"}}
+	prov := fakeProvider{teams: map[string][]string{"org/docs": {"writer"}}}
+	for _, test := range []struct {
+		Name      string
+		Minimum   int
+		Approvals []string
+		Expected  int
+	}{
+		{"Team member", 1, []string{"writer"}, 0},
+		{"Not enough", 2, []string{"writer"}, 1},
+		{"Author", 2, []string{"author", "writer"}, 1},
+		{"Not owner", 0, []string{"lead"}, 2},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			p := PullRequest{
+				MinimumApprovals:         test.Minimum,
+				RequireCodeOwnerApproval: true,
+				provider:                 prov,
+				pr:                       &provider.PullRequest{Author: "author"},
+				approvals:                test.Approvals,
+				codeOwners:               codeOwners,
+				diffs:                    diffs,
+			}
+			if errs := p.ValidateApprovals().Errors(); len(errs) != test.Expected {
+				tt.Errorf("Expected %d errors, got %v", test.Expected, errs)
+			}
+		})
+	}
+}
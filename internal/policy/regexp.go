@@ -0,0 +1,24 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package policy
+
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+// ValidateRegexps returns an error for each expression that does not compile.
+// The description names the expressions in the errors (e.g. "label pattern").
+func ValidateRegexps(description string, exprs ...string) []error {
+	var errs []error
+	for _, expr := range exprs {
+		if _, err := regexp.Compile(expr); err != nil {
+			errs = append(errs, errors.Errorf("Invalid %s %q: %v", description, expr, err))
+		}
+	}
+
+	return errs
+}
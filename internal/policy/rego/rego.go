@@ -0,0 +1,170 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package rego
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/autonomy/conform/internal/policy/input"
+	"github.com/pkg/errors"
+)
+
+// DefaultQuery is the default query evaluated against the modules.
+const DefaultQuery = "data.conform.deny"
+
+// Rego implements the policy.Policy interface and evaluates user supplied
+// Rego modules against the input.Input of the changes being enforced. The
+// modules are evaluated with the opa binary, which must be in the PATH.
+type Rego struct {
+	// Name is the name of the check. Defaults to "Rego".
+	Name string `mapstructure:"name"`
+	// Modules are the paths of the Rego modules, or of directories of them.
+	Modules []string `mapstructure:"modules"`
+	// Query is the query to evaluate. It must produce a set of violation
+	// messages, or a boolean. Defaults to DefaultQuery.
+	Query string `mapstructure:"query"`
+
+	input *input.Input
+	ctx   context.Context
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (r *Rego) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	if r.input, err = input.New(options); err != nil {
+		return report, err
+	}
+	r.ctx = options.Context
+
+	report.AddCheck(r.ValidateRego())
+
+	return report, nil
+}
+
+// RegoCheck reports the violations produced by the query.
+type RegoCheck struct {
+	name   string
+	errors []error
+}
+
+// Name returns the name of the check.
+func (r RegoCheck) Name() string {
+	return r.name
+}
+
+// Message returns to check message.
+func (r RegoCheck) Message() string {
+	if len(r.errors) != 0 {
+		return fmt.Sprintf("Found %d violations", len(r.errors))
+	}
+	return "No violations"
+}
+
+// Errors returns any violations of the check.
+func (r RegoCheck) Errors() []error {
+	return r.errors
+}
+
+// ValidateRego evaluates the query and reports each violation message.
+func (r Rego) ValidateRego() policy.Check {
+	check := &RegoCheck{name: r.Name}
+	if check.name == "" {
+		check.name = "Rego"
+	}
+
+	query := r.Query
+	if query == "" {
+		query = DefaultQuery
+	}
+
+	value, defined, err := r.eval(query)
+	if err != nil {
+		check.errors = append(check.errors, errors.Errorf("Failed to evaluate %s: %v", query, err))
+		return check
+	}
+	if !defined {
+		return check
+	}
+
+	for _, msg := range Messages(value) {
+		check.errors = append(check.errors, errors.New(msg))
+	}
+	if b, ok := value.(bool); ok && !b {
+		check.errors = append(check.errors, errors.Errorf("Query %s is false", query))
+	}
+
+	return check
+}
+
+func (r Rego) eval(query string) (value interface{}, defined bool, err error) {
+	data, err := json.Marshal(r.input)
+	if err != nil {
+		return nil, false, err
+	}
+
+	args := []string{"eval", "--format", "json", "--stdin-input"}
+	for _, module := range r.Modules {
+		args = append(args, "--data", module)
+	}
+	args = append(args, query)
+
+	cmd := policy.Command(r.ctx, "opa", args...)
+	cmd.Stdin = bytes.NewReader(data)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, false, errors.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()+string(out)))
+	}
+
+	var result struct {
+		Result []struct {
+			Expressions []struct {
+				Value interface{} `json:"value"`
+			} `json:"expressions"`
+		} `json:"result"`
+	}
+	if err = json.Unmarshal(out, &result); err != nil {
+		return nil, false, err
+	}
+	if len(result.Result) == 0 || len(result.Result[0].Expressions) == 0 {
+		return nil, false, nil
+	}
+
+	return result.Result[0].Expressions[0].Value, true, nil
+}
+
+// Messages returns the violation messages of a query result. The result may
+// be a message, an object with a "msg" or "message" key, or a collection of
+// them.
+func Messages(value interface{}) (messages []string) {
+	switch v := value.(type) {
+	case string:
+		messages = append(messages, v)
+	case []interface{}:
+		for _, item := range v {
+			messages = append(messages, Messages(item)...)
+		}
+	case map[string]interface{}:
+		for _, key := range []string{"msg", "message"} {
+			if msg, ok := v[key].(string); ok {
+				return append(messages, msg)
+			}
+		}
+		if data, err := json.Marshal(v); err == nil {
+			messages = append(messages, string(data))
+		}
+	}
+
+	return messages
+}
@@ -0,0 +1,33 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package rego
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMessages(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Value    interface{}
+		Expected []string
+	}{
+		{"String", "a", []string{"a"}},
+		{"Set", []interface{}{"a", "b"}, []string{"a", "b"}},
+		{"Objects", []interface{}{map[string]interface{}{"msg": "a"}, map[string]interface{}{"message": "b"}}, []string{"a", "b"}},
+		{"Object", map[string]interface{}{"rule": "a"}, []string{`{"rule":"a"}`}},
+		{"Empty", []interface{}{}, nil},
+		{"Boolean", true, nil},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			if actual := Messages(test.Value); !reflect.DeepEqual(actual, test.Expected) {
+				tt.Errorf("Expected %v, got %v", test.Expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/jsonschema"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Schema implements the policy.Policy interface and validates YAML and JSON
+// files against JSON Schemas.
+type Schema struct {
+	// Rules map sets of files to the schema they must conform to.
+	Rules []*Rule `mapstructure:"rules"`
+
+	files []string
+	tree  *git.Tree
+}
+
+// Rule declares the schema that a set of files must conform to.
+type Rule struct {
+	// Paths are gitignore style patterns of the files the rule applies to
+	// (e.g. .github/workflows/*.yml).
+	Paths []string `mapstructure:"paths"`
+	// Schema is the local path or HTTP(S) URL of the JSON Schema.
+	Schema string `mapstructure:"schema"`
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (s *Schema) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	var g *git.Git
+	if g, err = policy.NewGit(options); err != nil {
+		return report, errors.Errorf("failed to open git repo: %v", err)
+	}
+
+	if s.files, err = policy.TrackedFiles(options.Tree, g); err != nil {
+		return report, errors.Errorf("failed to list tracked files: %v", err)
+	}
+	s.files = options.Ignore.Filter(s.files)
+	s.tree = options.Tree
+
+	report.AddCheck(s.ValidateSchemas())
+
+	return report, nil
+}
+
+// PathPatterns implements the policy.PathMatcher.PathPatterns function.
+func (s *Schema) PathPatterns() []string {
+	var patterns []string
+	for _, rule := range s.Rules {
+		patterns = append(patterns, rule.Paths...)
+	}
+
+	return patterns
+}
+
+// SchemaCheck ensures that files conform to their schemas.
+type SchemaCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (s SchemaCheck) Name() string {
+	return "Schema"
+}
+
+// Message returns to check message.
+func (s SchemaCheck) Message() string {
+	if len(s.errors) != 0 {
+		return fmt.Sprintf("Found %d schema violations", len(s.errors))
+	}
+	return "All files conform to their schemas"
+}
+
+// Errors returns any violations of the check.
+func (s SchemaCheck) Errors() []error {
+	return s.errors
+}
+
+// ValidateSchemas validates each file matched by a rule against the rule's
+// schema. Every document of a multi-document YAML file is validated.
+func (s Schema) ValidateSchemas() policy.Check {
+	check := &SchemaCheck{}
+
+	for _, rule := range s.Rules {
+		schema, err := jsonschema.Load(rule.Schema)
+		if err != nil {
+			check.errors = append(check.errors, errors.Errorf("Failed to load schema %s: %v", rule.Schema, err))
+			continue
+		}
+		for _, file := range s.files {
+			if !git.MatchAny(rule.Paths, file) {
+				continue
+			}
+			docs, err := decode(s.tree, file)
+			if err != nil {
+				check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("Failed to decode %s: %v", file, err)))
+				continue
+			}
+			for i, doc := range docs {
+				for _, verr := range schema.Validate(doc) {
+					location := file
+					if len(docs) > 1 {
+						location = fmt.Sprintf("%s[%d]", file, i)
+					}
+					check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("File %s does not conform to %s: %v", location, rule.Schema, verr)))
+				}
+			}
+		}
+	}
+
+	return check
+}
+
+func decode(tree *git.Tree, file string) (docs []interface{}, err error) {
+	contents, err := policy.ReadFile(tree, file)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(path.Ext(file), ".json") {
+		var doc interface{}
+		if err = json.Unmarshal(contents, &doc); err != nil {
+			return nil, err
+		}
+		return []interface{}{doc}, nil
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(contents))
+	for {
+		var doc interface{}
+		if err = decoder.Decode(&doc); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		if doc == nil {
+			continue
+		}
+		docs = append(docs, jsonschema.Normalize(doc))
+	}
+
+	return docs, nil
+}
@@ -0,0 +1,79 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package schema
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestValidateSchemas(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.Chdir(wd)
+
+	schema := `{
+  "type": "object",
+  "required": ["name"],
+  "properties": {"name": {"type": "string"}}
+}`
+	files := map[string]string{
+		"schema.json":    schema,
+		"valid.yml":      "name: conform\n",
+		"invalid.yml":    "name: 1\n",
+		"missing.yml":    "other: conform\n",
+		"documents.yml":  "name: a\n---\nname: 2\n---\nother: c\n",
+		"valid.json":     `{"name": "conform"}`,
+		"invalid.json":   `{"name": true}`,
+		"malformed.json": `{"name": `,
+		"malformed.yml":  "name: [\n",
+	}
+	for name, contents := range files {
+		if err = ioutil.WriteFile(name, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, test := range []struct {
+		Name   string
+		Schema string
+		Paths  []string
+		File   string
+		Errors int
+	}{
+		{Name: "Valid YAML", Schema: "schema.json", Paths: []string{"*.yml"}, File: "valid.yml"},
+		{Name: "Invalid YAML", Schema: "schema.json", Paths: []string{"*.yml"}, File: "invalid.yml", Errors: 1},
+		{Name: "Missing property", Schema: "schema.json", Paths: []string{"*.yml"}, File: "missing.yml", Errors: 1},
+		{Name: "Documents", Schema: "schema.json", Paths: []string{"*.yml"}, File: "documents.yml", Errors: 2},
+		{Name: "Valid JSON", Schema: "schema.json", Paths: []string{"*.json"}, File: "valid.json"},
+		{Name: "Invalid JSON", Schema: "schema.json", Paths: []string{"*.json"}, File: "invalid.json", Errors: 1},
+		{Name: "Malformed JSON", Schema: "schema.json", Paths: []string{"*.json"}, File: "malformed.json", Errors: 1},
+		{Name: "Malformed YAML", Schema: "schema.json", Paths: []string{"*.yml"}, File: "malformed.yml", Errors: 1},
+		{Name: "Not matched", Schema: "schema.json", Paths: []string{"*.json"}, File: "invalid.yml"},
+		{Name: "Missing schema", Schema: "missing.json", Paths: []string{"*.yml"}, File: "valid.yml", Errors: 1},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			s := Schema{Rules: []*Rule{{Paths: test.Paths, Schema: test.Schema}}, files: []string{test.File}}
+			if errs := s.ValidateSchemas().Errors(); len(errs) != test.Errors {
+				tt.Errorf("Expected %d errors, got %v", test.Errors, errs)
+			}
+		})
+	}
+}
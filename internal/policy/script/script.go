@@ -0,0 +1,246 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package script
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/autonomy/conform/internal/policy/input"
+	"github.com/pkg/errors"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+// Script implements the policy.Policy interface and executes a Starlark
+// script. The script must define a check function that takes the
+// input.Input of the changes being enforced and returns a violation message,
+// a list of them, or None. The script runs in a sandbox: it cannot load
+// other modules, and can only read tracked files with read_file.
+type Script struct {
+	// Name is the name of the check. Defaults to "Script".
+	Name string `mapstructure:"name"`
+	// Source is the inline source of the script.
+	Source string `mapstructure:"source"`
+	// Path is the path of the script. It is used when Source is empty.
+	Path string `mapstructure:"path"`
+
+	input *input.Input
+	tree  *git.Tree
+	ctx   context.Context
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (s *Script) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	if s.input, err = input.New(options); err != nil {
+		return report, err
+	}
+	s.tree = options.Tree
+	s.ctx = options.Context
+
+	report.AddCheck(s.ValidateScript())
+
+	return report, nil
+}
+
+// ScriptCheck reports the violations returned by the script.
+type ScriptCheck struct {
+	name   string
+	errors []error
+}
+
+// Name returns the name of the check.
+func (s ScriptCheck) Name() string {
+	return s.name
+}
+
+// Message returns to check message.
+func (s ScriptCheck) Message() string {
+	if len(s.errors) != 0 {
+		return fmt.Sprintf("Found %d violations", len(s.errors))
+	}
+	return "No violations"
+}
+
+// Errors returns any violations of the check.
+func (s ScriptCheck) Errors() []error {
+	return s.errors
+}
+
+// ValidateScript executes the script and reports each violation returned by
+// its check function.
+func (s Script) ValidateScript() policy.Check {
+	check := &ScriptCheck{name: s.Name}
+	if check.name == "" {
+		check.name = "Script"
+	}
+
+	filename, src := "inline.star", []byte(s.Source)
+	if s.Source == "" {
+		var err error
+		if src, err = ioutil.ReadFile(s.Path); err != nil {
+			check.errors = append(check.errors, errors.Errorf("Failed to read script: %v", err))
+			return check
+		}
+		filename = s.Path
+	}
+
+	violations, err := Run(s.ctx, filename, src, s.input, s.tree)
+	if err != nil {
+		check.errors = append(check.errors, errors.Errorf("Failed to run script: %v", err))
+		return check
+	}
+	for _, v := range violations {
+		check.errors = append(check.errors, errors.New(v))
+	}
+
+	return check
+}
+
+// Run executes the Starlark source and calls its check function with the
+// input. Files are read from the tree if it is not nil. The script is canceled
+// once the context is done, e.g. when the policy times out.
+func Run(ctx context.Context, filename string, src []byte, in *input.Input, tree *git.Tree) ([]string, error) {
+	value, err := toValue(in)
+	if err != nil {
+		return nil, err
+	}
+
+	tracked := map[string]bool{}
+	for _, file := range in.Files {
+		tracked[file] = true
+	}
+
+	thread := &starlark.Thread{
+		Name:  filename,
+		Print: func(_ *starlark.Thread, msg string) {},
+	}
+	predeclared := starlark.StringDict{
+		"struct":    starlark.NewBuiltin("struct", starlarkstruct.Make),
+		"read_file": starlark.NewBuiltin("read_file", readFile(tree, tracked)),
+	}
+
+	ctx = policy.Context(ctx)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			thread.Cancel(ctx.Err().Error())
+		case <-stop:
+		}
+	}()
+
+	globals, err := starlark.ExecFile(thread, filename, src, predeclared)
+	if err != nil {
+		return nil, err
+	}
+	fn, ok := globals["check"].(starlark.Callable)
+	if !ok {
+		return nil, errors.New("script does not define a check function")
+	}
+	result, err := starlark.Call(thread, fn, starlark.Tuple{value}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return violations(result)
+}
+
+func readFile(tree *git.Tree, tracked map[string]bool) func(*starlark.Thread, *starlark.Builtin, starlark.Tuple, []starlark.Tuple) (starlark.Value, error) {
+	return func(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var name string
+		if err := starlark.UnpackArgs(b.Name(), args, kwargs, "path", &name); err != nil {
+			return nil, err
+		}
+		name = path.Clean(name)
+		if !tracked[name] {
+			return nil, errors.Errorf("%s: %s is not a tracked file", b.Name(), name)
+		}
+		contents, err := policy.ReadFile(tree, name)
+		if err != nil {
+			return nil, errors.Errorf("%s: %v", b.Name(), err)
+		}
+
+		return starlark.String(contents), nil
+	}
+}
+
+func violations(result starlark.Value) ([]string, error) {
+	switch v := result.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.String:
+		return []string{string(v)}, nil
+	case *starlark.List:
+		messages := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			msg, ok := v.Index(i).(starlark.String)
+			if !ok {
+				return nil, errors.Errorf("check returned a list containing %s, expected strings", v.Index(i).Type())
+			}
+			messages = append(messages, string(msg))
+		}
+		return messages, nil
+	default:
+		return nil, errors.Errorf("check returned %s, expected None, a string, or a list of strings", result.Type())
+	}
+}
+
+// toValue converts the input to Starlark values by way of its JSON encoding,
+// so that scripts see the same field names as other engines. Objects are
+// converted to structs, so that fields are accessed as attributes (e.g.
+// input.commit.header).
+func toValue(in *input.Input) (starlark.Value, error) {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err = json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+
+	return convert(v), nil
+}
+
+func convert(v interface{}) starlark.Value {
+	switch v := v.(type) {
+	case nil:
+		return starlark.None
+	case bool:
+		return starlark.Bool(v)
+	case float64:
+		if v == float64(int64(v)) {
+			return starlark.MakeInt64(int64(v))
+		}
+		return starlark.Float(v)
+	case string:
+		return starlark.String(v)
+	case []interface{}:
+		elems := make([]starlark.Value, 0, len(v))
+		for _, item := range v {
+			elems = append(elems, convert(item))
+		}
+		return starlark.NewList(elems)
+	case map[string]interface{}:
+		fields := starlark.StringDict{}
+		for key, item := range v {
+			fields[key] = convert(item)
+		}
+		return starlarkstruct.FromStringDict(starlarkstruct.Default, fields)
+	default:
+		return starlark.String(fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,98 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package script
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/autonomy/conform/internal/policy/input"
+)
+
+func TestRun(t *testing.T) {
+	in := &input.Input{
+		Commit:  input.Commit{Header: "fix: a bug"},
+		Changes: []input.Change{{Path: "main.go", Added: 10}, {Path: "vendor/a.go", Added: 500}},
+	}
+	for _, test := range []struct {
+		Name     string
+		Source   string
+		Expected []string
+		Error    bool
+	}{
+		{
+			Name:     "None",
+			Source:   "def check(input):\n  return None\n",
+			Expected: nil,
+		},
+		{
+			Name:     "String",
+			Source:   "def check(input):\n  if not input.commit.header.startswith('feat'):\n    return 'header must start with feat'\n",
+			Expected: []string{"header must start with feat"},
+		},
+		{
+			Name:     "List",
+			Source:   "def check(input):\n  return [c.path for c in input.changes if c.added > 100]\n",
+			Expected: []string{"vendor/a.go"},
+		},
+		{
+			Name:   "Untracked",
+			Source: "def check(input):\n  return read_file('/etc/passwd')\n",
+			Error:  true,
+		},
+		{
+			Name:   "Load",
+			Source: "load('other.star', 'x')\ndef check(input):\n  return None\n",
+			Error:  true,
+		},
+		{
+			Name:   "Missing",
+			Source: "x = 1\n",
+			Error:  true,
+		},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			actual, err := Run(context.Background(), "test.star", []byte(test.Source), in, nil)
+			if test.Error {
+				if err == nil {
+					tt.Error("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				tt.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, test.Expected) {
+				tt.Errorf("Expected %v, got %v", test.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	src := "def check(input):\n  for i in range(1 << 30):\n    for j in range(1 << 30):\n      pass\n"
+	done := make(chan error, 1)
+	go func() {
+		_, err := Run(ctx, "test.star", []byte(src), &input.Input{}, nil)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+			t.Errorf("Expected the script to be canceled, got %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected the script to stop once its context is done")
+	}
+}
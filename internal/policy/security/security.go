@@ -0,0 +1,144 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package security
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// DefaultPaths are the locations searched for the security policy, in the
+// order GitHub looks for them.
+var DefaultPaths = []string{".github/SECURITY.md", "SECURITY.md", "docs/SECURITY.md"}
+
+var contactRegex = regexp.MustCompile(`(?:mailto:)?[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}|https://[^\s)>\]"']+`)
+
+// Security implements the policy.Policy interface and enforces that the
+// repository has a security policy declaring how to report vulnerabilities.
+type Security struct {
+	// Paths are the locations searched for the security policy. The first
+	// one that exists is used. Defaults to DefaultPaths.
+	Paths []string `mapstructure:"paths"`
+	// Contacts are regular expressions of the accepted security contacts
+	// (e.g. "^security@example\.com$" or "^https://example\.com/security").
+	// The policy must declare an email address or https URL matching one of
+	// them. By default any email address or https URL is accepted.
+	Contacts []string `mapstructure:"contacts"`
+
+	path     string
+	contents string
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (s *Security) Compliance(options *policy.Options) (*policy.Report, error) {
+	report := &policy.Report{}
+
+	paths := s.Paths
+	if len(paths) == 0 {
+		paths = DefaultPaths
+	}
+
+	s.path = ""
+	for _, path := range paths {
+		contents, err := policy.ReadFile(options.Tree, path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return report, errors.Errorf("failed to read %s: %v", path, err)
+		}
+		s.path, s.contents = path, string(contents)
+		break
+	}
+
+	report.AddCheck(s.ValidateSecurity())
+
+	return report, nil
+}
+
+// Validate implements the policy.Validator.Validate function.
+func (s *Security) Validate() []error {
+	return policy.ValidateRegexps("contact pattern", s.Contacts...)
+}
+
+// SecurityCheck ensures that the security policy exists and declares a valid
+// contact.
+type SecurityCheck struct {
+	contact string
+	errors  []error
+}
+
+// Name returns the name of the check.
+func (s SecurityCheck) Name() string {
+	return "Security Policy"
+}
+
+// Message returns to check message.
+func (s SecurityCheck) Message() string {
+	if len(s.errors) != 0 {
+		return fmt.Sprintf("Found %d security policy violations", len(s.errors))
+	}
+	return fmt.Sprintf("Security contact is %s", s.contact)
+}
+
+// Errors returns any violations of the check.
+func (s SecurityCheck) Errors() []error {
+	return s.errors
+}
+
+// ValidateSecurity checks that the security policy exists and that it declares
+// an accepted contact.
+func (s Security) ValidateSecurity() policy.Check {
+	check := &SecurityCheck{}
+
+	if s.path == "" {
+		paths := s.Paths
+		if len(paths) == 0 {
+			paths = DefaultPaths
+		}
+		check.errors = append(check.errors, errors.Errorf("No security policy found in %s", strings.Join(paths, ", ")))
+		return check
+	}
+
+	var patterns []*regexp.Regexp
+	for _, pattern := range s.Contacts {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			check.errors = append(check.errors, errors.Errorf("Invalid contact pattern %q: %v", pattern, err))
+			continue
+		}
+		patterns = append(patterns, re)
+	}
+	if len(check.errors) != 0 {
+		return check
+	}
+
+	for _, contact := range contactRegex.FindAllString(s.contents, -1) {
+		contact = strings.TrimRight(strings.TrimPrefix(contact, "mailto:"), ".,;")
+		if len(patterns) == 0 {
+			check.contact = contact
+			return check
+		}
+		for _, re := range patterns {
+			if re.MatchString(contact) {
+				check.contact = contact
+				return check
+			}
+		}
+	}
+
+	if len(patterns) == 0 {
+		check.errors = append(check.errors, policy.FileError(s.path, 0, errors.Errorf("%s does not declare a security contact email address or URL", s.path)))
+	} else {
+		check.errors = append(check.errors, policy.FileError(s.path, 0, errors.Errorf("%s does not declare an accepted security contact", s.path)))
+	}
+
+	return check
+}
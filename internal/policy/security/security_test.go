@@ -0,0 +1,38 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package security
+
+import (
+	"testing"
+)
+
+func TestValidateSecurity(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Contacts []string
+		Contents string
+		Expected int
+	}{
+		{"Email", nil, "Report vulnerabilities to [security@example.com](mailto:security@example.com).", 0},
+		{"URL", nil, "Use https://example.com/security to report vulnerabilities.", 0},
+		{"No contact", nil, "Open an issue.", 1},
+		{"Accepted", []string{`^security@example\.com$`}, "Email security@example.com.", 0},
+		{"Not accepted", []string{`^security@example\.com$`}, "Email someone@gmail.com.", 1},
+		{"Invalid", []string{`(`}, "Email security@example.com.", 1},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			s := Security{Contacts: test.Contacts, path: "SECURITY.md", contents: test.Contents}
+			if errs := s.ValidateSecurity().Errors(); len(errs) != test.Expected {
+				tt.Errorf("Expected %d errors, got %v", test.Expected, errs)
+			}
+		})
+	}
+
+	if errs := (Security{}).ValidateSecurity().Errors(); len(errs) != 1 {
+		t.Errorf("Expected a missing security policy error, got %v", errs)
+	}
+}
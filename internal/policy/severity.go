@@ -0,0 +1,56 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package policy
+
+// Severity is the severity of the violations of a check.
+type Severity string
+
+const (
+	// SeverityError violations fail enforcement.
+	SeverityError Severity = "error"
+	// SeverityWarn violations are reported as warnings, and only fail
+	// enforcement in strict mode.
+	SeverityWarn Severity = "warn"
+	// SeverityInfo violations are reported for information, and never fail
+	// enforcement.
+	SeverityInfo Severity = "info"
+)
+
+// Severities are the valid severities, from the most to the least severe.
+var Severities = []Severity{SeverityError, SeverityWarn, SeverityInfo}
+
+// Status returns the status reported for a failed check of the severity.
+func (s Severity) Status() string {
+	switch s {
+	case SeverityWarn:
+		return "WARNING"
+	case SeverityInfo:
+		return "INFO"
+	default:
+		return "FAILED"
+	}
+}
+
+// AtLeast reports whether the severity is at least as severe as min. An empty
+// severity is that of errors, the default, and an empty min is that of info,
+// the least severe.
+func (s Severity) AtLeast(min Severity) bool {
+	if min == "" {
+		return true
+	}
+
+	return s.rank() >= min.rank()
+}
+
+func (s Severity) rank() int {
+	switch s {
+	case SeverityInfo:
+		return 1
+	case SeverityWarn:
+		return 2
+	default:
+		return 3
+	}
+}
@@ -0,0 +1,33 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package policy
+
+import "testing"
+
+func TestAtLeast(t *testing.T) {
+	tests := []struct {
+		severity Severity
+		min      Severity
+		expected bool
+	}{
+		{severity: SeverityInfo, min: "", expected: true},
+		{severity: SeverityInfo, min: SeverityInfo, expected: true},
+		{severity: SeverityInfo, min: SeverityWarn, expected: false},
+		{severity: SeverityWarn, min: SeverityWarn, expected: true},
+		{severity: SeverityWarn, min: SeverityError, expected: false},
+		{severity: SeverityError, min: SeverityWarn, expected: true},
+		{severity: "", min: SeverityError, expected: true},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(string(test.severity)+">="+string(test.min), func(tt *testing.T) {
+			if actual := test.severity.AtLeast(test.min); actual != test.expected {
+				tt.Errorf("Expected %t, got %t", test.expected, actual)
+			}
+		})
+	}
+}
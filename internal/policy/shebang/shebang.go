@@ -0,0 +1,202 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package shebang
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/autonomy/conform/internal/policy/executable"
+	"github.com/pkg/errors"
+)
+
+// DefaultAllowed is the default set of approved shebangs.
+var DefaultAllowed = []string{"#!/usr/bin/env bash", "#!/bin/sh"}
+
+// Shebang implements the policy.Policy interface and enforces the shebang of
+// scripts.
+type Shebang struct {
+	// Paths are gitignore style patterns of the scripts to check (e.g.
+	// hack/, scripts/*.sh).
+	Paths []string `mapstructure:"paths"`
+	// Allowed are the approved shebangs. A shebang is approved if it equals
+	// one of them, optionally followed by arguments. Defaults to
+	// DefaultAllowed.
+	Allowed []string `mapstructure:"allowed"`
+	// Executable enforces that scripts are executable, and that executable
+	// files have a shebang.
+	Executable bool `mapstructure:"executable"`
+
+	scripts []string
+	modes   map[string]os.FileMode
+	tree    *git.Tree
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (s *Shebang) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	var g *git.Git
+	if g, err = policy.NewGit(options); err != nil {
+		return report, errors.Errorf("failed to open git repo: %v", err)
+	}
+
+	if s.modes, err = policy.TrackedFileModes(options.Tree, g); err != nil {
+		return report, errors.Errorf("failed to list tracked files: %v", err)
+	}
+	s.modes = options.Ignore.FilterModes(s.modes)
+	s.tree = options.Tree
+
+	s.scripts = nil
+	for file, mode := range s.modes {
+		if mode.IsRegular() && git.MatchAny(s.Paths, file) {
+			s.scripts = append(s.scripts, file)
+		}
+	}
+	sort.Strings(s.scripts)
+
+	report.AddCheck(s.ValidateShebang())
+
+	if s.Executable {
+		report.AddCheck(s.ValidateExecutable())
+	}
+
+	return report, nil
+}
+
+// PathPatterns implements the policy.PathMatcher.PathPatterns function.
+func (s *Shebang) PathPatterns() []string {
+	return s.Paths
+}
+
+// ShebangCheck ensures that scripts start with an approved shebang.
+type ShebangCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (s ShebangCheck) Name() string {
+	return "Shebang"
+}
+
+// Message returns to check message.
+func (s ShebangCheck) Message() string {
+	if len(s.errors) != 0 {
+		return fmt.Sprintf("Found %d scripts without an approved shebang", len(s.errors))
+	}
+	return "All scripts have an approved shebang"
+}
+
+// Errors returns any violations of the check.
+func (s ShebangCheck) Errors() []error {
+	return s.errors
+}
+
+// ValidateShebang checks that each script starts with an approved shebang.
+func (s Shebang) ValidateShebang() policy.Check {
+	check := &ShebangCheck{}
+
+	allowed := s.Allowed
+	if allowed == nil {
+		allowed = DefaultAllowed
+	}
+
+	for _, file := range s.scripts {
+		line, err := Read(s.tree, file)
+		if err != nil {
+			check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("Failed to open %s", file)))
+			continue
+		}
+		if line == "" {
+			check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("Script %s does not have a shebang", file)))
+			continue
+		}
+		if !approved(allowed, line) {
+			check.errors = append(check.errors, policy.FileError(file, 1, errors.Errorf("Script %s has shebang %q: must be one of %s", file, line, strings.Join(allowed, ", "))))
+		}
+	}
+
+	return check
+}
+
+// ExecutableCheck ensures that scripts and the executable bit agree.
+type ExecutableCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (e ExecutableCheck) Name() string {
+	return "Script Executable Bit"
+}
+
+// Message returns to check message.
+func (e ExecutableCheck) Message() string {
+	if len(e.errors) != 0 {
+		return fmt.Sprintf("Found %d scripts with an invalid mode", len(e.errors))
+	}
+	return "All scripts have a valid mode"
+}
+
+// Errors returns any violations of the check.
+func (e ExecutableCheck) Errors() []error {
+	return e.errors
+}
+
+// ValidateExecutable checks that scripts with a shebang are executable, and
+// that executable files have a shebang.
+func (s Shebang) ValidateExecutable() policy.Check {
+	check := &ExecutableCheck{}
+
+	for _, file := range s.scripts {
+		line, err := Read(s.tree, file)
+		if err != nil {
+			check.errors = append(check.errors, policy.FileError(file, 0, errors.Errorf("Failed to open %s", file)))
+			continue
+		}
+		if err = executable.ModeViolation(file, s.modes[file], line != "", true); err != nil {
+			check.errors = append(check.errors, err)
+		}
+	}
+
+	return check
+}
+
+// Read returns the shebang line of the file, read from the tree if it is not
+// nil, or an empty string if the file does not begin with "#!".
+func Read(t *git.Tree, name string) (string, error) {
+	f, err := policy.Open(t, name)
+	if err != nil {
+		return "", err
+	}
+	// nolint: errcheck
+	defer f.Close()
+
+	line, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil && line == "" {
+		return "", nil
+	}
+	if !strings.HasPrefix(line, "#!") {
+		return "", nil
+	}
+
+	return strings.TrimRight(line, " \t\r\n"), nil
+}
+
+func approved(allowed []string, line string) bool {
+	for _, a := range allowed {
+		if line == a || strings.HasPrefix(line, a+" ") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,28 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package shebang
+
+import "testing"
+
+func TestApproved(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Line     string
+		Expected bool
+	}{
+		{"Exact", "#!/usr/bin/env bash", true},
+		{"Arguments", "#!/bin/sh -e", true},
+		{"Prefix", "#!/bin/shell", false},
+		{"Unknown", "#!/bin/bash", false},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			if actual := approved(DefaultAllowed, test.Line); actual != test.Expected {
+				tt.Errorf("Expected %v, got %v", test.Expected, actual)
+			}
+		})
+	}
+}
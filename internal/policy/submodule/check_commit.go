@@ -0,0 +1,60 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package submodule
+
+import (
+	"fmt"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// CommitCheck ensures that submodules are pinned to commits on the default
+// branch of their remote.
+type CommitCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (c CommitCheck) Name() string {
+	return "Submodule Commit"
+}
+
+// Message returns to check message.
+func (c CommitCheck) Message() string {
+	if len(c.errors) != 0 {
+		return fmt.Sprintf("Found %d submodules pinned to invalid commits", len(c.errors))
+	}
+	return "All submodules are pinned to commits on the default branch"
+}
+
+// Errors returns any violations of the check.
+func (c CommitCheck) Errors() []error {
+	return c.errors
+}
+
+// ValidateCommits checks that the pinned commit of each submodule exists on
+// the default branch of the remote.
+func (s Submodule) ValidateCommits() policy.Check {
+	check := &CommitCheck{}
+
+	for _, submodule := range s.submodules {
+		if submodule.Hash == "" {
+			check.errors = append(check.errors, errors.Errorf("Submodule %s is not pinned to a commit", submodule.Path))
+			continue
+		}
+		ok, err := git.ReachableFromRemoteHead(submodule.URL, submodule.Hash)
+		if err != nil {
+			check.errors = append(check.errors, errors.Errorf("Failed to fetch submodule %s from %s: %v", submodule.Path, submodule.URL, err))
+			continue
+		}
+		if !ok {
+			check.errors = append(check.errors, errors.Errorf("Submodule %s is pinned to %s which is not on the default branch of %s", submodule.Path, submodule.Hash, submodule.URL))
+		}
+	}
+
+	return check
+}
@@ -0,0 +1,44 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package submodule
+
+import (
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// ForbiddenCheck ensures that the repository has no submodules.
+type ForbiddenCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (f ForbiddenCheck) Name() string {
+	return "No Submodules"
+}
+
+// Message returns to check message.
+func (f ForbiddenCheck) Message() string {
+	if len(f.errors) != 0 {
+		return f.errors[0].Error()
+	}
+	return "Repository has no submodules"
+}
+
+// Errors returns any violations of the check.
+func (f ForbiddenCheck) Errors() []error {
+	return f.errors
+}
+
+// ValidateForbidden checks that no submodules are declared.
+func (s Submodule) ValidateForbidden() policy.Check {
+	check := &ForbiddenCheck{}
+
+	for _, submodule := range s.submodules {
+		check.errors = append(check.errors, errors.Errorf("Submodule %s is not allowed", submodule.Path))
+	}
+
+	return check
+}
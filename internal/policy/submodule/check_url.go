@@ -0,0 +1,66 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package submodule
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// URLCheck ensures that submodules are cloned from allowed URLs.
+type URLCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (u URLCheck) Name() string {
+	return "Submodule URL"
+}
+
+// Message returns to check message.
+func (u URLCheck) Message() string {
+	if len(u.errors) != 0 {
+		return fmt.Sprintf("Found %d submodules with invalid URLs", len(u.errors))
+	}
+	return "All submodule URLs are allowed"
+}
+
+// Errors returns any violations of the check.
+func (u URLCheck) Errors() []error {
+	return u.errors
+}
+
+// ValidateURLs checks the URL of each submodule against the allowlist.
+func (s Submodule) ValidateURLs() policy.Check {
+	check := &URLCheck{}
+
+	regexes := make([]*regexp.Regexp, 0, len(s.AllowedURLs))
+	for _, expr := range s.AllowedURLs {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			check.errors = append(check.errors, errors.Errorf("Invalid URL pattern %q: %v", expr, err))
+			return check
+		}
+		regexes = append(regexes, re)
+	}
+
+	for _, submodule := range s.submodules {
+		allowed := false
+		for _, re := range regexes {
+			if re.MatchString(submodule.URL) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			check.errors = append(check.errors, errors.Errorf("Submodule %s has URL %s which is not allowed", submodule.Path, submodule.URL))
+		}
+	}
+
+	return check
+}
@@ -0,0 +1,60 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package submodule
+
+import (
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// Submodule implements the policy.Policy interface and enforces rules on git
+// submodules.
+type Submodule struct {
+	// Forbid disallows submodules entirely.
+	Forbid bool `mapstructure:"forbid"`
+	// AllowedURLs are regular expressions that submodule URLs must match.
+	AllowedURLs []string `mapstructure:"allowedURLs"`
+	// RequireOnDefaultBranch enforces that the pinned commit of each
+	// submodule is reachable from the default branch of its remote.
+	RequireOnDefaultBranch bool `mapstructure:"requireOnDefaultBranch"`
+
+	submodules []git.Submodule
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (s *Submodule) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	var g *git.Git
+	if g, err = policy.NewGit(options); err != nil {
+		return report, errors.Errorf("failed to open git repo: %v", err)
+	}
+
+	if s.submodules, err = g.Submodules(); err != nil {
+		return report, errors.Errorf("failed to list submodules: %v", err)
+	}
+
+	if s.Forbid {
+		report.AddCheck(s.ValidateForbidden())
+	}
+
+	if len(s.AllowedURLs) != 0 {
+		report.AddCheck(s.ValidateURLs())
+	}
+
+	if s.RequireOnDefaultBranch {
+		report.AddCheck(s.ValidateCommits())
+	}
+
+	return report, nil
+}
+
+// Validate implements the policy.Validator.Validate function.
+func (s *Submodule) Validate() []error {
+	return policy.ValidateRegexps("URL pattern", s.AllowedURLs...)
+}
@@ -0,0 +1,73 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package submodule
+
+import (
+	"testing"
+
+	"github.com/autonomy/conform/internal/git"
+)
+
+func TestValidateSubmodules(t *testing.T) {
+	vendor := git.Submodule{Name: "vendor", Path: "vendor/lib", URL: "https://github.com/autonomy/lib.git", Hash: "0123456789abcdef0123456789abcdef01234567"}
+	other := git.Submodule{Name: "other", Path: "other", URL: "https://example.com/other.git", Hash: "0123456789abcdef0123456789abcdef01234567"}
+	unpinned := git.Submodule{Name: "unpinned", Path: "unpinned", URL: "https://github.com/autonomy/unpinned.git"}
+
+	for _, test := range []struct {
+		Name       string
+		Submodule  Submodule
+		Submodules []git.Submodule
+		Validate   func(Submodule) []error
+		Errors     int
+	}{
+		{
+			Name:       "Forbidden none",
+			Validate:   func(s Submodule) []error { return s.ValidateForbidden().Errors() },
+			Submodules: nil,
+		},
+		{
+			Name:       "Forbidden",
+			Validate:   func(s Submodule) []error { return s.ValidateForbidden().Errors() },
+			Submodules: []git.Submodule{vendor, other},
+			Errors:     2,
+		},
+		{
+			Name:       "Allowed URL",
+			Submodule:  Submodule{AllowedURLs: []string{`^https://github\.com/autonomy/`}},
+			Validate:   func(s Submodule) []error { return s.ValidateURLs().Errors() },
+			Submodules: []git.Submodule{vendor},
+		},
+		{
+			Name:       "Not allowed URL",
+			Submodule:  Submodule{AllowedURLs: []string{`^https://github\.com/autonomy/`}},
+			Validate:   func(s Submodule) []error { return s.ValidateURLs().Errors() },
+			Submodules: []git.Submodule{vendor, other},
+			Errors:     1,
+		},
+		{
+			Name:       "Invalid URL pattern",
+			Submodule:  Submodule{AllowedURLs: []string{`(`}},
+			Validate:   func(s Submodule) []error { return s.ValidateURLs().Errors() },
+			Submodules: []git.Submodule{vendor},
+			Errors:     1,
+		},
+		{
+			Name:       "Not pinned",
+			Validate:   func(s Submodule) []error { return s.ValidateCommits().Errors() },
+			Submodules: []git.Submodule{unpinned},
+			Errors:     1,
+		},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			s := test.Submodule
+			s.submodules = test.Submodules
+			if errs := test.Validate(s); len(errs) != test.Errors {
+				tt.Errorf("Expected %d errors, got %v", test.Errors, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package symlink
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// Symlink implements the policy.Policy interface and controls the use of
+// symbolic links in the tree.
+type Symlink struct {
+	// Forbid disallows symlinks entirely.
+	Forbid bool `mapstructure:"forbid"`
+	// ForbidEscape disallows symlinks that point outside of the repository.
+	ForbidEscape bool `mapstructure:"forbidEscape"`
+	// AllowedPaths restricts symlinks to paths matching the patterns. When
+	// empty, symlinks are allowed anywhere.
+	AllowedPaths []string `mapstructure:"allowedPaths"`
+
+	links map[string]string
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (s *Symlink) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	var g *git.Git
+	if g, err = policy.NewGit(options); err != nil {
+		return report, errors.Errorf("failed to open git repo: %v", err)
+	}
+
+	var modes map[string]os.FileMode
+	if modes, err = policy.TrackedFileModes(options.Tree, g); err != nil {
+		return report, errors.Errorf("failed to list tracked files: %v", err)
+	}
+	modes = options.Ignore.FilterModes(modes)
+
+	s.links = map[string]string{}
+	for file, mode := range modes {
+		if mode&os.ModeSymlink == 0 {
+			continue
+		}
+		if s.links[file], err = readlink(options.Tree, file); err != nil {
+			return report, errors.Errorf("failed to read symlink %s: %v", file, err)
+		}
+	}
+
+	report.AddCheck(s.ValidateSymlinks())
+
+	return report, nil
+}
+
+// PathPatterns implements the policy.PathMatcher.PathPatterns function.
+func (s *Symlink) PathPatterns() []string {
+	return s.AllowedPaths
+}
+
+// SymlinkCheck enforces the symlink rules.
+type SymlinkCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (s SymlinkCheck) Name() string {
+	return "Symlinks"
+}
+
+// Message returns to check message.
+func (s SymlinkCheck) Message() string {
+	if len(s.errors) != 0 {
+		return fmt.Sprintf("Found %d invalid symlinks", len(s.errors))
+	}
+	return "All symlinks are valid"
+}
+
+// Errors returns any violations of the check.
+func (s SymlinkCheck) Errors() []error {
+	return s.errors
+}
+
+// ValidateSymlinks checks each symlink against the configured rules.
+func (s Symlink) ValidateSymlinks() policy.Check {
+	check := &SymlinkCheck{}
+
+	links := make([]string, 0, len(s.links))
+	for link := range s.links {
+		links = append(links, link)
+	}
+	sort.Strings(links)
+
+	for _, link := range links {
+		target := s.links[link]
+		if s.Forbid {
+			check.errors = append(check.errors, policy.FileError(link, 0, errors.Errorf("Symlink %s is not allowed", link)))
+			continue
+		}
+		if len(s.AllowedPaths) != 0 && !git.MatchAny(s.AllowedPaths, link) {
+			check.errors = append(check.errors, policy.FileError(link, 0, errors.Errorf("Symlink %s is not in an allowed path", link)))
+			continue
+		}
+		if s.ForbidEscape && escapes(link, target) {
+			check.errors = append(check.errors, policy.FileError(link, 0, errors.Errorf("Symlink %s points outside of the repository: %s", link, target)))
+		}
+	}
+
+	return check
+}
+
+// escapes reports whether the target of the link resolves to a path outside
+// of the repository.
+func escapes(link, target string) bool {
+	target = filepath.ToSlash(target)
+	if path.IsAbs(target) || filepath.IsAbs(target) {
+		return true
+	}
+	resolved := path.Join(path.Dir(link), target)
+
+	return resolved == ".." || strings.HasPrefix(resolved, "../")
+}
+
+// readlink returns the target of a symlink. When git is configured with
+// core.symlinks=false the link is checked out as a regular file containing
+// the target, as are the links of a tree.
+func readlink(t *git.Tree, name string) (string, error) {
+	if t != nil {
+		contents, err := t.ReadFile(name)
+		return string(contents), err
+	}
+	target, err := os.Readlink(name)
+	if err == nil {
+		return target, nil
+	}
+	contents, err := ioutil.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+
+	return string(contents), nil
+}
@@ -0,0 +1,38 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package symlink
+
+import (
+	"testing"
+)
+
+func TestValidateSymlinks(t *testing.T) {
+	for _, test := range []struct {
+		Name    string
+		Symlink Symlink
+		Link    string
+		Target  string
+		Errors  int
+	}{
+		{Name: "Allowed", Symlink: Symlink{}, Link: "docs/README.md", Target: "../README.md"},
+		{Name: "Forbidden", Symlink: Symlink{Forbid: true}, Link: "docs/README.md", Target: "../README.md", Errors: 1},
+		{Name: "Allowed path", Symlink: Symlink{AllowedPaths: []string{"docs/"}}, Link: "docs/README.md", Target: "../README.md"},
+		{Name: "Not allowed path", Symlink: Symlink{AllowedPaths: []string{"docs/"}}, Link: "README.md", Target: "docs/README.md", Errors: 1},
+		{Name: "Inside", Symlink: Symlink{ForbidEscape: true}, Link: "a/b/c", Target: "../../d"},
+		{Name: "Escapes", Symlink: Symlink{ForbidEscape: true}, Link: "a/b", Target: "../../etc/passwd", Errors: 1},
+		{Name: "Absolute", Symlink: Symlink{ForbidEscape: true}, Link: "a", Target: "/etc/passwd", Errors: 1},
+		{Name: "Escape allowed", Symlink: Symlink{}, Link: "a", Target: "/etc/passwd"},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			s := test.Symlink
+			s.links = map[string]string{test.Link: test.Target}
+			if errs := s.ValidateSymlinks().Errors(); len(errs) != test.Errors {
+				tt.Errorf("Expected %d errors, got %v", test.Errors, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package policy
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/autonomy/conform/internal/git"
+)
+
+// The functions below read files from the tree of the commit being enforced,
+// as set by WithTree, or from the working tree if the tree is nil. Paths are
+// relative to the working directory.
+
+// ReadFile reads the file.
+func ReadFile(t *git.Tree, name string) ([]byte, error) {
+	if t != nil {
+		return t.ReadFile(name)
+	}
+
+	return ioutil.ReadFile(name)
+}
+
+// Open opens the file.
+func Open(t *git.Tree, name string) (io.ReadCloser, error) {
+	if t != nil {
+		return t.Open(name)
+	}
+
+	return os.Open(name)
+}
+
+// Stat returns the info of the file.
+func Stat(t *git.Tree, name string) (os.FileInfo, error) {
+	if t != nil {
+		return t.Stat(name)
+	}
+
+	return os.Stat(name)
+}
+
+// ReadAttributes reads the .gitattributes file. A missing file results in an
+// empty set of rules.
+func ReadAttributes(t *git.Tree, name string) (*git.Attributes, error) {
+	if t == nil {
+		return git.ReadAttributes(name)
+	}
+
+	r, err := t.Open(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &git.Attributes{}, nil
+		}
+		return nil, err
+	}
+	// nolint: errcheck
+	defer r.Close()
+
+	return git.ParseAttributes(r)
+}
+
+// TrackedFiles returns the paths of the files of the tree, or of the files
+// tracked in the index, below the working directory. The paths are slash
+// separated and relative to the working directory, like those read by the
+// functions above, since a nested configuration is enforced from its
+// subdirectory.
+func TrackedFiles(t *git.Tree, g *git.Git) ([]string, error) {
+	var files []string
+	if t != nil {
+		files = t.Files()
+	} else {
+		var err error
+		if files, err = g.TrackedFiles(); err != nil {
+			return nil, err
+		}
+	}
+	prefix, err := g.Prefix()
+	if err != nil {
+		return nil, err
+	}
+
+	rel := make([]string, 0, len(files))
+	for _, file := range files {
+		if strings.HasPrefix(file, prefix) {
+			rel = append(rel, strings.TrimPrefix(file, prefix))
+		}
+	}
+
+	return rel, nil
+}
+
+// TrackedFileModes returns the modes of the files of the tree, or of the files
+// tracked in the index, below the working directory, keyed by their slash
+// separated paths relative to the working directory.
+func TrackedFileModes(t *git.Tree, g *git.Git) (map[string]os.FileMode, error) {
+	var modes map[string]os.FileMode
+	var err error
+	if t != nil {
+		modes, err = t.FileModes()
+	} else {
+		modes, err = g.TrackedFileModes()
+	}
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := g.Prefix()
+	if err != nil {
+		return nil, err
+	}
+
+	rel := make(map[string]os.FileMode, len(modes))
+	for file, mode := range modes {
+		if strings.HasPrefix(file, prefix) {
+			rel[strings.TrimPrefix(file, prefix)] = mode
+		}
+	}
+
+	return rel, nil
+}
+
+// ReadCodeOwners reads the first CODEOWNERS file found in
+// git.CodeOwnersPaths. A missing file results in an empty set of rules.
+func ReadCodeOwners(t *git.Tree) (*git.CodeOwners, error) {
+	if t == nil {
+		return git.ReadCodeOwners(".")
+	}
+
+	for _, name := range git.CodeOwnersPaths {
+		r, err := t.Open(name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		c, err := git.ParseCodeOwners(r)
+		// nolint: errcheck
+		r.Close()
+		if err != nil {
+			return nil, err
+		}
+		c.Path = name
+
+		return c, nil
+	}
+
+	return &git.CodeOwners{}, nil
+}
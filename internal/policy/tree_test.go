@@ -0,0 +1,182 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package policy
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/autonomy/conform/internal/git"
+)
+
+func TestTreeReads(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.Chdir(wd)
+
+	if err = os.Mkdir(".github", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(".github/CODEOWNERS", []byte("* @committed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "initial commit"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	// The working tree differs from the commit.
+	if err = ioutil.WriteFile(".github/CODEOWNERS", []byte("* @uncommitted\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := git.NewGit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, err := g.Tree("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name     string
+		tree     *git.Tree
+		expected string
+	}{
+		{name: "Tree", tree: tree, expected: "@committed"},
+		{name: "Working tree", tree: nil, expected: "@uncommitted"},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(tt *testing.T) {
+			c, err := ReadCodeOwners(test.tree)
+			if err != nil {
+				tt.Fatal(err)
+			}
+			if owners := c.Owners("main.go"); len(owners) != 1 || owners[0] != test.expected {
+				tt.Errorf("Expected the owners %s, got %v", test.expected, owners)
+			}
+			info, err := Stat(test.tree, ".github/CODEOWNERS")
+			if err != nil {
+				tt.Fatal(err)
+			}
+			if size := int64(len("* " + test.expected + "\n")); info.Size() != size {
+				tt.Errorf("Expected a size of %d, got %d", size, info.Size())
+			}
+		})
+	}
+}
+
+func TestTrackedFilesSubdirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.Chdir(wd)
+
+	if err = os.MkdirAll("sub/nested", 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"root.txt", "sub/x.txt", "sub/nested/y.txt", "subdir.txt"} {
+		if err = ioutil.WriteFile(name, []byte(name+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "initial commit"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	// Nested configurations are enforced from their subdirectory.
+	if err = os.Chdir("sub"); err != nil {
+		t.Fatal(err)
+	}
+	g, err := git.NewGit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, err := g.Tree("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"nested/y.txt", "x.txt"}
+	for _, test := range []struct {
+		name string
+		tree *git.Tree
+	}{
+		{name: "Tree", tree: tree},
+		{name: "Index", tree: nil},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(tt *testing.T) {
+			files, err := TrackedFiles(test.tree, g)
+			if err != nil {
+				tt.Fatal(err)
+			}
+			sort.Strings(files)
+			if !reflect.DeepEqual(files, expected) {
+				tt.Fatalf("Expected %v, got %v", expected, files)
+			}
+			for _, file := range files {
+				if _, err = ReadFile(test.tree, file); err != nil {
+					tt.Errorf("Expected %s to be readable, got %v", file, err)
+				}
+			}
+			modes, err := TrackedFileModes(test.tree, g)
+			if err != nil {
+				tt.Fatal(err)
+			}
+			if len(modes) != len(expected) || modes["x.txt"] != 0644 {
+				tt.Errorf("Expected the modes of %v, got %v", expected, modes)
+			}
+		})
+	}
+
+	contents, err := g.IndexContents("x.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(contents) != "sub/x.txt\n" {
+		t.Errorf("Expected the contents of sub/x.txt, got %q", contents)
+	}
+}
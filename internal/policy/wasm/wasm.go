@@ -0,0 +1,164 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package wasm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/autonomy/conform/internal/oci"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/autonomy/conform/internal/policy/exec"
+	"github.com/autonomy/conform/internal/policy/input"
+	"github.com/pkg/errors"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+)
+
+// DefaultMemoryLimitPages is the default maximum number of 64KiB memory pages
+// a module may use.
+const DefaultMemoryLimitPages = 4096
+
+// WASM implements the policy.Policy interface and runs a policy compiled to
+// a WASI command module. The module uses the same protocol as the exec
+// policy: the input.Input of the changes being enforced is written to its
+// standard input as JSON, and it writes an exec.Result to its standard output
+// as JSON. The module is sandboxed: it has no access to the filesystem, the
+// environment, or the network.
+type WASM struct {
+	// Name is the name of the check. Defaults to "WASM".
+	Name string `mapstructure:"name"`
+	// Module is the path of the module, or an OCI reference of the form
+	// oci://registry/repository[:tag|@digest].
+	Module string `mapstructure:"module"`
+	// Args are the arguments passed to the module.
+	Args []string `mapstructure:"args"`
+	// MemoryLimitPages is the maximum number of 64KiB memory pages the module
+	// may use. Defaults to DefaultMemoryLimitPages.
+	MemoryLimitPages uint32 `mapstructure:"memoryLimitPages"`
+
+	input *input.Input
+	ctx   context.Context
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (w *WASM) Compliance(options *policy.Options) (*policy.Report, error) {
+	var err error
+
+	report := &policy.Report{}
+
+	if w.input, err = input.New(options); err != nil {
+		return report, err
+	}
+	w.ctx = options.Context
+
+	report.AddCheck(w.ValidateWASM())
+
+	return report, nil
+}
+
+// WASMCheck reports the result of the module.
+type WASMCheck struct {
+	name    string
+	message string
+	errors  []error
+}
+
+// Name returns the name of the check.
+func (w WASMCheck) Name() string {
+	return w.name
+}
+
+// Message returns to check message.
+func (w WASMCheck) Message() string {
+	return w.message
+}
+
+// Errors returns any violations of the check.
+func (w WASMCheck) Errors() []error {
+	return w.errors
+}
+
+// ValidateWASM runs the module and converts its result to a check.
+func (w WASM) ValidateWASM() policy.Check {
+	check := &WASMCheck{name: w.Name}
+	if check.name == "" {
+		check.name = "WASM"
+	}
+
+	binary, err := load(w.Module)
+	if err != nil {
+		check.message = "Failed to load " + w.Module
+		check.errors = append(check.errors, errors.Errorf("Failed to load %s: %v", w.Module, err))
+		return check
+	}
+
+	result, err := w.run(binary)
+	if err != nil {
+		check.message = "Failed to run " + w.Module
+		check.errors = append(check.errors, errors.Errorf("Failed to run %s: %v", w.Module, err))
+		return check
+	}
+
+	check.message = result.Message
+	check.errors = result.Violations(w.Module + " failed")
+
+	return check
+}
+
+func load(module string) ([]byte, error) {
+	if strings.HasPrefix(module, oci.Scheme) {
+		return oci.Pull(module)
+	}
+
+	return ioutil.ReadFile(module)
+}
+
+func (w WASM) run(binary []byte) (*exec.Result, error) {
+	data, err := json.Marshal(w.input)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := w.MemoryLimitPages
+	if limit == 0 {
+		limit = DefaultMemoryLimitPages
+	}
+
+	ctx := policy.Context(w.ctx)
+	runtimeConfig := wazero.NewRuntimeConfig().WithMemoryLimitPages(limit).WithCloseOnContextDone(true)
+	r := wazero.NewRuntimeWithConfig(ctx, runtimeConfig)
+	// nolint: errcheck
+	defer r.Close(ctx)
+
+	if _, err = wasi_snapshot_preview1.Instantiate(ctx, r); err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	config := wazero.NewModuleConfig().
+		WithArgs(append([]string{w.Module}, w.Args...)...).
+		WithStdin(bytes.NewReader(data)).
+		WithStdout(&stdout).
+		WithStderr(&stderr)
+
+	var exitErr error
+	if _, err = r.InstantiateWithConfig(ctx, binary, config); err != nil {
+		e, ok := err.(*sys.ExitError)
+		if !ok {
+			return nil, err
+		}
+		if e.ExitCode() != 0 {
+			exitErr = fmt.Errorf("exit status %d", e.ExitCode())
+		}
+	}
+
+	return exec.ParseResult(stdout.Bytes(), stderr.Bytes(), exitErr)
+}
@@ -0,0 +1,129 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package wasm
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy/input"
+)
+
+func TestValidateWASM(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conform")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer os.RemoveAll(dir)
+
+	for _, test := range []struct {
+		Name     string
+		Module   []byte
+		Limit    uint32
+		Expected int
+	}{
+		{"Pass", module(`{"pass": true, "message": "ok"}`, 0, 1), 0, 0},
+		{"Errors", module(`{"pass": false, "errors": ["a", "b"]}`, 0, 1), 0, 2},
+		{"Fail", module(`{"pass": false, "message": "failed"}`, 0, 1), 0, 1},
+		{"Exit Status", module(`{"pass": true}`, 1, 1), 0, 1},
+		{"No Result", module("", 1, 1), 0, 1},
+		{"Invalid Result", module("oops", 0, 1), 0, 1},
+		{"Memory Limit", module(`{"pass": true}`, 0, 2), 1, 1},
+		{"Invalid Module", []byte("oops"), 0, 1},
+		{"Missing Module", nil, 0, 1},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			path := filepath.Join(dir, test.Name+".wasm")
+			if test.Module != nil {
+				if err := ioutil.WriteFile(path, test.Module, 0644); err != nil {
+					tt.Fatal(err)
+				}
+			}
+			w := WASM{
+				Module:           path,
+				MemoryLimitPages: test.Limit,
+				input:            &input.Input{Commit: input.Commit{Header: "feat: a"}},
+			}
+			if errs := w.ValidateWASM().Errors(); len(errs) != test.Expected {
+				tt.Errorf("Expected %d errors, got %v", test.Expected, errs)
+			}
+		})
+	}
+}
+
+// module returns a WASI command module that writes stdout to its standard
+// output and exits with the code, with a memory of the number of pages.
+func module(stdout string, code int32, pages uint32) []byte {
+	section := func(id byte, contents ...[]byte) []byte {
+		var b []byte
+		for _, c := range contents {
+			b = append(b, c...)
+		}
+		return append(append([]byte{id}, uleb(uint32(len(b)))...), b...)
+	}
+	name := func(s string) []byte {
+		return append(uleb(uint32(len(s))), s...)
+	}
+
+	// The data starts with the iovec of stdout, followed by the number of
+	// bytes written by fd_write.
+	data := make([]byte, 16, 16+len(stdout))
+	binary.LittleEndian.PutUint32(data[0:], 16)
+	binary.LittleEndian.PutUint32(data[4:], uint32(len(stdout)))
+	data = append(data, stdout...)
+
+	// _start calls fd_write(1, 0, 1, 8) and proc_exit(code).
+	body := []byte{0x00, 0x41, 0x01, 0x41, 0x00, 0x41, 0x01, 0x41, 0x08, 0x10, 0x00, 0x1a, 0x41}
+	body = append(body, sleb(code)...)
+	body = append(body, 0x10, 0x01, 0x0b)
+
+	b := []byte("\x00asm\x01\x00\x00\x00")
+	b = append(b, section(1, []byte{0x03,
+		0x60, 0x04, 0x7f, 0x7f, 0x7f, 0x7f, 0x01, 0x7f,
+		0x60, 0x01, 0x7f, 0x00,
+		0x60, 0x00, 0x00})...)
+	b = append(b, section(2, []byte{0x02},
+		name("wasi_snapshot_preview1"), name("fd_write"), []byte{0x00, 0x00},
+		name("wasi_snapshot_preview1"), name("proc_exit"), []byte{0x00, 0x01})...)
+	b = append(b, section(3, []byte{0x01, 0x02})...)
+	b = append(b, section(5, []byte{0x01, 0x00}, uleb(pages))...)
+	b = append(b, section(7, []byte{0x02},
+		name("_start"), []byte{0x00, 0x02},
+		name("memory"), []byte{0x02, 0x00})...)
+	b = append(b, section(10, []byte{0x01}, uleb(uint32(len(body))), body)...)
+	b = append(b, section(11, []byte{0x01, 0x00, 0x41, 0x00, 0x0b}, uleb(uint32(len(data))), data)...)
+
+	return b
+}
+
+func uleb(v uint32) []byte {
+	var b []byte
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+func sleb(v int32) []byte {
+	var b []byte
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && c&0x40 == 0) || (v == -1 && c&0x40 != 0) {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
@@ -0,0 +1,205 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package whitespace
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+	"github.com/pkg/errors"
+)
+
+// Whitespace implements the policy.Policy interface and enforces that lines
+// added by the changes being enforced do not contain trailing whitespace.
+// Only added lines are checked so that existing files do not fail the policy.
+type Whitespace struct {
+	// Files is the set of files that the whitespace policy should be applied
+	// to. Without included suffixes, all text files are.
+	policy.Files `mapstructure:",squash"`
+
+	diffs []*git.FileDiff
+	// root is the absolute path of the root of the working tree, which the
+	// paths of the diffs are relative to.
+	root string
+}
+
+// Compliance implements the policy.Policy.Compliance function.
+func (w *Whitespace) Compliance(options *policy.Options) (*policy.Report, error) {
+	report := &policy.Report{}
+
+	if err := w.diff(options); err != nil {
+		return report, err
+	}
+
+	report.AddCheck(w.ValidateTrailingWhitespace())
+
+	return report, nil
+}
+
+// Fixes implements the policy.Fixer.Fixes function, removing the trailing
+// whitespace of the added lines.
+func (w *Whitespace) Fixes(options *policy.Options) ([]policy.Fix, error) {
+	if err := w.diff(options); err != nil {
+		return nil, err
+	}
+
+	var fixes []policy.Fix
+	for _, d := range w.diffs {
+		if d.To == "" || d.Binary || !w.selected(d.To) {
+			continue
+		}
+		path := filepath.Join(w.root, filepath.FromSlash(d.To))
+		info, err := policy.Stat(options.Tree, path)
+		if err != nil {
+			return nil, err
+		}
+		contents, err := policy.ReadFile(options.Tree, path)
+		if err != nil {
+			return nil, err
+		}
+		// The working tree may differ from HEAD, such as when other fixes
+		// have been applied, so that added lines are found by their text,
+		// preferably at their line number.
+		lines := strings.Split(string(contents), "\n")
+		trimmed := 0
+		for _, added := range d.Added {
+			if strings.TrimRight(added.Text, " \t") == added.Text {
+				continue
+			}
+			if i := findLine(lines, added); i >= 0 {
+				lines[i] = trimTrailing(lines[i])
+				trimmed++
+			}
+		}
+		if trimmed == 0 {
+			continue
+		}
+		fixed := []byte(strings.Join(lines, "\n"))
+		fixes = append(fixes, policy.Fix{
+			File:        d.To,
+			Description: fmt.Sprintf("Remove the trailing whitespace of %d lines", trimmed),
+			Apply: func() error {
+				return ioutil.WriteFile(path, fixed, info.Mode())
+			},
+		})
+	}
+
+	return fixes, nil
+}
+
+func (w *Whitespace) diff(options *policy.Options) error {
+	g, err := policy.NewGit(options)
+	if err != nil {
+		return errors.Errorf("failed to open git repo: %v", err)
+	}
+
+	var base string
+	if options.BaseBranch != nil {
+		base = *options.BaseBranch
+	}
+	if w.diffs, err = g.Diff(base); err != nil {
+		return errors.Errorf("failed to get diff: %v", err)
+	}
+	w.diffs = options.Ignore.FilterDiffs(w.diffs)
+	if w.root, err = g.Root(); err != nil {
+		return errors.Errorf("failed to get root of git repo: %v", err)
+	}
+
+	return nil
+}
+
+// TrailingWhitespaceCheck ensures that added lines do not end with
+// whitespace.
+type TrailingWhitespaceCheck struct {
+	errors []error
+}
+
+// Name returns the name of the check.
+func (t TrailingWhitespaceCheck) Name() string {
+	return "Trailing Whitespace"
+}
+
+// Message returns to check message.
+func (t TrailingWhitespaceCheck) Message() string {
+	if len(t.errors) != 0 {
+		return fmt.Sprintf("Found %d added lines with trailing whitespace", len(t.errors))
+	}
+	return "No added lines have trailing whitespace"
+}
+
+// Errors returns any violations of the check.
+func (t TrailingWhitespaceCheck) Errors() []error {
+	return t.errors
+}
+
+// ValidateTrailingWhitespace checks the added lines of each selected file for
+// trailing spaces and tabs.
+func (w Whitespace) ValidateTrailingWhitespace() policy.Check {
+	check := &TrailingWhitespaceCheck{}
+
+	for _, d := range w.diffs {
+		if d.To == "" || d.Binary || !w.selected(d.To) {
+			continue
+		}
+		for _, line := range d.Added {
+			if strings.TrimRight(line.Text, " \t") != line.Text {
+				check.errors = append(check.errors, policy.FileError(d.To, line.Number, errors.Errorf("File %s has trailing whitespace on line %d", d.To, line.Number)))
+			}
+		}
+	}
+
+	return check
+}
+
+// selected reports whether the added lines of the file are checked. Without
+// included suffixes, those of all files are, but the skipped paths and the
+// files with excluded suffixes.
+func (w Whitespace) selected(p string) bool {
+	if len(w.IncludeSuffixes) != 0 {
+		return w.Selected(p)
+	}
+	if git.MatchAny(w.SkipPaths, p) {
+		return false
+	}
+	for _, suffix := range w.ExcludeSuffixes {
+		if strings.HasSuffix(p, suffix) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// findLine returns the index of the line of the working tree that is the added
+// line, or -1 if there is none.
+func findLine(lines []string, added git.Line) int {
+	matches := func(i int) bool {
+		return strings.TrimSuffix(lines[i], "\r") == added.Text
+	}
+	if i := added.Number - 1; i < len(lines) && matches(i) {
+		return i
+	}
+	for i := range lines {
+		if matches(i) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// trimTrailing removes the trailing whitespace of a line, preserving its
+// carriage return.
+func trimTrailing(line string) string {
+	if strings.HasSuffix(line, "\r") {
+		return strings.TrimRight(strings.TrimSuffix(line, "\r"), " \t") + "\r"
+	}
+
+	return strings.TrimRight(line, " \t")
+}
@@ -0,0 +1,108 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package whitespace
+
+import (
+	"testing"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestValidateTrailingWhitespace(t *testing.T) {
+	for _, test := range []struct {
+		Name   string
+		Diff   *git.FileDiff
+		Errors int
+	}{
+		{"Clean", &git.FileDiff{To: "a.go", Added: []git.Line{{Number: 1, Text: "package a"}}}, 0},
+		{"Space", &git.FileDiff{To: "a.go", Added: []git.Line{{Number: 1, Text: "package a "}}}, 1},
+		{"Tab", &git.FileDiff{To: "a.go", Added: []git.Line{{Number: 1, Text: "package a\t"}, {Number: 2, Text: "\t"}}}, 2},
+		{"Deleted", &git.FileDiff{From: "a.go"}, 0},
+		{"Binary", &git.FileDiff{To: "a.go", Binary: true, Added: []git.Line{{Number: 1, Text: "a "}}}, 0},
+		{"Skipped", &git.FileDiff{To: "vendor/a.go", Added: []git.Line{{Number: 1, Text: "package a "}}}, 0},
+		{"Suffix", &git.FileDiff{To: "a.md", Added: []git.Line{{Number: 1, Text: "text  "}}}, 0},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			w := Whitespace{
+				Files: policy.Files{IncludeSuffixes: []string{".go"}, SkipPaths: []string{"vendor/"}},
+				diffs: []*git.FileDiff{test.Diff},
+			}
+			if errs := w.ValidateTrailingWhitespace().Errors(); len(errs) != test.Errors {
+				tt.Errorf("Expected %d errors, got %v", test.Errors, errs)
+			}
+		})
+	}
+}
+
+func TestValidateTrailingWhitespaceDefault(t *testing.T) {
+	for _, test := range []struct {
+		Name   string
+		Diff   *git.FileDiff
+		Errors int
+	}{
+		{"Go", &git.FileDiff{To: "a.go", Added: []git.Line{{Number: 1, Text: "package a "}}}, 1},
+		{"Markdown", &git.FileDiff{To: "docs/a.md", Added: []git.Line{{Number: 1, Text: "text  "}}}, 1},
+		{"Binary", &git.FileDiff{To: "a.png", Binary: true, Added: []git.Line{{Number: 1, Text: "a "}}}, 0},
+		{"Skipped", &git.FileDiff{To: "vendor/a.go", Added: []git.Line{{Number: 1, Text: "package a "}}}, 0},
+		{"Excluded", &git.FileDiff{To: "a.patch", Added: []git.Line{{Number: 1, Text: "+ "}}}, 0},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			w := Whitespace{
+				Files: policy.Files{SkipPaths: []string{"vendor/"}, ExcludeSuffixes: []string{".patch"}},
+				diffs: []*git.FileDiff{test.Diff},
+			}
+			if errs := w.ValidateTrailingWhitespace().Errors(); len(errs) != test.Errors {
+				tt.Errorf("Expected %d errors, got %v", test.Errors, errs)
+			}
+		})
+	}
+}
+
+func TestFindLine(t *testing.T) {
+	lines := []string{"a ", "b\t\r", "a "}
+	for _, test := range []struct {
+		Name     string
+		Added    git.Line
+		Expected int
+	}{
+		{"Number", git.Line{Number: 3, Text: "a "}, 2},
+		{"Moved", git.Line{Number: 1, Text: "b\t"}, 1},
+		{"Missing", git.Line{Number: 1, Text: "c "}, -1},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			if actual := findLine(lines, test.Added); actual != test.Expected {
+				tt.Errorf("Expected %d, got %d", test.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestTrimTrailing(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Line     string
+		Expected string
+	}{
+		{"Spaces", "a  ", "a"},
+		{"Tabs", "a\t \t", "a"},
+		{"CRLF", "a \r", "a\r"},
+		{"Clean", "a", "a"},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			if actual := trimTrailing(test.Line); actual != test.Expected {
+				tt.Errorf("Expected %q, got %q", test.Expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+// Package progress reports the progress of long running enforcement as a
+// single line that is rewritten in place, e.g.
+// [2/5] license: 1234 files scanned, 12 commits checked.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// interval is the minimum time between two renderings of the line, so that
+// reporting does not slow down scanning.
+const interval = 100 * time.Millisecond
+
+var (
+	mu       sync.Mutex
+	output   io.Writer
+	policy   string
+	index    int
+	total    int
+	files    int
+	commits  int
+	rendered time.Time
+)
+
+// Enable enables reporting to w, which should be a terminal. Reporting is
+// disabled by default.
+func Enable(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	output = w
+}
+
+// Policy reports that the policy with the 1-indexed index out of total is
+// being enforced.
+func Policy(name string, i, n int) {
+	mu.Lock()
+	defer mu.Unlock()
+	policy, index, total = name, i, n
+	render(true)
+}
+
+// File reports that a file has been scanned.
+func File() {
+	mu.Lock()
+	defer mu.Unlock()
+	files++
+	render(false)
+}
+
+// Commit reports that a commit has been checked.
+func Commit() {
+	mu.Lock()
+	defer mu.Unlock()
+	commits++
+	render(false)
+}
+
+// Clear erases the line and resets the counts, so that the results can be
+// written.
+func Clear() {
+	mu.Lock()
+	defer mu.Unlock()
+	if output != nil && !rendered.IsZero() {
+		// nolint: errcheck
+		io.WriteString(output, "\r\x1b[K")
+	}
+	policy, index, total, files, commits = "", 0, 0, 0, 0
+	rendered = time.Time{}
+}
+
+func render(force bool) {
+	if output == nil || (!force && time.Since(rendered) < interval) {
+		return
+	}
+	rendered = time.Now()
+
+	line := fmt.Sprintf("%d files scanned, %d commits checked", files, commits)
+	if policy != "" {
+		line = fmt.Sprintf("[%d/%d] %s: %s", index, total, policy, line)
+	}
+	// nolint: errcheck
+	io.WriteString(output, "\r\x1b[K"+line)
+}
@@ -0,0 +1,42 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProgress(t *testing.T) {
+	var b bytes.Buffer
+	Enable(&b)
+	defer Enable(nil)
+
+	Policy("commit", 1, 2)
+	Commit()
+	File()
+	File()
+	Policy("license", 2, 2)
+
+	if expected := "\r\x1b[K[2/2] license: 2 files scanned, 1 commits checked"; !strings.HasSuffix(b.String(), expected) {
+		t.Errorf("Expected the line to end with %q, got %q", expected, b.String())
+	}
+	if strings.Count(b.String(), "\r") != 2 {
+		t.Errorf("Expected updates within the interval not to be rendered, got %q", b.String())
+	}
+
+	b.Reset()
+	Clear()
+	if b.String() != "\r\x1b[K" {
+		t.Errorf("Expected the line to be erased, got %q", b.String())
+	}
+
+	b.Reset()
+	Clear()
+	if b.Len() != 0 {
+		t.Errorf("Expected nothing to be erased once cleared, got %q", b.String())
+	}
+}
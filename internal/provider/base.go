@@ -0,0 +1,30 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package provider
+
+// BaseBranchEnv are the environment variables that CI services set to the
+// base branch of the pull request being built, in order of precedence.
+var BaseBranchEnv = []string{
+	// GitHub Actions
+	"GITHUB_BASE_REF",
+	// GitLab CI
+	"CI_MERGE_REQUEST_TARGET_BRANCH_NAME",
+	// Buildkite
+	"BUILDKITE_PULL_REQUEST_BASE_BRANCH",
+}
+
+// BaseBranch detects the base branch of the pull request being built from
+// the environment, and returns it along with the variable it was read from.
+// An empty branch is returned when not building a pull request.
+func BaseBranch(lookupEnv func(string) (string, bool)) (branch, env string) {
+	for _, env = range BaseBranchEnv {
+		// The variables may be set, but empty, for builds of branches.
+		if branch, _ = lookupEnv(env); branch != "" {
+			return branch, env
+		}
+	}
+
+	return "", ""
+}
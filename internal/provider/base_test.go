@@ -0,0 +1,56 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package provider
+
+import "testing"
+
+func TestBaseBranch(t *testing.T) {
+	tests := []struct {
+		name     string
+		environ  map[string]string
+		expected string
+		env      string
+	}{
+		{
+			name:     "GitHub",
+			environ:  map[string]string{"GITHUB_BASE_REF": "main"},
+			expected: "main",
+			env:      "GITHUB_BASE_REF",
+		},
+		{
+			name:     "GitLab",
+			environ:  map[string]string{"CI_MERGE_REQUEST_TARGET_BRANCH_NAME": "develop"},
+			expected: "develop",
+			env:      "CI_MERGE_REQUEST_TARGET_BRANCH_NAME",
+		},
+		{
+			name: "Buildkite",
+			environ: map[string]string{
+				"GITHUB_BASE_REF":                    "",
+				"BUILDKITE_PULL_REQUEST_BASE_BRANCH": "release",
+			},
+			expected: "release",
+			env:      "BUILDKITE_PULL_REQUEST_BASE_BRANCH",
+		},
+		{
+			name:    "Branch",
+			environ: map[string]string{"GITHUB_BASE_REF": "", "BUILDKITE_PULL_REQUEST_BASE_BRANCH": ""},
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(tt *testing.T) {
+			branch, env := BaseBranch(func(name string) (string, bool) {
+				value, ok := test.environ[name]
+				return value, ok
+			})
+			if branch != test.expected || env != test.env {
+				tt.Errorf("Expected %q from %q, got %q from %q", test.expected, test.env, branch, env)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package provider
+
+import "strings"
+
+// Branch detects the branch being built from the environment. An empty
+// branch is returned when building a pull request, a tag, or outside of a
+// known CI service.
+func Branch(lookupEnv func(string) (string, bool)) string {
+	// GitHub Actions
+	if ref, _ := lookupEnv("GITHUB_REF"); strings.HasPrefix(ref, "refs/heads/") {
+		return strings.TrimPrefix(ref, "refs/heads/")
+	}
+	// GitLab CI, which does not set it in merge request pipelines.
+	if branch, _ := lookupEnv("CI_COMMIT_BRANCH"); branch != "" {
+		return branch
+	}
+	// Buildkite
+	if pr, _ := lookupEnv("BUILDKITE_PULL_REQUEST"); pr == "" || pr == "false" {
+		if branch, _ := lookupEnv("BUILDKITE_BRANCH"); branch != "" {
+			return branch
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,57 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package provider
+
+import "testing"
+
+func TestBranch(t *testing.T) {
+	tests := []struct {
+		name     string
+		environ  map[string]string
+		expected string
+	}{
+		{
+			name:     "GitHub",
+			environ:  map[string]string{"GITHUB_REF": "refs/heads/release/1.0"},
+			expected: "release/1.0",
+		},
+		{
+			name:    "GitHubPullRequest",
+			environ: map[string]string{"GITHUB_REF": "refs/pull/42/merge"},
+		},
+		{
+			name:     "GitLab",
+			environ:  map[string]string{"CI_COMMIT_BRANCH": "main"},
+			expected: "main",
+		},
+		{
+			name:     "Buildkite",
+			environ:  map[string]string{"BUILDKITE_BRANCH": "main", "BUILDKITE_PULL_REQUEST": "false"},
+			expected: "main",
+		},
+		{
+			name:    "BuildkitePullRequest",
+			environ: map[string]string{"BUILDKITE_BRANCH": "feature", "BUILDKITE_PULL_REQUEST": "42"},
+		},
+		{
+			name:    "Unknown",
+			environ: map[string]string{},
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(tt *testing.T) {
+			branch := Branch(func(name string) (string, bool) {
+				value, ok := test.environ[name]
+				return value, ok
+			})
+			if branch != test.expected {
+				tt.Errorf("Expected %q, got %q", test.expected, branch)
+			}
+		})
+	}
+}
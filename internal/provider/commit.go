@@ -0,0 +1,25 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package provider
+
+import "strings"
+
+// CommitURL detects the URL of the commits of the repository being built from
+// the environment, to which the SHA of a commit is appended to link to it.
+// An empty URL is returned when it is not known.
+func CommitURL(lookupEnv func(string) (string, bool)) string {
+	// GitHub Actions
+	server, _ := lookupEnv("GITHUB_SERVER_URL")
+	repository, _ := lookupEnv("GITHUB_REPOSITORY")
+	if server != "" && repository != "" {
+		return strings.TrimSuffix(server, "/") + "/" + repository + "/commit/"
+	}
+	// GitLab CI
+	if project, _ := lookupEnv("CI_PROJECT_URL"); project != "" {
+		return strings.TrimSuffix(project, "/") + "/-/commit/"
+	}
+
+	return ""
+}
@@ -0,0 +1,44 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package provider
+
+import "testing"
+
+func TestCommitURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		environ  map[string]string
+		expected string
+	}{
+		{
+			name:     "GitHub",
+			environ:  map[string]string{"GITHUB_SERVER_URL": "https://github.com", "GITHUB_REPOSITORY": "autonomy/conform"},
+			expected: "https://github.com/autonomy/conform/commit/",
+		},
+		{
+			name:     "GitLab",
+			environ:  map[string]string{"CI_PROJECT_URL": "https://gitlab.com/autonomy/conform/"},
+			expected: "https://gitlab.com/autonomy/conform/-/commit/",
+		},
+		{
+			name:    "Unknown",
+			environ: map[string]string{"GITHUB_SERVER_URL": "https://github.com"},
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(tt *testing.T) {
+			url := CommitURL(func(name string) (string, bool) {
+				value, ok := test.environ[name]
+				return value, ok
+			})
+			if url != test.expected {
+				tt.Errorf("Expected %q, got %q", test.expected, url)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/google/go-github/github"
+	"github.com/pkg/errors"
+)
+
+// GitHub is a provider that reads the pull request from the event that
+// triggered a GitHub Actions workflow. When GITHUB_TOKEN is set, the pull
+// request is fetched from the API instead, so that changes made after the
+// event (e.g. labels) are seen.
+type GitHub struct {
+	ctx   context.Context
+	token string
+	event *github.PullRequestEvent
+}
+
+// NewGitHub returns a GitHub provider.
+func NewGitHub(ctx context.Context) (*GitHub, error) {
+	data, err := ioutil.ReadFile(os.Getenv("GITHUB_EVENT_PATH"))
+	if err != nil {
+		return nil, err
+	}
+
+	event := &github.PullRequestEvent{}
+	if err = json.Unmarshal(data, event); err != nil {
+		return nil, err
+	}
+	if event.PullRequest == nil {
+		return nil, ErrNotPullRequest
+	}
+
+	return &GitHub{ctx: ctx, token: os.Getenv("GITHUB_TOKEN"), event: event}, nil
+}
+
+// PullRequest implements the Provider.PullRequest function.
+func (gh *GitHub) PullRequest() (*PullRequest, error) {
+	pr := gh.event.PullRequest
+	if gh.token != "" {
+		var err error
+		if pr, _, err = gh.client().PullRequests.Get(gh.ctx, gh.owner(), gh.repo(), gh.event.GetNumber()); err != nil {
+			return nil, err
+		}
+	}
+
+	labels := make([]string, 0, len(pr.Labels))
+	for _, label := range pr.Labels {
+		labels = append(labels, label.GetName())
+	}
+
+	return &PullRequest{
+		Number:  pr.GetNumber(),
+		Title:   pr.GetTitle(),
+		Body:    pr.GetBody(),
+		Author:  pr.GetUser().GetLogin(),
+		Labels:  labels,
+		Base:    pr.GetBase().GetRef(),
+		BaseSHA: pr.GetBase().GetSHA(),
+		Head:    pr.GetHead().GetRef(),
+	}, nil
+}
+
+// Approvals implements the Provider.Approvals function. Only the latest
+// review of each user counts, so that approvals which were later dismissed
+// or followed by a request for changes are not counted.
+func (gh *GitHub) Approvals() ([]string, error) {
+	if gh.token == "" {
+		return nil, errors.New("GITHUB_TOKEN is required to list reviews")
+	}
+
+	var reviews []*github.PullRequestReview
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := gh.client().PullRequests.ListReviews(gh.ctx, gh.owner(), gh.repo(), gh.event.GetNumber(), opt)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return LatestApprovals(reviews), nil
+}
+
+// LatestApprovals returns the users whose latest review is an approval.
+// Comments do not replace an earlier review.
+func LatestApprovals(reviews []*github.PullRequestReview) []string {
+	var users []string
+	latest := map[string]string{}
+	for _, review := range reviews {
+		user := review.GetUser().GetLogin()
+		if review.GetState() == "COMMENTED" {
+			continue
+		}
+		if _, ok := latest[user]; !ok {
+			users = append(users, user)
+		}
+		latest[user] = review.GetState()
+	}
+
+	var approvals []string
+	for _, user := range users {
+		if latest[user] == "APPROVED" {
+			approvals = append(approvals, user)
+		}
+	}
+
+	return approvals
+}
+
+// TeamMembers implements the Provider.TeamMembers function.
+func (gh *GitHub) TeamMembers(team string) ([]string, error) {
+	if gh.token == "" {
+		return nil, errors.New("GITHUB_TOKEN is required to list team members")
+	}
+	parts := strings.SplitN(team, "/", 2)
+	if len(parts) != 2 {
+		return nil, errors.Errorf("invalid team %q", team)
+	}
+
+	client := gh.client()
+
+	var id int64
+	opt := &github.ListOptions{PerPage: 100}
+	for id == 0 {
+		teams, resp, err := client.Teams.ListTeams(gh.ctx, parts[0], opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range teams {
+			if strings.EqualFold(t.GetSlug(), parts[1]) {
+				id = t.GetID()
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	if id == 0 {
+		return nil, errors.Errorf("team %q not found", team)
+	}
+
+	var members []string
+	memberOpt := &github.TeamListTeamMembersOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		users, resp, err := client.Teams.ListTeamMembers(gh.ctx, id, memberOpt)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			members = append(members, user.GetLogin())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		memberOpt.Page = resp.NextPage
+	}
+
+	return members, nil
+}
+
+func (gh *GitHub) owner() string {
+	return gh.event.GetRepo().GetOwner().GetLogin()
+}
+
+func (gh *GitHub) repo() string {
+	return gh.event.GetRepo().GetName()
+}
+
+func (gh *GitHub) client() *github.Client {
+	return github.NewClient(&http.Client{Transport: roundTripper{gh.token}, Timeout: apiTimeout})
+}
+
+type roundTripper struct {
+	accessToken string
+}
+
+// RoundTrip implements the net/http.RoundTripper interface.
+func (rt roundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	r.Header.Set("Authorization", fmt.Sprintf("Bearer %s", rt.accessToken))
+	return http.DefaultTransport.RoundTrip(r)
+}
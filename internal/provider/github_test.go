@@ -0,0 +1,31 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/github"
+)
+
+func review(user, state string) *github.PullRequestReview {
+	return &github.PullRequestReview{User: &github.User{Login: github.String(user)}, State: github.String(state)}
+}
+
+func TestLatestApprovals(t *testing.T) {
+	reviews := []*github.PullRequestReview{
+		review("a", "APPROVED"),
+		review("b", "APPROVED"),
+		review("c", "CHANGES_REQUESTED"),
+		review("b", "CHANGES_REQUESTED"),
+		review("a", "COMMENTED"),
+		review("c", "APPROVED"),
+		review("d", "DISMISSED"),
+	}
+	if approvals := LatestApprovals(reviews); !reflect.DeepEqual(approvals, []string{"a", "c"}) {
+		t.Errorf("Expected approvals by a and c, got %v", approvals)
+	}
+}
@@ -0,0 +1,155 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// GitLab is a provider that fetches the merge request of a GitLab CI merge
+// request pipeline from the API. It authenticates with GITLAB_TOKEN if set,
+// and with CI_JOB_TOKEN otherwise.
+type GitLab struct {
+	ctx     context.Context
+	api     string
+	project string
+	iid     int
+	client  *http.Client
+}
+
+// NewGitLab returns a GitLab provider.
+func NewGitLab(ctx context.Context) (*GitLab, error) {
+	iid, ok := os.LookupEnv("CI_MERGE_REQUEST_IID")
+	if !ok {
+		return nil, ErrNotPullRequest
+	}
+	n, err := strconv.Atoi(iid)
+	if err != nil {
+		return nil, errors.Errorf("invalid CI_MERGE_REQUEST_IID %q", iid)
+	}
+
+	return &GitLab{
+		ctx:     ctx,
+		api:     os.Getenv("CI_API_V4_URL"),
+		project: os.Getenv("CI_PROJECT_ID"),
+		iid:     n,
+		client:  &http.Client{Timeout: apiTimeout},
+	}, nil
+}
+
+// PullRequest implements the Provider.PullRequest function.
+func (gl *GitLab) PullRequest() (*PullRequest, error) {
+	var mr struct {
+		IID          int      `json:"iid"`
+		Title        string   `json:"title"`
+		Description  string   `json:"description"`
+		Labels       []string `json:"labels"`
+		SourceBranch string   `json:"source_branch"`
+		TargetBranch string   `json:"target_branch"`
+		DiffRefs     struct {
+			BaseSHA string `json:"base_sha"`
+		} `json:"diff_refs"`
+		Author struct {
+			Username string `json:"username"`
+		} `json:"author"`
+	}
+	if err := gl.get("", &mr); err != nil {
+		return nil, err
+	}
+
+	return &PullRequest{
+		Number:  mr.IID,
+		Title:   mr.Title,
+		Body:    mr.Description,
+		Author:  mr.Author.Username,
+		Labels:  mr.Labels,
+		Base:    mr.TargetBranch,
+		BaseSHA: mr.DiffRefs.BaseSHA,
+		Head:    mr.SourceBranch,
+	}, nil
+}
+
+// Approvals implements the Provider.Approvals function.
+func (gl *GitLab) Approvals() ([]string, error) {
+	var approvals struct {
+		ApprovedBy []struct {
+			User struct {
+				Username string `json:"username"`
+			} `json:"user"`
+		} `json:"approved_by"`
+	}
+	if err := gl.get("/approvals", &approvals); err != nil {
+		return nil, err
+	}
+
+	users := make([]string, 0, len(approvals.ApprovedBy))
+	for _, approval := range approvals.ApprovedBy {
+		users = append(users, approval.User.Username)
+	}
+
+	return users, nil
+}
+
+// TeamMembers implements the Provider.TeamMembers function. Members inherited
+// from parent groups are included.
+func (gl *GitLab) TeamMembers(team string) ([]string, error) {
+	var users []string
+	u := fmt.Sprintf("%s/groups/%s/members/all?per_page=100", gl.api, url.PathEscape(team))
+	for page := "1"; page != ""; {
+		var members []struct {
+			Username string `json:"username"`
+		}
+		next, err := gl.do(u+"&page="+page, &members)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			users = append(users, member.Username)
+		}
+		page = next
+	}
+
+	return users, nil
+}
+
+// get decodes a resource of the merge request from the API.
+func (gl *GitLab) get(resource string, v interface{}) error {
+	_, err := gl.do(fmt.Sprintf("%s/projects/%s/merge_requests/%d%s", gl.api, url.PathEscape(gl.project), gl.iid, resource), v)
+	return err
+}
+
+// do decodes the response of a request to the API, and returns the number of
+// the next page of a paginated resource, empty for the last page.
+func (gl *GitLab) do(u string, v interface{}) (next string, err error) {
+	req, err := http.NewRequestWithContext(gl.ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	if token, ok := os.LookupEnv("GITLAB_TOKEN"); ok {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	} else {
+		req.Header.Set("JOB-TOKEN", os.Getenv("CI_JOB_TOKEN"))
+	}
+
+	resp, err := gl.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	// nolint: errcheck
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("GET %s: %s", u, resp.Status)
+	}
+
+	return resp.Header.Get("X-Next-Page"), json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,42 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGitLabTeamMembers(t *testing.T) {
+	pages := map[string]string{
+		"1": `[{"username": "a"}, {"username": "b"}]`,
+		"2": `[{"username": "c"}]`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/groups/org/team/members/all" {
+			http.NotFound(w, r)
+			return
+		}
+		page := r.URL.Query().Get("page")
+		if page == "1" {
+			w.Header().Set("X-Next-Page", "2")
+		}
+		fmt.Fprint(w, pages[page])
+	}))
+	defer server.Close()
+
+	gl := &GitLab{ctx: context.Background(), api: server.URL, client: server.Client()}
+	members, err := gl.TeamMembers("org/team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(members, []string{"a", "b", "c"}) {
+		t.Errorf("Expected the members of every page, got %v", members)
+	}
+}
@@ -0,0 +1,62 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+// Package provider retrieves pull request metadata from the Git hosting
+// provider that conform is running on.
+package provider
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+)
+
+// apiTimeout is the timeout of each request to the API of a provider.
+const apiTimeout = 30 * time.Second
+
+// ErrNotPullRequest is returned when conform is not running for a pull
+// request.
+var ErrNotPullRequest = errors.New("not running for a pull request")
+
+// PullRequest describes a pull request, or a merge request on GitLab.
+type PullRequest struct {
+	Number int
+	Title  string
+	Body   string
+	Author string
+	Labels []string
+	// Base is the name of the branch the pull request is merged into.
+	Base string
+	// BaseSHA is the SHA of the commit of the base branch the pull request
+	// is compared against.
+	BaseSHA string
+	// Head is the name of the branch of the pull request.
+	Head string
+}
+
+// Provider is a Git hosting provider.
+type Provider interface {
+	// PullRequest returns the pull request being enforced.
+	PullRequest() (*PullRequest, error)
+	// Approvals returns the users currently approving the pull request.
+	Approvals() ([]string, error)
+	// TeamMembers returns the users of a team, in the form "org/team" on
+	// GitHub and "group/subgroup" on GitLab.
+	TeamMembers(team string) ([]string, error)
+}
+
+// New detects the provider from the CI environment. Requests to the API are
+// canceled when the context is done. It returns ErrNotPullRequest when not
+// running for a pull request.
+func New(ctx context.Context) (Provider, error) {
+	if _, ok := os.LookupEnv("GITHUB_EVENT_PATH"); ok {
+		return NewGitHub(ctx)
+	}
+	if _, ok := os.LookupEnv("GITLAB_CI"); ok {
+		return NewGitLab(ctx)
+	}
+
+	return nil, ErrNotPullRequest
+}
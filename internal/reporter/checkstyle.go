@@ -0,0 +1,88 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+// Checkstyle writes reports in the XML format of Checkstyle, as read by code
+// review bots and editors to show violations inline. Only the violations of
+// files are written, grouped by file, since the format has no place for
+// the violations of commits. Suppressed and baselined violations are not
+// written.
+type Checkstyle struct{}
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// Write implements the Reporter.Write function.
+func (Checkstyle) Write(w io.Writer, r *Report) error {
+	doc := checkstyleReport{Version: "4.3"}
+	files := map[string]int{}
+	for _, c := range r.Checks {
+		source := "conform." + strings.Replace(RuleID(c), "/", ".", -1)
+		for _, v := range c.Violations {
+			if v.File == "" || v.Status == StatusSuppressed || v.Status == StatusBaseline {
+				continue
+			}
+			index, ok := files[v.File]
+			if !ok {
+				index = len(doc.Files)
+				files[v.File] = index
+				doc.Files = append(doc.Files, checkstyleFile{Name: v.File})
+			}
+			doc.Files[index].Errors = append(doc.Files[index].Errors, checkstyleError{
+				Line:     v.Line,
+				Severity: checkstyleSeverity(c.Severity),
+				Message:  v.Message,
+				Source:   source,
+			})
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+// checkstyleSeverity returns the Checkstyle severity of the severity.
+func checkstyleSeverity(severity policy.Severity) string {
+	switch severity {
+	case policy.SeverityWarn:
+		return "warning"
+	case policy.SeverityInfo:
+		return "info"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,53 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestCheckstyle(t *testing.T) {
+	r := &Report{Outcome: "failure"}
+	r.Add(&Check{
+		Policy:     "commit",
+		Name:       "DCO",
+		Severity:   policy.SeverityError,
+		Violations: []*Violation{{Message: "Commit does not have a DCO", Status: StatusFailed, Commit: "0123456789abcdef0123456789abcdef01234567"}},
+	})
+	r.Add(&Check{
+		Policy:   "license",
+		Name:     "File Header",
+		Severity: policy.SeverityError,
+		Violations: []*Violation{
+			{Message: "File main.go does not contain a license header", Status: StatusFailed, File: "main.go", Line: 1},
+			{Message: "File gen.go does not contain a license header", Status: StatusSuppressed, Directive: "conform:ignore", File: "gen.go", Line: 1},
+		},
+	})
+	r.Add(&Check{
+		Policy:     "docs:newline",
+		Name:       "Final Newline",
+		Severity:   policy.SeverityWarn,
+		Violations: []*Violation{{Message: "File main.go does not end with a newline", Status: StatusWarning, File: "main.go"}},
+	})
+
+	var buf bytes.Buffer
+	if err := (Checkstyle{}).Write(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3">
+  <file name="main.go">
+    <error line="1" severity="error" message="File main.go does not contain a license header" source="conform.license.file-header"></error>
+    <error severity="warning" message="File main.go does not end with a newline" source="conform.newline.final-newline"></error>
+  </file>
+</checkstyle>
+`
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
@@ -0,0 +1,102 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+// CodeQuality writes reports as GitLab code quality reports, as shown in the
+// widget of merge requests. Only the violations of files are written, since
+// each issue must be located in a file. Suppressed and baselined violations
+// are not written.
+type CodeQuality struct{}
+
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines codeQualityLines `json:"lines"`
+}
+
+type codeQualityLines struct {
+	Begin int `json:"begin"`
+}
+
+// Write implements the Reporter.Write function.
+func (CodeQuality) Write(w io.Writer, r *Report) error {
+	issues := []codeQualityIssue{}
+	seen := map[string]int{}
+	for _, c := range r.Checks {
+		id := RuleID(c)
+		for _, v := range c.Violations {
+			if v.File == "" || v.Status == StatusSuppressed || v.Status == StatusBaseline {
+				continue
+			}
+			line := v.Line
+			if line == 0 {
+				line = 1
+			}
+			issues = append(issues, codeQualityIssue{
+				Description: v.Message,
+				CheckName:   id,
+				Fingerprint: fingerprint(seen, id, v.File, v.Message),
+				Severity:    codeQualitySeverity(c.Severity),
+				Location:    codeQualityLocation{Path: v.File, Lines: codeQualityLines{Begin: line}},
+			})
+		}
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+
+	return e.Encode(issues)
+}
+
+// fingerprint returns the fingerprint of an issue, which identifies it across
+// enforcements so that GitLab tells new issues from existing ones. It does
+// not depend on the line of the issue, which changes as the file is edited.
+// Identical issues are told apart by the order they are found in, counted
+// in seen.
+func fingerprint(seen map[string]int, fields ...string) string {
+	h := sha256.New()
+	for _, field := range fields {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	n := seen[sum]
+	seen[sum]++
+	if n == 0 {
+		return sum
+	}
+	h.Write([]byte(strconv.Itoa(n)))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// codeQualitySeverity returns the code quality severity of the severity.
+func codeQualitySeverity(severity policy.Severity) string {
+	switch severity {
+	case policy.SeverityWarn:
+		return "minor"
+	case policy.SeverityInfo:
+		return "info"
+	default:
+		return "major"
+	}
+}
@@ -0,0 +1,79 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestCodeQuality(t *testing.T) {
+	r := &Report{Outcome: "failure"}
+	r.Add(&Check{
+		Policy:     "commit",
+		Name:       "DCO",
+		Severity:   policy.SeverityError,
+		Violations: []*Violation{{Message: "Commit does not have a DCO", Status: StatusFailed, Commit: "0123456789abcdef0123456789abcdef01234567"}},
+	})
+	r.Add(&Check{
+		Policy:   "license",
+		Name:     "File Header",
+		Severity: policy.SeverityError,
+		Violations: []*Violation{
+			{Message: "File main.go does not contain a license header", Status: StatusFailed, File: "main.go", Line: 1},
+			{Message: "File gen.go does not contain a license header", Status: StatusSuppressed, Directive: "conform:ignore", File: "gen.go", Line: 1},
+		},
+	})
+	r.Add(&Check{
+		Policy:   "whitespace",
+		Name:     "Trailing Whitespace",
+		Severity: policy.SeverityWarn,
+		Violations: []*Violation{
+			{Message: "File main.go has trailing whitespace", Status: StatusWarning, File: "main.go"},
+			{Message: "File main.go has trailing whitespace", Status: StatusWarning, File: "main.go"},
+		},
+	})
+
+	var buf bytes.Buffer
+	if err := (CodeQuality{}).Write(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	var issues []codeQualityIssue
+	if err := json.Unmarshal(buf.Bytes(), &issues); err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 3 {
+		t.Fatalf("Expected 3 issues, got %s", buf.String())
+	}
+
+	expected := codeQualityIssue{
+		Description: "File main.go does not contain a license header",
+		CheckName:   "license/file-header",
+		Fingerprint: issues[0].Fingerprint,
+		Severity:    "major",
+		Location:    codeQualityLocation{Path: "main.go", Lines: codeQualityLines{Begin: 1}},
+	}
+	if issues[0] != expected {
+		t.Errorf("Expected issue %+v, got %+v", expected, issues[0])
+	}
+	if issues[1].Severity != "minor" || issues[1].Location.Lines.Begin != 1 {
+		t.Errorf("Expected a minor issue at line 1, got %+v", issues[1])
+	}
+	if issues[1].Fingerprint == issues[2].Fingerprint {
+		t.Errorf("Expected identical issues to have distinct fingerprints, got %s", issues[1].Fingerprint)
+	}
+
+	// Fingerprints are stable across enforcements.
+	var again bytes.Buffer
+	if err := (CodeQuality{}).Write(&again, r); err != nil {
+		t.Fatal(err)
+	}
+	if again.String() != buf.String() {
+		t.Errorf("Expected the same report, got:\n%s", again.String())
+	}
+}
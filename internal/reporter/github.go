@@ -0,0 +1,84 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+// GitHub writes reports as GitHub Actions workflow commands, which annotate
+// the files of the pull request with the violations, at their line where it
+// is known. The violations of commits annotate the run, as do notices of the
+// checks slower than the slow threshold. Suppressed and baselined violations
+// are not written. At most MaxIssues violations of each check are annotated,
+// followed by a notice of the number of those that are not.
+type GitHub struct{}
+
+// Write implements the Reporter.Write function.
+func (GitHub) Write(w io.Writer, r *Report) error {
+	b := bufio.NewWriter(w)
+	for _, c := range r.Checks {
+		if c.Slow {
+			fmt.Fprintf(b, "::notice title=%s::%s\n", escapeProperty("Slow check"), escapeData(slowMessage(r, c)))
+		}
+		written, omitted := 0, 0
+		for _, v := range c.Violations {
+			if v.Status == StatusSuppressed || v.Status == StatusBaseline {
+				continue
+			}
+			if r.MaxIssues > 0 && written == r.MaxIssues {
+				omitted++
+				continue
+			}
+			written++
+			var props []string
+			if v.File != "" {
+				props = append(props, "file="+escapeProperty(v.File))
+				if v.Line != 0 {
+					props = append(props, fmt.Sprintf("line=%d", v.Line))
+				}
+			}
+			props = append(props, "title="+escapeProperty(c.Policy+": "+c.Name))
+			message := v.Message
+			if v.File == "" && v.Commit != "" {
+				message = shortSHA(v.Commit) + ": " + message
+			}
+			fmt.Fprintf(b, "::%s %s::%s\n", githubCommand(c.Severity), strings.Join(props, ","), escapeData(message))
+		}
+		if omitted != 0 {
+			fmt.Fprintf(b, "::notice title=%s::%s\n", escapeProperty(c.Policy+": "+c.Name), escapeData(fmt.Sprintf("%d more violations are not annotated", omitted)))
+		}
+	}
+
+	return b.Flush()
+}
+
+// githubCommand returns the workflow command of the annotations of the
+// severity.
+func githubCommand(severity policy.Severity) string {
+	switch severity {
+	case policy.SeverityWarn:
+		return "warning"
+	case policy.SeverityInfo:
+		return "notice"
+	default:
+		return "error"
+	}
+}
+
+// escapeData escapes the message of a workflow command.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a property of a workflow command.
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,79 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestGitHub(t *testing.T) {
+	r := &Report{Outcome: "failure", SlowThreshold: time.Second}
+	r.Add(&Check{
+		Policy:     "commit",
+		Name:       "Conventional Commit",
+		Severity:   policy.SeverityError,
+		Violations: []*Violation{{Message: "Invalid conventional commits format: \"wip\n\"", Status: StatusFailed, Commit: "0123456789abcdef0123456789abcdef01234567"}},
+	})
+	r.Add(&Check{
+		Policy:   "docs:license",
+		Name:     "File Header",
+		Severity: policy.SeverityError,
+		Violations: []*Violation{
+			{Message: "File main.go does not contain a license header", Status: StatusFailed, File: "docs/main.go", Line: 1},
+			{Message: "File gen.go does not contain a license header", Status: StatusSuppressed, Directive: "conform:ignore", File: "docs/gen.go", Line: 1},
+		},
+	})
+	r.Add(&Check{
+		Policy:     "newline",
+		Name:       "Final Newline",
+		Severity:   policy.SeverityWarn,
+		Duration:   1500 * time.Millisecond,
+		Slow:       true,
+		Violations: []*Violation{{Message: "File a,b.go does not end with a newline", Status: StatusWarning, File: "a,b.go"}},
+	})
+
+	var buf bytes.Buffer
+	if err := (GitHub{}).Write(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	expected := `::error title=commit%3A Conventional Commit::0123456789ab: Invalid conventional commits format: "wip%0A"
+::error file=docs/main.go,line=1,title=docs%3Alicense%3A File Header::File main.go does not contain a license header
+::notice title=Slow check::newline: Final Newline took 1.5s, over the slow threshold of 1s
+::warning file=a%2Cb.go,title=newline%3A Final Newline::File a,b.go does not end with a newline
+`
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestGitHubMaxIssues(t *testing.T) {
+	r := &Report{Outcome: "failure", MaxIssues: 1}
+	r.Add(&Check{
+		Policy:   "license",
+		Name:     "File Header",
+		Severity: policy.SeverityError,
+		Violations: []*Violation{
+			{Message: "File gen.go does not contain a license header", Status: StatusSuppressed, File: "gen.go"},
+			{Message: "File a.go does not contain a license header", Status: StatusFailed, File: "a.go"},
+			{Message: "File b.go does not contain a license header", Status: StatusFailed, File: "b.go"},
+			{Message: "File c.go does not contain a license header", Status: StatusFailed, File: "c.go"},
+		},
+	})
+
+	var buf bytes.Buffer
+	if err := (GitHub{}).Write(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	expected := `::error file=a.go,title=license%3A File Header::File a.go does not contain a license header
+::notice title=license%3A File Header::2 more violations are not annotated
+`
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
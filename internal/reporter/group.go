@@ -0,0 +1,135 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Placeholders of the locations of the violations in the messages of groups.
+const (
+	FilePlaceholder   = "<file>"
+	CommitPlaceholder = "<commit>"
+)
+
+// Group is a group of identical violations of a check, such as the files
+// missing the same header, reported as a single entry.
+type Group struct {
+	// Message is the message of the violations, in which the file or commit
+	// of each is replaced by a placeholder.
+	Message    string
+	Status     string
+	Directive  string
+	Violations []*Violation
+}
+
+// GroupViolations groups the identical violations of a check, those with
+// the same status, directive, and message but for their location, in the
+// order of their first violation.
+func GroupViolations(violations []*Violation) []*Group {
+	type key struct{ message, status, directive string }
+
+	var groups []*Group
+	index := map[key]*Group{}
+	for _, v := range violations {
+		k := key{message: groupMessage(v), status: v.Status, directive: v.Directive}
+		g, ok := index[k]
+		if !ok {
+			g = &Group{Message: k.message, Status: v.Status, Directive: v.Directive}
+			index[k] = g
+			groups = append(groups, g)
+		}
+		g.Violations = append(g.Violations, v)
+	}
+	// The message of a single violation is kept as is.
+	for _, g := range groups {
+		if len(g.Violations) == 1 {
+			g.Message = g.Violations[0].Message
+		}
+	}
+
+	return groups
+}
+
+// groupMessage returns the message of the violation, without its location.
+func groupMessage(v *Violation) string {
+	message := v.Message
+	if v.File != "" {
+		message = strings.Replace(message, v.File, FilePlaceholder, -1)
+		if base := path.Base(v.File); base != v.File {
+			message = strings.Replace(message, base, FilePlaceholder, -1)
+		}
+	}
+	if v.Commit != "" {
+		message = strings.Replace(message, v.Commit, CommitPlaceholder, -1)
+		message = strings.Replace(message, shortSHA(v.Commit), CommitPlaceholder, -1)
+	}
+
+	return message
+}
+
+// Locations returns the locations of the violations of the group that have
+// one, e.g. main.go:1 or the short SHA of a commit.
+func (g *Group) Locations() []string {
+	var locations []string
+	for _, v := range g.Violations {
+		switch {
+		case v.File != "" && v.Line != 0:
+			locations = append(locations, fmt.Sprintf("%s:%d", v.File, v.Line))
+		case v.File != "":
+			locations = append(locations, v.File)
+		case v.Commit != "":
+			locations = append(locations, shortSHA(v.Commit))
+		}
+	}
+
+	return locations
+}
+
+// Title returns the message of the group, followed by the directive of
+// suppressed violations.
+func (g *Group) Title() string {
+	if g.Directive != "" {
+		return fmt.Sprintf("%s (%s)", g.Message, g.Directive)
+	}
+
+	return g.Message
+}
+
+// Text returns the title of the group, followed by the number of its
+// violations and up to samples of their locations if it has several, e.g.
+// File <file> does not contain a license header (400 times: a.go, b.go, ...).
+func (g *Group) Text(samples int) string {
+	text := g.Title()
+	if len(g.Violations) == 1 {
+		return text
+	}
+
+	locations := g.Locations()
+	if len(locations) == 0 {
+		return fmt.Sprintf("%s (%d times)", text, len(g.Violations))
+	}
+	if len(locations) > samples {
+		locations = append(locations[:samples:samples], "...")
+	}
+
+	return fmt.Sprintf("%s (%d times: %s)", text, len(g.Violations), strings.Join(locations, ", "))
+}
+
+// Capped returns the first max groups, and the number of violations of the
+// others, or all of the groups if max is 0.
+func Capped(groups []*Group, max int) ([]*Group, int) {
+	if max == 0 || len(groups) <= max {
+		return groups, 0
+	}
+	omitted := 0
+	for _, g := range groups[max:] {
+		omitted += len(g.Violations)
+	}
+
+	return groups[:max], omitted
+}
@@ -0,0 +1,54 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"testing"
+)
+
+func TestGroupViolations(t *testing.T) {
+	violations := []*Violation{
+		{Message: "File a.go does not contain a license header", Status: StatusFailed, File: "a.go"},
+		{Message: "File main.go does not contain a license header", Status: StatusFailed, File: "cmd/main.go", Line: 1},
+		{Message: "File docs/b.go does not contain a license header", Status: StatusFailed, File: "docs/b.go"},
+		{Message: "File gen.go does not contain a license header", Status: StatusSuppressed, Directive: "conform:ignore", File: "gen.go"},
+		{Message: "File c.go does not contain a license header", Status: StatusFailed, File: "c.go"},
+		{Message: "File d.go does not contain a license header", Status: StatusFailed, File: "d.go"},
+		{Message: "Commit 0123456789ab is not signed", Status: StatusFailed, Commit: "0123456789abcdef0123456789abcdef01234567"},
+		{Message: "Commit fedcba987654 is not signed", Status: StatusFailed, Commit: "fedcba9876543210fedcba9876543210fedcba98"},
+		{Message: "Repository has no README", Status: StatusFailed},
+		{Message: "Repository has no README", Status: StatusFailed},
+	}
+
+	groups := GroupViolations(violations)
+	expected := []struct {
+		count int
+		text  string
+	}{
+		{5, "File <file> does not contain a license header (5 times: a.go, cmd/main.go:1, docs/b.go, ...)"},
+		{1, "File gen.go does not contain a license header (conform:ignore)"},
+		{2, "Commit <commit> is not signed (2 times: 0123456789ab, fedcba987654)"},
+		{2, "Repository has no README (2 times)"},
+	}
+	if len(groups) != len(expected) {
+		t.Fatalf("Expected %d groups, got %d", len(expected), len(groups))
+	}
+	for i, g := range groups {
+		if len(g.Violations) != expected[i].count {
+			t.Errorf("Expected group %d to have %d violations, got %d", i, expected[i].count, len(g.Violations))
+		}
+		if text := g.Text(3); text != expected[i].text {
+			t.Errorf("Expected group %d to be %q, got %q", i, expected[i].text, text)
+		}
+	}
+
+	capped, omitted := Capped(groups, 2)
+	if len(capped) != 2 || omitted != 4 {
+		t.Errorf("Expected 2 groups and 4 omitted violations, got %d and %d", len(capped), omitted)
+	}
+	if capped, omitted = Capped(groups, 0); len(capped) != 4 || omitted != 0 {
+		t.Errorf("Expected all groups when uncapped, got %d and %d omitted", len(capped), omitted)
+	}
+}
@@ -0,0 +1,203 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+// HTML writes reports as a standalone HTML page, as published as an artifact
+// of CI jobs. The results are in a section per policy, with a row per
+// violation, or per check if it has none, and can be filtered by severity
+// and status. Identical violations are grouped in a single row, whose
+// locations are listed in an expandable section. Checks are shown with the
+// time they took, highlighted when
+// slow. The results are preceded by the summary of the report. Commits are linked to when the commit URL of the report is
+// known.
+type HTML struct{}
+
+type htmlReport struct {
+	Outcome    string
+	Summary    *Summary
+	Duration   string
+	Severities []string
+	Statuses   []string
+	Policies   []*htmlPolicy
+}
+
+type htmlPolicy struct {
+	Name string
+	Rows []htmlRow
+}
+
+type htmlRow struct {
+	Check       string
+	Description string
+	Severity    string
+	Status      string
+	Message     string
+	Directive   string
+	File        string
+	Line        int
+	Commit      string
+	CommitURL   string
+	// Locations are the locations of a group of identical violations.
+	Locations []string
+	Duration  string
+	Slow      bool
+}
+
+// Write implements the Reporter.Write function.
+func (HTML) Write(w io.Writer, r *Report) error {
+	doc := htmlReport{
+		Outcome:    r.Outcome,
+		Summary:    r.Summary(),
+		Severities: []string{"error", "warn", "info"},
+		Statuses:   []string{StatusFailed, StatusWarning, StatusInfo, StatusPass, StatusSkipped, StatusSuppressed, StatusBaseline},
+	}
+	if r.Duration != 0 {
+		doc.Duration = r.Duration.Round(time.Millisecond).String()
+	}
+	policies := map[string]*htmlPolicy{}
+	for _, c := range r.Checks {
+		p, ok := policies[c.Policy]
+		if !ok {
+			p = &htmlPolicy{Name: c.Policy}
+			policies[c.Policy] = p
+			doc.Policies = append(doc.Policies, p)
+		}
+		row := htmlRow{Check: c.Name, Description: c.Description, Severity: string(c.Severity), Status: c.Status, Message: c.Message, Slow: c.Slow}
+		if c.Duration != 0 {
+			row.Duration = c.Duration.Round(time.Millisecond).String()
+		}
+		if len(c.Violations) == 0 {
+			p.Rows = append(p.Rows, row)
+			continue
+		}
+		for _, g := range GroupViolations(c.Violations) {
+			row.Status = g.Status
+			row.Directive = g.Directive
+			if len(g.Violations) > 1 {
+				row.Message = g.Message
+				row.Locations = g.Locations()
+				if len(row.Locations) == 0 {
+					row.Message = fmt.Sprintf("%s (%d times)", g.Message, len(g.Violations))
+				}
+				p.Rows = append(p.Rows, row)
+				continue
+			}
+			v := g.Violations[0]
+			row.Message = v.Message
+			row.File = v.File
+			row.Line = v.Line
+			row.Commit = v.Commit
+			row.CommitURL = ""
+			if v.Commit != "" && r.CommitURL != "" {
+				row.CommitURL = r.CommitURL + v.Commit
+			}
+			p.Rows = append(p.Rows, row)
+		}
+	}
+
+	return htmlTemplate.Execute(w, doc)
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{"short": shortSHA}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Conform: {{.Outcome}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292e; }
+h1 span { font-size: 0.6em; padding: 0.2em 0.5em; border-radius: 0.3em; vertical-align: middle; }
+fieldset { display: inline-block; margin: 0 1em 1em 0; border: 1px solid #d1d5da; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { text-align: left; padding: 0.4em 0.8em; border-bottom: 1px solid #e1e4e8; vertical-align: top; }
+th { background: #f6f8fa; }
+code { font-size: 0.9em; }
+.status { font-weight: bold; white-space: nowrap; }
+.PASS, .pass { color: #22863a; }
+.FAILED, .failure, .config, .error { color: #cb2431; }
+.WARNING, .warnings { color: #b08800; }
+.INFO { color: #0366d6; }
+.SKIPPED, .SUPPRESSED, .BASELINE { color: #6a737d; }
+.slow { color: #b08800; font-weight: bold; }
+.hidden { display: none; }
+</style>
+</head>
+<body>
+<h1>Conform <span class="{{.Outcome}}">{{.Outcome}}</span></h1>
+{{- with .Summary}}
+<p class="summary">{{.Checks}} checks: {{.Passed}} passed, {{.Failed}} failed, {{.Warned}} warned, {{.Info}} info, {{.Skipped}} skipped, with {{.Violations}} violations{{if $.Duration}}, in {{$.Duration}}{{end}}.</p>
+{{- end}}
+<form id="filters">
+<fieldset><legend>Severity</legend>
+{{- range .Severities}}
+<label><input type="checkbox" name="severity" value="{{.}}" checked> {{.}}</label>
+{{- end}}
+</fieldset>
+<fieldset><legend>Status</legend>
+{{- range .Statuses}}
+<label><input type="checkbox" name="status" value="{{.}}" checked> {{.}}</label>
+{{- end}}
+</fieldset>
+</form>
+{{- range .Policies}}
+<section>
+<h2>{{.Name}}</h2>
+<table>
+<thead><tr><th>Check</th><th>Severity</th><th>Status</th><th>Message</th><th>Location</th><th>Time</th></tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr data-severity="{{.Severity}}" data-status="{{.Status}}">
+<td{{if .Description}} title="{{.Description}}"{{end}}>{{.Check}}</td>
+<td>{{.Severity}}</td>
+<td class="status {{.Status}}">{{.Status}}</td>
+<td>{{.Message}}{{if .Directive}} <em>({{.Directive}})</em>{{end}}</td>
+<td>
+{{- if .Locations}}<details><summary>{{len .Locations}} times</summary>{{range $i, $l := .Locations}}{{if $i}}<br>{{end}}<code>{{$l}}</code>{{end}}</details>{{end}}
+{{- if .File}}<code>{{.File}}{{if .Line}}:{{.Line}}{{end}}</code>{{end}}
+{{- if .CommitURL}}<a href="{{.CommitURL}}"><code>{{short .Commit}}</code></a>{{else if .Commit}}<code>{{short .Commit}}</code>{{end -}}
+</td>
+<td{{if .Slow}} class="slow" title="Slower than the threshold"{{end}}>{{.Duration}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+</section>
+{{- end}}
+<script>
+(function () {
+  var form = document.getElementById("filters");
+  function checked(name) {
+    var values = {};
+    form.querySelectorAll("input[name=" + name + "]").forEach(function (input) {
+      values[input.value] = input.checked;
+    });
+    return values;
+  }
+  function filter() {
+    var severities = checked("severity"), statuses = checked("status");
+    document.querySelectorAll("section").forEach(function (section) {
+      var visible = 0;
+      section.querySelectorAll("tbody tr").forEach(function (row) {
+        var shown = severities[row.dataset.severity] !== false && statuses[row.dataset.status] !== false;
+        row.classList.toggle("hidden", !shown);
+        if (shown) {
+          visible++;
+        }
+      });
+      section.classList.toggle("hidden", visible === 0);
+    });
+  }
+  form.addEventListener("change", filter);
+})();
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,63 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestHTML(t *testing.T) {
+	r := &Report{Outcome: "failure", CommitURL: "https://github.com/autonomy/conform/commit/", Duration: 1234567 * time.Microsecond}
+	r.Add(&Check{
+		Policy:     "commit",
+		Name:       "DCO",
+		Severity:   policy.SeverityError,
+		Violations: []*Violation{{Message: "Commit does not have a DCO", Status: StatusFailed, Commit: "0123456789abcdef0123456789abcdef01234567"}},
+	})
+	r.Add(&Check{
+		Policy:   "license",
+		Name:     "File Header",
+		Severity: policy.SeverityError,
+		Violations: []*Violation{
+			{Message: "File <main>.go does not contain a license header", Status: StatusFailed, File: "main.go", Line: 1},
+			{Message: "File a.go does not contain a license header", Status: StatusWarning, File: "docs/a.go"},
+			{Message: "File b.go does not contain a license header", Status: StatusWarning, File: "docs/b.go"},
+		},
+	})
+	r.Add(&Check{Policy: "license", Name: "Notice", Severity: policy.SeverityWarn, Message: "All notices are valid", Duration: 2500 * time.Millisecond, Slow: true})
+
+	var buf bytes.Buffer
+	if err := (HTML{}).Write(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, expected := range []string{
+		`<title>Conform: failure</title>`,
+		`<p class="summary">3 checks: 1 passed, 2 failed, 0 warned, 0 info, 0 skipped, with 4 violations, in 1.235s.</p>`,
+		`<h2>commit</h2>`,
+		`<h2>license</h2>`,
+		`<tr data-severity="error" data-status="FAILED">`,
+		`<tr data-severity="warn" data-status="PASS">`,
+		`<a href="https://github.com/autonomy/conform/commit/0123456789abcdef0123456789abcdef01234567"><code>0123456789ab</code></a>`,
+		`<td>File &lt;main&gt;.go does not contain a license header</td>`,
+		`<code>main.go:1</code>`,
+		`<td>File &lt;file&gt; does not contain a license header</td>`,
+		`<details><summary>2 times</summary><code>docs/a.go</code><br><code>docs/b.go</code></details>`,
+		`<td class="slow" title="Slower than the threshold">2.5s</td>`,
+		`<input type="checkbox" name="severity" value="warn" checked>`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected the report to contain %q, got:\n%s", expected, out)
+		}
+	}
+	if strings.Count(out, "<section>") != 2 {
+		t.Errorf("Expected a section per policy, got:\n%s", out)
+	}
+}
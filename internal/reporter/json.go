@@ -0,0 +1,139 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONVersion is the version of the JSON document. Fields may be added
+// without changing the version, but changing or removing one bumps it.
+const JSONVersion = 1
+
+// JSON writes reports as a JSON document:
+//
+//	{
+//	  "version": 1,
+//	  "outcome": "failure",
+//	  "checks": [
+//	    {
+//	      "policy": "commit",
+//	      "check": "Header Length",
+//	      "severity": "error",
+//	      "status": "FAILED",
+//	      "message": "Header is 92 characters",
+//	      "durationSeconds": 0.012,
+//	      "violations": [
+//	        {"message": "Commit header is 92 characters", "status": "FAILED", "commit": "<sha>"}
+//	      ]
+//	    }
+//	  ],
+//	  "summary": {
+//	    "checks": 1,
+//	    "passed": 0,
+//	    "failed": 1,
+//	    "warned": 0,
+//	    "info": 0,
+//	    "skipped": 0,
+//	    "violations": 1,
+//	    "policies": [{"policy": "commit", "violations": 1}],
+//	    "durationSeconds": 0.42
+//	  }
+//	}
+type JSON struct{}
+
+type jsonReport struct {
+	Version int         `json:"version"`
+	Outcome string      `json:"outcome"`
+	Checks  []jsonCheck `json:"checks"`
+	Summary jsonSummary `json:"summary"`
+}
+
+type jsonSummary struct {
+	Checks          int                 `json:"checks"`
+	Passed          int                 `json:"passed"`
+	Failed          int                 `json:"failed"`
+	Warned          int                 `json:"warned"`
+	Info            int                 `json:"info"`
+	Skipped         int                 `json:"skipped"`
+	Violations      int                 `json:"violations"`
+	Policies        []jsonPolicySummary `json:"policies"`
+	DurationSeconds float64             `json:"durationSeconds"`
+}
+
+type jsonPolicySummary struct {
+	Policy     string `json:"policy"`
+	Violations int    `json:"violations"`
+}
+
+type jsonCheck struct {
+	Policy          string          `json:"policy"`
+	Check           string          `json:"check"`
+	Severity        string          `json:"severity"`
+	Status          string          `json:"status"`
+	Message         string          `json:"message"`
+	DurationSeconds float64         `json:"durationSeconds"`
+	Slow            bool            `json:"slow,omitempty"`
+	Violations      []jsonViolation `json:"violations"`
+}
+
+type jsonViolation struct {
+	Message   string `json:"message"`
+	Status    string `json:"status"`
+	Directive string `json:"directive,omitempty"`
+	File      string `json:"file,omitempty"`
+	Line      int    `json:"line,omitempty"`
+	Commit    string `json:"commit,omitempty"`
+}
+
+// Write implements the Reporter.Write function.
+func (JSON) Write(w io.Writer, r *Report) error {
+	doc := jsonReport{Version: JSONVersion, Outcome: r.Outcome, Checks: []jsonCheck{}}
+	for _, c := range r.Checks {
+		check := jsonCheck{
+			Policy:          c.Policy,
+			Check:           c.Name,
+			Severity:        string(c.Severity),
+			Status:          c.Status,
+			Message:         c.Message,
+			DurationSeconds: c.Duration.Seconds(),
+			Slow:            c.Slow,
+			Violations:      []jsonViolation{},
+		}
+		for _, v := range c.Violations {
+			check.Violations = append(check.Violations, jsonViolation{
+				Message:   v.Message,
+				Status:    v.Status,
+				Directive: v.Directive,
+				File:      v.File,
+				Line:      v.Line,
+				Commit:    v.Commit,
+			})
+		}
+		doc.Checks = append(doc.Checks, check)
+	}
+
+	s := r.Summary()
+	doc.Summary = jsonSummary{
+		Checks:          s.Checks,
+		Passed:          s.Passed,
+		Failed:          s.Failed,
+		Warned:          s.Warned,
+		Info:            s.Info,
+		Skipped:         s.Skipped,
+		Violations:      s.Violations(),
+		Policies:        []jsonPolicySummary{},
+		DurationSeconds: s.Duration.Seconds(),
+	}
+	for _, p := range s.Policies {
+		doc.Summary.Policies = append(doc.Summary.Policies, jsonPolicySummary{Policy: p.Policy, Violations: p.Violations})
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+
+	return e.Encode(doc)
+}
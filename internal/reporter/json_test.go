@@ -0,0 +1,92 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestJSON(t *testing.T) {
+	r := &Report{Outcome: "failure", Duration: 1250 * time.Millisecond}
+	r.Add(&Check{Policy: "commit", Name: "DCO", Severity: policy.SeverityError, Message: "Commit has a DCO", Duration: 1500 * time.Millisecond, Slow: true})
+	r.Add(&Check{
+		Policy:   "license",
+		Name:     "File Header",
+		Severity: policy.SeverityError,
+		Message:  "Found 1 files without license header",
+		Violations: []*Violation{
+			{Message: "File main.go does not contain a license header", Status: StatusFailed},
+			{Message: "File gen.go does not contain a license header", Status: StatusSuppressed, Directive: "conform:ignore"},
+		},
+	})
+
+	var buf bytes.Buffer
+	if err := (JSON{}).Write(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  "version": 1,
+  "outcome": "failure",
+  "checks": [
+    {
+      "policy": "commit",
+      "check": "DCO",
+      "severity": "error",
+      "status": "PASS",
+      "message": "Commit has a DCO",
+      "durationSeconds": 1.5,
+      "slow": true,
+      "violations": []
+    },
+    {
+      "policy": "license",
+      "check": "File Header",
+      "severity": "error",
+      "status": "FAILED",
+      "message": "Found 1 files without license header",
+      "durationSeconds": 0,
+      "violations": [
+        {
+          "message": "File main.go does not contain a license header",
+          "status": "FAILED"
+        },
+        {
+          "message": "File gen.go does not contain a license header",
+          "status": "SUPPRESSED",
+          "directive": "conform:ignore"
+        }
+      ]
+    }
+  ],
+  "summary": {
+    "checks": 2,
+    "passed": 1,
+    "failed": 1,
+    "warned": 0,
+    "info": 0,
+    "skipped": 0,
+    "violations": 1,
+    "policies": [
+      {
+        "policy": "commit",
+        "violations": 0
+      },
+      {
+        "policy": "license",
+        "violations": 1
+      }
+    ],
+    "durationSeconds": 1.25
+  }
+}
+`
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
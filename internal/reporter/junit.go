@@ -0,0 +1,179 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// JUnit writes reports as JUnit XML, as read by the test report views of CI
+// systems. Each policy is a test suite, and each check a test case, or a test
+// case for each of its violations when it has any. Failed violations are
+// failures, warnings and info pass with their message as output, and skipped,
+// suppressed, and baselined checks and violations are skipped. The severity
+// of each check is a property of its test cases.
+type JUnit struct{}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr,omitempty"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr,omitempty"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name       string           `xml:"name,attr"`
+	ClassName  string           `xml:"classname,attr"`
+	File       string           `xml:"file,attr,omitempty"`
+	Line       int              `xml:"line,attr,omitempty"`
+	Time       string           `xml:"time,attr,omitempty"`
+	Properties *junitProperties `xml:"properties"`
+	Failure    *junitMessage    `xml:"failure"`
+	Skipped    *junitMessage    `xml:"skipped"`
+	SystemOut  string           `xml:"system-out,omitempty"`
+}
+
+type junitProperties struct {
+	Properties []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// Write implements the Reporter.Write function.
+func (JUnit) Write(w io.Writer, r *Report) error {
+	doc := junitTestSuites{Name: "conform", Time: junitTime(r.Duration)}
+	durations := map[string]time.Duration{}
+	for _, p := range r.Policies {
+		durations[p.Name] += p.Duration
+	}
+	suites := map[string]int{}
+	for _, c := range r.Checks {
+		index, ok := suites[c.Policy]
+		if !ok {
+			index = len(doc.Suites)
+			suites[c.Policy] = index
+			doc.Suites = append(doc.Suites, junitTestSuite{Name: c.Policy, Time: junitTime(durations[c.Policy])})
+		}
+		suite := &doc.Suites[index]
+		for _, tc := range junitTestCases(c) {
+			suite.Tests++
+			if tc.Failure != nil {
+				suite.Failures++
+			}
+			if tc.Skipped != nil {
+				suite.Skipped++
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+	}
+	for _, suite := range doc.Suites {
+		doc.Tests += suite.Tests
+		doc.Failures += suite.Failures
+		doc.Skipped += suite.Skipped
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+// junitTime returns the time attribute of the duration, in seconds, or
+// empty if it is not known.
+func junitTime(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
+
+// junitTestCases returns the test cases of the check: a test case for each of
+// its violations, named after the file or commit it is in, or a single test
+// case if it has none. The time taken by the check is that of its first test
+// case, so that the times of the test cases add up to those of the suites.
+func junitTestCases(c *Check) []junitTestCase {
+	var properties *junitProperties
+	if c.Severity != "" {
+		properties = &junitProperties{Properties: []junitProperty{{Name: "severity", Value: string(c.Severity)}}}
+	}
+	if len(c.Violations) == 0 {
+		tc := junitTestCase{Name: c.Name, ClassName: c.Policy, Time: junitTime(c.Duration), Properties: properties}
+		if c.Status == StatusSkipped {
+			tc.Skipped = &junitMessage{Message: c.Message}
+		}
+		return []junitTestCase{tc}
+	}
+
+	cases := make([]junitTestCase, 0, len(c.Violations))
+	for _, v := range c.Violations {
+		tc := junitTestCase{Name: c.Name, ClassName: c.Policy, File: v.File, Line: v.Line, Properties: properties}
+		switch {
+		case v.File != "" && v.Line != 0:
+			tc.Name = fmt.Sprintf("%s (%s:%d)", c.Name, v.File, v.Line)
+		case v.File != "":
+			tc.Name = fmt.Sprintf("%s (%s)", c.Name, v.File)
+		case v.Commit != "":
+			tc.Name = fmt.Sprintf("%s (%s)", c.Name, shortSHA(v.Commit))
+		}
+		switch v.Status {
+		case StatusFailed:
+			tc.Failure = &junitMessage{Message: v.Message, Type: string(c.Severity), Text: v.Message}
+		case StatusSuppressed:
+			tc.Skipped = &junitMessage{Message: fmt.Sprintf("Suppressed by %s: %s", v.Directive, v.Message)}
+		case StatusBaseline:
+			tc.Skipped = &junitMessage{Message: "In the baseline: " + v.Message}
+		default:
+			tc.SystemOut = v.Status + ": " + v.Message
+		}
+		if len(cases) == 0 {
+			tc.Time = junitTime(c.Duration)
+		}
+		cases = append(cases, tc)
+	}
+
+	return cases
+}
+
+// shortSHA returns the abbreviated SHA of a commit.
+func shortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+
+	return sha
+}
@@ -0,0 +1,78 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestJUnit(t *testing.T) {
+	r := &Report{Outcome: "failure", Duration: 1500 * time.Millisecond}
+	r.Policies = []*Policy{{Name: "commit", Type: "commit", Duration: 250 * time.Millisecond}}
+	r.Add(&Check{Policy: "commit", Name: "DCO", Severity: policy.SeverityError, Message: "Commit has a DCO", Duration: 50 * time.Millisecond})
+	r.Add(&Check{
+		Policy:     "commit",
+		Name:       "Header Length",
+		Severity:   policy.SeverityWarn,
+		Violations: []*Violation{{Message: "Commit header is 92 characters", Status: StatusWarning, Commit: "0123456789abcdef0123456789abcdef01234567"}},
+	})
+	r.Add(&Check{
+		Policy:   "license",
+		Name:     "File Header",
+		Severity: policy.SeverityError,
+		Duration: 1200 * time.Millisecond,
+		Violations: []*Violation{
+			{Message: "File main.go does not contain a license header", Status: StatusFailed, File: "main.go", Line: 1},
+			{Message: "File gen.go does not contain a license header", Status: StatusSuppressed, Directive: "conform:ignore", File: "gen.go"},
+		},
+	})
+	r.Add(&Check{Policy: "license", Name: "Notice", Status: StatusSkipped, Message: "Skipped after a failure"})
+
+	var buf bytes.Buffer
+	if err := (JUnit{}).Write(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="conform" tests="5" failures="1" skipped="2" time="1.500">
+  <testsuite name="commit" tests="2" failures="0" skipped="0" time="0.250">
+    <testcase name="DCO" classname="commit" time="0.050">
+      <properties>
+        <property name="severity" value="error"></property>
+      </properties>
+    </testcase>
+    <testcase name="Header Length (0123456789ab)" classname="commit">
+      <properties>
+        <property name="severity" value="warn"></property>
+      </properties>
+      <system-out>WARNING: Commit header is 92 characters</system-out>
+    </testcase>
+  </testsuite>
+  <testsuite name="license" tests="3" failures="1" skipped="2">
+    <testcase name="File Header (main.go:1)" classname="license" file="main.go" line="1" time="1.200">
+      <properties>
+        <property name="severity" value="error"></property>
+      </properties>
+      <failure message="File main.go does not contain a license header" type="error">File main.go does not contain a license header</failure>
+    </testcase>
+    <testcase name="File Header (gen.go)" classname="license" file="gen.go">
+      <properties>
+        <property name="severity" value="error"></property>
+      </properties>
+      <skipped message="Suppressed by conform:ignore: File gen.go does not contain a license header"></skipped>
+    </testcase>
+    <testcase name="Notice" classname="license">
+      <skipped message="Skipped after a failure"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
@@ -0,0 +1,139 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Markdown writes reports as a Markdown summary, as shown by GitHub Actions
+// for each step of a job. The summary counts the checks by status, along with
+// the time taken, and the checks slower than the slow threshold, and is
+// followed by a table of the results, grouped by policy, with the severity of
+// each check and a row per group of identical violations, whose locations are
+// listed in an expandable section, or per check if it has none.
+type Markdown struct{}
+
+// markdownStatuses are the statuses counted by the summary, in order.
+var markdownStatuses = []string{StatusFailed, StatusWarning, StatusInfo, StatusPass, StatusSkipped}
+
+// markdownCounted are the words following the counts of the statuses.
+var markdownCounted = map[string]string{
+	StatusFailed:  "failed",
+	StatusWarning: "warned",
+	StatusInfo:    "info",
+	StatusPass:    "passed",
+	StatusSkipped: "skipped",
+}
+
+// Write implements the Reporter.Write function.
+func (Markdown) Write(w io.Writer, r *Report) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "### Conform: %s\n\n", r.Outcome)
+
+	counts := map[string]int{}
+	for _, c := range r.Checks {
+		counts[c.Status]++
+	}
+	var summary []string
+	for _, status := range markdownStatuses {
+		if counts[status] != 0 {
+			summary = append(summary, fmt.Sprintf("%s %d %s", markdownIcon(status), counts[status], markdownCounted[status]))
+		}
+	}
+	if len(summary) == 0 {
+		b.WriteString("No checks were enforced.\n")
+		return b.Flush()
+	}
+	b.WriteString(strings.Join(summary, " · "))
+	if r.Duration != 0 {
+		fmt.Fprintf(b, " in %s", r.Duration.Round(time.Millisecond))
+	}
+	b.WriteString("\n\n")
+	for _, c := range r.Checks {
+		if c.Slow {
+			fmt.Fprintf(b, "⏱️ %s\n\n", escapeMarkdown(slowMessage(r, c)))
+		}
+	}
+
+	b.WriteString("| Policy | Check | Severity | Status | Message |\n")
+	b.WriteString("| ------ | ----- | -------- | ------ | ------- |\n")
+	last := ""
+	for _, c := range r.Checks {
+		name := c.Policy
+		if name == last {
+			name = ""
+		}
+		last = c.Policy
+		if len(c.Violations) == 0 {
+			markdownRow(b, name, c, c.Status, c.Message, "")
+			continue
+		}
+		groups, omitted := Capped(GroupViolations(c.Violations), r.MaxIssues)
+		for _, g := range groups {
+			message, details := g.Title(), ""
+			if locations := g.Locations(); len(g.Violations) > 1 && len(locations) != 0 {
+				details = markdownDetails(len(g.Violations), locations)
+			} else if len(g.Violations) > 1 {
+				message = g.Text(0)
+			}
+			markdownRow(b, name, c, g.Status, message, details)
+			name = ""
+		}
+		if omitted != 0 {
+			markdownRow(b, name, c, c.Status, fmt.Sprintf("... and %d more violations", omitted), "")
+		}
+	}
+
+	return b.Flush()
+}
+
+// markdownRow writes a row of the table, followed in the message cell by the
+// HTML of the details, if any.
+func markdownRow(b *bufio.Writer, policy string, c *Check, status, message, details string) {
+	cells := []string{policy, c.Name, string(c.Severity), strings.TrimSpace(markdownIcon(status) + " " + status), message}
+	for i, cell := range cells {
+		cells[i] = escapeMarkdown(cell)
+	}
+	fmt.Fprintf(b, "| %s%s |\n", strings.Join(cells, " | "), details)
+}
+
+// markdownDetails returns the HTML of the expandable list of the locations of
+// a group of identical violations.
+func markdownDetails(count int, locations []string) string {
+	for i, location := range locations {
+		locations[i] = "<code>" + escapeMarkdown(location) + "</code>"
+	}
+
+	return fmt.Sprintf("<details><summary>%d times</summary>%s</details>", count, strings.Join(locations, "<br>"))
+}
+
+// markdownIcon returns the icon of the status, if it has one.
+func markdownIcon(status string) string {
+	switch status {
+	case StatusPass:
+		return "✅"
+	case StatusFailed:
+		return "❌"
+	case StatusWarning:
+		return "⚠️"
+	case StatusInfo:
+		return "ℹ️"
+	case StatusSkipped:
+		return "⏭️"
+	default:
+		return ""
+	}
+}
+
+// escapeMarkdown escapes the text of a cell of a table, which must be on a
+// single line and must not contain the separators of the cells.
+func escapeMarkdown(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "|", "\\|", "\r\n", "<br>", "\n", "<br>", "<", "&lt;", ">", "&gt;").Replace(s)
+}
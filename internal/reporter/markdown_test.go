@@ -0,0 +1,83 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestMarkdown(t *testing.T) {
+	r := &Report{Outcome: "failure", Duration: 2 * time.Second, SlowThreshold: time.Second}
+	r.Add(&Check{Policy: "commit", Name: "DCO", Severity: policy.SeverityError, Message: "Commit has a DCO", Duration: 1200 * time.Millisecond, Slow: true})
+	r.Add(&Check{
+		Policy:     "commit",
+		Name:       "Header Length",
+		Severity:   policy.SeverityWarn,
+		Violations: []*Violation{{Message: "Commit header is 92 characters", Status: StatusWarning}},
+	})
+	r.Add(&Check{
+		Policy:   "license",
+		Name:     "File Header",
+		Severity: policy.SeverityError,
+		Violations: []*Violation{
+			{Message: "File a|b.go does not contain a license header", Status: StatusFailed},
+			{Message: "File gen.go does not contain a license header", Status: StatusSuppressed, Directive: "conform:ignore"},
+		},
+	})
+	r.Add(&Check{Policy: "license", Name: "Notice", Status: StatusSkipped, Message: "<none>"})
+
+	var buf bytes.Buffer
+	if err := (Markdown{}).Write(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	expected := "### Conform: failure\n" +
+		"\n" +
+		"❌ 1 failed · ⚠️ 1 warned · ✅ 1 passed · ⏭️ 1 skipped in 2s\n" +
+		"\n" +
+		"⏱️ commit: DCO took 1.2s, over the slow threshold of 1s\n" +
+		"\n" +
+		"| Policy | Check | Severity | Status | Message |\n" +
+		"| ------ | ----- | -------- | ------ | ------- |\n" +
+		"| commit | DCO | error | ✅ PASS | Commit has a DCO |\n" +
+		"|  | Header Length | warn | ⚠️ WARNING | Commit header is 92 characters |\n" +
+		"| license | File Header | error | ❌ FAILED | File a\\|b.go does not contain a license header |\n" +
+		"|  | File Header | error | SUPPRESSED | File gen.go does not contain a license header (conform:ignore) |\n" +
+		"|  | Notice |  | ⏭️ SKIPPED | &lt;none&gt; |\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestMarkdownGroups(t *testing.T) {
+	r := &Report{Outcome: "failure", MaxIssues: 2}
+	r.Add(&Check{
+		Policy:   "license",
+		Name:     "File Header",
+		Severity: policy.SeverityError,
+		Violations: []*Violation{
+			{Message: "File a.go does not contain a license header", Status: StatusFailed, File: "a.go"},
+			{Message: "File b.go does not contain a license header", Status: StatusFailed, File: "b.go"},
+			{Message: "Repository has no LICENSE", Status: StatusFailed},
+			{Message: "Repository has no NOTICE", Status: StatusFailed},
+			{Message: "Repository has no COPYING", Status: StatusFailed},
+		},
+	})
+
+	var buf bytes.Buffer
+	if err := (Markdown{}).Write(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	expected := "| license | File Header | error | ❌ FAILED | File &lt;file&gt; does not contain a license header<details><summary>2 times</summary><code>a.go</code><br><code>b.go</code></details> |\n" +
+		"|  | File Header | error | ❌ FAILED | Repository has no LICENSE |\n" +
+		"|  | File Header | error | ❌ FAILED | ... and 2 more violations |\n"
+	if !strings.HasSuffix(buf.String(), expected) {
+		t.Errorf("Expected the table to end with:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
@@ -0,0 +1,188 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The formats of the notifications of chat services.
+const (
+	NotifySlack = "slack"
+	NotifyTeams = "teams"
+)
+
+// notifyTopViolations is the number of violations listed by notifications.
+const notifyTopViolations = 5
+
+// NotifyFormat detects the format of the notifications of the webhook from
+// its host, if it is that of Slack or Microsoft Teams.
+func NotifyFormat(webhook string) (string, bool) {
+	u, err := url.Parse(webhook)
+	if err != nil {
+		return "", false
+	}
+	host := u.Hostname()
+	switch {
+	case host == "hooks.slack.com":
+		return NotifySlack, true
+	case strings.HasSuffix(host, ".webhook.office.com"), host == "outlook.office.com", strings.HasSuffix(host, ".logic.azure.com"):
+		return NotifyTeams, true
+	default:
+		return "", false
+	}
+}
+
+// Notify sends a notification of the outcome of the enforcement of the
+// branch to the Slack or Microsoft Teams incoming webhook at the URL: the
+// counts of the checks by status, and the first failed violations.
+func Notify(client *http.Client, format, webhook, branch string, r *Report) error {
+	var payload interface{}
+	switch format {
+	case NotifySlack:
+		payload = slackPayload(branch, r)
+	case NotifyTeams:
+		payload = teamsPayload(branch, r)
+	default:
+		return errors.Errorf("Unknown notification format %q: must be %s or %s", format, NotifySlack, NotifyTeams)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return post(client, webhook, "", body)
+}
+
+// notifyTitle returns the title of the notification.
+func notifyTitle(branch string, r *Report) string {
+	title := "Conform: " + r.Outcome
+	if branch != "" {
+		title += " on " + branch
+	}
+
+	return title
+}
+
+// notifyCounts returns the counts of the checks by status, e.g. 2 failed, 5
+// passed.
+func notifyCounts(r *Report) string {
+	s := r.Summary()
+	var counts []string
+	for _, count := range []struct {
+		n    int
+		word string
+	}{
+		{s.Failed, "failed"},
+		{s.Warned, "warned"},
+		{s.Passed, "passed"},
+		{s.Skipped, "skipped"},
+	} {
+		if count.n != 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", count.n, count.word))
+		}
+	}
+
+	return strings.Join(counts, ", ")
+}
+
+// notifyViolations returns the first failed violations, formatted by line,
+// followed by the number of the others, if any.
+func notifyViolations(r *Report, line func(c *Check, v *Violation) string) []string {
+	var lines []string
+	more := 0
+	for _, c := range r.Checks {
+		for _, v := range c.Violations {
+			if v.Status != StatusFailed {
+				continue
+			}
+			if len(lines) == notifyTopViolations {
+				more++
+				continue
+			}
+			lines = append(lines, line(c, v))
+		}
+	}
+	if more != 0 {
+		lines = append(lines, fmt.Sprintf("and %d more", more))
+	}
+
+	return lines
+}
+
+// notifyLocation returns the location of the violation, if it has one.
+func notifyLocation(v *Violation) string {
+	switch {
+	case v.File != "" && v.Line != 0:
+		return fmt.Sprintf("%s:%d", v.File, v.Line)
+	case v.File != "":
+		return v.File
+	case v.Commit != "":
+		return shortSHA(v.Commit)
+	default:
+		return ""
+	}
+}
+
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// slackPayload returns the message of an incoming webhook of Slack, in its
+// mrkdwn format.
+func slackPayload(branch string, r *Report) slackMessage {
+	escape := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace
+	lines := []string{"*" + escape(notifyTitle(branch, r)) + "*", escape(notifyCounts(r))}
+	lines = append(lines, notifyViolations(r, func(c *Check, v *Violation) string {
+		line := fmt.Sprintf("• *%s: %s*: %s", escape(c.Policy), escape(c.Name), escape(v.Message))
+		if loc := notifyLocation(v); loc != "" {
+			line += " (`" + escape(loc) + "`)"
+		}
+		return line
+	})...)
+
+	return slackMessage{Text: strings.Join(lines, "\n")}
+}
+
+type teamsMessageCard struct {
+	Type       string `json:"@type"`
+	Context    string `json:"@context"`
+	Summary    string `json:"summary"`
+	ThemeColor string `json:"themeColor"`
+	Title      string `json:"title"`
+	Text       string `json:"text"`
+}
+
+// teamsPayload returns the message card of an incoming webhook of Microsoft
+// Teams, whose text is Markdown.
+func teamsPayload(branch string, r *Report) teamsMessageCard {
+	color := "22863A"
+	if r.Outcome != "pass" {
+		color = "CB2431"
+	}
+	lines := []string{escapeMarkdown(notifyCounts(r))}
+	lines = append(lines, notifyViolations(r, func(c *Check, v *Violation) string {
+		line := fmt.Sprintf("- **%s: %s**: %s", escapeMarkdown(c.Policy), escapeMarkdown(c.Name), escapeMarkdown(v.Message))
+		if loc := notifyLocation(v); loc != "" {
+			line += " (`" + loc + "`)"
+		}
+		return line
+	})...)
+
+	return teamsMessageCard{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		Summary:    notifyTitle(branch, r),
+		ThemeColor: color,
+		Title:      notifyTitle(branch, r),
+		Text:       strings.Join(lines, "\n\n"),
+	}
+}
@@ -0,0 +1,109 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestNotifyFormat(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		URL      string
+		Expected string
+	}{
+		{"Slack", "https://hooks.slack.com/services/T000/B000/XXXX", NotifySlack},
+		{"Teams", "https://example.webhook.office.com/webhookb2/XXXX", NotifyTeams},
+		{"TeamsWorkflow", "https://prod-00.westus.logic.azure.com/workflows/XXXX", NotifyTeams},
+		{"Unknown", "https://chat.example.com/hooks/XXXX", ""},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			format, ok := NotifyFormat(test.URL)
+			if format != test.Expected || ok != (test.Expected != "") {
+				tt.Errorf("Expected format %q, got %q", test.Expected, format)
+			}
+		})
+	}
+}
+
+func testNotifyReport() *Report {
+	r := &Report{Outcome: "failure"}
+	r.Add(&Check{Policy: "commit", Name: "DCO", Severity: policy.SeverityError})
+	var violations []*Violation
+	for i := 1; i <= notifyTopViolations+2; i++ {
+		violations = append(violations, &Violation{Message: fmt.Sprintf("File <%d>.go does not contain a license header", i), Status: StatusFailed, File: fmt.Sprintf("%d.go", i), Line: 1})
+	}
+	violations = append(violations, &Violation{Message: "File gen.go does not contain a license header", Status: StatusSuppressed, File: "gen.go"})
+	r.Add(&Check{Policy: "license", Name: "File Header", Severity: policy.SeverityError, Violations: violations})
+
+	return r
+}
+
+func TestNotify(t *testing.T) {
+	for _, test := range []struct {
+		Format   string
+		Expected map[string]interface{}
+	}{
+		{
+			Format: NotifySlack,
+			Expected: map[string]interface{}{
+				"text": "*Conform: failure on main*\n" +
+					"1 failed, 1 passed\n" +
+					"• *license: File Header*: File &lt;1&gt;.go does not contain a license header (`1.go:1`)\n" +
+					"• *license: File Header*: File &lt;2&gt;.go does not contain a license header (`2.go:1`)\n" +
+					"• *license: File Header*: File &lt;3&gt;.go does not contain a license header (`3.go:1`)\n" +
+					"• *license: File Header*: File &lt;4&gt;.go does not contain a license header (`4.go:1`)\n" +
+					"• *license: File Header*: File &lt;5&gt;.go does not contain a license header (`5.go:1`)\n" +
+					"and 2 more",
+			},
+		},
+		{
+			Format: NotifyTeams,
+			Expected: map[string]interface{}{
+				"@type":      "MessageCard",
+				"@context":   "https://schema.org/extensions",
+				"summary":    "Conform: failure on main",
+				"themeColor": "CB2431",
+				"title":      "Conform: failure on main",
+				"text": "1 failed, 1 passed\n\n" +
+					"- **license: File Header**: File &lt;1&gt;.go does not contain a license header (`1.go:1`)\n\n" +
+					"- **license: File Header**: File &lt;2&gt;.go does not contain a license header (`2.go:1`)\n\n" +
+					"- **license: File Header**: File &lt;3&gt;.go does not contain a license header (`3.go:1`)\n\n" +
+					"- **license: File Header**: File &lt;4&gt;.go does not contain a license header (`4.go:1`)\n\n" +
+					"- **license: File Header**: File &lt;5&gt;.go does not contain a license header (`5.go:1`)\n\n" +
+					"and 2 more",
+			},
+		},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Format, func(tt *testing.T) {
+			var payload map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+					tt.Error(err)
+				}
+			}))
+			defer server.Close()
+
+			if err := Notify(server.Client(), test.Format, server.URL, "main", testNotifyReport()); err != nil {
+				tt.Fatal(err)
+			}
+			for key, expected := range test.Expected {
+				if payload[key] != expected {
+					tt.Errorf("Expected %s:\n%v\ngot:\n%v", key, expected, payload[key])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenMetrics writes reports as metrics in the OpenMetrics text format, as
+// collected by Prometheus and pushed to a Pushgateway: the outcome and
+// duration of the enforcement, the number of checks by status, the duration
+// of each policy and of each check run, and the number of violations by
+// policy type, check, severity, and status. Every metric is a gauge, since
+// each enforcement is a separate run.
+type OpenMetrics struct{}
+
+// now returns the current time, and is replaced by tests.
+var now = time.Now
+
+// metricStatuses are the statuses of the checks, in order.
+var metricStatuses = []string{StatusPass, StatusFailed, StatusWarning, StatusInfo, StatusSkipped}
+
+type violationKey struct {
+	policyType string
+	check      string
+	severity   string
+	status     string
+}
+
+// Write implements the Reporter.Write function.
+func (OpenMetrics) Write(w io.Writer, r *Report) error {
+	b := bufio.NewWriter(w)
+
+	success := 0
+	if r.Outcome == "pass" {
+		success = 1
+	}
+	metricFamily(b, "conform_success", "Whether the enforcement passed.")
+	fmt.Fprintf(b, "conform_success %d\n", success)
+	metricFamily(b, "conform_duration_seconds", "The time taken by the enforcement.")
+	fmt.Fprintf(b, "conform_duration_seconds %s\n", seconds(r.Duration))
+	metricFamily(b, "conform_last_run_timestamp_seconds", "The time the enforcement finished at.")
+	fmt.Fprintf(b, "conform_last_run_timestamp_seconds %d\n", now().Unix())
+
+	checks := map[string]int{}
+	violations := map[violationKey]int{}
+	for _, c := range r.Checks {
+		checks[c.Status]++
+		for _, v := range c.Violations {
+			violations[violationKey{policyType: PolicyType(c), check: c.Name, severity: string(c.Severity), status: v.Status}]++
+		}
+	}
+	metricFamily(b, "conform_checks", "The number of checks enforced, by status.")
+	for _, status := range metricStatuses {
+		fmt.Fprintf(b, "conform_checks{status=%s} %d\n", labelValue(strings.ToLower(status)), checks[status])
+	}
+
+	metricFamily(b, "conform_policy_duration_seconds", "The time taken to enforce each policy.")
+	for _, p := range r.Policies {
+		fmt.Fprintf(b, "conform_policy_duration_seconds{policy=%s,type=%s} %s\n", labelValue(p.Name), labelValue(p.Type), seconds(p.Duration))
+	}
+
+	metricFamily(b, "conform_check_duration_seconds", "The time taken by each check.")
+	for _, c := range r.Checks {
+		if c.Status == StatusSkipped {
+			continue
+		}
+		fmt.Fprintf(b, "conform_check_duration_seconds{policy=%s,type=%s,check=%s,severity=%s} %s\n", labelValue(c.Policy), labelValue(PolicyType(c)), labelValue(c.Name), labelValue(string(c.Severity)), seconds(c.Duration))
+	}
+
+	keys := make([]violationKey, 0, len(violations))
+	for key := range violations {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].policyType != keys[j].policyType {
+			return keys[i].policyType < keys[j].policyType
+		}
+		if keys[i].check != keys[j].check {
+			return keys[i].check < keys[j].check
+		}
+		if keys[i].severity != keys[j].severity {
+			return keys[i].severity < keys[j].severity
+		}
+		return keys[i].status < keys[j].status
+	})
+	metricFamily(b, "conform_violations", "The number of violations, by policy type, check, severity, and status.")
+	for _, key := range keys {
+		fmt.Fprintf(b, "conform_violations{type=%s,check=%s,severity=%s,status=%s} %d\n", labelValue(key.policyType), labelValue(key.check), labelValue(key.severity), labelValue(strings.ToLower(key.status)), violations[key])
+	}
+
+	b.WriteString("# EOF\n")
+
+	return b.Flush()
+}
+
+// metricFamily writes the metadata of a family of gauges.
+func metricFamily(b *bufio.Writer, name, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+// seconds formats the duration in seconds.
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
+
+// labelValue quotes the value of a label.
+func labelValue(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
@@ -0,0 +1,120 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func testMetricsReport() *Report {
+	r := &Report{
+		Outcome:  "failure",
+		Duration: 1500 * time.Millisecond,
+		Policies: []*Policy{
+			{Name: "commit", Type: "commit", Duration: 250 * time.Millisecond},
+			{Name: "docs:license", Type: "license", Duration: time.Second},
+		},
+	}
+	r.Add(&Check{Policy: "commit", Name: "DCO", Severity: policy.SeverityError, Duration: 200 * time.Millisecond})
+	r.Add(&Check{
+		Policy:   "docs:license",
+		Name:     "File Header",
+		Severity: policy.SeverityError,
+		Duration: 900 * time.Millisecond,
+		Violations: []*Violation{
+			{Message: "File main.go does not contain a license header", Status: StatusFailed},
+			{Message: "File doc.go does not contain a license header", Status: StatusFailed},
+			{Message: "File gen.go does not contain a license header", Status: StatusSuppressed},
+		},
+	})
+
+	return r
+}
+
+func TestOpenMetrics(t *testing.T) {
+	defer func() { now = time.Now }()
+	now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	var buf bytes.Buffer
+	if err := (OpenMetrics{}).Write(&buf, testMetricsReport()); err != nil {
+		t.Fatal(err)
+	}
+	expected := `# HELP conform_success Whether the enforcement passed.
+# TYPE conform_success gauge
+conform_success 0
+# HELP conform_duration_seconds The time taken by the enforcement.
+# TYPE conform_duration_seconds gauge
+conform_duration_seconds 1.5
+# HELP conform_last_run_timestamp_seconds The time the enforcement finished at.
+# TYPE conform_last_run_timestamp_seconds gauge
+conform_last_run_timestamp_seconds 1700000000
+# HELP conform_checks The number of checks enforced, by status.
+# TYPE conform_checks gauge
+conform_checks{status="pass"} 1
+conform_checks{status="failed"} 1
+conform_checks{status="warning"} 0
+conform_checks{status="info"} 0
+conform_checks{status="skipped"} 0
+# HELP conform_policy_duration_seconds The time taken to enforce each policy.
+# TYPE conform_policy_duration_seconds gauge
+conform_policy_duration_seconds{policy="commit",type="commit"} 0.25
+conform_policy_duration_seconds{policy="docs:license",type="license"} 1
+# HELP conform_check_duration_seconds The time taken by each check.
+# TYPE conform_check_duration_seconds gauge
+conform_check_duration_seconds{policy="commit",type="commit",check="DCO",severity="error"} 0.2
+conform_check_duration_seconds{policy="docs:license",type="license",check="File Header",severity="error"} 0.9
+# HELP conform_violations The number of violations, by policy type, check, severity, and status.
+# TYPE conform_violations gauge
+conform_violations{type="license",check="File Header",severity="error",status="failed"} 2
+conform_violations{type="license",check="File Header",severity="error",status="suppressed"} 1
+# EOF
+`
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestPush(t *testing.T) {
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.EscapedPath()
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		body = string(b)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	labels := map[string]string{"repository": "autonomy/conform", "branch": "main"}
+	if err := Push(server.Client(), server.URL+"/", "conform", labels, testMetricsReport()); err != nil {
+		t.Fatal(err)
+	}
+	if method != http.MethodPut {
+		t.Errorf("Expected a PUT, got %s", method)
+	}
+	if expected := "/metrics/job/conform/branch/main/repository@base64/YXV0b25vbXkvY29uZm9ybQ"; path != expected {
+		t.Errorf("Expected path %s, got %s", expected, path)
+	}
+	if !bytes.Contains([]byte(body), []byte("conform_success 0\n")) {
+		t.Errorf("Expected the metrics to be pushed, got:\n%s", body)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer failing.Close()
+	if err := Push(failing.Client(), failing.URL, "conform", nil, testMetricsReport()); err == nil {
+		t.Error("Expected an error")
+	}
+}
@@ -0,0 +1,239 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// PatchFormat is the name of the patch format, which is the only one that
+// writes the fixes of a report.
+const PatchFormat = "patch"
+
+// Patch writes the fixes of reports as a unified diff, relative to the root
+// of the repository, that can be applied with git apply. A report without
+// fixes is an empty patch.
+type Patch struct{}
+
+// patchContext is the number of unchanged lines around the changes of a
+// hunk.
+const patchContext = 3
+
+// FileFix is the repair of the violations of a file whose correct contents
+// are known.
+type FileFix struct {
+	// File is the slash separated path of the file, relative to the root of
+	// the repository.
+	File string
+	// Contents are the contents of the file, and Fixed its contents
+	// repaired.
+	Contents []byte
+	Fixed    []byte
+}
+
+// Write implements the Reporter.Write function.
+func (Patch) Write(w io.Writer, r *Report) error {
+	b := bufio.NewWriter(w)
+	for _, fix := range r.Fixes {
+		if bytes.Equal(fix.Contents, fix.Fixed) {
+			continue
+		}
+		fmt.Fprintf(b, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n", fix.File, fix.File, fix.File, fix.File)
+		edits := diffLines(splitLines(fix.Contents), splitLines(fix.Fixed))
+		for _, h := range hunks(edits) {
+			writeHunk(b, edits[h.start:h.end], h.from, h.to)
+		}
+	}
+
+	return b.Flush()
+}
+
+// splitLines splits the contents into lines, each ending with its newline,
+// but the last if the contents do not end with a newline.
+func splitLines(contents []byte) []string {
+	var lines []string
+	for len(contents) != 0 {
+		i := bytes.IndexByte(contents, '\n') + 1
+		if i == 0 {
+			i = len(contents)
+		}
+		lines = append(lines, string(contents[:i]))
+		contents = contents[i:]
+	}
+
+	return lines
+}
+
+// Operations of the edits of a diff.
+const (
+	diffEqual  = ' '
+	diffDelete = '-'
+	diffInsert = '+'
+)
+
+// diffEdit is an edit of a line of a diff.
+type diffEdit struct {
+	op   byte
+	line string
+}
+
+// diffLines returns the shortest edit script from the lines of a to those of
+// b, with Myers' algorithm.
+func diffLines(a, b []string) []diffEdit {
+	n, m := len(a), len(b)
+	max := n + m
+	// v is the furthest x reached on each diagonal k, at index max+k, and
+	// trace the v of each number of edits before it.
+	v := make([]int, 2*max+2)
+	var trace [][]int
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int{}, v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+				x = v[max+k+1]
+			} else {
+				x = v[max+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[max+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, a, b)
+			}
+		}
+	}
+
+	return nil
+}
+
+// backtrack returns the edits of the path traced by diffLines.
+func backtrack(trace [][]int, a, b []string) []diffEdit {
+	max := len(a) + len(b)
+	x, y := len(a), len(b)
+	var edits []diffEdit
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		prev := k - 1
+		if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+			prev = k + 1
+		}
+		prevX := v[max+prev]
+		prevY := prevX - prev
+		for x > prevX && y > prevY {
+			edits = append(edits, diffEdit{op: diffEqual, line: a[x-1]})
+			x--
+			y--
+		}
+		if x == prevX {
+			edits = append(edits, diffEdit{op: diffInsert, line: b[y-1]})
+			y--
+		} else {
+			edits = append(edits, diffEdit{op: diffDelete, line: a[x-1]})
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		edits = append(edits, diffEdit{op: diffEqual, line: a[x-1]})
+		x--
+		y--
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+
+	return edits
+}
+
+// hunk is a range of edits, from start to end, whose first lines are the
+// 0-indexed lines from and to of the files.
+type hunk struct {
+	start, end int
+	from, to   int
+}
+
+// hunks returns the hunks of the edits: their changes with the unchanged
+// lines around them, merging the hunks whose unchanged lines overlap.
+func hunks(edits []diffEdit) []hunk {
+	var hs []hunk
+	var h *hunk
+	// last is the index of the edit after the last change.
+	from, to, last := 0, 0, 0
+	for i, e := range edits {
+		if e.op != diffEqual {
+			if h != nil && i-last > 2*patchContext {
+				h.end = last + patchContext
+				hs = append(hs, *h)
+				h = nil
+			}
+			if h == nil {
+				// The edits before the first change of a hunk are unchanged.
+				start := i - patchContext
+				if start < 0 {
+					start = 0
+				}
+				h = &hunk{start: start, from: from - (i - start), to: to - (i - start)}
+			}
+			last = i + 1
+		}
+		switch e.op {
+		case diffEqual:
+			from++
+			to++
+		case diffDelete:
+			from++
+		case diffInsert:
+			to++
+		}
+	}
+	if h != nil {
+		h.end = last + patchContext
+		if h.end > len(edits) {
+			h.end = len(edits)
+		}
+		hs = append(hs, *h)
+	}
+
+	return hs
+}
+
+// writeHunk writes the hunk of the edits, whose first lines are the
+// 0-indexed lines from and to of the files.
+func writeHunk(b *bufio.Writer, edits []diffEdit, from, to int) {
+	deleted, inserted := 0, 0
+	for _, e := range edits {
+		if e.op != diffInsert {
+			deleted++
+		}
+		if e.op != diffDelete {
+			inserted++
+		}
+	}
+	fmt.Fprintf(b, "@@ -%s +%s @@\n", hunkRange(from, deleted), hunkRange(to, inserted))
+	for _, e := range edits {
+		b.WriteByte(e.op)
+		b.WriteString(e.line)
+		if e.line == "" || e.line[len(e.line)-1] != '\n' {
+			b.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange returns the range of lines of a hunk, whose first line is the
+// 0-indexed line start. An empty range starts at the line before it.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
@@ -0,0 +1,109 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPatch(t *testing.T) {
+	lines := func(n int) string {
+		var b strings.Builder
+		for i := 1; i <= n; i++ {
+			b.WriteString("line " + string(rune('a'+i-1)) + "\n")
+		}
+		return b.String()
+	}
+
+	tests := []struct {
+		name     string
+		fix      *FileFix
+		expected string
+	}{
+		{
+			name: "Header",
+			fix:  &FileFix{File: "main.go", Contents: []byte("package main\n"), Fixed: []byte("// Header\n\npackage main\n")},
+			expected: `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,1 +1,3 @@
++// Header
++
+ package main
+`,
+		},
+		{
+			name: "Newline",
+			fix:  &FileFix{File: "docs/a.md", Contents: []byte(lines(5) + "end"), Fixed: []byte(lines(5) + "end\n")},
+			expected: `diff --git a/docs/a.md b/docs/a.md
+--- a/docs/a.md
++++ b/docs/a.md
+@@ -3,4 +3,4 @@
+ line c
+ line d
+ line e
+-end
+\ No newline at end of file
++end
+`,
+		},
+		{
+			name: "Newlines",
+			fix:  &FileFix{File: "a.txt", Contents: []byte("a\n\n\n"), Fixed: []byte("a\n")},
+			expected: `diff --git a/a.txt b/a.txt
+--- a/a.txt
++++ b/a.txt
+@@ -1,3 +1,1 @@
+ a
+-
+-
+`,
+		},
+		{
+			name: "Hunks",
+			fix: &FileFix{
+				File:     "b.txt",
+				Contents: []byte(lines(20)),
+				Fixed:    []byte("header\n" + strings.Replace(lines(20), "line t\n", "line T\n", 1)),
+			},
+			expected: `diff --git a/b.txt b/b.txt
+--- a/b.txt
++++ b/b.txt
+@@ -1,3 +1,4 @@
++header
+ line a
+ line b
+ line c
+@@ -17,4 +18,4 @@
+ line q
+ line r
+ line s
+-line t
++line T
+`,
+		},
+		{
+			name:     "Unchanged",
+			fix:      &FileFix{File: "c.txt", Contents: []byte("c\n"), Fixed: []byte("c\n")},
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(tt *testing.T) {
+			var buf bytes.Buffer
+			if err := (Patch{}).Write(&buf, &Report{Fixes: []*FileFix{test.fix}}); err != nil {
+				tt.Fatal(err)
+			}
+			if buf.String() != test.expected {
+				tt.Errorf("Expected:\n%s\ngot:\n%s", test.expected, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Push pushes the metrics of the report to the Pushgateway at the URL, in
+// the group of the job and the labels, replacing the metrics of the previous
+// enforcement of the group.
+func Push(client *http.Client, gateway, job string, labels map[string]string, r *Report) error {
+	var body bytes.Buffer
+	if err := (OpenMetrics{}).Write(&body, r); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, groupURL(gateway, job, labels), &body)
+	if err != nil {
+		return err
+	}
+	// The Pushgateway reads the text format, in which the # EOF marker of
+	// OpenMetrics is a comment.
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	// nolint: errcheck
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("PUT %s: %s", req.URL, resp.Status)
+	}
+
+	return nil
+}
+
+// groupURL returns the URL of the group of the job and the labels. Values
+// that cannot be path segments, such as those containing slashes, are base64
+// encoded.
+func groupURL(gateway, job string, labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	u := strings.TrimSuffix(gateway, "/") + "/metrics/" + groupSegment("job", job)
+	for _, name := range names {
+		u += "/" + groupSegment(name, labels[name])
+	}
+
+	return u
+}
+
+func groupSegment(name, value string) string {
+	if value == "" || strings.Contains(value, "/") {
+		encoded := base64.RawURLEncoding.EncodeToString([]byte(value))
+		if encoded == "" {
+			encoded = "="
+		}
+		return name + "@base64/" + encoded
+	}
+
+	return name + "/" + url.PathEscape(value)
+}
@@ -0,0 +1,193 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+// Package reporter writes the results of an enforcement in the formats read
+// by other tools, such as CI systems and code scanning services. The text
+// table written by default is not a reporter, since it is written as the
+// policies are enforced.
+package reporter
+
+import (
+	"io"
+	"sort"
+	"time"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+// Statuses of checks and violations, as written in the text table.
+const (
+	StatusPass       = "PASS"
+	StatusFailed     = "FAILED"
+	StatusWarning    = "WARNING"
+	StatusInfo       = "INFO"
+	StatusSkipped    = "SKIPPED"
+	StatusSuppressed = "SUPPRESSED"
+	StatusBaseline   = "BASELINE"
+)
+
+// Report is the results of an enforcement.
+type Report struct {
+	// Outcome is the outcome of the enforcement, e.g. pass or failure.
+	Outcome string
+	// CommitURL is the URL of the commits of the repository, to which the
+	// SHA of a commit is appended to link to it, if known.
+	CommitURL string
+	// Duration is the time taken by the enforcement.
+	Duration time.Duration
+	// SlowThreshold is the duration of the checks highlighted as slow, or 0
+	// if checks are not highlighted.
+	SlowThreshold time.Duration
+	// MaxIssues is the number of entries, each a group of identical
+	// violations, written for each check by the formats read in CI logs, or
+	// 0 if they are not capped.
+	MaxIssues int
+	// Policies are the policies enforced, in order.
+	Policies []*Policy
+	Checks   []*Check
+	// Fixes are the repairs of the violations of files, which are only
+	// known when the patch format is written.
+	Fixes []*FileFix
+}
+
+// Policy is a policy enforced.
+type Policy struct {
+	// Name is the name of the policy, prefixed by the subdirectory or
+	// repository it is declared in, if any.
+	Name string
+	// Type is the type of the policy.
+	Type string
+	// Duration is the time taken to enforce the policy.
+	Duration time.Duration
+}
+
+// Check is the result of a check of a policy.
+type Check struct {
+	// Policy is the name of the policy, prefixed by the subdirectory or
+	// repository it is declared in, if any.
+	Policy string
+	Name   string
+	// Description describes what the check enforces, if it is known.
+	Description string
+	Severity    policy.Severity
+	// Status is the most severe status of the violations of the check, or
+	// StatusPass if it has none, or StatusSkipped if it was not run.
+	Status string
+	// Message is the message summarizing the result of the check.
+	Message string
+	// Duration is the approximate time taken by the check, or 0 if it was
+	// not run.
+	Duration time.Duration
+	// Slow is true if the check took longer than the slow threshold of the
+	// report.
+	Slow       bool
+	Violations []*Violation
+}
+
+// Violation is a violation of a check.
+type Violation struct {
+	Message string
+	// Status is the status of the violation: StatusFailed, StatusWarning, or
+	// StatusInfo according to the severity of the check, or StatusSuppressed
+	// or StatusBaseline if it does not count.
+	Status string
+	// Directive is the directive that suppressed the violation, if any.
+	Directive string
+	// File is the slash separated path, relative to the root of the
+	// repository, of the file the violation is in, if any.
+	File string
+	// Line is the 1-indexed line of the violation in the file, or 0 if the
+	// violation is of the whole file.
+	Line int
+	// Commit is the SHA of the commit the violation is in, if any.
+	Commit string
+}
+
+// Add adds the check to the report, setting its status from its violations
+// unless it is skipped.
+func (r *Report) Add(c *Check) {
+	if c.Status != StatusSkipped {
+		c.Status = StatusPass
+		for _, v := range c.Violations {
+			if rank(v.Status) > rank(c.Status) {
+				c.Status = v.Status
+			}
+		}
+	}
+	r.Checks = append(r.Checks, c)
+}
+
+// Filter returns the report of the checks at least as severe as min only,
+// which are all of the checks if min is empty.
+func (r *Report) Filter(min policy.Severity) *Report {
+	if min == "" {
+		return r
+	}
+
+	filtered := *r
+	filtered.Checks = nil
+	for _, c := range r.Checks {
+		if c.Severity.AtLeast(min) {
+			filtered.Checks = append(filtered.Checks, c)
+		}
+	}
+
+	return &filtered
+}
+
+func rank(status string) int {
+	switch status {
+	case StatusFailed:
+		return 3
+	case StatusWarning:
+		return 2
+	case StatusInfo:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Reporter writes reports in a format.
+type Reporter interface {
+	Write(w io.Writer, r *Report) error
+}
+
+// Table is the name of the default format, the text table written by the
+// enforcer.
+const Table = "table"
+
+// reporters are the reporters of the formats, keyed by name.
+var reporters = map[string]Reporter{
+	"checkstyle":  Checkstyle{},
+	"codequality": CodeQuality{},
+	"github":      GitHub{},
+	"html":        HTML{},
+	"json":        JSON{},
+	"junit":       JUnit{},
+	"markdown":    Markdown{},
+	"openmetrics": OpenMetrics{},
+	PatchFormat:   Patch{},
+	"sarif":       SARIF{},
+	"tap":         TAP{},
+}
+
+// Get returns the reporter of the format of the specified name.
+func Get(name string) (Reporter, bool) {
+	r, ok := reporters[name]
+
+	return r, ok
+}
+
+// Formats returns the names of the formats, including the table format,
+// sorted.
+func Formats() []string {
+	names := []string{Table}
+	for name := range reporters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,76 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestAdd(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Check    *Check
+		Expected string
+	}{
+		{"Pass", &Check{}, StatusPass},
+		{"Failed", &Check{Violations: []*Violation{{Status: StatusWarning}, {Status: StatusFailed}}}, StatusFailed},
+		{"Warning", &Check{Violations: []*Violation{{Status: StatusInfo}, {Status: StatusWarning}}}, StatusWarning},
+		{"Suppressed", &Check{Violations: []*Violation{{Status: StatusSuppressed}, {Status: StatusBaseline}}}, StatusPass},
+		{"Skipped", &Check{Status: StatusSkipped}, StatusSkipped},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			r := &Report{}
+			r.Add(test.Check)
+			if test.Check.Status != test.Expected {
+				tt.Errorf("Expected status %s, got %s", test.Expected, test.Check.Status)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	r := &Report{Outcome: "failure"}
+	r.Add(&Check{Name: "DCO", Severity: policy.SeverityError})
+	r.Add(&Check{Name: "Header Length", Severity: policy.SeverityWarn})
+	r.Add(&Check{Name: "Body", Severity: policy.SeverityInfo})
+
+	for _, test := range []struct {
+		Name     string
+		Min      policy.Severity
+		Expected []string
+	}{
+		{"All", "", []string{"DCO", "Header Length", "Body"}},
+		{"Warn", policy.SeverityWarn, []string{"DCO", "Header Length"}},
+		{"Error", policy.SeverityError, []string{"DCO"}},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			filtered := r.Filter(test.Min)
+			var names []string
+			for _, c := range filtered.Checks {
+				names = append(names, c.Name)
+			}
+			if len(names) != len(test.Expected) {
+				tt.Fatalf("Expected checks %v, got %v", test.Expected, names)
+			}
+			for i := range names {
+				if names[i] != test.Expected[i] {
+					tt.Errorf("Expected checks %v, got %v", test.Expected, names)
+				}
+			}
+			if filtered.Outcome != r.Outcome {
+				tt.Errorf("Expected outcome %s, got %s", r.Outcome, filtered.Outcome)
+			}
+		})
+	}
+	if len(r.Checks) != 3 {
+		t.Errorf("Expected the report not to be changed, got %d checks", len(r.Checks))
+	}
+}
@@ -0,0 +1,217 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+// SARIF writes reports in the Static Analysis Results Interchange Format
+// 2.1.0, as uploaded to GitHub code scanning. Each check is a rule, and each
+// violation a result, located at its file or commit. Suppressed violations
+// are results with an in source suppression, and violations in the baseline
+// are results whose baseline state is unchanged. Checks slower than the slow
+// threshold are notifications of the invocation.
+type SARIF struct{}
+
+// sarifSchema is the schema of SARIF 2.1.0 documents.
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// sarifSourceRoot is the base of the URIs of the files of the repository,
+// which code scanning resolves to the root of the checkout.
+const sarifSourceRoot = "%SRCROOT%"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations,omitempty"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications"`
+}
+
+type sarifNotification struct {
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID        string             `json:"ruleId"`
+	RuleIndex     int                `json:"ruleIndex"`
+	Level         string             `json:"level"`
+	Message       sarifMessage       `json:"message"`
+	Locations     []sarifLocation    `json:"locations,omitempty"`
+	Suppressions  []sarifSuppression `json:"suppressions,omitempty"`
+	BaselineState string             `json:"baselineState,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+// Write implements the Reporter.Write function.
+func (SARIF) Write(w io.Writer, r *Report) error {
+	driver := sarifDriver{Name: "conform", InformationURI: "https://github.com/autonomy/conform", Rules: []sarifRule{}}
+	results := []sarifResult{}
+	var notifications []sarifNotification
+	rules := map[string]int{}
+	for _, c := range r.Checks {
+		if c.Slow {
+			notifications = append(notifications, sarifNotification{Level: "note", Message: sarifMessage{Text: slowMessage(r, c)}})
+		}
+		id := RuleID(c)
+		index, ok := rules[id]
+		if !ok {
+			index = len(driver.Rules)
+			rules[id] = index
+			description := c.Description
+			if description == "" {
+				description = c.Name
+			}
+			driver.Rules = append(driver.Rules, sarifRule{
+				ID:                   id,
+				Name:                 c.Name,
+				ShortDescription:     sarifMessage{Text: description},
+				DefaultConfiguration: sarifConfiguration{Level: sarifLevel(c.Severity)},
+			})
+		}
+		for _, v := range c.Violations {
+			result := sarifResult{
+				RuleID:    id,
+				RuleIndex: index,
+				Level:     sarifLevel(c.Severity),
+				Message:   sarifMessage{Text: v.Message},
+			}
+			if loc, ok := sarifLocate(v); ok {
+				result.Locations = []sarifLocation{loc}
+			}
+			switch v.Status {
+			case StatusSuppressed:
+				result.Suppressions = []sarifSuppression{{Kind: "inSource", Justification: v.Directive}}
+			case StatusBaseline:
+				result.BaselineState = "unchanged"
+			}
+			results = append(results, result)
+		}
+	}
+
+	run := sarifRun{Tool: sarifTool{Driver: driver}, Results: results}
+	if len(notifications) != 0 {
+		run.Invocations = []sarifInvocation{{ExecutionSuccessful: true, ToolExecutionNotifications: notifications}}
+	}
+	doc := sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+
+	return e.Encode(doc)
+}
+
+// RuleID returns the identifier of the rule of the check, the type of its
+// policy and its name, e.g. commit/header-length. The checks of the policies
+// of subdirectories and repositories share the rules of their types.
+func RuleID(c *Check) string {
+	return PolicyType(c) + "/" + strings.ToLower(strings.Join(strings.Fields(c.Name), "-"))
+}
+
+// PolicyType returns the type of the policy of the check, without the
+// subdirectory or repository it is declared in.
+func PolicyType(c *Check) string {
+	return c.Policy[strings.LastIndex(c.Policy, ":")+1:]
+}
+
+// sarifLevel returns the level of the results of the severity.
+func sarifLevel(severity policy.Severity) string {
+	switch severity {
+	case policy.SeverityWarn:
+		return "warning"
+	case policy.SeverityInfo:
+		return "note"
+	default:
+		return "error"
+	}
+}
+
+// sarifLocate returns the location of the violation, in a file of the
+// repository, or at a commit, if it has one.
+func sarifLocate(v *Violation) (sarifLocation, bool) {
+	switch {
+	case v.File != "":
+		loc := &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: v.File, URIBaseID: sarifSourceRoot}}
+		if v.Line != 0 {
+			loc.Region = &sarifRegion{StartLine: v.Line}
+		}
+		return sarifLocation{PhysicalLocation: loc}, true
+	case v.Commit != "":
+		return sarifLocation{LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: v.Commit, Kind: "commit"}}}, true
+	default:
+		return sarifLocation{}, false
+	}
+}
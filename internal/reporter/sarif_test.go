@@ -0,0 +1,134 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestSARIF(t *testing.T) {
+	r := &Report{Outcome: "failure"}
+	r.Add(&Check{
+		Policy:      "commit",
+		Name:        "Header Length",
+		Description: "The commit header does not exceed the maximum length",
+		Severity:    policy.SeverityWarn,
+		Violations:  []*Violation{{Message: "Commit header is 92 characters", Status: StatusWarning, Commit: "0123456789abcdef0123456789abcdef01234567"}},
+	})
+	r.Add(&Check{
+		Policy:   "docs:license",
+		Name:     "File Header",
+		Severity: policy.SeverityError,
+		Violations: []*Violation{
+			{Message: "File main.go does not contain a license header", Status: StatusFailed, File: "docs/main.go", Line: 1},
+			{Message: "File gen.go does not contain a license header", Status: StatusSuppressed, Directive: "conform:ignore", File: "docs/gen.go", Line: 1},
+			{Message: "File old.go does not contain a license header", Status: StatusBaseline, File: "docs/old.go"},
+		},
+	})
+	r.Add(&Check{Policy: "license", Name: "File Header", Severity: policy.SeverityError})
+
+	var buf bytes.Buffer
+	if err := (SARIF{}).Write(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	var doc sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Version != "2.1.0" || len(doc.Runs) != 1 {
+		t.Fatalf("Expected a single SARIF 2.1.0 run, got %s", buf.String())
+	}
+	run := doc.Runs[0]
+
+	rules := []sarifRule{
+		{
+			ID:                   "commit/header-length",
+			Name:                 "Header Length",
+			ShortDescription:     sarifMessage{Text: "The commit header does not exceed the maximum length"},
+			DefaultConfiguration: sarifConfiguration{Level: "warning"},
+		},
+		{
+			ID:                   "license/file-header",
+			Name:                 "File Header",
+			ShortDescription:     sarifMessage{Text: "File Header"},
+			DefaultConfiguration: sarifConfiguration{Level: "error"},
+		},
+	}
+	if !reflect.DeepEqual(run.Tool.Driver.Rules, rules) {
+		t.Errorf("Expected rules %+v, got %+v", rules, run.Tool.Driver.Rules)
+	}
+
+	file := func(uri string, line int) []sarifLocation {
+		loc := &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: uri, URIBaseID: sarifSourceRoot}}
+		if line != 0 {
+			loc.Region = &sarifRegion{StartLine: line}
+		}
+		return []sarifLocation{{PhysicalLocation: loc}}
+	}
+	results := []sarifResult{
+		{
+			RuleID:    "commit/header-length",
+			Level:     "warning",
+			Message:   sarifMessage{Text: "Commit header is 92 characters"},
+			Locations: []sarifLocation{{LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: "0123456789abcdef0123456789abcdef01234567", Kind: "commit"}}}},
+		},
+		{
+			RuleID:    "license/file-header",
+			RuleIndex: 1,
+			Level:     "error",
+			Message:   sarifMessage{Text: "File main.go does not contain a license header"},
+			Locations: file("docs/main.go", 1),
+		},
+		{
+			RuleID:       "license/file-header",
+			RuleIndex:    1,
+			Level:        "error",
+			Message:      sarifMessage{Text: "File gen.go does not contain a license header"},
+			Locations:    file("docs/gen.go", 1),
+			Suppressions: []sarifSuppression{{Kind: "inSource", Justification: "conform:ignore"}},
+		},
+		{
+			RuleID:        "license/file-header",
+			RuleIndex:     1,
+			Level:         "error",
+			Message:       sarifMessage{Text: "File old.go does not contain a license header"},
+			Locations:     file("docs/old.go", 0),
+			BaselineState: "unchanged",
+		},
+	}
+	if !reflect.DeepEqual(run.Results, results) {
+		t.Errorf("Expected results %+v, got %+v", results, run.Results)
+	}
+}
+
+func TestSARIFSlow(t *testing.T) {
+	r := &Report{Outcome: "pass", SlowThreshold: time.Second}
+	r.Add(&Check{Policy: "license", Name: "File Header", Duration: 1500 * time.Millisecond, Slow: true})
+	r.Add(&Check{Policy: "commit", Name: "DCO", Duration: 10 * time.Millisecond})
+
+	var buf bytes.Buffer
+	if err := (SARIF{}).Write(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	var doc sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	expected := []sarifInvocation{{
+		ExecutionSuccessful: true,
+		ToolExecutionNotifications: []sarifNotification{
+			{Level: "note", Message: sarifMessage{Text: "license: File Header took 1.5s, over the slow threshold of 1s"}},
+		},
+	}}
+	if !reflect.DeepEqual(doc.Runs[0].Invocations, expected) {
+		t.Errorf("Expected invocations %+v, got %+v", expected, doc.Runs[0].Invocations)
+	}
+}
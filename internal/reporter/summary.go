@@ -0,0 +1,88 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"fmt"
+	"time"
+)
+
+// Summary summarizes the results of an enforcement.
+type Summary struct {
+	// Checks is the number of checks enforced, including those skipped.
+	Checks  int
+	Passed  int
+	Failed  int
+	Warned  int
+	Info    int
+	Skipped int
+	// Policies are the number of violations of each policy, in the order
+	// the policies were enforced.
+	Policies []*PolicySummary
+	// Slow are the checks that took longer than the slow threshold, in the
+	// order they were enforced.
+	Slow []*Check
+	// Duration is the time taken by the enforcement.
+	Duration time.Duration
+}
+
+// PolicySummary is the number of violations of a policy. Suppressed
+// violations and violations in the baseline do not count.
+type PolicySummary struct {
+	Policy     string
+	Violations int
+}
+
+// Violations returns the number of violations of all the policies.
+func (s *Summary) Violations() int {
+	n := 0
+	for _, p := range s.Policies {
+		n += p.Violations
+	}
+
+	return n
+}
+
+// Summary returns the summary of the report.
+func (r *Report) Summary() *Summary {
+	s := &Summary{Checks: len(r.Checks), Duration: r.Duration}
+	policies := map[string]*PolicySummary{}
+	for _, c := range r.Checks {
+		switch c.Status {
+		case StatusPass:
+			s.Passed++
+		case StatusFailed:
+			s.Failed++
+		case StatusWarning:
+			s.Warned++
+		case StatusInfo:
+			s.Info++
+		case StatusSkipped:
+			s.Skipped++
+		}
+		if c.Slow {
+			s.Slow = append(s.Slow, c)
+		}
+		p, ok := policies[c.Policy]
+		if !ok {
+			p = &PolicySummary{Policy: c.Policy}
+			policies[c.Policy] = p
+			s.Policies = append(s.Policies, p)
+		}
+		for _, v := range c.Violations {
+			if rank(v.Status) > 0 {
+				p.Violations++
+			}
+		}
+	}
+
+	return s
+}
+
+// slowMessage returns the message highlighting the check of the report as
+// slow.
+func slowMessage(r *Report, c *Check) string {
+	return fmt.Sprintf("%s: %s took %s, over the slow threshold of %s", c.Policy, c.Name, c.Duration.Round(time.Millisecond), r.SlowThreshold)
+}
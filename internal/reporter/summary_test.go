@@ -0,0 +1,42 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSummary(t *testing.T) {
+	r := &Report{Duration: 1500 * time.Millisecond}
+	r.Add(&Check{Policy: "commit", Name: "Header Length", Violations: []*Violation{{Status: StatusFailed}, {Status: StatusFailed}}})
+	dco := &Check{Policy: "commit", Name: "DCO", Duration: 2 * time.Second, Slow: true}
+	r.Add(dco)
+	r.Add(&Check{Policy: "commit", Name: "Spelling", Violations: []*Violation{{Status: StatusWarning}, {Status: StatusSuppressed}}})
+	r.Add(&Check{Policy: "license", Name: "File Header", Violations: []*Violation{{Status: StatusBaseline}}})
+	r.Add(&Check{Policy: "license", Name: "Copyright", Status: StatusSkipped})
+
+	expected := &Summary{
+		Checks:  5,
+		Passed:  2,
+		Failed:  1,
+		Warned:  1,
+		Skipped: 1,
+		Policies: []*PolicySummary{
+			{Policy: "commit", Violations: 3},
+			{Policy: "license", Violations: 0},
+		},
+		Slow:     []*Check{dco},
+		Duration: 1500 * time.Millisecond,
+	}
+	s := r.Summary()
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("Expected summary %+v, got %+v", expected, s)
+	}
+	if s.Violations() != 3 {
+		t.Errorf("Expected 3 violations, got %d", s.Violations())
+	}
+}
@@ -0,0 +1,83 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// TAP writes reports in the Test Anything Protocol, version 13. Each check is
+// a test point, which is not ok if it failed, and is skipped if it was not
+// run. The time taken by a check, and its violations, including its warnings,
+// are written as a YAML diagnostic block below its test point.
+type TAP struct{}
+
+// Write implements the Reporter.Write function.
+func (TAP) Write(w io.Writer, r *Report) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "TAP version 13\n1..%d\n", len(r.Checks))
+	for i, c := range r.Checks {
+		result := "ok"
+		if c.Status == StatusFailed {
+			result = "not ok"
+		}
+		// Descriptions must not contain a # outside of a directive.
+		description := strings.Replace(c.Policy+": "+c.Name, "#", `\#`, -1)
+		if c.Status == StatusSkipped {
+			fmt.Fprintf(b, "%s %d - %s # SKIP %s\n", result, i+1, description, c.Message)
+			continue
+		}
+		fmt.Fprintf(b, "%s %d - %s\n", result, i+1, description)
+		if len(c.Violations) == 0 && c.Duration == 0 {
+			continue
+		}
+
+		b.WriteString("  ---\n")
+		tapField(b, "  ", "message", c.Message)
+		tapField(b, "  ", "severity", string(c.Severity))
+		tapField(b, "  ", "status", c.Status)
+		if c.Duration != 0 {
+			tapField(b, "  ", "duration_ms", strconv.FormatFloat(c.Duration.Seconds()*1000, 'f', -1, 64))
+		}
+		if c.Slow {
+			tapField(b, "  ", "slow", "true")
+		}
+		if len(c.Violations) == 0 {
+			b.WriteString("  ...\n")
+			continue
+		}
+		b.WriteString("  violations:\n")
+		for _, v := range c.Violations {
+			tapField(b, "    - ", "message", v.Message)
+			tapField(b, "      ", "status", v.Status)
+			tapField(b, "      ", "directive", v.Directive)
+			tapField(b, "      ", "file", v.File)
+			if v.Line != 0 {
+				tapField(b, "      ", "line", strconv.Itoa(v.Line))
+			}
+			tapField(b, "      ", "commit", v.Commit)
+		}
+		b.WriteString("  ...\n")
+	}
+
+	return b.Flush()
+}
+
+// tapField writes a field of a diagnostic block, if its value is not empty.
+// Diagnostics are written by hand rather than marshaled, since TAP harnesses
+// only read a subset of YAML, without folded or multi-line scalars.
+func tapField(b *bufio.Writer, indent, key, value string) {
+	if value == "" {
+		return
+	}
+	if strings.TrimSpace(value) != value || strings.ContainsAny(value, "\n\t\"'#:[]{},&*!|>%@`") {
+		value = strconv.Quote(value)
+	}
+	fmt.Fprintf(b, "%s%s: %s\n", indent, key, value)
+}
@@ -0,0 +1,64 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/autonomy/conform/internal/policy"
+)
+
+func TestTAP(t *testing.T) {
+	r := &Report{Outcome: "failure"}
+	r.Add(&Check{Policy: "commit", Name: "DCO", Severity: policy.SeverityError, Message: "Commit has a DCO", Duration: 1500 * time.Millisecond, Slow: true})
+	r.Add(&Check{
+		Policy:   "license",
+		Name:     "File Header",
+		Severity: policy.SeverityError,
+		Message:  "Found 1 files without license header",
+		Violations: []*Violation{
+			{Message: "File main.go does not contain a license header", Status: StatusFailed, File: "main.go", Line: 1},
+			{Message: "File gen.go does not contain a license header", Status: StatusSuppressed, Directive: "conform:ignore", File: "gen.go"},
+		},
+	})
+	r.Add(&Check{Policy: "license", Name: "Notice", Status: StatusSkipped, Message: "Skipped after a failure"})
+
+	var buf bytes.Buffer
+	if err := (TAP{}).Write(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	expected := `TAP version 13
+1..3
+ok 1 - commit: DCO
+  ---
+  message: Commit has a DCO
+  severity: error
+  status: PASS
+  duration_ms: 1500
+  slow: true
+  ...
+not ok 2 - license: File Header
+  ---
+  message: Found 1 files without license header
+  severity: error
+  status: FAILED
+  violations:
+    - message: File main.go does not contain a license header
+      status: FAILED
+      file: main.go
+      line: 1
+    - message: File gen.go does not contain a license header
+      status: SUPPRESSED
+      directive: "conform:ignore"
+      file: gen.go
+  ...
+ok 3 - license: Notice # SKIP Skipped after a failure
+`
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
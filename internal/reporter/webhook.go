@@ -0,0 +1,129 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// WebhookSignatureHeader is the header of the signature of the body of the
+// requests to webhooks, sha256= followed by the hex encoded HMAC-SHA256 of
+// the body keyed by the secret of the webhook.
+const WebhookSignatureHeader = "X-Conform-Signature-256"
+
+// webhookAttempts is the number of attempts of a request to a webhook.
+const webhookAttempts = 4
+
+// webhookBackoff is the delay before retrying a request to a webhook, which
+// doubles with each attempt, and is replaced by tests.
+var webhookBackoff = time.Second
+
+// ValidateWebhookURL validates the URL of a webhook, which must be HTTPS,
+// unless it is of the loopback interface, so that the report is not sent in
+// the clear.
+func ValidateWebhookURL(webhook string) error {
+	u, err := url.Parse(webhook)
+	if err != nil {
+		return errors.Errorf("Invalid webhook URL %q: %v", webhook, err)
+	}
+	switch {
+	case u.Scheme == "https" && u.Host != "":
+		return nil
+	case u.Scheme == "http" && isLoopback(u.Hostname()):
+		return nil
+	default:
+		return errors.Errorf("Invalid webhook URL %q: must be an https URL", webhook)
+	}
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
+
+// Publish POSTs the JSON document of the report to the webhook at the URL,
+// signed with the secret unless it is empty.
+func Publish(client *http.Client, webhook, secret string, r *Report) error {
+	var body bytes.Buffer
+	if err := (JSON{}).Write(&body, r); err != nil {
+		return err
+	}
+	signature := ""
+	if secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		// nolint: errcheck
+		mac.Write(body.Bytes())
+		signature = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	return post(client, webhook, signature, body.Bytes())
+}
+
+// post POSTs the JSON body to the webhook, with the signature unless it is
+// empty. Requests that fail with a network error, a 429, or a 5xx status
+// are retried with exponential backoff, waiting for the delay of the
+// Retry-After header of the response if it is longer.
+func post(client *http.Client, webhook, signature string, body []byte) error {
+	backoff := webhookBackoff
+	for attempt := 1; ; attempt++ {
+		retryAfter, err := postOnce(client, webhook, signature, body)
+		if err == nil || retryAfter < 0 || attempt == webhookAttempts {
+			return err
+		}
+		if retryAfter < backoff {
+			retryAfter = backoff
+		}
+		time.Sleep(retryAfter)
+		backoff *= 2
+	}
+}
+
+// postOnce makes a request to the webhook, and returns its error, along with
+// the delay before retrying it, or a negative delay if it must not be
+// retried.
+func postOnce(client *http.Client, webhook, signature string, body []byte) (time.Duration, error) {
+	req, err := http.NewRequest(http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "conform")
+	if signature != "" {
+		req.Header.Set(WebhookSignatureHeader, signature)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	// nolint: errcheck
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return 0, nil
+	}
+
+	err = errors.Errorf("POST %s: %s", webhook, resp.Status)
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode/100 != 5 {
+		return -1, err
+	}
+	if seconds, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second, err
+	}
+
+	return 0, err
+}
@@ -0,0 +1,101 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package reporter
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestValidateWebhookURL(t *testing.T) {
+	for _, test := range []struct {
+		Name  string
+		URL   string
+		Valid bool
+	}{
+		{"HTTPS", "https://compliance.example.com/conform", true},
+		{"Loopback", "http://127.0.0.1:8080/conform", true},
+		{"Localhost", "http://localhost/conform", true},
+		{"HTTP", "http://compliance.example.com/conform", false},
+		{"NoHost", "https:///conform", false},
+		{"Invalid", "://conform", false},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			err := ValidateWebhookURL(test.URL)
+			if test.Valid && err != nil {
+				tt.Errorf("Expected %s to be valid, got %v", test.URL, err)
+			}
+			if !test.Valid && err == nil {
+				tt.Errorf("Expected %s to be invalid", test.URL)
+			}
+		})
+	}
+}
+
+func TestPublish(t *testing.T) {
+	webhookBackoff = time.Millisecond
+	defer func() { webhookBackoff = time.Second }()
+
+	for _, test := range []struct {
+		Name     string
+		Statuses []int
+		Attempts int
+		Err      bool
+	}{
+		{"Success", []int{http.StatusOK}, 1, false},
+		{"Retried", []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusAccepted}, 3, false},
+		{"ClientError", []int{http.StatusBadRequest}, 1, true},
+		{"Exhausted", []int{http.StatusBadGateway}, webhookAttempts, true},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					tt.Error(err)
+				}
+				mac := hmac.New(sha256.New, []byte("secret"))
+				// nolint: errcheck
+				mac.Write(body)
+				if expected := "sha256=" + hex.EncodeToString(mac.Sum(nil)); r.Header.Get(WebhookSignatureHeader) != expected {
+					tt.Errorf("Expected signature %s, got %s", expected, r.Header.Get(WebhookSignatureHeader))
+				}
+				var doc jsonReport
+				if err = json.Unmarshal(body, &doc); err != nil || doc.Outcome != "failure" {
+					tt.Errorf("Expected the JSON report, got %s", body)
+				}
+				status := test.Statuses[len(test.Statuses)-1]
+				if attempts < len(test.Statuses) {
+					status = test.Statuses[attempts]
+				}
+				attempts++
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			err := Publish(server.Client(), server.URL, "secret", testMetricsReport())
+			if test.Err && err == nil {
+				tt.Error("Expected an error")
+			}
+			if !test.Err && err != nil {
+				tt.Error(err)
+			}
+			if attempts != test.Attempts {
+				tt.Errorf("Expected %d attempts, got %d", test.Attempts, attempts)
+			}
+		})
+	}
+}
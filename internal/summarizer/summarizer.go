@@ -10,7 +10,6 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"os"
 	"path"
@@ -75,7 +74,7 @@ func NewGitHubSummarizer(token string) (*GitHub, error) {
 
 	sha, err := g.SHA()
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	gh := &GitHub{
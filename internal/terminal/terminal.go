@@ -0,0 +1,118 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+// Package terminal detects terminals and colors the text written to them.
+package terminal
+
+import (
+	"os"
+	"sort"
+)
+
+// NoColorEnv is the environment variable that disables colors when set, as
+// described by https://no-color.org.
+const NoColorEnv = "NO_COLOR"
+
+// IsTerminal reports whether the file is a terminal.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// ColorEnabled reports whether the text written to the file should be
+// colored, which is when it is a terminal and NO_COLOR is not set.
+func ColorEnabled(f *os.File) bool {
+	if _, ok := os.LookupEnv(NoColorEnv); ok {
+		return false
+	}
+
+	return IsTerminal(f)
+}
+
+// Color is a Select Graphic Rendition parameter. Colors are always two
+// digits, so that colored text always has the same number of invisible
+// bytes, and tabwriter columns stay aligned when all of their cells are
+// colored.
+type Color string
+
+// Colors of the themes.
+const (
+	Plain        Color = "00"
+	Bold         Color = "01"
+	Red          Color = "31"
+	Green        Color = "32"
+	Yellow       Color = "33"
+	Cyan         Color = "36"
+	White        Color = "37"
+	Gray         Color = "90"
+	BrightRed    Color = "91"
+	BrightGreen  Color = "92"
+	BrightYellow Color = "93"
+	BrightCyan   Color = "96"
+)
+
+// Paint returns the text in the color.
+func Paint(c Color, s string) string {
+	return "\x1b[" + string(c) + "m" + s + "\x1b[0m"
+}
+
+// Theme is the colors of the parts of the output.
+type Theme struct {
+	// Header is the color of the column headers.
+	Header Color
+	// Name is the color of the names of the policies.
+	Name Color
+	// Text is the color of the other cells.
+	Text Color
+	// Pass is the color of passing checks and applied fixes.
+	Pass Color
+	// Fail is the color of failing checks and fixes.
+	Fail Color
+	// Warn is the color of warnings.
+	Warn Color
+	// Info is the color of informational results and suggestions.
+	Info Color
+	// Muted is the color of skipped, suppressed, and baselined results.
+	Muted Color
+}
+
+// DefaultTheme is the name of the default theme.
+const DefaultTheme = "light"
+
+// Themes are the themes by name. The light theme uses the standard colors,
+// and the dark theme their bright variants, which are easier to read on dark
+// backgrounds.
+var Themes = map[string]Theme{
+	"light": {
+		Header: Bold,
+		Name:   Bold,
+		Text:   Plain,
+		Pass:   Green,
+		Fail:   Red,
+		Warn:   Yellow,
+		Info:   Cyan,
+		Muted:  Gray,
+	},
+	"dark": {
+		Header: Bold,
+		Name:   Bold,
+		Text:   Plain,
+		Pass:   BrightGreen,
+		Fail:   BrightRed,
+		Warn:   BrightYellow,
+		Info:   BrightCyan,
+		Muted:  White,
+	},
+}
+
+// ThemeNames returns the names of the themes, sorted.
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
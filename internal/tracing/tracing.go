@@ -0,0 +1,343 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+// Package tracing records the spans of an enforcement, and exports them to an
+// OpenTelemetry collector with the OTLP/HTTP protocol, in its JSON encoding.
+// The exporter is configured by the standard OTEL_EXPORTER_OTLP_* environment
+// variables. A nil tracer, and the nil spans it starts, record nothing, so
+// that enforcement is instrumented whether or not tracing is configured.
+package tracing
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Environment variables configuring the exporter, as specified by
+// OpenTelemetry.
+const (
+	EndpointEnv       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	TracesEndpointEnv = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	HeadersEnv        = "OTEL_EXPORTER_OTLP_HEADERS"
+	ProtocolEnv       = "OTEL_EXPORTER_OTLP_PROTOCOL"
+	ServiceNameEnv    = "OTEL_SERVICE_NAME"
+	// TraceParentEnv is the W3C trace context of the CI job, if traced, in
+	// which the spans of the enforcement are recorded.
+	TraceParentEnv = "TRACEPARENT"
+)
+
+// exportTimeout is the timeout of exporting the spans, so that an unavailable
+// collector does not hang enforcement.
+const exportTimeout = 30 * time.Second
+
+// Tracer records spans, and exports them once the enforcement is done.
+type Tracer struct {
+	endpoint string
+	headers  map[string]string
+	service  string
+	traceID  [16]byte
+	// parentID is the span of the CI job the root spans are children of, if
+	// any.
+	parentID [8]byte
+
+	mu    sync.Mutex
+	spans []*Span
+}
+
+// FromEnv returns a tracer configured by the environment, or nil if no OTLP
+// endpoint is set. Only the http/json protocol is supported.
+func FromEnv(lookupEnv func(string) (string, bool)) (*Tracer, error) {
+	endpoint, _ := lookupEnv(TracesEndpointEnv)
+	if endpoint == "" {
+		base, _ := lookupEnv(EndpointEnv)
+		if base == "" {
+			return nil, nil
+		}
+		endpoint = strings.TrimSuffix(base, "/") + "/v1/traces"
+	}
+
+	for _, env := range []string{"OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", ProtocolEnv} {
+		if protocol, _ := lookupEnv(env); protocol != "" && protocol != "http/json" {
+			return nil, errors.Errorf("%s: unsupported protocol %q: spans are only exported over OTLP/HTTP in its JSON encoding, set it to http/json", env, protocol)
+		}
+	}
+
+	t := &Tracer{endpoint: endpoint, headers: map[string]string{}, service: "conform"}
+	if service, _ := lookupEnv(ServiceNameEnv); service != "" {
+		t.service = service
+	}
+	if headers, _ := lookupEnv(HeadersEnv); headers != "" {
+		for _, header := range strings.Split(headers, ",") {
+			kv := strings.SplitN(header, "=", 2)
+			if len(kv) != 2 {
+				return nil, errors.Errorf("%s: invalid header %q: must be key=value", HeadersEnv, header)
+			}
+			value, err := url.QueryUnescape(strings.TrimSpace(kv[1]))
+			if err != nil {
+				return nil, errors.Errorf("%s: invalid header %q: %v", HeadersEnv, header, err)
+			}
+			t.headers[strings.TrimSpace(kv[0])] = value
+		}
+	}
+	if parent, _ := lookupEnv(TraceParentEnv); parent != "" {
+		if err := t.parseTraceParent(parent); err != nil {
+			return nil, errors.Errorf("%s: %v", TraceParentEnv, err)
+		}
+	} else if _, err := rand.Read(t.traceID[:]); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// parseTraceParent reads the trace and parent span of a W3C trace context,
+// e.g. 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01.
+func (t *Tracer) parseTraceParent(s string) error {
+	parts := strings.Split(s, "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return errors.Errorf("invalid trace context %q", s)
+	}
+	if _, err := hex.Decode(t.traceID[:], []byte(parts[1])); err != nil {
+		return errors.Errorf("invalid trace context %q: %v", s, err)
+	}
+	if _, err := hex.Decode(t.parentID[:], []byte(parts[2])); err != nil {
+		return errors.Errorf("invalid trace context %q: %v", s, err)
+	}
+
+	return nil
+}
+
+// Span is an operation of the enforcement, such as the enforcement of a
+// policy.
+type Span struct {
+	tracer   *Tracer
+	id       [8]byte
+	parentID [8]byte
+	name     string
+	start    time.Time
+	end      time.Time
+	attrs    []attribute
+	err      string
+}
+
+type attribute struct {
+	key   string
+	value interface{}
+}
+
+// Start starts a root span of the specified name, with the alternating keys
+// and values of its attributes.
+func (t *Tracer) Start(name string, keyvals ...interface{}) *Span {
+	if t == nil {
+		return nil
+	}
+
+	return t.record(t.parentID, name, time.Now(), keyvals)
+}
+
+// Start starts a child span of the specified name, with the alternating keys
+// and values of its attributes.
+func (s *Span) Start(name string, keyvals ...interface{}) *Span {
+	return s.Record(name, time.Now(), time.Time{}, keyvals...)
+}
+
+// Record records a child span of the specified name that started at start,
+// and ended at end unless end is zero, for operations timed after the fact.
+func (s *Span) Record(name string, start, end time.Time, keyvals ...interface{}) *Span {
+	if s == nil {
+		return nil
+	}
+	child := s.tracer.record(s.id, name, start, keyvals)
+	child.end = end
+
+	return child
+}
+
+func (t *Tracer) record(parentID [8]byte, name string, start time.Time, keyvals []interface{}) *Span {
+	s := &Span{tracer: t, parentID: parentID, name: name, start: start}
+	// nolint: errcheck
+	rand.Read(s.id[:])
+	s.SetAttributes(keyvals...)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = append(t.spans, s)
+
+	return s
+}
+
+// SetAttributes sets the alternating keys and values of attributes of the
+// span.
+func (s *Span) SetAttributes(keyvals ...interface{}) {
+	if s == nil {
+		return
+	}
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if key, ok := keyvals[i].(string); ok {
+			s.attrs = append(s.attrs, attribute{key: key, value: keyvals[i+1]})
+		}
+	}
+}
+
+// SetError marks the span as failed, with the message.
+func (s *Span) SetError(message string) {
+	if s == nil {
+		return
+	}
+	s.err = message
+}
+
+// End ends the span.
+func (s *Span) End() {
+	if s == nil || !s.end.IsZero() {
+		return
+	}
+	s.end = time.Now()
+}
+
+// Export exports the spans recorded to the OTLP endpoint. Spans that have
+// not ended are not exported.
+func (t *Tracer) Export() error {
+	if t == nil {
+		return nil
+	}
+
+	body, err := json.Marshal(t.request())
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := (&http.Client{Timeout: exportTimeout}).Do(req)
+	if err != nil {
+		return err
+	}
+	// nolint: errcheck
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("POST %s: %s", t.endpoint, resp.Status)
+	}
+
+	return nil
+}
+
+// The types of the OTLP JSON encoding of an ExportTraceServiceRequest, in
+// which IDs are hex encoded and 64 bit integers are strings.
+type (
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID           string          `json:"traceId"`
+		SpanID            string          `json:"spanId"`
+		ParentSpanID      string          `json:"parentSpanId,omitempty"`
+		Name              string          `json:"name"`
+		Kind              int             `json:"kind"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+		Attributes        []otlpAttribute `json:"attributes,omitempty"`
+		Status            *otlpStatus     `json:"status,omitempty"`
+	}
+	otlpAttribute struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+	otlpValue struct {
+		StringValue *string `json:"stringValue,omitempty"`
+		IntValue    *string `json:"intValue,omitempty"`
+		BoolValue   *bool   `json:"boolValue,omitempty"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	}
+)
+
+const (
+	spanKindInternal = 1
+	statusCodeError  = 2
+)
+
+func (t *Tracer) request() otlpRequest {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	spans := make([]otlpSpan, 0, len(t.spans))
+	for _, s := range t.spans {
+		if s.end.IsZero() {
+			continue
+		}
+		span := otlpSpan{
+			TraceID:           hex.EncodeToString(t.traceID[:]),
+			SpanID:            hex.EncodeToString(s.id[:]),
+			Name:              s.name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+		}
+		if s.parentID != ([8]byte{}) {
+			span.ParentSpanID = hex.EncodeToString(s.parentID[:])
+		}
+		for _, a := range s.attrs {
+			span.Attributes = append(span.Attributes, otlpAttribute{Key: a.key, Value: value(a.value)})
+		}
+		if s.err != "" {
+			span.Status = &otlpStatus{Code: statusCodeError, Message: s.err}
+		}
+		spans = append(spans, span)
+	}
+
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: []otlpAttribute{{Key: "service.name", Value: value(t.service)}}},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: "github.com/autonomy/conform"}, Spans: spans}},
+	}}}
+}
+
+func value(v interface{}) otlpValue {
+	switch v := v.(type) {
+	case int:
+		s := strconv.Itoa(v)
+		return otlpValue{IntValue: &s}
+	case bool:
+		return otlpValue{BoolValue: &v}
+	case string:
+		return otlpValue{StringValue: &v}
+	default:
+		s := ""
+		if stringer, ok := v.(interface{ String() string }); ok {
+			s = stringer.String()
+		}
+		return otlpValue{StringValue: &s}
+	}
+}
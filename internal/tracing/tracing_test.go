@@ -0,0 +1,185 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package tracing
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func lookup(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		endpoint string
+		err      bool
+	}{
+		{
+			name: "Unconfigured",
+			env:  map[string]string{},
+		},
+		{
+			name:     "Endpoint",
+			env:      map[string]string{EndpointEnv: "http://collector:4318/"},
+			endpoint: "http://collector:4318/v1/traces",
+		},
+		{
+			name:     "TracesEndpoint",
+			env:      map[string]string{EndpointEnv: "http://collector:4318", TracesEndpointEnv: "http://traces:4318/traces"},
+			endpoint: "http://traces:4318/traces",
+		},
+		{
+			name: "UnsupportedProtocol",
+			env:  map[string]string{EndpointEnv: "http://collector:4317", ProtocolEnv: "grpc"},
+			err:  true,
+		},
+		{
+			name: "InvalidHeader",
+			env:  map[string]string{EndpointEnv: "http://collector:4318", HeadersEnv: "api-key"},
+			err:  true,
+		},
+		{
+			name: "InvalidTraceParent",
+			env:  map[string]string{EndpointEnv: "http://collector:4318", TraceParentEnv: "00-xyz-01"},
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(tt *testing.T) {
+			tracer, err := FromEnv(lookup(test.env))
+			if test.err {
+				if err == nil {
+					tt.Fatal("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				tt.Fatal(err)
+			}
+			endpoint := ""
+			if tracer != nil {
+				endpoint = tracer.endpoint
+			}
+			if endpoint != test.endpoint {
+				tt.Errorf("Expected endpoint %q, got %q", test.endpoint, endpoint)
+			}
+		})
+	}
+}
+
+func TestNilTracer(t *testing.T) {
+	var tracer *Tracer
+	span := tracer.Start("conform enforce")
+	span.Start("policy commit").End()
+	span.SetError("failure")
+	span.End()
+	if err := tracer.Export(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExport(t *testing.T) {
+	var (
+		body    []byte
+		headers http.Header
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		body, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	tracer, err := FromEnv(lookup(map[string]string{
+		EndpointEnv:    server.URL,
+		HeadersEnv:     "api-key=secret%20key",
+		ServiceNameEnv: "ci",
+		TraceParentEnv: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := tracer.Start("conform enforce")
+	policy := root.Start("policy commit", "conform.policy.type", "commit")
+	start := time.Unix(0, 1000)
+	check := policy.Record("check Header Length", start, start.Add(time.Microsecond), "conform.check.violations", 1)
+	check.SetError("Header is 92 characters")
+	// Spans that have not ended are not exported.
+	root.Start("policy license")
+	policy.End()
+	root.End()
+	if err = tracer.Export(); err != nil {
+		t.Fatal(err)
+	}
+
+	if headers.Get("api-key") != "secret key" {
+		t.Errorf("Expected header api-key %q, got %q", "secret key", headers.Get("api-key"))
+	}
+	var req otlpRequest
+	if err = json.Unmarshal(body, &req); err != nil {
+		t.Fatal(err)
+	}
+	if service := *req.ResourceSpans[0].Resource.Attributes[0].Value.StringValue; service != "ci" {
+		t.Errorf("Expected service ci, got %q", service)
+	}
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %d", len(spans))
+	}
+	parents := map[string]string{}
+	for _, s := range spans {
+		if s.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("Expected trace of the trace context, got %q", s.TraceID)
+		}
+		parents[s.Name] = s.ParentSpanID
+	}
+	if parents["conform enforce"] != "00f067aa0ba902b7" {
+		t.Errorf("Expected root span to be a child of the trace context, got parent %q", parents["conform enforce"])
+	}
+	if parents["policy commit"] != spans[0].SpanID {
+		t.Errorf("Expected policy span to be a child of the root span, got parent %q", parents["policy commit"])
+	}
+
+	c := spans[2]
+	if c.StartTimeUnixNano != "1000" || c.EndTimeUnixNano != "2000" {
+		t.Errorf("Expected check span from 1000 to 2000, got %s to %s", c.StartTimeUnixNano, c.EndTimeUnixNano)
+	}
+	if c.Status == nil || c.Status.Code != statusCodeError {
+		t.Errorf("Expected check span to be failed, got status %+v", c.Status)
+	}
+	if v := c.Attributes[0].Value.IntValue; v == nil || *v != "1" {
+		t.Errorf("Expected check span attribute conform.check.violations=1, got %+v", c.Attributes[0])
+	}
+}
+
+func TestExportError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	tracer, err := FromEnv(lookup(map[string]string{TracesEndpointEnv: server.URL}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracer.Start("conform enforce").End()
+	if err = tracer.Export(); err == nil {
+		t.Fatal("Expected an error")
+	}
+}
@@ -0,0 +1,98 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+// Package update checks for newer releases of conform.
+package update
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/github"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Owner is the owner of the GitHub repository of conform.
+	Owner = "autonomy"
+	// Repository is the name of the GitHub repository of conform.
+	Repository = "conform"
+)
+
+// Release is a published release.
+type Release struct {
+	// Tag is the semantic version of the release, e.g. v0.1.0.
+	Tag string
+	// URL is the URL of the page of the release.
+	URL string
+}
+
+// Latest returns the latest release of conform.
+func Latest(ctx context.Context, client *github.Client) (*Release, error) {
+	release, _, err := client.Repositories.GetLatestRelease(ctx, Owner, Repository)
+	if err != nil {
+		return nil, errors.Errorf("failed to get the latest release: %v", err)
+	}
+
+	return &Release{Tag: release.GetTagName(), URL: release.GetHTMLURL()}, nil
+}
+
+// Newer reports whether the semantic version latest is newer than current.
+// Versions that are not semantic, such as those of development builds, are
+// never newer nor older.
+func Newer(current, latest string) bool {
+	c, ok := parse(current)
+	if !ok {
+		return false
+	}
+	l, ok := parse(latest)
+	if !ok {
+		return false
+	}
+	for i := range c.numbers {
+		if l.numbers[i] != c.numbers[i] {
+			return l.numbers[i] > c.numbers[i]
+		}
+	}
+
+	// A pre-release has a lower precedence than the release.
+	switch {
+	case c.prerelease == "" || l.prerelease == "":
+		return c.prerelease != "" && l.prerelease == ""
+	default:
+		return l.prerelease > c.prerelease
+	}
+}
+
+type version struct {
+	numbers    [3]int
+	prerelease string
+}
+
+// parse parses a semantic version, with an optional v prefix and ignoring
+// build metadata.
+func parse(s string) (version, bool) {
+	var v version
+	s = strings.TrimPrefix(s, "v")
+	if i := strings.Index(s, "+"); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.Index(s, "-"); i >= 0 {
+		s, v.prerelease = s[:i], s[i+1:]
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) != len(v.numbers) {
+		return v, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		v.numbers[i] = n
+	}
+
+	return v, true
+}
@@ -0,0 +1,72 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package update
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/github"
+)
+
+func TestNewer(t *testing.T) {
+	type testDesc struct {
+		Current  string
+		Latest   string
+		Expected bool
+	}
+
+	for _, test := range []testDesc{
+		{Current: "v0.1.0", Latest: "v0.2.0", Expected: true},
+		{Current: "v0.1.0", Latest: "v0.1.0", Expected: false},
+		{Current: "v0.2.0", Latest: "v0.1.9", Expected: false},
+		{Current: "v0.9.0", Latest: "v0.10.0", Expected: true},
+		{Current: "0.1.0", Latest: "v1.0.0", Expected: true},
+		{Current: "v0.1.0-alpha.1", Latest: "v0.1.0", Expected: true},
+		{Current: "v0.1.0", Latest: "v0.1.1-alpha.1", Expected: true},
+		{Current: "v0.1.0", Latest: "v0.1.0-alpha.1", Expected: false},
+		{Current: "v0.1.0-alpha.1", Latest: "v0.1.0-alpha.2", Expected: true},
+		{Current: "v0.1.0+build.1", Latest: "v0.1.0", Expected: false},
+		{Current: "", Latest: "v0.1.0", Expected: false},
+		{Current: "undefined", Latest: "v0.1.0", Expected: false},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(fmt.Sprintf("%s-%s", test.Current, test.Latest), func(tt *testing.T) {
+			if actual := Newer(test.Current, test.Latest); actual != test.Expected {
+				tt.Errorf("Expected Newer(%q, %q) to be %v", test.Current, test.Latest, test.Expected)
+			}
+		})
+	}
+}
+
+func TestLatest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/autonomy/conform/releases/latest" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"tag_name": "v0.2.0", "html_url": "https://github.com/autonomy/conform/releases/tag/v0.2.0"}`)
+	}))
+	defer server.Close()
+
+	client := github.NewClient(server.Client())
+	var err error
+	if client.BaseURL, err = url.Parse(server.URL + "/"); err != nil {
+		t.Fatal(err)
+	}
+
+	release, err := Latest(context.Background(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if release.Tag != "v0.2.0" || release.URL != "https://github.com/autonomy/conform/releases/tag/v0.2.0" {
+		t.Errorf("Expected release v0.2.0, got %+v", release)
+	}
+}
@@ -0,0 +1,250 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/autonomy/conform/internal/git"
+	"github.com/autonomy/conform/internal/logging"
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/src-d/go-billy.v4/osfs"
+	"gopkg.in/src-d/go-git.v4/plumbing/format/gitignore"
+)
+
+// commitMsgFile is the name of the file of the git directory of the message
+// of the commit being authored.
+const commitMsgFile = "COMMIT_EDITMSG"
+
+// Change is a set of changes to the working tree.
+type Change struct {
+	// Files are the slash separated paths of the files changed, relative to
+	// the root of the working tree.
+	Files []string
+	// CommitMsg is true if the message of the commit being authored changed.
+	CommitMsg bool
+}
+
+// Watcher watches the working tree of a repository, except the files ignored
+// by .gitignore and .conformignore files, and the message of the commit
+// being authored for changes. Changes made in quick succession, e.g. by an
+// editor saving a file, are reported as one.
+type Watcher struct {
+	// Changes receives the changes to the working tree.
+	Changes chan Change
+	// Errors receives the errors of watching the working tree.
+	Errors chan error
+
+	root string
+	// gitDir is the git directory of the working tree, which is outside of
+	// it in linked worktrees.
+	gitDir    string
+	watcher   *fsnotify.Watcher
+	gitignore gitignore.Matcher
+	ignore    *git.Ignore
+	debounce  time.Duration
+}
+
+// New starts watching the working tree at the root. Changes are reported
+// once no other change is made for the debounce duration.
+func New(root string, debounce time.Duration) (*Watcher, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	patterns, err := gitignore.ReadPatterns(osfs.New(root), nil)
+	if err != nil {
+		return nil, err
+	}
+	ignore, err := git.ReadIgnore(root)
+	if err != nil {
+		return nil, err
+	}
+	gitDir, err := git.GitDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		Changes:   make(chan Change),
+		Errors:    make(chan error),
+		root:      root,
+		gitDir:    gitDir,
+		watcher:   watcher,
+		gitignore: gitignore.NewMatcher(patterns),
+		ignore:    ignore,
+		debounce:  debounce,
+	}
+
+	// The git directory is watched for the commit message only.
+	if err = watcher.Add(gitDir); err != nil {
+		// nolint: errcheck
+		watcher.Close()
+		return nil, err
+	}
+	if err = w.add(root); err != nil {
+		// nolint: errcheck
+		watcher.Close()
+		return nil, err
+	}
+
+	go w.run()
+
+	return w, nil
+}
+
+// CommitMsgFile returns the path of the message of the commit being authored.
+func (w *Watcher) CommitMsgFile() string {
+	return filepath.Join(w.gitDir, commitMsgFile)
+}
+
+// Close stops watching the working tree.
+func (w *Watcher) Close() error {
+	return w.watcher.Close()
+}
+
+// add watches the directory and its subdirectories that are not ignored.
+func (w *Watcher) add(dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				// The directory was removed since it was created.
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != w.root && w.ignored(path, true) {
+			return filepath.SkipDir
+		}
+		logging.Debug("watching directory", "path", path)
+
+		return w.watcher.Add(path)
+	})
+}
+
+func (w *Watcher) run() {
+	change := Change{}
+	changed := map[string]bool{}
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() && !w.ignored(event.Name, true) {
+					if err = w.add(event.Name); err != nil {
+						w.Errors <- err
+					}
+				}
+			}
+			file, commitMsg, ok := w.classify(event.Name)
+			if !ok {
+				continue
+			}
+			logging.Debug("changed path", "path", event.Name, "op", event.Op.String())
+			if commitMsg {
+				change.CommitMsg = true
+			} else if !changed[file] {
+				changed[file] = true
+				change.Files = append(change.Files, file)
+			}
+			timer.Reset(w.debounce)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			w.Errors <- err
+		case <-timer.C:
+			sort.Strings(change.Files)
+			w.Changes <- change
+			change = Change{}
+			changed = map[string]bool{}
+		}
+	}
+}
+
+// classify returns the slash separated path of the changed file relative to
+// the root of the working tree, and whether it is the commit message. Changes
+// to other files of the git directory, and to ignored files, are not
+// reported.
+func (w *Watcher) classify(name string) (string, bool, bool) {
+	rel, err := filepath.Rel(w.root, name)
+	if err != nil {
+		return "", false, false
+	}
+	rel = filepath.ToSlash(rel)
+	switch {
+	case name == w.CommitMsgFile():
+		return rel, true, true
+	case rel == ".git" || strings.HasPrefix(rel, ".git/") || rel == "." || strings.HasPrefix(rel, "../"):
+		return "", false, false
+	case w.ignored(name, false):
+		return "", false, false
+	default:
+		return rel, false, true
+	}
+}
+
+// ignored reports whether the path is ignored by the .gitignore or
+// .conformignore files.
+func (w *Watcher) ignored(name string, isDir bool) bool {
+	rel, err := filepath.Rel(w.root, name)
+	if err != nil {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == ".git" {
+		return true
+	}
+	if w.gitignore != nil && w.gitignore.Match(strings.Split(rel, "/"), isDir) {
+		return true
+	}
+
+	return w.ignore.Match(rel, isDir)
+}
+
+// scissors is the line of a commit message below which git removes the
+// contents, e.g. the diff of git commit --verbose.
+const scissors = "# ------------------------ >8 ------------------------"
+
+// CleanCommitMsg cleans up the message of a commit being authored as git
+// does by default before committing: the contents below the scissors line
+// and comments are removed, as are trailing whitespace and blank lines.
+func CleanCommitMsg(msg string) string {
+	var lines []string
+	for _, line := range strings.Split(msg, "\n") {
+		if line == scissors {
+			break
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t"))
+	}
+
+	cleaned := strings.Trim(strings.Join(lines, "\n"), "\n")
+	if cleaned == "" {
+		return ""
+	}
+
+	return cleaned + "\n"
+}
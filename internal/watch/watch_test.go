@@ -0,0 +1,85 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package watch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/autonomy/conform/internal/git"
+	"gopkg.in/src-d/go-git.v4/plumbing/format/gitignore"
+)
+
+func TestClassify(t *testing.T) {
+	ignore, err := git.ParseIgnore(strings.NewReader("vendor/\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &Watcher{
+		root:      "/repo",
+		gitDir:    "/repo/.git",
+		gitignore: gitignore.NewMatcher([]gitignore.Pattern{gitignore.ParsePattern("*.swp", nil)}),
+		ignore:    ignore,
+	}
+
+	for _, test := range []struct {
+		Name      string
+		Path      string
+		File      string
+		CommitMsg bool
+		OK        bool
+	}{
+		{"File", "/repo/cmd/main.go", "cmd/main.go", false, true},
+		{"Commit message", "/repo/.git/COMMIT_EDITMSG", ".git/COMMIT_EDITMSG", true, true},
+		{"Git directory", "/repo/.git/index.lock", "", false, false},
+		{"Ignored by .gitignore", "/repo/.main.go.swp", "", false, false},
+		{"Ignored by .conformignore", "/repo/vendor/a/a.go", "", false, false},
+		{"Outside of the root", "/other/main.go", "", false, false},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			file, commitMsg, ok := w.classify(test.Path)
+			if file != test.File || commitMsg != test.CommitMsg || ok != test.OK {
+				tt.Errorf("Expected %q, %v, %v, got %q, %v, %v", test.File, test.CommitMsg, test.OK, file, commitMsg, ok)
+			}
+		})
+	}
+}
+
+func TestClassifyWorktree(t *testing.T) {
+	w := &Watcher{root: "/worktree", gitDir: "/repo/.git/worktrees/worktree"}
+
+	if _, commitMsg, ok := w.classify("/repo/.git/worktrees/worktree/COMMIT_EDITMSG"); !commitMsg || !ok {
+		t.Errorf("Expected the commit message of the worktree to be reported, got %v, %v", commitMsg, ok)
+	}
+	if _, _, ok := w.classify("/repo/.git/worktrees/worktree/index.lock"); ok {
+		t.Error("Expected the git directory of the worktree to be ignored")
+	}
+	if _, _, ok := w.classify("/worktree/.git"); ok {
+		t.Error("Expected the .git file of the worktree to be ignored")
+	}
+}
+
+func TestCleanCommitMsg(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Message  string
+		Expected string
+	}{
+		{"Comments", "feat: add a thing\n\n# Please enter the commit message.\n#\n", "feat: add a thing\n"},
+		{"Trailing whitespace", "feat: add a thing  \n\nBody.\t\n\n\n", "feat: add a thing\n\nBody.\n"},
+		{"Scissors", "fix: a bug\n# ------------------------ >8 ------------------------\ndiff --git a/a b/a\n", "fix: a bug\n"},
+		{"Empty", "\n# Please enter the commit message.\n", ""},
+	} {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.Name, func(tt *testing.T) {
+			if msg := CleanCommitMsg(test.Message); msg != test.Expected {
+				tt.Errorf("Expected %q, got %q", test.Expected, msg)
+			}
+		})
+	}
+}
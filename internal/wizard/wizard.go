@@ -0,0 +1,245 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+// Package wizard interactively composes commit messages that comply with the
+// commit policy.
+package wizard
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/autonomy/conform/internal/policy/commit"
+	"github.com/pkg/errors"
+)
+
+// Prompter prompts for answers on a terminal.
+type Prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// NewPrompter returns a Prompter reading the answers from in, and writing the
+// prompts to out.
+func NewPrompter(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{in: bufio.NewReader(in), out: out}
+}
+
+// readLine reads a line of input, without the line ending.
+func (p *Prompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	if err == io.EOF {
+		return "", errors.New("Aborted")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// Line prompts for a line until it is valid. A nil validate accepts any line.
+func (p *Prompter) Line(label string, validate func(string) error) (string, error) {
+	for {
+		fmt.Fprintf(p.out, "%s: ", label)
+		line, err := p.readLine()
+		if err != nil {
+			return "", err
+		}
+		line = strings.TrimSpace(line)
+		if validate == nil {
+			return line, nil
+		}
+		if err = validate(line); err == nil {
+			return line, nil
+		}
+		fmt.Fprintf(p.out, "  %v\n", err)
+	}
+}
+
+// Lines prompts for lines, up to the first empty line.
+func (p *Prompter) Lines(label string) (string, error) {
+	fmt.Fprintf(p.out, "%s (end with an empty line):\n", label)
+
+	var lines []string
+	for {
+		line, err := p.readLine()
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(line) == "" {
+			return strings.Join(lines, "\n"), nil
+		}
+		lines = append(lines, strings.TrimRight(line, " \t"))
+	}
+}
+
+// Choose prompts for one of the choices, by number or by name, and returns
+// the choice. If optional is true, an empty choice may be returned.
+func (p *Prompter) Choose(label string, choices []string, optional bool) (string, error) {
+	fmt.Fprintf(p.out, "%s:\n", label)
+	for i, choice := range choices {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, choice)
+	}
+	if optional {
+		label += " (optional)"
+	}
+
+	answer, err := p.Line(label, func(answer string) error {
+		if answer == "" && optional {
+			return nil
+		}
+		if _, ok := choose(answer, choices); !ok {
+			return errors.Errorf("Invalid choice %q: must be one of 1-%d, or a name", answer, len(choices))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	choice, _ := choose(answer, choices)
+
+	return choice, nil
+}
+
+// choose returns the choice of the answer, either a number or a name.
+func choose(answer string, choices []string) (string, bool) {
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(choices) {
+		return choices[n-1], true
+	}
+	for _, choice := range choices {
+		if choice == answer {
+			return choice, true
+		}
+	}
+
+	return "", false
+}
+
+// Confirm prompts for a yes or no answer, which defaults to yes.
+func (p *Prompter) Confirm(label string) (bool, error) {
+	answer, err := p.Line(label+" [Y/n]", func(answer string) error {
+		switch strings.ToLower(answer) {
+		case "", "y", "yes", "n", "no":
+			return nil
+		default:
+			return errors.Errorf("Invalid answer %q: must be yes or no", answer)
+		}
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return !strings.HasPrefix(strings.ToLower(answer), "n"), nil
+}
+
+// Compose prompts for the parts of a commit message complying with the commit
+// policy: the type and scope of conventional commits, the subject, the body,
+// and the footers. The commit is signed off by signOff, e.g.
+// "Jane Doe <jane@example.com>", when the policy requires a DCO. The message
+// is validated against the policy before being returned.
+func Compose(p *Prompter, c *commit.Commit, signOff string) (string, error) {
+	// The header is validated on its own, without the checks of the rest of
+	// the message.
+	headerPolicy := *c
+	headerPolicy.DCO = false
+	headerPolicy.GPG = false
+	headerPolicy.RequireCommitBody = false
+
+	var prefix string
+	if c.Conventional != nil {
+		t, err := p.Choose("Type", Types(c.Conventional), false)
+		if err != nil {
+			return "", err
+		}
+		prefix = t
+		if len(c.Conventional.Scopes) != 0 {
+			scope, err := p.Choose("Scope", c.Conventional.Scopes, true)
+			if err != nil {
+				return "", err
+			}
+			if scope != "" {
+				prefix += "(" + scope + ")"
+			}
+		}
+		prefix += ": "
+	}
+
+	subject, err := p.Line("Subject", func(subject string) error {
+		if subject == "" {
+			return errors.New("The subject is required")
+		}
+		if errs := headerPolicy.ValidateMessage(prefix + subject); len(errs) != 0 {
+			return errs[0]
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	msg := prefix + subject + "\n"
+
+	for {
+		var body string
+		if body, err = p.Lines("Body"); err != nil {
+			return "", err
+		}
+		if body != "" {
+			msg += "\n" + body + "\n"
+			break
+		}
+		if !c.RequireCommitBody {
+			break
+		}
+		fmt.Fprintln(p.out, "  The body is required")
+	}
+
+	var footers []string
+	if c.Conventional != nil {
+		var breaking string
+		if breaking, err = p.Line("Breaking change (optional)", nil); err != nil {
+			return "", err
+		}
+		if breaking != "" {
+			footers = append(footers, "BREAKING CHANGE: "+breaking)
+		}
+	}
+	other, err := p.Lines("Footers, e.g. Closes #123")
+	if err != nil {
+		return "", err
+	}
+	if other != "" {
+		footers = append(footers, other)
+	}
+	if c.DCO {
+		footers = append(footers, "Signed-off-by: "+signOff)
+	}
+	if len(footers) != 0 {
+		msg += "\n" + strings.Join(footers, "\n") + "\n"
+	}
+
+	if errs := c.ValidateMessage(msg); len(errs) != 0 {
+		return "", errors.Errorf("The commit message does not comply with the commit policy: %v", errs[0])
+	}
+
+	return msg, nil
+}
+
+// Types returns the types of conventional commits allowed by the policy.
+func Types(c *commit.Conventional) []string {
+	types := []string{commit.TypeFeat, commit.TypeFix}
+	for _, t := range c.Types {
+		if t != commit.TypeFeat && t != commit.TypeFix {
+			types = append(types, t)
+		}
+	}
+
+	return types
+}
@@ -0,0 +1,77 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package wizard
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/autonomy/conform/internal/policy/commit"
+)
+
+func TestCompose(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   *commit.Commit
+		input    string
+		expected string
+		err      bool
+	}{
+		{
+			name:     "Plain",
+			policy:   &commit.Commit{},
+			input:    "Add x\n\n\n",
+			expected: "Add x\n",
+		},
+		{
+			name: "Conventional",
+			policy: &commit.Commit{
+				HeaderLength: 20,
+				DCO:          true,
+				Conventional: &commit.Conventional{Types: []string{"chore"}, Scopes: []string{"cli"}},
+			},
+			// The invalid answers are prompted for again.
+			input:    "docs\nchore\n\nadd a much too long subject\nadd x\nWhy.\n\nbreaks y\nCloses #1\n\n",
+			expected: "chore: add x\n\nWhy.\n\nBREAKING CHANGE: breaks y\nCloses #1\nSigned-off-by: A <a@b>\n",
+		},
+		{
+			name: "Numbers",
+			policy: &commit.Commit{
+				RequireCommitBody: true,
+				Conventional:      &commit.Conventional{Scopes: []string{"cli", "api"}},
+			},
+			input:    "2\n2\nadd x\n\nWhy.\n\n\n\n",
+			expected: "fix(api): add x\n\nWhy.\n",
+		},
+		{
+			name:   "Aborted",
+			policy: &commit.Commit{},
+			input:  "Add x\n",
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		// Fixes scopelint error.
+		test := test
+		t.Run(test.name, func(tt *testing.T) {
+			p := NewPrompter(strings.NewReader(test.input), ioutil.Discard)
+			msg, err := Compose(p, test.policy, "A <a@b>")
+			if test.err {
+				if err == nil {
+					tt.Error("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				tt.Fatal(err)
+			}
+			if msg != test.expected {
+				tt.Errorf("Expected message %q, got %q", test.expected, msg)
+			}
+		})
+	}
+}
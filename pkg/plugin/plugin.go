@@ -0,0 +1,152 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+// Package plugin defines the ABI of third-party conform policies. A plugin is
+// an executable that calls Serve with its Policy. Conform starts the plugin
+// and calls it with JSON-RPC over its standard input and output, so plugins
+// must write any logs to standard error.
+package plugin
+
+import (
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// ProtocolVersion is the version of the plugin protocol. It is incremented
+// whenever a change breaks compatibility between conform and its plugins.
+const ProtocolVersion = 1
+
+// Check defines a policy check. It is identical to the checks of the
+// builtin policies.
+type Check interface {
+	Name() string
+	Message() string
+	Errors() []error
+}
+
+// Policy is the interface that plugins must implement.
+type Policy interface {
+	Compliance(*Options) ([]Check, error)
+}
+
+// Options defines the set of options passed to a plugin.
+type Options struct {
+	// CommitMsgFile is the path to the commit message file, if any.
+	CommitMsgFile string
+	// BaseBranch is the base branch that HEAD is compared against, if any.
+	BaseBranch string
+	// AuthorName and AuthorEmail override the author of HEAD, if any.
+	AuthorName  string
+	AuthorEmail string
+	// Ref is the branch the commit is made on, overriding the branch HEAD
+	// points to, if any.
+	Ref string
+	// Spec is the spec declared for the policy in .conform.yaml.
+	Spec map[string]interface{}
+}
+
+// Result is the result of a check, as sent over the wire.
+type Result struct {
+	Name    string
+	Message string
+	Errors  []string
+}
+
+// Reply is the reply to a call to Compliance.
+type Reply struct {
+	Results []Result
+}
+
+// server exposes a Policy over RPC.
+type server struct {
+	policy Policy
+}
+
+// Version returns the protocol version of the plugin.
+func (s *server) Version(_ struct{}, version *int) error {
+	*version = ProtocolVersion
+	return nil
+}
+
+// Compliance calls the Compliance function of the policy.
+func (s *server) Compliance(opts *Options, reply *Reply) error {
+	checks, err := s.policy.Compliance(opts)
+	if err != nil {
+		return err
+	}
+	for _, check := range checks {
+		result := Result{Name: check.Name(), Message: check.Message()}
+		for _, err := range check.Errors() {
+			result.Errors = append(result.Errors, err.Error())
+		}
+		reply.Results = append(reply.Results, result)
+	}
+
+	return nil
+}
+
+// Serve serves the policy over standard input and output. It returns when
+// conform closes the connection.
+func Serve(p Policy) error {
+	return ServeConn(p, struct {
+		io.Reader
+		io.Writer
+		io.Closer
+	}{os.Stdin, os.Stdout, os.Stdin})
+}
+
+// ServeConn serves the policy over a connection.
+func ServeConn(p Policy, conn io.ReadWriteCloser) error {
+	s := rpc.NewServer()
+	if err := s.RegisterName("Plugin", &server{policy: p}); err != nil {
+		return err
+	}
+	s.ServeCodec(jsonrpc.NewServerCodec(conn))
+
+	return nil
+}
+
+// Client calls a plugin.
+type Client struct {
+	rpc *rpc.Client
+}
+
+// NewClient returns a client of the plugin served over the connection. It
+// fails if the plugin uses a different protocol version.
+func NewClient(conn io.ReadWriteCloser) (*Client, error) {
+	c := &Client{rpc: jsonrpc.NewClient(conn)}
+
+	var version int
+	if err := c.rpc.Call("Plugin.Version", struct{}{}, &version); err != nil {
+		// nolint: errcheck
+		c.Close()
+		return nil, errors.Errorf("failed to get protocol version: %v", err)
+	}
+	if version != ProtocolVersion {
+		// nolint: errcheck
+		c.Close()
+		return nil, errors.Errorf("unsupported protocol version %d, expected %d", version, ProtocolVersion)
+	}
+
+	return c, nil
+}
+
+// Compliance calls the Compliance function of the plugin.
+func (c *Client) Compliance(opts *Options) ([]Result, error) {
+	reply := &Reply{}
+	if err := c.rpc.Call("Plugin.Compliance", opts, reply); err != nil {
+		return nil, err
+	}
+
+	return reply.Results, nil
+}
+
+// Close closes the connection to the plugin.
+func (c *Client) Close() error {
+	return c.rpc.Close()
+}
@@ -0,0 +1,51 @@
+/* This Source Code Form is subject to the terms of the Mozilla Public
+ * License, v. 2.0. If a copy of the MPL was not distributed with this
+ * file, You can obtain one at http://mozilla.org/MPL/2.0/. */
+
+package plugin
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+)
+
+type check struct {
+	errors []error
+}
+
+func (c check) Name() string    { return "Test" }
+func (c check) Message() string { return "Tested" }
+func (c check) Errors() []error { return c.errors }
+
+type policy struct{}
+
+func (p policy) Compliance(opts *Options) ([]Check, error) {
+	if opts.Spec["fail"] == true {
+		return []Check{check{errors: []error{errors.New(opts.BaseBranch)}}}, nil
+	}
+	return []Check{check{}}, nil
+}
+
+func TestClient(t *testing.T) {
+	serverConn, clientConn := net.Pipe()
+	// nolint: errcheck
+	go ServeConn(policy{}, serverConn)
+
+	c, err := NewClient(clientConn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nolint: errcheck
+	defer c.Close()
+
+	results, err := c.Compliance(&Options{BaseBranch: "master", Spec: map[string]interface{}{"fail": true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Result{{Name: "Test", Message: "Tested", Errors: []string{"master"}}}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Expected %v, got %v", expected, results)
+	}
+}